@@ -132,7 +132,7 @@ func DescribeExecution(exec *execv1alpha1.Execution) {
 	for taskName, vertices := range status {
 		task := FindExecutionTask(exec, taskName)
 		totalJob := len(task.CommandSet)
-		var succeedJob, failedJob, runningJob, errorJob int
+		var succeedJob, failedJob, runningJob, errorJob, skippedJob int
 		for _, vertex := range vertices {
 			switch vertex.Phase {
 			case execv1alpha1.VertexFailed:
@@ -143,10 +143,13 @@ func DescribeExecution(exec *execv1alpha1.Execution) {
 				runningJob++
 			case execv1alpha1.VertexSucceeded:
 				succeedJob++
+			case execv1alpha1.VertexSkipped:
+				skippedJob++
 			}
 		}
 
-		info := fmt.Sprintf("(total: %d; success: %d; failed: %d; running: %d; error: %d)", totalJob, succeedJob, failedJob, runningJob, errorJob)
+		info := fmt.Sprintf("(total: %d; success: %d; failed: %d; running: %d; error: %d; skipped: %d)",
+			totalJob, succeedJob, failedJob, runningJob, errorJob, skippedJob)
 		writer.Write(1, taskName+info+":\n")
 
 		if len(vertices) == 0 {
@@ -165,6 +168,15 @@ func DescribeExecution(exec *execv1alpha1.Execution) {
 		}
 	}
 
+	if len(exec.Status.Outputs) != 0 {
+		writer.Write(0, "outputs:\n")
+		writer.Write(1, "Name\tPath\tSize\tChecksum\n")
+		writer.Write(1, "----\t----\t----\t--------\n")
+		for _, output := range exec.Status.Outputs {
+			writer.Write(1, "%v\t%v\t%v\t%v\n", output.Name, output.Path, output.Size, output.Checksum)
+		}
+	}
+
 	tabWriter.Flush()
 	str := string(buf.String())
 	fmt.Fprintf(os.Stdout, "%s\n", str)
@@ -171,6 +171,7 @@ func PrintExecutionListWide(execList []execv1alpha1.Execution) {
 
 func getAllPhases() []execv1alpha1.VertexPhase {
 	return []execv1alpha1.VertexPhase{
+		execv1alpha1.VertexQueued,
 		execv1alpha1.VertexRunning,
 		execv1alpha1.VertexSucceeded,
 		execv1alpha1.VertexFailed,
@@ -38,6 +38,7 @@ func NewSubCommand() *cobra.Command {
 	}
 
 	command.PersistentFlags().String("tool-repo", ToolDir, "directory or URL to tool repository, if it is a URL, it must point to tool file.")
+	command.PersistentFlags().Bool("cluster-tools", true, "resolve the tools against the tools of the cluster before the tool repository.")
 	command.PersistentFlags().BoolVarP(&subOptions.dryRun, "dry-run", "", false, "If true, display results but do not submit workflow")
 
 	command.AddCommand(NewSubJobCommand())
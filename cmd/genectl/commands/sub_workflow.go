@@ -24,14 +24,15 @@ import (
 	"html/template"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/renstrom/dedent"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"kubegene.io/kubegene/cmd/genectl/client"
-	"kubegene.io/kubegene/cmd/genectl/parser"
 	"kubegene.io/kubegene/cmd/genectl/util"
+	"kubegene.io/kubegene/pkg/parser"
 )
 
 var submitSuccessMessage = template.Must(template.New("message").Parse(dedent.Dedent(`
@@ -86,7 +87,7 @@ func SubWorkflow(cmd *cobra.Command, args []string, workflowFlags *workflowFlags
 
 func ProcessWorkflow(cmd *cobra.Command, workflowPath string, inputs map[string]interface{}) {
 	// fetch all usable tools.
-	tools, err := parser.FetchTools(cmd)
+	tools, err := fetchTools(cmd)
 	if err != nil {
 		ExitWithError(err)
 	}
@@ -116,6 +117,12 @@ func ProcessWorkflow(cmd *cobra.Command, workflowPath string, inputs map[string]
 	if err != nil {
 		ExitWithError(err)
 	}
+	// expand sub workflows
+	load := workflowLoader(cmd, workflowPath, parser.GetExecutionNamespace(workflow.Inputs))
+	err = parser.ExpandSubWorkflows(workflow, tools, load)
+	if err != nil {
+		ExitWithError(err)
+	}
 	if util.GetFlagBool(cmd, "dry-run") {
 		util.PrintYAML(workflow)
 		return
@@ -149,6 +156,31 @@ func ProcessWorkflow(cmd *cobra.Command, workflowPath string, inputs map[string]
 	fmt.Println(msg.String())
 }
 
+// workflowLoader loads the sub workflows from the files relative to the
+// directory of the workflow file, or from the workflow templates in the
+// namespace of the execution.
+func workflowLoader(cmd *cobra.Command, workflowPath, namespace string) parser.WorkflowLoader {
+	return func(sub *parser.SubWorkflow) ([]byte, error) {
+		if len(sub.Template) != 0 {
+			geneClient, err := client.GetGeneClient(cmd)
+			if err != nil {
+				return nil, err
+			}
+			workflowTemplate, err := geneClient.ExecutionV1alpha1().WorkflowTemplates(namespace).Get(context.TODO(), sub.Template, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return []byte(workflowTemplate.Spec.Workflow), nil
+		}
+
+		path := sub.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(workflowPath), path)
+		}
+		return ioutil.ReadFile(path)
+	}
+}
+
 func readInputJson(inputFile string) (map[string]interface{}, error) {
 	if inputFile == "" {
 		return nil, nil
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubegene.io/kubegene/cmd/genectl/client"
+	"kubegene.io/kubegene/cmd/genectl/util"
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/parser"
+)
+
+// fetchTools returns the tools of the cluster and of the tool repo, the tools
+// of the cluster take precedence. Either one is enough when the other one
+// can not be fetched.
+func fetchTools(cmd *cobra.Command) (map[string]parser.Tool, error) {
+	tools, err := parser.LoadTools(util.GetFlagString(cmd, "tool-repo"))
+	if !util.GetFlagBool(cmd, "cluster-tools") {
+		return tools, err
+	}
+
+	clusterTools, clusterErr := fetchClusterTools(cmd)
+	if err != nil {
+		if clusterErr != nil {
+			return nil, fmt.Errorf("%v, and fetch cluster tools error: %v", err, clusterErr)
+		}
+		return clusterTools, nil
+	}
+	for key, tool := range clusterTools {
+		tools[key] = tool
+	}
+	return tools, nil
+}
+
+// fetchClusterTools returns the tools of the cluster.
+func fetchClusterTools(cmd *cobra.Command) (map[string]parser.Tool, error) {
+	geneClient, err := client.GetGeneClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	toolList, err := geneClient.ExecutionV1alpha1().Tools().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]*execv1alpha1.Tool, 0, len(toolList.Items))
+	for i := range toolList.Items {
+		tools = append(tools, &toolList.Items[i])
+	}
+	return parser.TransClusterTools2Map(tools), nil
+}
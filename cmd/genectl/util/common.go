@@ -23,7 +23,6 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
-	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog"
 	//"time"
 	"path"
@@ -64,14 +63,6 @@ func PrintYAML(obj interface{}) {
 	fmt.Println(string(byte))
 }
 
-func GenerateExecName(prefix string) string {
-	//formatTime := time.Now().Format("2006-0102-1504")
-	uuid := uuid.NewUUID()
-	randStr := strings.Replace(string(uuid), "-", "", -1)[0:5]
-	jobId := fmt.Sprintf("%s-%s", prefix, randStr)
-	return jobId
-}
-
 func GetFileNameOnly(filePath string) string {
 	fileNameWithSuffix := path.Base(filePath)
 	fileSuffix := path.Ext(fileNameWithSuffix)
@@ -88,14 +79,3 @@ func CreateDirectory(dirName string) {
 		}
 	}
 }
-
-func PathExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		return true, nil
-	}
-	if os.IsNotExist(err) {
-		return false, nil
-	}
-	return false, err
-}
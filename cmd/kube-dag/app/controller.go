@@ -19,20 +19,26 @@ package app
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"reflect"
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
-	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -42,12 +48,19 @@ import (
 	"kubegene.io/kubegene/cmd/kube-dag/app/options"
 	"kubegene.io/kubegene/pkg/apis/gene"
 	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	genev1beta1 "kubegene.io/kubegene/pkg/apis/gene/v1beta1"
 	execclientset "kubegene.io/kubegene/pkg/client/clientset/versioned"
 	execscheme "kubegene.io/kubegene/pkg/client/clientset/versioned/scheme"
 	execinformers "kubegene.io/kubegene/pkg/client/informers/externalversions"
 	"kubegene.io/kubegene/pkg/controller"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/jobcache"
+	"kubegene.io/kubegene/pkg/parser"
+	"kubegene.io/kubegene/pkg/shard"
 	"kubegene.io/kubegene/pkg/util"
 	"kubegene.io/kubegene/pkg/version"
+	"kubegene.io/kubegene/pkg/volcano"
+	"kubegene.io/kubegene/pkg/webhook"
 )
 
 const (
@@ -65,10 +78,10 @@ func createRecorder(kubeClient clientset.Interface) record.EventRecorder {
 	return eventBroadcaster.NewRecorder(scheme.Scheme, apiv1.EventSource{Component: "gene-controller"})
 }
 
-func createClients(o *options.ExecutionOption) (clientset.Interface, clientset.Interface, *execclientset.Clientset, *apiextensionsclient.Clientset, error) {
+func createClients(o *options.ExecutionOption) (clientset.Interface, clientset.Interface, *execclientset.Clientset, *apiextensionsclient.Clientset, dynamic.Interface, error) {
 	kubeconfig, err := clientcmd.BuildConfigFromFlags("", o.KubeConfig)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("unable to build config from flags: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("unable to build config from flags: %v", err)
 	}
 
 	// Override kubeconfig qps/burst settings from flags
@@ -77,40 +90,55 @@ func createClients(o *options.ExecutionOption) (clientset.Interface, clientset.I
 
 	kubeClient, err := clientset.NewForConfig(restclient.AddUserAgent(kubeconfig, "gene-controller"))
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
 	}
 
 	leaderElectionClient := clientset.NewForConfigOrDie(restclient.AddUserAgent(kubeconfig, "leader-election"))
 
 	apiextentionsClient, err := apiextensionsclient.NewForConfig(kubeconfig)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
 	}
 
 	geneClient, err := execclientset.NewForConfig(kubeconfig)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create clientset: %v", err)
 	}
 
-	return kubeClient, leaderElectionClient, geneClient, apiextentionsClient, nil
+	dynamicClient, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create dynamic client: %v", err)
+	}
+
+	return kubeClient, leaderElectionClient, geneClient, apiextentionsClient, dynamicClient, nil
 }
 
-func installExecutionCRD(apiextensionsclient apiextensionsclient.Interface) error {
-	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+// installExecutionCRD installs the execution CRD, the v1beta1 version is
+// served if the conversion webhook converting it to v1alpha1 is set.
+func installExecutionCRD(apiextensionsclient apiextensionsclient.Interface, conversion *apiextensionsv1.CustomResourceConversion) error {
+	versions := []apiextensionsv1.CustomResourceDefinitionVersion{
+		executionCRDVersion(genev1alpha1.SchemeGroupVersion.Version, true,
+			util.StructuralSchema(genev1alpha1.ExecutionSpec{}, genev1alpha1.ExecutionStatus{})),
+	}
+	if conversion != nil {
+		versions = append(versions, executionCRDVersion(genev1beta1.SchemeGroupVersion.Version, false,
+			util.StructuralSchema(genev1beta1.ExecutionSpec{}, genev1beta1.ExecutionStatus{})))
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: gene.ExecutionPlural + "." + gene.GroupName,
 		},
-		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
-			Group:   gene.GroupName,
-			Version: genev1alpha1.SchemeGroupVersion.Version,
-			Scope:   apiextensionsv1beta1.NamespaceScoped,
-			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
-				Plural: gene.ExecutionPlural,
-				Kind:   reflect.TypeOf(genev1alpha1.Execution{}).Name(),
-			},
-			Subresources: &apiextensionsv1beta1.CustomResourceSubresources{
-				Status: &apiextensionsv1beta1.CustomResourceSubresourceStatus{},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gene.GroupName,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     gene.ExecutionPlural,
+				Kind:       reflect.TypeOf(genev1alpha1.Execution{}).Name(),
+				ShortNames: []string{gene.ExecutionShort},
 			},
+			Versions:   versions,
+			Conversion: conversion,
 		},
 	}
 
@@ -121,52 +149,478 @@ func installExecutionCRD(apiextensionsclient apiextensionsclient.Interface) erro
 	return nil
 }
 
+func executionCRDVersion(name string, storage bool, schema *apiextensionsv1.JSONSchemaProps) apiextensionsv1.CustomResourceDefinitionVersion {
+	return apiextensionsv1.CustomResourceDefinitionVersion{
+		Name:    name,
+		Served:  true,
+		Storage: storage,
+		Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: schema},
+		Subresources: &apiextensionsv1.CustomResourceSubresources{
+			Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+		},
+		AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+			{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+			{Name: "Progress", Type: "string", JSONPath: ".status.progress"},
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+		},
+	}
+}
+
+// installCronExecutionCRD installs the cron execution CRD.
+func installCronExecutionCRD(apiextensionsclient apiextensionsclient.Interface) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: gene.CronExecutionPlural + "." + gene.GroupName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gene.GroupName,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     gene.CronExecutionPlural,
+				Kind:       reflect.TypeOf(genev1alpha1.CronExecution{}).Name(),
+				ShortNames: []string{gene.CronExecutionShort},
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    genev1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: util.StructuralSchema(genev1alpha1.CronExecutionSpec{}, genev1alpha1.CronExecutionStatus{}),
+					},
+					Subresources: &apiextensionsv1.CustomResourceSubresources{
+						Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{Name: "Schedule", Type: "string", JSONPath: ".spec.schedule"},
+						{Name: "Suspend", Type: "boolean", JSONPath: ".spec.suspend"},
+						{Name: "Last Schedule", Type: "date", JSONPath: ".status.lastScheduleTime"},
+						{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+					},
+				},
+			},
+		},
+	}
+
+	return util.EnsureCreateCRD(apiextensionsclient, crd)
+}
+
+// installWorkflowTemplateCRD installs the workflow template CRD.
+func installWorkflowTemplateCRD(apiextensionsclient apiextensionsclient.Interface) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: gene.WorkflowTemplatePlural + "." + gene.GroupName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gene.GroupName,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     gene.WorkflowTemplatePlural,
+				Kind:       reflect.TypeOf(genev1alpha1.WorkflowTemplate{}).Name(),
+				ShortNames: []string{gene.WorkflowTemplateShort},
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    genev1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: util.StructuralSchema(genev1alpha1.WorkflowTemplateSpec{}, nil),
+					},
+				},
+			},
+		},
+	}
+
+	return util.EnsureCreateCRD(apiextensionsclient, crd)
+}
+
+// installWorkflowRunCRD installs the workflow run CRD.
+func installWorkflowRunCRD(apiextensionsclient apiextensionsclient.Interface) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: gene.WorkflowRunPlural + "." + gene.GroupName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gene.GroupName,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     gene.WorkflowRunPlural,
+				Kind:       reflect.TypeOf(genev1alpha1.WorkflowRun{}).Name(),
+				ShortNames: []string{gene.WorkflowRunShort},
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    genev1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: util.StructuralSchema(genev1alpha1.WorkflowRunSpec{}, genev1alpha1.WorkflowRunStatus{}),
+					},
+					Subresources: &apiextensionsv1.CustomResourceSubresources{
+						Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{Name: "Template", Type: "string", JSONPath: ".spec.workflowTemplateRef"},
+						{Name: "Execution", Type: "string", JSONPath: ".status.execution"},
+						{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+						{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+					},
+				},
+			},
+		},
+	}
+
+	return util.EnsureCreateCRD(apiextensionsclient, crd)
+}
+
+// installToolCRD installs the tool CRD.
+func installToolCRD(apiextensionsclient apiextensionsclient.Interface) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: gene.ToolPlural + "." + gene.GroupName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: gene.GroupName,
+			Scope: apiextensionsv1.ClusterScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: gene.ToolPlural,
+				Kind:   reflect.TypeOf(genev1alpha1.Tool{}).Name(),
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    genev1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: util.StructuralSchema(genev1alpha1.ToolSpec{}, nil),
+					},
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{Name: "Tool", Type: "string", JSONPath: ".spec.name"},
+						{Name: "Version", Type: "string", JSONPath: ".spec.version"},
+						{Name: "Image", Type: "string", JSONPath: ".spec.image"},
+						{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+					},
+				},
+			},
+		},
+	}
+
+	return util.EnsureCreateCRD(apiextensionsclient, crd)
+}
+
+// conversionWebhook returns the conversion of the execution CRD through the
+// conversion webhook service, nil if the service is not set.
+func conversionWebhook(o *options.ExecutionOption) (*apiextensionsv1.CustomResourceConversion, error) {
+	if len(o.ConversionWebhookService) == 0 {
+		return nil, nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(o.ConversionWebhookService)
+	if err != nil || len(namespace) == 0 {
+		return nil, fmt.Errorf("invalid conversion webhook service %q, must be namespace/name", o.ConversionWebhookService)
+	}
+	var caBundle []byte
+	if len(o.ConversionWebhookCAFile) != 0 {
+		if caBundle, err = ioutil.ReadFile(o.ConversionWebhookCAFile); err != nil {
+			return nil, fmt.Errorf("read conversion webhook CA file error: %v", err)
+		}
+	}
+	path := webhook.ConversionPath
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service:  &apiextensionsv1.ServiceReference{Namespace: namespace, Name: name, Path: &path},
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1", "v1beta1"},
+		},
+	}, nil
+}
+
+// serveConversionWebhook serves the conversion webhook of the executions until the process exits.
+func serveConversionWebhook(o *options.ExecutionOption) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(webhook.ConversionPath, webhook.ServeConversion)
+	server := &http.Server{Addr: o.ConversionWebhookAddress, Handler: mux}
+	klog.Fatalf("conversion webhook error: %v", server.ListenAndServeTLS(o.TLSCertFile, o.TLSPrivateKeyFile))
+}
+
+// identity returns a unique identity of this process.
+func identity() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to get hostname: %v", err)
+	}
+	// add a uniquifier so that two processes on the same host don't accidentally both become active
+	return hostname + "_" + string(uuid.NewUUID()), nil
+}
+
+// podNameEnv is the environment variable the pod name of kube-dag is given in.
+const podNameEnv = "MY_NAME"
+
+// replicaName returns the name of the pod of this replica, or its hostname
+// if the pod name is not given, so that a replica restarted in the same pod
+// takes its shard lease back rather than leaving a stale one behind.
+func replicaName() (string, error) {
+	if name := os.Getenv(podNameEnv); len(name) != 0 {
+		return name, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to get hostname: %v", err)
+	}
+	return hostname, nil
+}
+
+// informerFactory starts the informers it has created.
+type informerFactory interface {
+	Start(stopCh <-chan struct{})
+}
+
+// runner is a controller run by kube-dag.
+type runner interface {
+	Run(workers int, stopCh <-chan struct{})
+	// Rebalance syncs the objects again after the replicas sharing them have changed.
+	Rebalance()
+}
+
+// newCronExecutionController returns the controller of the cron executions
+// in the namespace, all of them if namespace is empty, and the factories of
+// its informers to start.
+func newCronExecutionController(o *options.ExecutionOption, geneClient execclientset.Interface,
+	eventRecorder record.EventRecorder, namespace string, sharder controller.Sharder) (*controller.CronExecutionController, []informerFactory) {
+	cronInformer := execinformers.NewSharedInformerFactoryWithOptions(geneClient, o.ResyncPeriod,
+		execinformers.WithNamespace(namespace),
+		execinformers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = o.ExecutionSelector }))
+	// the executions created by a cron execution have the labels of its
+	// template, which may not match the execution selector.
+	execInformer := execinformers.NewSharedInformerFactoryWithOptions(geneClient, o.ResyncPeriod,
+		execinformers.WithNamespace(namespace))
+	parameter := &controller.CronExecutionParameters{
+		EventRecorder:         eventRecorder,
+		ExecutionClient:       geneClient.ExecutionV1alpha1(),
+		CronExecutionInformer: cronInformer.Execution().V1alpha1().CronExecutions(),
+		ExecutionInformer:     execInformer.Execution().V1alpha1().Executions(),
+		Sharder:               sharder,
+	}
+	return controller.NewCronExecutionController(parameter), []informerFactory{cronInformer, execInformer}
+}
+
+// newWorkflowRunController returns the controller of the workflow runs in
+// the namespace, all of them if namespace is empty, and the factories of its
+// informers to start.
+func newWorkflowRunController(o *options.ExecutionOption, geneClient execclientset.Interface, eventRecorder record.EventRecorder,
+	namespace string, tools map[string]parser.Tool, sharder controller.Sharder) (*controller.WorkflowRunController, []informerFactory) {
+	runInformer := execinformers.NewSharedInformerFactoryWithOptions(geneClient, o.ResyncPeriod,
+		execinformers.WithNamespace(namespace),
+		execinformers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = o.ExecutionSelector }))
+	// the templates are not labeled for the runs referring to them.
+	geneInformer := execinformers.NewSharedInformerFactoryWithOptions(geneClient, o.ResyncPeriod,
+		execinformers.WithNamespace(namespace))
+	parameter := &controller.WorkflowRunParameters{
+		EventRecorder:            eventRecorder,
+		ExecutionClient:          geneClient.ExecutionV1alpha1(),
+		WorkflowRunInformer:      runInformer.Execution().V1alpha1().WorkflowRuns(),
+		WorkflowTemplateInformer: geneInformer.Execution().V1alpha1().WorkflowTemplates(),
+		ExecutionInformer:        geneInformer.Execution().V1alpha1().Executions(),
+		Tools:                    tools,
+		Sharder:                  sharder,
+	}
+	if o.ClusterTools {
+		// the tools are cluster scoped, they are not limited to the namespace.
+		parameter.ToolInformer = geneInformer.Execution().V1alpha1().Tools()
+	}
+	return controller.NewWorkflowRunController(parameter), []informerFactory{runInformer, geneInformer}
+}
+
+// newExecutionController returns the controller of the executions in the
+// namespace, all of them if namespace is empty, and the factories of its
+// informers to start.
+func newExecutionController(o *options.ExecutionOption, kubeClient clientset.Interface, geneClient execclientset.Interface,
+	dynamicClient dynamic.Interface, eventRecorder record.EventRecorder, namespace string, admission *controller.Admission,
+	cacheStore jobcache.Store, sharder controller.Sharder) (*controller.ExecutionController, []informerFactory) {
+	sharedInformers := informers.NewSharedInformerFactoryWithOptions(kubeClient, o.ResyncPeriod,
+		informers.WithNamespace(namespace))
+	geneInformer := execinformers.NewSharedInformerFactoryWithOptions(geneClient, o.ResyncPeriod,
+		execinformers.WithNamespace(namespace),
+		execinformers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = o.ExecutionSelector }))
+	parameter := &controller.ControllerParameters{
+		EventRecorder:     eventRecorder,
+		KubeClient:        kubeClient,
+		ExecutionClient:   geneClient.ExecutionV1alpha1(),
+		JobInformer:       sharedInformers.Batch().V1().Jobs(),
+		ExecutionInformer: geneInformer.Execution().V1alpha1().Executions(),
+
+		CacheStore:          cacheStore,
+		Admission:           admission,
+		LaunchOrder:         controller.LaunchOrder(o.LaunchOrder),
+		StuckPodGracePeriod: o.StuckPodGracePeriod,
+		Sharder:             sharder,
+	}
+	if o.EnablePodTasks {
+		parameter.PodInformer = sharedInformers.Core().V1().Pods()
+	}
+	dynamicInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, o.ResyncPeriod, namespace, nil)
+	if o.EnableVolcano {
+		parameter.DynamicClient = dynamicClient
+		parameter.VolcanoJobInformer = dynamicInformer.ForResource(volcano.JobResource)
+	}
+	indexedJobInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, o.ResyncPeriod,
+		namespace, func(options *metav1.ListOptions) { options.LabelSelector = indexedjob.Label })
+	if o.EnableIndexedJobs {
+		parameter.DynamicClient = dynamicClient
+		parameter.IndexedJobInformer = indexedJobInformer.ForResource(indexedjob.JobResource)
+	}
+	jobPodInformer := informers.NewSharedInformerFactoryWithOptions(kubeClient, o.ResyncPeriod,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = controller.ExecutionUIDLabel }))
+	parameter.JobPodInformer = jobPodInformer.Core().V1().Pods()
+
+	factories := []informerFactory{sharedInformers, geneInformer, dynamicInformer, indexedJobInformer, jobPodInformer}
+	return controller.NewExecutionController(parameter), factories
+}
+
 func Run(o *options.ExecutionOption, stopCh <-chan struct{}) error {
 	if o.PrintVersion {
 		version := version.GetVersion()
 		fmt.Printf("  kube-dag Version: %s\n", version)
 		os.Exit(0)
 	}
-	kubeClient, leaderElectionClient, geneClient, apiextentionsClient, err := createClients(o)
+	if !controller.IsValidLaunchOrder(controller.LaunchOrder(o.LaunchOrder)) {
+		return fmt.Errorf("invalid launch order %q, must be one of %v", o.LaunchOrder, controller.LaunchOrders)
+	}
+	if _, err := labels.Parse(o.ExecutionSelector); err != nil {
+		return fmt.Errorf("invalid execution selector %q: %v", o.ExecutionSelector, err)
+	}
+	kubeClient, leaderElectionClient, geneClient, apiextentionsClient, dynamicClient, err := createClients(o)
 	if err != nil {
 		return err
 	}
 
-	// ensure execution resource has been created, if not, create it.
-	if err := installExecutionCRD(apiextentionsClient); err != nil {
-		return err
+	if len(o.ConversionWebhookAddress) != 0 {
+		if len(o.TLSCertFile) == 0 || len(o.TLSPrivateKeyFile) == 0 {
+			return fmt.Errorf("the conversion webhook requires --tls-cert-file and --tls-private-key-file")
+		}
+		// every replica serves the webhook, leading or not.
+		go serveConversionWebhook(o)
+	}
+
+	if o.InstallCRD {
+		conversion, err := conversionWebhook(o)
+		if err != nil {
+			return err
+		}
+		// ensure execution resource has been created, if not, create it.
+		if err := installExecutionCRD(apiextentionsClient, conversion); err != nil {
+			return err
+		}
+		if o.EnableCronExecutions {
+			if err := installCronExecutionCRD(apiextentionsClient); err != nil {
+				return err
+			}
+		}
+		if o.EnableWorkflowRuns {
+			if err := installWorkflowTemplateCRD(apiextentionsClient); err != nil {
+				return err
+			}
+			if err := installWorkflowRunCRD(apiextentionsClient); err != nil {
+				return err
+			}
+			if o.ClusterTools {
+				if err := installToolCRD(apiextentionsClient); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var tools map[string]parser.Tool
+	if o.EnableWorkflowRuns && len(o.ToolRepo) != 0 {
+		tools, err = parser.LoadTools(o.ToolRepo)
+		if err != nil {
+			return err
+		}
+	}
+
+	var membership *shard.Membership
+	var sharder controller.Sharder
+	if o.Sharding {
+		id, err := identity()
+		if err != nil {
+			return err
+		}
+		name, err := replicaName()
+		if err != nil {
+			return err
+		}
+		membership = shard.NewMembership(leaderElectionClient, o.LockObjectNamespace,
+			"kubegene-controller-"+name, id, o.ShardLeaseDuration)
+		sharder = membership
 	}
 
-	sharedInformers := informers.NewSharedInformerFactory(kubeClient, o.ResyncPeriod)
-	geneInformer := execinformers.NewSharedInformerFactory(geneClient, o.ResyncPeriod)
+	// the informers of a controller can only watch one namespace or all of
+	// them, so every watched namespace has its own controller.
+	namespaces := o.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
 	eventRecorder := createRecorder(kubeClient)
-	parameter := &controller.ControllerParameters{
-		EventRecorder:     eventRecorder,
-		KubeClient:        kubeClient,
-		ExecutionClient:   geneClient.ExecutionV1alpha1(),
-		JobInformer:       sharedInformers.Batch().V1().Jobs(),
-		ExecutionInformer: geneInformer.Execution().V1alpha1().Executions(),
+	// the controllers of the namespaces share the limits of running executions.
+	admission := controller.NewAdmission(o.MaxRunningExecutions, o.MaxRunningExecutionsPerNamespace)
+	cacheStore := jobcache.NewConfigMapStore(kubeClient)
+	var runners []runner
+	var factories []informerFactory
+	for _, namespace := range namespaces {
+		execCtrl, controllerFactories := newExecutionController(o, kubeClient, geneClient, dynamicClient,
+			eventRecorder, namespace, admission, cacheStore, sharder)
+		runners = append(runners, execCtrl)
+		factories = append(factories, controllerFactories...)
+		if o.EnableCronExecutions {
+			cronCtrl, cronFactories := newCronExecutionController(o, geneClient, eventRecorder, namespace, sharder)
+			runners = append(runners, cronCtrl)
+			factories = append(factories, cronFactories...)
+		}
+		if o.EnableWorkflowRuns {
+			runCtrl, runFactories := newWorkflowRunController(o, geneClient, eventRecorder, namespace, tools, sharder)
+			runners = append(runners, runCtrl)
+			factories = append(factories, runFactories...)
+		}
 	}
 
-	execCtrl := controller.NewExecutionController(parameter)
 	run := func(ctx context.Context) {
-		go sharedInformers.Start(stopCh)
-		go geneInformer.Start(stopCh)
-		execCtrl.Run(1, stopCh)
+		for _, factory := range factories {
+			go factory.Start(stopCh)
+		}
+		for _, r := range runners {
+			go r.Run(o.Workers, stopCh)
+		}
 		<-stopCh
 	}
 
+	if o.Sharding {
+		// join the shards before syncing, the executions are rebalanced
+		// every time a replica joins or leaves.
+		if err := membership.Sync(); err != nil {
+			return fmt.Errorf("join shards error: %v", err)
+		}
+		for _, r := range runners {
+			membership.AddHandler(r.Rebalance)
+		}
+		go membership.Run(stopCh)
+		run(context.TODO())
+		panic("unreachable")
+	}
+
 	if !o.LeaderElect {
 		run(context.TODO())
 		panic("unreachable")
 	}
 
-	hostname, err := os.Hostname()
+	id, err := identity()
 	if err != nil {
-		return fmt.Errorf("unable to get hostname: %v", err)
+		return err
 	}
-	// add a uniquifier so that two processes on the same host don't accidentally both become active
-	id := hostname + "_" + string(uuid.NewUUID())
 
 	rl, err := resourcelock.New(resourcelock.ConfigMapsResourceLock,
 		o.LockObjectNamespace,
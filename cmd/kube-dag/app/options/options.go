@@ -36,17 +36,78 @@ type ExecutionOption struct {
 	LockObjectNamespace string
 	ResyncPeriod        time.Duration
 	PrintVersion        bool
+	// EnableVolcano enables running the tasks of type VolcanoJob as Volcano Jobs.
+	EnableVolcano bool
+	// EnablePodTasks enables running the tasks of type Pod as bare Pods.
+	EnablePodTasks bool
+	// EnableIndexedJobs enables running the tasks of type IndexedJob as Indexed Jobs.
+	EnableIndexedJobs bool
+	// MaxRunningExecutions is the max number of running executions in the cluster, 0 means no limit.
+	MaxRunningExecutions int
+	// MaxRunningExecutionsPerNamespace is the max number of running executions in a namespace, 0 means no limit.
+	MaxRunningExecutionsPerNamespace int
+	// LaunchOrder orders the jobs ready to start at the same time, one of FIFO, Priority and CriticalPath.
+	LaunchOrder string
+	// StuckPodGracePeriod is how long a pod may be unschedulable or unable to
+	// start its containers before its vertex fails, 0 means never.
+	StuckPodGracePeriod time.Duration
+	// Workers is the number of workers syncing each queue of the controller.
+	Workers int
+	// Sharding runs every replica, each syncing a shard of the executions,
+	// instead of electing a leader syncing all of them.
+	Sharding bool
+	// ShardLeaseDuration is how long the shard of a replica which stops
+	// renewing its lease is kept before it moves to the other replicas.
+	ShardLeaseDuration time.Duration
+	// Namespaces are the namespaces whose executions are synced, all of them if empty.
+	Namespaces []string
+	// ExecutionSelector is the label selector of the executions synced, all of them if empty.
+	ExecutionSelector string
+	// InstallCRD creates the execution CRD if it does not exist, which
+	// requires cluster-wide permissions.
+	InstallCRD bool
+	// EnableCronExecutions enables creating the executions of the cron executions on their schedules.
+	EnableCronExecutions bool
+	// EnableWorkflowRuns enables expanding the workflow runs into executions
+	// with the workflows of their templates.
+	EnableWorkflowRuns bool
+	// ToolRepo is the directory of tool files, or the URL of a tool file, the
+	// jobs of the workflow templates refer to.
+	ToolRepo string
+	// ClusterTools resolves the tools of the workflow templates against the
+	// tools of the cluster before the tool repo.
+	ClusterTools bool
+	// ConversionWebhookAddress is the address the conversion webhook of the
+	// executions listens on, the v1beta1 version is only served with it.
+	ConversionWebhookAddress string
+	// TLSCertFile and TLSPrivateKeyFile are the serving certificate of the conversion webhook.
+	TLSCertFile       string
+	TLSPrivateKeyFile string
+	// ConversionWebhookService is the namespace/name of the service of the
+	// conversion webhook the installed CRD refers to.
+	ConversionWebhookService string
+	// ConversionWebhookCAFile is the CA bundle the API server verifies the
+	// serving certificate of the conversion webhook with.
+	ConversionWebhookCAFile string
 }
 
 func NewExecutionOption() *ExecutionOption {
 	// init default configuration
 	return &ExecutionOption{
-		KubeAPIQPS:          defaultKubeAPIQPS,
-		KubeAPIBurst:        defaultKubeAPIBurst,
-		LeaderElect:         true,
-		LockObjectNamespace: "kube-system",
-		ResyncPeriod:        60 * time.Second,
-		PrintVersion:        false,
+		KubeAPIQPS:           defaultKubeAPIQPS,
+		KubeAPIBurst:         defaultKubeAPIBurst,
+		LeaderElect:          true,
+		LockObjectNamespace:  "kube-system",
+		ResyncPeriod:         60 * time.Second,
+		PrintVersion:         false,
+		LaunchOrder:          "FIFO",
+		StuckPodGracePeriod:  10 * time.Minute,
+		Workers:              1,
+		ShardLeaseDuration:   15 * time.Second,
+		InstallCRD:           true,
+		EnableCronExecutions: true,
+		EnableWorkflowRuns:   true,
+		ClusterTools:         true,
 	}
 }
 
@@ -62,4 +123,26 @@ func (o *ExecutionOption) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.LeaderElect, "leader-elect", o.LeaderElect, "Start a leader election client and gain leadership before executing the main loop.")
 	fs.StringVar(&o.LockObjectNamespace, "lock-object-namespace", o.LockObjectNamespace, "The namespace of the lock object.")
 	fs.BoolVar(&o.PrintVersion, "version", o.PrintVersion, "Show version and quit")
+	fs.BoolVar(&o.EnableVolcano, "enable-volcano", o.EnableVolcano, "Run the tasks of type VolcanoJob as Volcano Jobs, requires Volcano installed in the cluster.")
+	fs.BoolVar(&o.EnablePodTasks, "enable-pod-tasks", o.EnablePodTasks, "Run the tasks of type Pod as bare Pods, requires watching all the pods.")
+	fs.BoolVar(&o.EnableIndexedJobs, "enable-indexed-jobs", o.EnableIndexedJobs, "Run the tasks of type IndexedJob as Indexed Jobs, requires Kubernetes 1.22 or later.")
+	fs.IntVar(&o.MaxRunningExecutions, "max-running-executions", o.MaxRunningExecutions, "The max number of executions running in the cluster, the others are queued by priority. 0 means no limit.")
+	fs.IntVar(&o.MaxRunningExecutionsPerNamespace, "max-running-executions-per-namespace", o.MaxRunningExecutionsPerNamespace, "The max number of executions running in a namespace, the others are queued by priority. 0 means no limit.")
+	fs.StringVar(&o.LaunchOrder, "launch-order", o.LaunchOrder, "The order to start the jobs ready at the same time, one of FIFO, Priority and CriticalPath.")
+	fs.DurationVar(&o.StuckPodGracePeriod, "stuck-pod-grace-period", o.StuckPodGracePeriod, "How long a pod may be unschedulable or fail to pull its image or create its containers before its vertex is marked Error. 0 means never.")
+	fs.IntVar(&o.Workers, "workers", o.Workers, "The number of workers syncing each queue of executions, jobs and pods.")
+	fs.BoolVar(&o.Sharding, "sharding", o.Sharding, "Run all the replicas, each syncing a shard of the executions, instead of electing a leader. Overrides --leader-elect.")
+	fs.DurationVar(&o.ShardLeaseDuration, "shard-lease-duration", o.ShardLeaseDuration, "How long the shard of a replica which stops renewing its lease is kept before it moves to the other replicas.")
+	fs.StringSliceVar(&o.Namespaces, "namespaces", o.Namespaces, "The namespaces whose executions are synced, separated by commas. All the namespaces if empty, which requires cluster-wide permissions.")
+	fs.StringVar(&o.ExecutionSelector, "execution-selector", o.ExecutionSelector, "The label selector of the executions synced. All the executions if empty.")
+	fs.BoolVar(&o.InstallCRD, "install-crd", o.InstallCRD, "Create the execution CRD if it does not exist. Disable it when kube-dag only has the permissions of its namespaces.")
+	fs.BoolVar(&o.EnableCronExecutions, "enable-cron-executions", o.EnableCronExecutions, "Create the executions of the cron executions on their schedules, requires the cron execution CRD.")
+	fs.BoolVar(&o.EnableWorkflowRuns, "enable-workflow-runs", o.EnableWorkflowRuns, "Expand the workflow runs into executions with the workflows of their templates, requires the workflow template and workflow run CRDs.")
+	fs.StringVar(&o.ToolRepo, "tool-repo", o.ToolRepo, "The directory or URL of the tool repository the workflow templates are resolved against. If it is a URL, it must point to a tool file.")
+	fs.BoolVar(&o.ClusterTools, "cluster-tools", o.ClusterTools, "Resolve the tools of the workflow templates against the tools of the cluster before the tool repository, requires the tool CRD and watching the tools cluster-wide.")
+	fs.StringVar(&o.ConversionWebhookAddress, "conversion-webhook-address", o.ConversionWebhookAddress, "The address the conversion webhook of the executions listens on, e.g. :8443. The v1beta1 version of the executions is only served with the webhook.")
+	fs.StringVar(&o.TLSCertFile, "tls-cert-file", o.TLSCertFile, "The file of the serving certificate of the conversion webhook.")
+	fs.StringVar(&o.TLSPrivateKeyFile, "tls-private-key-file", o.TLSPrivateKeyFile, "The file of the private key of the serving certificate of the conversion webhook.")
+	fs.StringVar(&o.ConversionWebhookService, "conversion-webhook-service", o.ConversionWebhookService, "The namespace/name of the service of the conversion webhook, set in the installed execution CRD.")
+	fs.StringVar(&o.ConversionWebhookCAFile, "conversion-webhook-ca-file", o.ConversionWebhookCAFile, "The CA bundle verifying the serving certificate of the conversion webhook, set in the installed execution CRD.")
 }
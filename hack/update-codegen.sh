@@ -23,6 +23,6 @@ KUBEGENE_ROOT=$(dirname ${BASH_SOURCE})/..
 # generate the code
 ${KUBEGENE_ROOT}/hack/generate-groups.sh "deepcopy,client,informer,lister" \
   kubegene.io/kubegene/pkg/client kubegene.io/kubegene/pkg/apis \
-  gene:v1alpha1 \
+  gene:v1alpha1,v1beta1 \
   --output-base "$(dirname ${BASH_SOURCE})/../../.." \
   --go-header-file ${KUBEGENE_ROOT}/hack/boilerplate.go.txt
@@ -19,4 +19,16 @@ package gene
 const (
 	GroupName       = "execution.kubegene.io"
 	ExecutionPlural = "executions"
+	ExecutionShort  = "exec"
+
+	CronExecutionPlural = "cronexecutions"
+	CronExecutionShort  = "cronexec"
+
+	WorkflowTemplatePlural = "workflowtemplates"
+	WorkflowTemplateShort  = "wftmpl"
+
+	WorkflowRunPlural = "workflowruns"
+	WorkflowRunShort  = "wfrun"
+
+	ToolPlural = "tools"
 )
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"encoding/json"
 	apiv1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -32,6 +33,10 @@ const (
 	VertexSucceeded VertexPhase = "Succeeded"
 	VertexFailed    VertexPhase = "Failed"
 	VertexError     VertexPhase = "Error"
+	VertexSkipped   VertexPhase = "Skipped"
+	// VertexQueued is only the phase of an execution, waiting for the
+	// running executions to leave room for it.
+	VertexQueued VertexPhase = "Queued"
 )
 
 // TaskType is the type of a job
@@ -41,6 +46,14 @@ type TaskType string
 const (
 	JobTaskType   TaskType = "Job"
 	SparkTaskType TaskType = "Spark"
+	// VolcanoJobTaskType runs the jobs of the task as Volcano Jobs.
+	VolcanoJobTaskType TaskType = "VolcanoJob"
+	// PodTaskType runs the jobs of the task as bare Pods, which are not
+	// restarted when the command fails.
+	PodTaskType TaskType = "Pod"
+	// IndexedJobTaskType runs all the jobs of the task as one Indexed Job,
+	// each pod of which runs the command of its completion index.
+	IndexedJobTaskType TaskType = "IndexedJob"
 )
 
 // VertexType is the type of a vertex
@@ -64,6 +77,18 @@ const (
 	DependTypeIterate DependType = "iterate"
 )
 
+// SkipPolicy describes what happens to the dependents of a task
+// whose condition is not satisfied.
+type SkipPolicy string
+
+const (
+	// SkipPolicyContinue runs the dependents of a skipped task as if
+	// the task had succeeded. This is the default.
+	SkipPolicyContinue SkipPolicy = "Continue"
+	// SkipPolicyCascade skips every task that depends on a skipped task.
+	SkipPolicyCascade SkipPolicy = "Cascade"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -112,6 +137,83 @@ type ExecutionSpec struct {
 	// Parallelism limits the max total parallel jobs that can execute at the same time in a workflow
 	// +optional
 	Parallelism *int64 `json:"parallelism,omitempty"`
+
+	// ParallelResources limits the total resources requested by the jobs
+	// running at the same time in a workflow, e.g. at most 256 cpus. A job
+	// is started once the running jobs have left enough room for it.
+	// Every task must request at most these resources, a job whose pod
+	// requests more through the containers added next to the task container
+	// starts once no other job runs. A zero quantity does not limit the resource.
+	// +optional
+	ParallelResources *ResourceRequirements `json:"parallelResources,omitempty"`
+
+	// Priority orders the executions waiting to run when the number of
+	// running executions is limited, the executions with a higher priority
+	// start first. Defaults to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// ArtifactRepository is where the artifacts passed between tasks are stored.
+	// Required if any task declares input or output artifacts.
+	// +optional
+	ArtifactRepository *ArtifactRepository `json:"artifactRepository,omitempty"`
+
+	// Outputs are the results of the workflow. Every path is checked
+	// to exist in the volumes of the tasks after all the tasks have
+	// finished, and the execution fails if any of them is missing.
+	// +optional
+	Outputs map[string]Output `json:"outputs,omitempty"`
+
+	// Cache enables call caching for the tasks of the workflow. A job whose
+	// identical work has already succeeded is marked as succeeded without
+	// running it again. Can be overridden by the cache set in the task.
+	// Does not apply to the tasks reading persistent data without input
+	// digests. Defaults to false.
+	// +optional
+	Cache *bool `json:"cache,omitempty"`
+
+	// Env is a list of environment variables set in all the task containers.
+	// Can be extended or overridden by the env specified in the task.
+	// +optional
+	Env []apiv1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom is a list of sources to populate environment variables in all
+	// the task containers. Can be overridden by the envFrom specified in the task.
+	// +optional
+	EnvFrom []apiv1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount to run all the task pods.
+	// Can be overridden by the serviceAccountName specified in the task.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets used to pull the images of the tasks.
+	// Can be overridden by the imagePullSecrets specified in the task.
+	// +optional
+	ImagePullSecrets []apiv1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImagePullPolicy of the images of the tasks.
+	// One of Always, Never, IfNotPresent. Defaults to IfNotPresent.
+	// Can be overridden by the imagePullPolicy specified in the task.
+	// +optional
+	ImagePullPolicy apiv1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// SecurityContext is the security options all the task containers run with.
+	// Can be overridden by the securityContext specified in the task.
+	// +optional
+	SecurityContext *apiv1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Volcano is the options of the tasks run as Volcano Jobs.
+	// Can be overridden by the volcano options specified in the task.
+	// +optional
+	Volcano *VolcanoOptions `json:"volcano,omitempty"`
+}
+
+// Output is a declared result of the workflow.
+type Output struct {
+	// Paths is a list of absolute paths of files or directories in the
+	// volumes mounted by the tasks.
+	Paths []string `json:"paths"`
 }
 
 // A match  operator is the set of operators that can be used in
@@ -154,7 +256,8 @@ type GenericCondition struct {
 	MatchRules    []MatchRule `json:"matchRules"`
 }
 
-// Condition in Task
+// Condition in Task, one of the arrays [bool], ["check_result", job, value]
+// and ["expression", expression].
 type Condition struct {
 	Condition interface{} `json:"condition,omitempty"`
 }
@@ -213,6 +316,33 @@ type Task struct {
 	// Overrides the parallelism set at the execution level (if any)
 	Parallelism *int64 `json:"parallelism,omitempty"`
 
+	// Priority orders the jobs ready to start when the parallelism of the
+	// execution does not let them all start, the jobs of the tasks with a
+	// higher priority start first. Defaults to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// EstimatedDuration is how long a job of the task is expected to run. It
+	// is used to start first the jobs on the longest remaining path of the
+	// workflow. Defaults to the duration of the jobs of the task in the
+	// executions which have run before.
+	// +optional
+	EstimatedDuration *metav1.Duration `json:"estimatedDuration,omitempty"`
+
+	// ChunkSize is the number of commands run one after another by each job
+	// of the task, so that short commands do not each pay for the start of a pod.
+	// All the commands of a job run, the exit status of each one is recorded in
+	// the status of the vertex, and only the failed ones run again when the job
+	// is retried. Only for the tasks of type Job and Spark. Defaults to 1.
+	// +optional
+	ChunkSize *int32 `json:"chunkSize,omitempty"`
+
+	// MemoryEscalation has a job killed for running out of memory start
+	// again with more memory. Only for the tasks of type Job and Spark
+	// which request memory.
+	// +optional
+	MemoryEscalation *MemoryEscalation `json:"memoryEscalation,omitempty"`
+
 	// Specifies the dependency by this task
 	// +optional
 	Dependents []Dependent `json:"dependents"`
@@ -225,6 +355,108 @@ type Task struct {
 	// The task will be executed only when any one of the rule of condition is  satisfied
 	// +optional
 	GenericCondition *GenericCondition `json:"genericCondition,omitempty"`
+
+	// SkipPolicy specifies how the tasks depending on this task are handled
+	// when this task is skipped because its condition is not satisfied.
+	// One of Continue, Cascade. Defaults to Continue.
+	// +optional
+	SkipPolicy SkipPolicy `json:"skipPolicy,omitempty"`
+
+	// InputArtifacts is a list of artifacts fetched from the artifact
+	// repository before the task runs.
+	// +optional
+	InputArtifacts []Artifact `json:"inputArtifacts,omitempty"`
+
+	// OutputArtifacts is a list of artifacts uploaded to the artifact
+	// repository after the task succeeds.
+	// +optional
+	OutputArtifacts []Artifact `json:"outputArtifacts,omitempty"`
+
+	// Cache enables call caching for the jobs of this task.
+	// Overrides the cache set at the execution level (if any).
+	// +optional
+	Cache *bool `json:"cache,omitempty"`
+
+	// InputDigests identify the content of the data the task reads which is
+	// not part of its spec, e.g. the digests of the files in its volumes or
+	// the generation of a dataset. They are part of the cache key, so that a
+	// job is run again once the data it reads changes. The cache set at the
+	// execution level only applies to the tasks reading volumes other than
+	// emptyDir and ephemeral ones if they set input digests.
+	// +optional
+	InputDigests []string `json:"inputDigests,omitempty"`
+
+	// Env is a list of environment variables set in the task container.
+	// Variables with the same name as the ones set at the execution level override them.
+	// +optional
+	Env []apiv1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom is a list of sources to populate environment variables in the task container.
+	// Overrides the envFrom set at the execution level (if any).
+	// +optional
+	EnvFrom []apiv1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount to run the task pods.
+	// Overrides the serviceAccountName set at the execution level (if any).
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets used to pull the image of the task.
+	// Overrides the imagePullSecrets set at the execution level (if any).
+	// +optional
+	ImagePullSecrets []apiv1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImagePullPolicy of the image of the task.
+	// One of Always, Never, IfNotPresent.
+	// Overrides the imagePullPolicy set at the execution level (if any).
+	// +optional
+	ImagePullPolicy apiv1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// SecurityContext is the security options the task container runs with.
+	// Overrides the securityContext set at the execution level (if any).
+	// +optional
+	SecurityContext *apiv1.SecurityContext `json:"securityContext,omitempty"`
+
+	// InitContainers run in order before the task container, e.g. to stage
+	// the data the task needs into the task volumes. The task volumes are
+	// mounted in the init containers which do not specify volumeMounts.
+	// +optional
+	InitContainers []apiv1.Container `json:"initContainers,omitempty"`
+
+	// Sidecars run next to the task container, e.g. a log shipper or a sync
+	// agent, and are stopped once the task command succeeds, or once it fails
+	// in a pod which does not restart the task container. The task volumes
+	// are mounted in the sidecars which do not specify volumeMounts.
+	// The command of a sidecar must be specified and its image must provide sh.
+	// +optional
+	Sidecars []apiv1.Container `json:"sidecars,omitempty"`
+
+	// Volcano is the options of the Volcano Jobs when the type of the task is VolcanoJob.
+	// The options which are not specified are taken from the execution.
+	// +optional
+	Volcano *VolcanoOptions `json:"volcano,omitempty"`
+}
+
+// VolcanoOptions are the scheduling options of the Volcano Jobs running a task.
+type VolcanoOptions struct {
+	// Queue the jobs are submitted to.
+	// +optional
+	Queue string `json:"queue,omitempty"`
+	// SchedulerName of the pods of the jobs.
+	// Defaults to volcano.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// PriorityClassName of the jobs and their pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Replicas is the number of pods running the command of each job.
+	// Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// MinAvailable is the number of pods which must be scheduled together.
+	// Defaults to replicas.
+	// +optional
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
 }
 
 // +k8s:openapi-gen=false
@@ -241,8 +473,33 @@ type ExecutionStatus struct {
 	// A human readable message indicating details about why the workflow is in this condition.
 	Message string `json:"message,omitempty"`
 
+	// Progress is the number of finished vertices out of the vertices of the workflow,
+	// in the form "finished/total".
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
 	// Vertices is a mapping between a vertex ID and the vertex's status.
 	Vertices map[string]VertexStatus `json:"vertices,omitempty"`
+
+	// Outputs is the verified outputs of the workflow.
+	// +optional
+	Outputs []OutputStatus `json:"outputs,omitempty"`
+}
+
+// OutputStatus describes a path of a workflow output found on completion.
+type OutputStatus struct {
+	// Name is the name of the output the path belongs to.
+	Name string `json:"name"`
+
+	// Path is the path of the file or directory.
+	Path string `json:"path"`
+
+	// Size is the total size in bytes of the file, or of the files in the directory.
+	Size int64 `json:"size"`
+
+	// Checksum is the sha256 checksum of the file. For a directory it is the
+	// sha256 checksum of the sorted list of the checksums of its files.
+	Checksum string `json:"checksum"`
 }
 
 // CommandsIter defines command for workflows job. If both Vars and Vars_iter are specified,
@@ -307,15 +564,88 @@ type VertexStatus struct {
 
 	// Children is a list of child vertex IDs
 	Children []string `json:"children,omitempty"`
+
+	// Commands is the exit status of every command of the job of the vertex,
+	// recorded when the job runs a chunk of more than one command.
+	// +optional
+	Commands []CommandStatus `json:"commands,omitempty"`
+
+	// Resources are the resources requested by the last attempt of the job
+	// of the vertex, recorded when its memory has been escalated.
+	// +optional
+	Resources apiv1.ResourceList `json:"resources,omitempty"`
+
+	// MemoryEscalations is the number of times the job of the vertex has
+	// started again with more memory.
+	// +optional
+	MemoryEscalations int32 `json:"memoryEscalations,omitempty"`
+
+	// Disruptions is the number of pods of the job of the vertex which have
+	// been evicted, preempted or lost with their node. They are retried
+	// without counting toward the backoff limit of the task.
+	// +optional
+	Disruptions int32 `json:"disruptions,omitempty"`
+}
+
+// MemoryEscalation describes how the memory of a job grows on every
+// attempt after the job is killed for running out of memory.
+type MemoryEscalation struct {
+	// Multiplier of the memory of the last attempt, greater than 1.
+	Multiplier float64 `json:"multiplier"`
+
+	// Max is the most memory an attempt can request. Once it is reached the
+	// job is restarted by the backoff limit of the job as usual.
+	Max resource.Quantity `json:"max"`
+}
+
+// CommandStatus is the exit status of a command of a job running a chunk of commands.
+type CommandStatus struct {
+	// Index is the index of the command in the commands of the task.
+	Index int32 `json:"index"`
+
+	// ExitCode is the exit code of the last run of the command.
+	ExitCode int32 `json:"exitCode"`
 }
 
 type Volume struct {
 	MountPath string       `json:"mountPath"`
 	MountFrom VolumeSource `json:"mountFrom"`
+	// ReadOnly mounts the volume read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
+// VolumeSource is the source of a volume.
+// Exactly one of its members must be specified.
 type VolumeSource struct {
-	Pvc string `json:"pvc"`
+	// Pvc is the name of a PersistentVolumeClaim shared by all the jobs.
+	// +optional
+	Pvc string `json:"pvc,omitempty"`
+	// EmptyDir is a scratch directory of the pod, optionally memory-backed.
+	// +optional
+	EmptyDir *apiv1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+	// HostPath is a directory of the node, e.g. a local NVMe disk.
+	// +optional
+	HostPath *apiv1.HostPathVolumeSource `json:"hostPath,omitempty"`
+	// ConfigMap populates the volume with the keys of a configmap.
+	// +optional
+	ConfigMap *apiv1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+	// Secret populates the volume with the keys of a secret.
+	// +optional
+	Secret *apiv1.SecretVolumeSource `json:"secret,omitempty"`
+	// NFS is a NFS share shared by all the jobs.
+	// +optional
+	NFS *apiv1.NFSVolumeSource `json:"nfs,omitempty"`
+	// Ephemeral is a PersistentVolumeClaim provisioned for every job,
+	// which is deleted together with the job.
+	// +optional
+	Ephemeral *EphemeralVolumeSource `json:"ephemeral,omitempty"`
+}
+
+// EphemeralVolumeSource is the template of the PersistentVolumeClaim
+// provisioned for every job.
+type EphemeralVolumeSource struct {
+	VolumeClaimTemplate apiv1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate"`
 }
 
 type ResourceRequirements struct {
@@ -335,6 +665,301 @@ type Dependent struct {
 	Type DependType `json:"type,omitempty"`
 }
 
+// Artifact is a directory passed between tasks through the artifact repository.
+type Artifact struct {
+	// Name of the artifact. Must be unique within the input or output artifacts of a task.
+	Name string `json:"name"`
+
+	// Path is the directory of the artifact in the task container.
+	Path string `json:"path"`
+
+	// From is the output artifact an input artifact is fetched from,
+	// in the form of <task>.<artifact>. The task must be one this task depends on.
+	// For a `whole` dependent whose task runs more than one job, the directory
+	// contains one subdirectory per job named after the job index.
+	// For an `iterate` dependent, the artifact of the job with the same index is fetched.
+	// Only used by input artifacts.
+	// +optional
+	From string `json:"from,omitempty"`
+}
+
+// ArtifactRepository describes where artifacts are stored.
+// Exactly one of Local, S3 must be specified.
+type ArtifactRepository struct {
+	// Local stores artifacts in a persistent volume claim.
+	// +optional
+	Local *LocalArtifactRepository `json:"local,omitempty"`
+
+	// S3 stores artifacts in an S3 compatible object storage such as MinIO.
+	// +optional
+	S3 *S3ArtifactRepository `json:"s3,omitempty"`
+
+	// Image used by the containers which fetch and upload artifacts.
+	// Defaults to an image suitable for the repository kind.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// LocalArtifactRepository stores artifacts in a persistent volume claim.
+type LocalArtifactRepository struct {
+	// Pvc is the name of the persistent volume claim.
+	Pvc string `json:"pvc"`
+
+	// SubPath is the directory in the volume artifacts are stored under.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// S3ArtifactRepository stores artifacts in an S3 compatible object storage.
+type S3ArtifactRepository struct {
+	// Endpoint is the host and optional port of the storage service.
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket artifacts are stored in.
+	Bucket string `json:"bucket"`
+
+	// KeyPrefix is prepended to the key of every artifact.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// Insecure connects to the endpoint over plain http.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// AccessKeySecret is the secret key holding the access key.
+	AccessKeySecret apiv1.SecretKeySelector `json:"accessKeySecret"`
+
+	// SecretKeySecret is the secret key holding the secret key.
+	SecretKeySecret apiv1.SecretKeySelector `json:"secretKeySecret"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronExecution creates executions on a cron schedule.
+type CronExecution struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec CronExecutionSpec `json:"spec,omitempty"`
+	// +optional
+	Status CronExecutionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronExecutionList is a collection of cron executions.
+type CronExecutionList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of cron executions.
+	Items []CronExecution `json:"items"`
+}
+
+// ConcurrencyPolicy tells how to treat the executions of a cron execution running at the same time.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows the executions to run at the same time.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the next execution if the last one is still running.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running executions before creating the next one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+type CronExecutionSpec struct {
+	// Schedule is the cron schedule of the executions, e.g. "0 2 * * *",
+	// or one of @yearly, @monthly, @weekly, @daily and @hourly.
+	Schedule string `json:"schedule"`
+
+	// StartingDeadlineSeconds is how late an execution may start after its
+	// scheduled time, the execution is skipped afterwards. No deadline if unset.
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy is one of Allow, Forbid and Replace. Defaults to Allow.
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend stops creating executions, the running ones are not affected.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// ExecutionTemplate is the template of the executions created.
+	ExecutionTemplate ExecutionTemplateSpec `json:"executionTemplate"`
+
+	// SuccessfulExecutionsHistoryLimit is the number of succeeded executions kept. Defaults to 3.
+	// +optional
+	SuccessfulExecutionsHistoryLimit *int32 `json:"successfulExecutionsHistoryLimit,omitempty"`
+
+	// FailedExecutionsHistoryLimit is the number of failed executions kept. Defaults to 1.
+	// +optional
+	FailedExecutionsHistoryLimit *int32 `json:"failedExecutionsHistoryLimit,omitempty"`
+}
+
+// ExecutionTemplateSpec describes the executions created by a cron execution.
+type ExecutionTemplateSpec struct {
+	// Labels and annotations of the executions.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the spec of the executions.
+	Spec ExecutionSpec `json:"spec"`
+}
+
+type CronExecutionStatus struct {
+	// Active are the executions running.
+	// +optional
+	Active []apiv1.ObjectReference `json:"active,omitempty"`
+
+	// LastScheduleTime is the scheduled time of the last execution created.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is the time the last succeeded execution finished at.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkflowTemplate is a genecontainer workflow stored in the cluster, the
+// workflow runs referring to it are expanded into executions by kube-dag.
+type WorkflowTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec WorkflowTemplateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkflowTemplateList is a collection of workflow templates.
+type WorkflowTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of workflow templates.
+	Items []WorkflowTemplate `json:"items"`
+}
+
+type WorkflowTemplateSpec struct {
+	// Workflow is the genecontainer workflow in YAML or JSON, as submitted
+	// by genectl. Its inputs are the inputs of the workflow runs, and its
+	// tools are resolved against the tool repository of kube-dag.
+	Workflow string `json:"workflow"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkflowRun runs a workflow template with input values.
+type WorkflowRun struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec WorkflowRunSpec `json:"spec,omitempty"`
+	// +optional
+	Status WorkflowRunStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkflowRunList is a collection of workflow runs.
+type WorkflowRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of workflow runs.
+	Items []WorkflowRun `json:"items"`
+}
+
+type WorkflowRunSpec struct {
+	// WorkflowTemplateRef is the name of the workflow template in the
+	// namespace of the workflow run.
+	WorkflowTemplateRef string `json:"workflowTemplateRef"`
+
+	// Inputs are the values of the inputs of the workflow, the defaults of
+	// the workflow are used for the others. The values of the namespace and
+	// executionName inputs are the ones of the workflow run.
+	// +optional
+	Inputs map[string]apiextensionsv1.JSON `json:"inputs,omitempty"`
+}
+
+type WorkflowRunStatus struct {
+	// Phase is the phase of the execution of the workflow run, Error if the
+	// workflow could not be expanded.
+	// +optional
+	Phase VertexPhase `json:"phase,omitempty"`
+
+	// Execution is the name of the execution the workflow run was expanded into.
+	// +optional
+	Execution string `json:"execution,omitempty"`
+
+	// Message tells why the workflow could not be expanded.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Tool is a gene sequencing container shared by the whole cluster. The jobs
+// of the workflows refer to it as name:version, and are resolved against the
+// tools of the cluster before the local tool repository of genectl.
+type Tool struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec ToolSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ToolList is a collection of tools.
+type ToolList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of tools.
+	Items []Tool `json:"items"`
+}
+
+type ToolSpec struct {
+	// Name is the name of the tool the jobs refer to, e.g. GATK.
+	Name string `json:"name"`
+
+	// Version is the version of the tool the jobs refer to, e.g. 4.0.1.
+	Version string `json:"version"`
+
+	// Image is the docker image of the tool.
+	Image string `json:"image"`
+
+	// Command is run by the jobs of the tool without commands.
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Type is the type of the tool, e.g. basic.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Description describes what the tool is used for.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
 // DeepCopyInto is an custom deepcopy function to deal with our use of the interface{} type
 func (i *CommandsIter) DeepCopyInto(out *CommandsIter) {
 
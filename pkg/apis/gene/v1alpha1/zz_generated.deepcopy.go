@@ -22,9 +22,69 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Artifact) DeepCopyInto(out *Artifact) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Artifact.
+func (in *Artifact) DeepCopy() *Artifact {
+	if in == nil {
+		return nil
+	}
+	out := new(Artifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactRepository) DeepCopyInto(out *ArtifactRepository) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalArtifactRepository)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3ArtifactRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactRepository.
+func (in *ArtifactRepository) DeepCopy() *ArtifactRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandStatus) DeepCopyInto(out *CommandStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandStatus.
+func (in *CommandStatus) DeepCopy() *CommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandsIter.
 func (in *CommandsIter) DeepCopy() *CommandsIter {
 	if in == nil {
@@ -45,6 +105,133 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecution) DeepCopyInto(out *CronExecution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecution.
+func (in *CronExecution) DeepCopy() *CronExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronExecution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionList) DeepCopyInto(out *CronExecutionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CronExecution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionList.
+func (in *CronExecutionList) DeepCopy() *CronExecutionList {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronExecutionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionSpec) DeepCopyInto(out *CronExecutionSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	in.ExecutionTemplate.DeepCopyInto(&out.ExecutionTemplate)
+	if in.SuccessfulExecutionsHistoryLimit != nil {
+		in, out := &in.SuccessfulExecutionsHistoryLimit, &out.SuccessfulExecutionsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedExecutionsHistoryLimit != nil {
+		in, out := &in.FailedExecutionsHistoryLimit, &out.FailedExecutionsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionSpec.
+func (in *CronExecutionSpec) DeepCopy() *CronExecutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronExecutionStatus) DeepCopyInto(out *CronExecutionStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronExecutionStatus.
+func (in *CronExecutionStatus) DeepCopy() *CronExecutionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CronExecutionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dependent) DeepCopyInto(out *Dependent) {
 	*out = *in
@@ -61,6 +248,23 @@ func (in *Dependent) DeepCopy() *Dependent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralVolumeSource) DeepCopyInto(out *EphemeralVolumeSource) {
+	*out = *in
+	in.VolumeClaimTemplate.DeepCopyInto(&out.VolumeClaimTemplate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralVolumeSource.
+func (in *EphemeralVolumeSource) DeepCopy() *EphemeralVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Execution) DeepCopyInto(out *Execution) {
 	*out = *in
@@ -156,6 +360,62 @@ func (in *ExecutionSpec) DeepCopyInto(out *ExecutionSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ParallelResources != nil {
+		in, out := &in.ParallelResources, &out.ParallelResources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ArtifactRepository != nil {
+		in, out := &in.ArtifactRepository, &out.ArtifactRepository
+		*out = new(ArtifactRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]Output, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volcano != nil {
+		in, out := &in.Volcano, &out.Volcano
+		*out = new(VolcanoOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -181,6 +441,11 @@ func (in *ExecutionStatus) DeepCopyInto(out *ExecutionStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]OutputStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -194,6 +459,24 @@ func (in *ExecutionStatus) DeepCopy() *ExecutionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionTemplateSpec) DeepCopyInto(out *ExecutionTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionTemplateSpec.
+func (in *ExecutionTemplateSpec) DeepCopy() *ExecutionTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericCondition) DeepCopyInto(out *GenericCondition) {
 	*out = *in
@@ -217,6 +500,22 @@ func (in *GenericCondition) DeepCopy() *GenericCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalArtifactRepository) DeepCopyInto(out *LocalArtifactRepository) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalArtifactRepository.
+func (in *LocalArtifactRepository) DeepCopy() *LocalArtifactRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalArtifactRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchRule) DeepCopyInto(out *MatchRule) {
 	*out = *in
@@ -238,6 +537,60 @@ func (in *MatchRule) DeepCopy() *MatchRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryEscalation) DeepCopyInto(out *MemoryEscalation) {
+	*out = *in
+	out.Max = in.Max.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryEscalation.
+func (in *MemoryEscalation) DeepCopy() *MemoryEscalation {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryEscalation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Output) DeepCopyInto(out *Output) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
+func (in *Output) DeepCopy() *Output {
+	if in == nil {
+		return nil
+	}
+	out := new(Output)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
+func (in *OutputStatus) DeepCopy() *OutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
 	*out = *in
@@ -256,6 +609,24 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ArtifactRepository) DeepCopyInto(out *S3ArtifactRepository) {
+	*out = *in
+	in.AccessKeySecret.DeepCopyInto(&out.AccessKeySecret)
+	in.SecretKeySecret.DeepCopyInto(&out.SecretKeySecret)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ArtifactRepository.
+func (in *S3ArtifactRepository) DeepCopy() *S3ArtifactRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ArtifactRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Task) DeepCopyInto(out *Task) {
 	*out = *in
@@ -291,7 +662,7 @@ func (in *Task) DeepCopyInto(out *Task) {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make(map[string]Volume, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
@@ -310,11 +681,31 @@ func (in *Task) DeepCopyInto(out *Task) {
 		*out = new(int64)
 		**out = **in
 	}
-	if in.Dependents != nil {
-		in, out := &in.Dependents, &out.Dependents
-		*out = make([]Dependent, len(*in))
-		copy(*out, *in)
-	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EstimatedDuration != nil {
+		in, out := &in.EstimatedDuration, &out.EstimatedDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ChunkSize != nil {
+		in, out := &in.ChunkSize, &out.ChunkSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryEscalation != nil {
+		in, out := &in.MemoryEscalation, &out.MemoryEscalation
+		*out = new(MemoryEscalation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dependents != nil {
+		in, out := &in.Dependents, &out.Dependents
+		*out = make([]Dependent, len(*in))
+		copy(*out, *in)
+	}
 	if in.Condition != nil {
 		in, out := &in.Condition, &out.Condition
 		*out = (*in).DeepCopy()
@@ -324,6 +715,69 @@ func (in *Task) DeepCopyInto(out *Task) {
 		*out = new(GenericCondition)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InputArtifacts != nil {
+		in, out := &in.InputArtifacts, &out.InputArtifacts
+		*out = make([]Artifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutputArtifacts != nil {
+		in, out := &in.OutputArtifacts, &out.OutputArtifacts
+		*out = make([]Artifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InputDigests != nil {
+		in, out := &in.InputDigests, &out.InputDigests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volcano != nil {
+		in, out := &in.Volcano, &out.Volcano
+		*out = new(VolcanoOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -337,6 +791,82 @@ func (in *Task) DeepCopy() *Task {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tool) DeepCopyInto(out *Tool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tool.
+func (in *Tool) DeepCopy() *Tool {
+	if in == nil {
+		return nil
+	}
+	out := new(Tool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Tool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolList) DeepCopyInto(out *ToolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolList.
+func (in *ToolList) DeepCopy() *ToolList {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSpec) DeepCopyInto(out *ToolSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSpec.
+func (in *ToolSpec) DeepCopy() *ToolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VertexStatus) DeepCopyInto(out *VertexStatus) {
 	*out = *in
@@ -347,6 +877,18 @@ func (in *VertexStatus) DeepCopyInto(out *VertexStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]CommandStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -360,10 +902,36 @@ func (in *VertexStatus) DeepCopy() *VertexStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolcanoOptions) DeepCopyInto(out *VolcanoOptions) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolcanoOptions.
+func (in *VolcanoOptions) DeepCopy() *VolcanoOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(VolcanoOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
-	out.MountFrom = in.MountFrom
+	in.MountFrom.DeepCopyInto(&out.MountFrom)
 	return
 }
 
@@ -380,6 +948,36 @@ func (in *Volume) DeepCopy() *Volume {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSource) DeepCopyInto(out *VolumeSource) {
 	*out = *in
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(v1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPath != nil {
+		in, out := &in.HostPath, &out.HostPath
+		*out = new(v1.HostPathVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.ConfigMapVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(v1.SecretVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(v1.NFSVolumeSource)
+		**out = **in
+	}
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(EphemeralVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -392,3 +990,179 @@ func (in *VolumeSource) DeepCopy() *VolumeSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowRun) DeepCopyInto(out *WorkflowRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowRun.
+func (in *WorkflowRun) DeepCopy() *WorkflowRun {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowRunList) DeepCopyInto(out *WorkflowRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkflowRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowRunList.
+func (in *WorkflowRunList) DeepCopy() *WorkflowRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowRunSpec) DeepCopyInto(out *WorkflowRunSpec) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowRunSpec.
+func (in *WorkflowRunSpec) DeepCopy() *WorkflowRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowRunStatus) DeepCopyInto(out *WorkflowRunStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowRunStatus.
+func (in *WorkflowRunStatus) DeepCopy() *WorkflowRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowTemplate) DeepCopyInto(out *WorkflowTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowTemplate.
+func (in *WorkflowTemplate) DeepCopy() *WorkflowTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowTemplateList) DeepCopyInto(out *WorkflowTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkflowTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowTemplateList.
+func (in *WorkflowTemplateList) DeepCopy() *WorkflowTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowTemplateSpec) DeepCopyInto(out *WorkflowTemplateSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowTemplateSpec.
+func (in *WorkflowTemplateSpec) DeepCopy() *WorkflowTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+// The functions of the positional arrays of v1alpha1.
+const (
+	getResultFunc   = "get_result"
+	checkResultFunc = "check_result"
+	expressionFunc  = "expression"
+)
+
+// ConvertFromV1alpha1 converts a v1alpha1 execution into out. The positional
+// arrays of the conditions and varsIter of its tasks are converted to the typed
+// fields, the other fields are the same in both versions.
+func ConvertFromV1alpha1(in *v1alpha1.Execution, out *Execution) error {
+	conditions := make([]*Condition, len(in.Spec.Tasks))
+	varsIters := make([][]VarIter, len(in.Spec.Tasks))
+	for i, task := range in.Spec.Tasks {
+		var err error
+		if conditions[i], err = convertConditionFromV1alpha1(task.Condition); err != nil {
+			return fmt.Errorf("task %s: %v", task.Name, err)
+		}
+		if task.CommandsIter == nil {
+			continue
+		}
+		if varsIters[i], err = convertVarsIterFromV1alpha1(task.CommandsIter.VarsIter); err != nil {
+			return fmt.Errorf("task %s: %v", task.Name, err)
+		}
+	}
+
+	exec := in.DeepCopy()
+	for i := range exec.Spec.Tasks {
+		exec.Spec.Tasks[i].Condition = nil
+		if exec.Spec.Tasks[i].CommandsIter != nil {
+			exec.Spec.Tasks[i].CommandsIter.VarsIter = nil
+		}
+	}
+	if err := convertJSON(exec, out); err != nil {
+		return err
+	}
+	for i := range out.Spec.Tasks {
+		out.Spec.Tasks[i].Condition = conditions[i]
+		if out.Spec.Tasks[i].CommandsIter != nil {
+			out.Spec.Tasks[i].CommandsIter.VarsIter = varsIters[i]
+		}
+	}
+	out.APIVersion = SchemeGroupVersion.String()
+	return nil
+}
+
+// ConvertToV1alpha1 converts a v1beta1 execution into out, the reverse of ConvertFromV1alpha1.
+func ConvertToV1alpha1(in *Execution, out *v1alpha1.Execution) error {
+	conditions := make([]*v1alpha1.Condition, len(in.Spec.Tasks))
+	varsIters := make([][]interface{}, len(in.Spec.Tasks))
+	for i, task := range in.Spec.Tasks {
+		var err error
+		if conditions[i], err = convertConditionToV1alpha1(task.Condition); err != nil {
+			return fmt.Errorf("task %s: %v", task.Name, err)
+		}
+		if task.CommandsIter == nil {
+			continue
+		}
+		if varsIters[i], err = convertVarsIterToV1alpha1(task.CommandsIter.VarsIter); err != nil {
+			return fmt.Errorf("task %s: %v", task.Name, err)
+		}
+	}
+
+	exec := in.DeepCopy()
+	for i := range exec.Spec.Tasks {
+		exec.Spec.Tasks[i].Condition = nil
+		if exec.Spec.Tasks[i].CommandsIter != nil {
+			exec.Spec.Tasks[i].CommandsIter.VarsIter = nil
+		}
+	}
+	if err := convertJSON(exec, out); err != nil {
+		return err
+	}
+	for i := range out.Spec.Tasks {
+		out.Spec.Tasks[i].Condition = conditions[i]
+		if out.Spec.Tasks[i].CommandsIter != nil {
+			out.Spec.Tasks[i].CommandsIter.VarsIter = varsIters[i]
+		}
+	}
+	out.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	return nil
+}
+
+// convertJSON converts in into out through their json encoding.
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func convertConditionFromV1alpha1(condition *v1alpha1.Condition) (*Condition, error) {
+	if condition == nil {
+		return nil, nil
+	}
+	if condition.Condition == nil {
+		return &Condition{}, nil
+	}
+	v, ok := condition.Condition.([]interface{})
+	if !ok || len(v) == 0 {
+		return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+	}
+	if b, ok := v[0].(bool); ok && len(v) == 1 {
+		return &Condition{Bool: &b}, nil
+	}
+	switch v[0] {
+	case checkResultFunc:
+		if len(v) != 3 {
+			return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+		}
+		job, ok1 := v[1].(string)
+		value, ok2 := v[2].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+		}
+		return &Condition{CheckResult: &CheckResult{Job: job, Value: value}}, nil
+	case expressionFunc:
+		if len(v) != 2 {
+			return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+		}
+		expression, ok := v[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+		}
+		return &Condition{Expression: expression}, nil
+	}
+	return nil, fmt.Errorf("invalid condition %v", condition.Condition)
+}
+
+func convertConditionToV1alpha1(condition *Condition) (*v1alpha1.Condition, error) {
+	if condition == nil {
+		return nil, nil
+	}
+	switch {
+	case condition.CheckResult != nil:
+		return &v1alpha1.Condition{
+			Condition: []interface{}{checkResultFunc, condition.CheckResult.Job, condition.CheckResult.Value},
+		}, nil
+	case condition.Bool != nil:
+		return &v1alpha1.Condition{Condition: []interface{}{*condition.Bool}}, nil
+	case len(condition.Expression) != 0:
+		return &v1alpha1.Condition{Condition: []interface{}{expressionFunc, condition.Expression}}, nil
+	}
+	return &v1alpha1.Condition{}, nil
+}
+
+func convertVarsIterFromV1alpha1(varsIter []interface{}) ([]VarIter, error) {
+	if varsIter == nil {
+		return nil, nil
+	}
+	out := make([]VarIter, 0, len(varsIter))
+	for _, vars := range varsIter {
+		v, ok := vars.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid varsIter %v", vars)
+		}
+		if len(v) == 3 && v[0] == getResultFunc {
+			job, ok1 := v[1].(string)
+			separator, ok2 := v[2].(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("invalid varsIter %v", vars)
+			}
+			out = append(out, VarIter{Result: &ResultRef{Job: job, Separator: separator}})
+			continue
+		}
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			switch value := value.(type) {
+			case string:
+				values = append(values, value)
+			case float64:
+				values = append(values, strconv.FormatFloat(value, 'f', -1, 64))
+			case int, int32, int64, bool:
+				values = append(values, fmt.Sprint(value))
+			default:
+				return nil, fmt.Errorf("invalid value %v of varsIter %v", value, vars)
+			}
+		}
+		out = append(out, VarIter{Values: values})
+	}
+	return out, nil
+}
+
+func convertVarsIterToV1alpha1(varsIter []VarIter) ([]interface{}, error) {
+	if varsIter == nil {
+		return nil, nil
+	}
+	out := make([]interface{}, 0, len(varsIter))
+	for _, vars := range varsIter {
+		if vars.Result != nil {
+			out = append(out, []interface{}{getResultFunc, vars.Result.Job, vars.Result.Separator})
+			continue
+		}
+		if len(vars.Values) == 0 {
+			return nil, fmt.Errorf("varsIter has neither values nor result")
+		}
+		values := make([]interface{}, 0, len(vars.Values))
+		for _, value := range vars.Values {
+			values = append(values, value)
+		}
+		out = append(out, values)
+	}
+	return out, nil
+}
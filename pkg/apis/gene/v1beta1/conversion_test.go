@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+const v1alpha1Execution = `{
+	"apiVersion": "execution.kubegene.io/v1alpha1",
+	"kind": "Execution",
+	"metadata": {"name": "exec", "namespace": "default"},
+	"spec": {
+		"tasks": [
+			{"name": "a", "type": "Job", "image": "busybox", "commandSet": ["echo 1 2"]},
+			{
+				"name": "b",
+				"type": "Job",
+				"image": "busybox",
+				"condition": {"condition": ["check_result", "a", "1 2"]},
+				"commandsIter": {
+					"command": "echo ${1} ${2}",
+					"varsIter": [["sample1", "sample2"], [0, 1.5], ["get_result", "exec.a.", " "]]
+				},
+				"dependents": [{"target": "a"}]
+			},
+			{"name": "c", "type": "Job", "image": "busybox", "condition": {"condition": [true]}},
+			{"name": "d", "type": "Job", "image": "busybox", "condition": {"condition": ["expression", "number(result(\"a\")) > 30"]}}
+		]
+	}
+}`
+
+func TestConvertFromV1alpha1(t *testing.T) {
+	in := &v1alpha1.Execution{}
+	if err := json.Unmarshal([]byte(v1alpha1Execution), in); err != nil {
+		t.Fatalf("unmarshal execution error: %v", err)
+	}
+
+	out := &Execution{}
+	if err := ConvertFromV1alpha1(in, out); err != nil {
+		t.Fatalf("convert error: %v", err)
+	}
+	if out.APIVersion != SchemeGroupVersion.String() || out.Name != "exec" || len(out.Spec.Tasks) != 4 {
+		t.Fatalf("unexpected execution %#v", out)
+	}
+
+	b := out.Spec.Tasks[1]
+	expectCondition := &Condition{CheckResult: &CheckResult{Job: "a", Value: "1 2"}}
+	if !reflect.DeepEqual(b.Condition, expectCondition) {
+		t.Errorf("expect condition %#v, but got %#v", expectCondition, b.Condition)
+	}
+	expectVarsIter := []VarIter{
+		{Values: []string{"sample1", "sample2"}},
+		{Values: []string{"0", "1.5"}},
+		{Result: &ResultRef{Job: "exec.a.", Separator: " "}},
+	}
+	if !reflect.DeepEqual(b.CommandsIter.VarsIter, expectVarsIter) {
+		t.Errorf("expect varsIter %#v, but got %#v", expectVarsIter, b.CommandsIter.VarsIter)
+	}
+	if c := out.Spec.Tasks[2].Condition; c == nil || c.Bool == nil || !*c.Bool {
+		t.Errorf("expect bool condition true, but got %#v", c)
+	}
+	if d := out.Spec.Tasks[3].Condition; d == nil || d.Expression != `number(result("a")) > 30` {
+		t.Errorf("expect expression condition, but got %#v", d)
+	}
+
+	// converting back only turns the numbers into strings.
+	back := &v1alpha1.Execution{}
+	if err := ConvertToV1alpha1(out, back); err != nil {
+		t.Fatalf("convert back error: %v", err)
+	}
+	in.Spec.Tasks[1].CommandsIter.VarsIter[1] = []interface{}{"0", "1.5"}
+	expect, _ := json.Marshal(in)
+	got, _ := json.Marshal(back)
+	if string(expect) != string(got) {
+		t.Errorf("expect %s, but got %s", expect, got)
+	}
+}
+
+func TestConvertFromV1alpha1Invalid(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Condition interface{}
+		VarsIter  []interface{}
+	}{
+		{
+			Name:      "unknown condition function",
+			Condition: []interface{}{"unknown", "a", "b"},
+		},
+		{
+			Name:      "check_result without value",
+			Condition: []interface{}{"check_result", "a"},
+		},
+		{
+			Name:      "condition not an array",
+			Condition: "check_result(a, b)",
+		},
+		{
+			Name:     "varsIter not an array",
+			VarsIter: []interface{}{"sample1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		task := v1alpha1.Task{Name: "a"}
+		if testCase.Condition != nil {
+			task.Condition = &v1alpha1.Condition{Condition: testCase.Condition}
+		}
+		if testCase.VarsIter != nil {
+			task.CommandsIter = &v1alpha1.CommandsIter{Command: "echo ${1}", VarsIter: testCase.VarsIter}
+		}
+		in := &v1alpha1.Execution{Spec: v1alpha1.ExecutionSpec{Tasks: []v1alpha1.Task{task}}}
+		if err := ConvertFromV1alpha1(in, &Execution{}); err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+	}
+}
@@ -0,0 +1,772 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VertexPhase is a label for the condition of a node at the current time.
+type VertexPhase string
+
+// Vertex status in the execution.
+const (
+	VertexRunning   VertexPhase = "Running"
+	VertexSucceeded VertexPhase = "Succeeded"
+	VertexFailed    VertexPhase = "Failed"
+	VertexError     VertexPhase = "Error"
+	VertexSkipped   VertexPhase = "Skipped"
+	// VertexQueued is only the phase of an execution, waiting for the
+	// running executions to leave room for it.
+	VertexQueued VertexPhase = "Queued"
+)
+
+// TaskType is the type of a job
+type TaskType string
+
+// Possible Task types
+const (
+	JobTaskType   TaskType = "Job"
+	SparkTaskType TaskType = "Spark"
+	// VolcanoJobTaskType runs the jobs of the task as Volcano Jobs.
+	VolcanoJobTaskType TaskType = "VolcanoJob"
+	// PodTaskType runs the jobs of the task as bare Pods, which are not
+	// restarted when the command fails.
+	PodTaskType TaskType = "Pod"
+	// IndexedJobTaskType runs all the jobs of the task as one Indexed Job,
+	// each pod of which runs the command of its completion index.
+	IndexedJobTaskType TaskType = "IndexedJob"
+)
+
+// VertexType is the type of a vertex
+type VertexType string
+
+// DAG vertex types
+const (
+	DAGVertexType        VertexType = "DAG"
+	JobVertexType        VertexType = "Job"
+	JobGroupVertexType   VertexType = "JobGroup"
+	SparkVertexType      VertexType = "Spark"
+	SparkGroupVertexType VertexType = "SparkGroup"
+)
+
+// DependType is the type of depend
+type DependType string
+
+// DependType
+const (
+	DependTypeWhole   DependType = "whole"
+	DependTypeIterate DependType = "iterate"
+)
+
+// SkipPolicy describes what happens to the dependents of a task
+// whose condition is not satisfied.
+type SkipPolicy string
+
+const (
+	// SkipPolicyContinue runs the dependents of a skipped task as if
+	// the task had succeeded. This is the default.
+	SkipPolicyContinue SkipPolicy = "Continue"
+	// SkipPolicyCascade skips every task that depends on a skipped task.
+	SkipPolicyCascade SkipPolicy = "Cascade"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Execution is the definition of kubegene workflow.
+type Execution struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec ExecutionSpec `json:"spec,omitempty"`
+	// +optional
+	Status ExecutionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExecutionList is a collection of executions.
+type ExecutionList struct {
+	metav1.TypeMeta `json:",inline" `
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of executions.
+	Items []Execution `json:"items"`
+}
+
+type ExecutionSpec struct {
+	// Tasks is a list of Tasks used in a workflow
+	Tasks []Task `json:"tasks"`
+
+	// NodeSelector is a selector which will result in all pods of the workflow
+	// to be scheduled on the selected node(s). This is able to be overridden by
+	// a nodeSelector specified in the job.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity sets the scheduling constraints for all pods in the workflow.
+	// Can be overridden by an affinity specified in the Job
+	// +optional
+	Affinity *apiv1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations to apply to workflow pods.
+	// +optional
+	Tolerations []apiv1.Toleration `json:"tolerations,omitempty"`
+
+	// Parallelism limits the max total parallel jobs that can execute at the same time in a workflow
+	// +optional
+	Parallelism *int64 `json:"parallelism,omitempty"`
+
+	// ParallelResources limits the total resources requested by the jobs
+	// running at the same time in a workflow, e.g. at most 256 cpus. A job
+	// is started once the running jobs have left enough room for it.
+	// Every task must request at most these resources, a job whose pod
+	// requests more through the containers added next to the task container
+	// starts once no other job runs. A zero quantity does not limit the resource.
+	// +optional
+	ParallelResources *ResourceRequirements `json:"parallelResources,omitempty"`
+
+	// Priority orders the executions waiting to run when the number of
+	// running executions is limited, the executions with a higher priority
+	// start first. Defaults to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// ArtifactRepository is where the artifacts passed between tasks are stored.
+	// Required if any task declares input or output artifacts.
+	// +optional
+	ArtifactRepository *ArtifactRepository `json:"artifactRepository,omitempty"`
+
+	// Outputs are the results of the workflow. Every path is checked
+	// to exist in the volumes of the tasks after all the tasks have
+	// finished, and the execution fails if any of them is missing.
+	// +optional
+	Outputs map[string]Output `json:"outputs,omitempty"`
+
+	// Cache enables call caching for the tasks of the workflow. A job whose
+	// identical work has already succeeded is marked as succeeded without
+	// running it again. Can be overridden by the cache set in the task.
+	// Does not apply to the tasks reading persistent data without input
+	// digests. Defaults to false.
+	// +optional
+	Cache *bool `json:"cache,omitempty"`
+
+	// Env is a list of environment variables set in all the task containers.
+	// Can be extended or overridden by the env specified in the task.
+	// +optional
+	Env []apiv1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom is a list of sources to populate environment variables in all
+	// the task containers. Can be overridden by the envFrom specified in the task.
+	// +optional
+	EnvFrom []apiv1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount to run all the task pods.
+	// Can be overridden by the serviceAccountName specified in the task.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets used to pull the images of the tasks.
+	// Can be overridden by the imagePullSecrets specified in the task.
+	// +optional
+	ImagePullSecrets []apiv1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImagePullPolicy of the images of the tasks.
+	// One of Always, Never, IfNotPresent. Defaults to IfNotPresent.
+	// Can be overridden by the imagePullPolicy specified in the task.
+	// +optional
+	ImagePullPolicy apiv1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// SecurityContext is the security options all the task containers run with.
+	// Can be overridden by the securityContext specified in the task.
+	// +optional
+	SecurityContext *apiv1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Volcano is the options of the tasks run as Volcano Jobs.
+	// Can be overridden by the volcano options specified in the task.
+	// +optional
+	Volcano *VolcanoOptions `json:"volcano,omitempty"`
+}
+
+// Output is a declared result of the workflow.
+type Output struct {
+	// Paths is a list of absolute paths of files or directories in the
+	// volumes mounted by the tasks.
+	Paths []string `json:"paths"`
+}
+
+// A match  operator is the set of operators that can be used in
+// a MatchRule.
+type MatchOperator string
+
+const (
+	MatchOperatorOpIn           MatchOperator = "In"
+	MatchOperatorOpNotIn        MatchOperator = "NotIn"
+	MatchOperatorOpExists       MatchOperator = "Exists"
+	MatchOperatorOpDoesNotExist MatchOperator = "DoesNotExist"
+	MatchOperatorOpGt           MatchOperator = "Gt"
+	MatchOperatorOpLt           MatchOperator = "Lt"
+	MatchOperatorOpEqual        MatchOperator = "="
+	MatchOperatorOpNotEqual     MatchOperator = "!="
+	MatchOperatorOpDoubleEqual  MatchOperator = "=="
+)
+
+// A matching rules is a requirement that contains values, a key, and an operator
+// that relates the key and values.
+type MatchRule struct {
+	// The key that the requirement applies to.
+	Key string `json:"key"`
+	// Represents a key's relationship to a set of values.
+	// Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.
+	Operator MatchOperator `json:"operator"`
+	// An array of string values. If the operator is In or NotIn,
+	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
+	// the values array must be empty. If the operator is Gt or Lt, the values
+	// array must have a single element, which will be interpreted as an integer.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// generic Conditional dynamic handling match rules are ORed.
+type GenericCondition struct {
+	// when we use generic condition this DependJobName should be same as jobName in Depends
+	// and Depends should have only one JobName
+	DependJobName string      `json:"dependJobName"`
+	MatchRules    []MatchRule `json:"matchRules"`
+}
+
+// Condition decides whether a task runs once the job it depends on has finished.
+// Exactly one of its fields is set.
+type Condition struct {
+	// CheckResult runs the task if the result of a job equals a value.
+	// +optional
+	CheckResult *CheckResult `json:"checkResult,omitempty"`
+
+	// Bool runs the task if true.
+	// +optional
+	Bool *bool `json:"bool,omitempty"`
+
+	// Expression runs the task if the expression evaluates to true. It compares
+	// numbers and strings with ==, !=, <, <=, > and >=, and combines the
+	// comparisons with &&, || and !. result("job") is the result of a job the
+	// task depends on as a whole and number(s) the number in the string s,
+	// e.g. "number(result(\"depth\")) > 30 && result(\"ref\") == \"hg38\"".
+	// +optional
+	Expression string `json:"expression,omitempty"`
+}
+
+// CheckResult compares the result of a job with a value.
+type CheckResult struct {
+	// Job is the name of the job whose result is checked.
+	Job string `json:"job"`
+
+	// Value is the expected result.
+	Value string `json:"value"`
+}
+
+// ResultRef refers to the result of a job, the standard output of its command.
+type ResultRef struct {
+	// Job is the name of the job whose result is referred to.
+	Job string `json:"job"`
+
+	// Separator splits the result into a list of values, the whole result
+	// is a single value if empty.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+}
+
+// Task is a unit of execution in an Execution
+type Task struct {
+	// Name is the name of the task
+	Name string `json:"name"`
+
+	// Type is the type of the task
+	Type TaskType `json:"type"`
+
+	// NodeSelector is a selector to schedule this step of the workflow to be
+	// run on the selected node(s). Overrides the selector set at the execution level.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity sets the pod's scheduling constraints
+	// Overrides the affinity set at the execution level (if any)
+	// +optional
+	Affinity *apiv1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations to apply to task pods.
+	// Overrides the tolerations set at the execution level (if any)
+	// +optional
+	Tolerations []apiv1.Toleration `json:"tolerations,omitempty"`
+
+	// CommandSet is a list of commands run by this task.
+	CommandSet []string `json:"commandSet,omitempty"`
+
+	// CommandsIter defines batch command for workflows job.
+	CommandsIter *CommandsIter `json:"commandsIter,omitempty"`
+	// Docker image name.
+	// More info: https://kubernetes.io/docs/concepts/containers/images
+	Image string `json:"image,omitempty"`
+
+	// Volumes is a list of volumes that can be mounted by containers in the task.
+	// +optional
+	Volumes map[string]Volume `json:"volumes,omitempty"`
+
+	// +optional
+	Resources ResourceRequirements `json:"resources,omitempty"`
+
+	// Specifies the duration in seconds relative to the startTime that the job may be active
+	// before the system tries to terminate it; value must be positive integer
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Specifies the number of retries before marking this job failed.
+	// If not set use the k8s job default.
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// Parallelism limits the max total parallel jobs that can execute at the same time within the
+	// boundaries of this job invocation.
+	// Overrides the parallelism set at the execution level (if any)
+	Parallelism *int64 `json:"parallelism,omitempty"`
+
+	// Priority orders the jobs ready to start when the parallelism of the
+	// execution does not let them all start, the jobs of the tasks with a
+	// higher priority start first. Defaults to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// EstimatedDuration is how long a job of the task is expected to run. It
+	// is used to start first the jobs on the longest remaining path of the
+	// workflow. Defaults to the duration of the jobs of the task in the
+	// executions which have run before.
+	// +optional
+	EstimatedDuration *metav1.Duration `json:"estimatedDuration,omitempty"`
+
+	// ChunkSize is the number of commands run one after another by each job
+	// of the task, so that short commands do not each pay for the start of a pod.
+	// All the commands of a job run, the exit status of each one is recorded in
+	// the status of the vertex, and only the failed ones run again when the job
+	// is retried. Only for the tasks of type Job and Spark. Defaults to 1.
+	// +optional
+	ChunkSize *int32 `json:"chunkSize,omitempty"`
+
+	// MemoryEscalation has a job killed for running out of memory start
+	// again with more memory. Only for the tasks of type Job and Spark
+	// which request memory.
+	// +optional
+	MemoryEscalation *MemoryEscalation `json:"memoryEscalation,omitempty"`
+
+	// Specifies the dependency by this task
+	// +optional
+	Dependents []Dependent `json:"dependents"`
+
+	// Specifies the condition for this task
+	// The task will be executed only when condition satisfied
+	// +optional
+	Condition *Condition `json:"condition,omitempty"`
+	// Specifies the generic condition for this task
+	// The task will be executed only when any one of the rule of condition is  satisfied
+	// +optional
+	GenericCondition *GenericCondition `json:"genericCondition,omitempty"`
+
+	// SkipPolicy specifies how the tasks depending on this task are handled
+	// when this task is skipped because its condition is not satisfied.
+	// One of Continue, Cascade. Defaults to Continue.
+	// +optional
+	SkipPolicy SkipPolicy `json:"skipPolicy,omitempty"`
+
+	// InputArtifacts is a list of artifacts fetched from the artifact
+	// repository before the task runs.
+	// +optional
+	InputArtifacts []Artifact `json:"inputArtifacts,omitempty"`
+
+	// OutputArtifacts is a list of artifacts uploaded to the artifact
+	// repository after the task succeeds.
+	// +optional
+	OutputArtifacts []Artifact `json:"outputArtifacts,omitempty"`
+
+	// Cache enables call caching for the jobs of this task.
+	// Overrides the cache set at the execution level (if any).
+	// +optional
+	Cache *bool `json:"cache,omitempty"`
+
+	// InputDigests identify the content of the data the task reads which is
+	// not part of its spec, e.g. the digests of the files in its volumes or
+	// the generation of a dataset. They are part of the cache key, so that a
+	// job is run again once the data it reads changes. The cache set at the
+	// execution level only applies to the tasks reading volumes other than
+	// emptyDir and ephemeral ones if they set input digests.
+	// +optional
+	InputDigests []string `json:"inputDigests,omitempty"`
+
+	// Env is a list of environment variables set in the task container.
+	// Variables with the same name as the ones set at the execution level override them.
+	// +optional
+	Env []apiv1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom is a list of sources to populate environment variables in the task container.
+	// Overrides the envFrom set at the execution level (if any).
+	// +optional
+	EnvFrom []apiv1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount to run the task pods.
+	// Overrides the serviceAccountName set at the execution level (if any).
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// ImagePullSecrets is a list of references to secrets used to pull the image of the task.
+	// Overrides the imagePullSecrets set at the execution level (if any).
+	// +optional
+	ImagePullSecrets []apiv1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImagePullPolicy of the image of the task.
+	// One of Always, Never, IfNotPresent.
+	// Overrides the imagePullPolicy set at the execution level (if any).
+	// +optional
+	ImagePullPolicy apiv1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// SecurityContext is the security options the task container runs with.
+	// Overrides the securityContext set at the execution level (if any).
+	// +optional
+	SecurityContext *apiv1.SecurityContext `json:"securityContext,omitempty"`
+
+	// InitContainers run in order before the task container, e.g. to stage
+	// the data the task needs into the task volumes. The task volumes are
+	// mounted in the init containers which do not specify volumeMounts.
+	// +optional
+	InitContainers []apiv1.Container `json:"initContainers,omitempty"`
+
+	// Sidecars run next to the task container, e.g. a log shipper or a sync
+	// agent, and are stopped once the task command succeeds, or once it fails
+	// in a pod which does not restart the task container. The task volumes
+	// are mounted in the sidecars which do not specify volumeMounts.
+	// The command of a sidecar must be specified and its image must provide sh.
+	// +optional
+	Sidecars []apiv1.Container `json:"sidecars,omitempty"`
+
+	// Volcano is the options of the Volcano Jobs when the type of the task is VolcanoJob.
+	// The options which are not specified are taken from the execution.
+	// +optional
+	Volcano *VolcanoOptions `json:"volcano,omitempty"`
+}
+
+// VolcanoOptions are the scheduling options of the Volcano Jobs running a task.
+type VolcanoOptions struct {
+	// Queue the jobs are submitted to.
+	// +optional
+	Queue string `json:"queue,omitempty"`
+	// SchedulerName of the pods of the jobs.
+	// Defaults to volcano.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// PriorityClassName of the jobs and their pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Replicas is the number of pods running the command of each job.
+	// Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// MinAvailable is the number of pods which must be scheduled together.
+	// Defaults to replicas.
+	// +optional
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+}
+
+// +k8s:openapi-gen=false
+type ExecutionStatus struct {
+	// Phase a simple, high-level summary of where the workflow is in its lifecycle.
+	Phase VertexPhase `json:"phase,omitempty"`
+
+	// Time at which this workflow started
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+
+	// Time at which this workflow completed
+	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+
+	// A human readable message indicating details about why the workflow is in this condition.
+	Message string `json:"message,omitempty"`
+
+	// Progress is the number of finished vertices out of the vertices of the workflow,
+	// in the form "finished/total".
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// Vertices is a mapping between a vertex ID and the vertex's status.
+	Vertices map[string]VertexStatus `json:"vertices,omitempty"`
+
+	// Outputs is the verified outputs of the workflow.
+	// +optional
+	Outputs []OutputStatus `json:"outputs,omitempty"`
+}
+
+// OutputStatus describes a path of a workflow output found on completion.
+type OutputStatus struct {
+	// Name is the name of the output the path belongs to.
+	Name string `json:"name"`
+
+	// Path is the path of the file or directory.
+	Path string `json:"path"`
+
+	// Size is the total size in bytes of the file, or of the files in the directory.
+	Size int64 `json:"size"`
+
+	// Checksum is the sha256 checksum of the file. For a directory it is the
+	// sha256 checksum of the sorted list of the checksums of its files.
+	Checksum string `json:"checksum"`
+}
+
+// CommandsIter defines command for workflows job. If both Vars and Vars_iter are specified,
+// the generate command will be merged. This is used for the dynamically generating task
+// based on the get_result
+type CommandsIter struct {
+	// Command is the base command that contains variables.
+	Command string `json:"command"`
+
+	// VarsIter lists all the possible values for every position of the command,
+	// the commands are generated from all the combinations of the values replacing
+	// the ${number} variables.
+	//
+	// commandsIter example
+	//
+	//    commandsIter:
+	//      command: sh /tmp/scripts/step1.splitfq.sh ${1} ${2}
+	//      varsIter:
+	//      - values: ["sample1", "sample2"]
+	//      - result:
+	//          job: job-1
+	//          separator: " "
+	//
+	// if the stdout of job-1 is "1 2" then the commands are
+	//
+	// sh /tmp/scripts/step1.splitfq.sh sample1 1
+	// sh /tmp/scripts/step1.splitfq.sh sample2 1
+	// sh /tmp/scripts/step1.splitfq.sh sample1 2
+	// sh /tmp/scripts/step1.splitfq.sh sample2 2
+	VarsIter []VarIter `json:"varsIter,omitempty"`
+}
+
+// VarIter is the list of the possible values of a position of a command.
+// Exactly one of its fields is set.
+type VarIter struct {
+	// Values are the values.
+	// +optional
+	Values []string `json:"values,omitempty"`
+
+	// Result makes the values out of the result of a job.
+	// +optional
+	Result *ResultRef `json:"result,omitempty"`
+}
+
+type VertexStatus struct {
+	// ID is a unique identifier of a vertex within the worklow
+	// It is implemented as a hash of the vertex name, which makes the ID deterministic
+	ID string `json:"id"`
+
+	// Name is unique name in the graph tree used to generate the vertex ID
+	Name string `json:"name"`
+
+	// Type indicates type of vertex
+	Type VertexType `json:"type"`
+
+	// Phase a simple, high-level summary of where the vertex is in its lifecycle.
+	// Can be used as a state machine.
+	Phase VertexPhase `json:"phase,omitempty"`
+
+	// A human readable message indicating details about why the vertex is in this condition.
+	Message string `json:"message,omitempty"`
+
+	// Time at which this vertex started
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+
+	// Time at which this vertex completed
+	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+
+	// Children is a list of child vertex IDs
+	Children []string `json:"children,omitempty"`
+
+	// Commands is the exit status of every command of the job of the vertex,
+	// recorded when the job runs a chunk of more than one command.
+	// +optional
+	Commands []CommandStatus `json:"commands,omitempty"`
+
+	// Resources are the resources requested by the last attempt of the job
+	// of the vertex, recorded when its memory has been escalated.
+	// +optional
+	Resources apiv1.ResourceList `json:"resources,omitempty"`
+
+	// MemoryEscalations is the number of times the job of the vertex has
+	// started again with more memory.
+	// +optional
+	MemoryEscalations int32 `json:"memoryEscalations,omitempty"`
+
+	// Disruptions is the number of pods of the job of the vertex which have
+	// been evicted, preempted or lost with their node. They are retried
+	// without counting toward the backoff limit of the task.
+	// +optional
+	Disruptions int32 `json:"disruptions,omitempty"`
+}
+
+// MemoryEscalation describes how the memory of a job grows on every
+// attempt after the job is killed for running out of memory.
+type MemoryEscalation struct {
+	// Multiplier of the memory of the last attempt, greater than 1.
+	Multiplier float64 `json:"multiplier"`
+
+	// Max is the most memory an attempt can request. Once it is reached the
+	// job is restarted by the backoff limit of the job as usual.
+	Max resource.Quantity `json:"max"`
+}
+
+// CommandStatus is the exit status of a command of a job running a chunk of commands.
+type CommandStatus struct {
+	// Index is the index of the command in the commands of the task.
+	Index int32 `json:"index"`
+
+	// ExitCode is the exit code of the last run of the command.
+	ExitCode int32 `json:"exitCode"`
+}
+
+type Volume struct {
+	MountPath string       `json:"mountPath"`
+	MountFrom VolumeSource `json:"mountFrom"`
+	// ReadOnly mounts the volume read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// VolumeSource is the source of a volume.
+// Exactly one of its members must be specified.
+type VolumeSource struct {
+	// Pvc is the name of a PersistentVolumeClaim shared by all the jobs.
+	// +optional
+	Pvc string `json:"pvc,omitempty"`
+	// EmptyDir is a scratch directory of the pod, optionally memory-backed.
+	// +optional
+	EmptyDir *apiv1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+	// HostPath is a directory of the node, e.g. a local NVMe disk.
+	// +optional
+	HostPath *apiv1.HostPathVolumeSource `json:"hostPath,omitempty"`
+	// ConfigMap populates the volume with the keys of a configmap.
+	// +optional
+	ConfigMap *apiv1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+	// Secret populates the volume with the keys of a secret.
+	// +optional
+	Secret *apiv1.SecretVolumeSource `json:"secret,omitempty"`
+	// NFS is a NFS share shared by all the jobs.
+	// +optional
+	NFS *apiv1.NFSVolumeSource `json:"nfs,omitempty"`
+	// Ephemeral is a PersistentVolumeClaim provisioned for every job,
+	// which is deleted together with the job.
+	// +optional
+	Ephemeral *EphemeralVolumeSource `json:"ephemeral,omitempty"`
+}
+
+// EphemeralVolumeSource is the template of the PersistentVolumeClaim
+// provisioned for every job.
+type EphemeralVolumeSource struct {
+	VolumeClaimTemplate apiv1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate"`
+}
+
+type ResourceRequirements struct {
+	Memory resource.Quantity `json:"memory"`
+	Cpu    resource.Quantity `json:"cpu"`
+}
+
+type Dependent struct {
+	// Target is the name of task this depends on.
+	Target string `json:"target"`
+	// Type is the depends type.
+	// Default to `whole`.
+	// Examples:
+	//  "whole" - A-->B": jobs of B depends on all jobs of A execution done.
+	//  "iterate" - A[1,2,3]-->B[1,2,3]: jobs of B depends on jobs of A one by one.
+	//   That is to say: A[1]->B[1], A[2]->B[2], A[3]->B[3]
+	Type DependType `json:"type,omitempty"`
+}
+
+// Artifact is a directory passed between tasks through the artifact repository.
+type Artifact struct {
+	// Name of the artifact. Must be unique within the input or output artifacts of a task.
+	Name string `json:"name"`
+
+	// Path is the directory of the artifact in the task container.
+	Path string `json:"path"`
+
+	// From is the output artifact an input artifact is fetched from,
+	// in the form of <task>.<artifact>. The task must be one this task depends on.
+	// For a `whole` dependent whose task runs more than one job, the directory
+	// contains one subdirectory per job named after the job index.
+	// For an `iterate` dependent, the artifact of the job with the same index is fetched.
+	// Only used by input artifacts.
+	// +optional
+	From string `json:"from,omitempty"`
+}
+
+// ArtifactRepository describes where artifacts are stored.
+// Exactly one of Local, S3 must be specified.
+type ArtifactRepository struct {
+	// Local stores artifacts in a persistent volume claim.
+	// +optional
+	Local *LocalArtifactRepository `json:"local,omitempty"`
+
+	// S3 stores artifacts in an S3 compatible object storage such as MinIO.
+	// +optional
+	S3 *S3ArtifactRepository `json:"s3,omitempty"`
+
+	// Image used by the containers which fetch and upload artifacts.
+	// Defaults to an image suitable for the repository kind.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// LocalArtifactRepository stores artifacts in a persistent volume claim.
+type LocalArtifactRepository struct {
+	// Pvc is the name of the persistent volume claim.
+	Pvc string `json:"pvc"`
+
+	// SubPath is the directory in the volume artifacts are stored under.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// S3ArtifactRepository stores artifacts in an S3 compatible object storage.
+type S3ArtifactRepository struct {
+	// Endpoint is the host and optional port of the storage service.
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket artifacts are stored in.
+	Bucket string `json:"bucket"`
+
+	// KeyPrefix is prepended to the key of every artifact.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// Insecure connects to the endpoint over plain http.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// AccessKeySecret is the secret key holding the access key.
+	AccessKeySecret apiv1.SecretKeySelector `json:"accessKeySecret"`
+
+	// SecretKeySecret is the secret key holding the secret key.
+	SecretKeySecret apiv1.SecretKeySelector `json:"secretKeySecret"`
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+const (
+	// VolumeName is the name of the emptyDir volume shared by the
+	// task container and the containers moving artifacts.
+	VolumeName = "kubegene-artifacts"
+	// MountPath is where the shared volume is mounted.
+	MountPath = "/kubegene/artifacts"
+	// ExitCodeFile records the exit code of the task command. The
+	// uploading sidecar waits for it before uploading outputs.
+	ExitCodeFile = MountPath + "/.exitcode"
+
+	loadContainerName = "kubegene-artifacts-load"
+	saveContainerName = "kubegene-artifacts-save"
+
+	inputsDir  = "inputs"
+	outputsDir = "outputs"
+)
+
+// Repository is the storage artifacts are passed between tasks through.
+type Repository interface {
+	// Image returns the default image of the containers moving artifacts.
+	Image() string
+	// Volumes returns the volumes the repository needs in the pod.
+	Volumes() []v1.Volume
+	// VolumeMounts returns the mounts of the repository volumes.
+	VolumeMounts() []v1.VolumeMount
+	// Env returns the environment the scripts need, e.g. credentials.
+	Env() []v1.EnvVar
+	// SetupScript returns a shell script run before the other scripts,
+	// e.g. to configure the client, or an empty string.
+	SetupScript() string
+	// LoadScript returns a shell script which fetches the artifact
+	// stored under key into the directory dir.
+	LoadScript(key, dir string) string
+	// SaveScript returns a shell script which uploads the content of
+	// the directory dir as the artifact stored under key.
+	SaveScript(dir, key string) string
+}
+
+// Location maps an artifact in the task container to its key in the repository.
+type Location struct {
+	Name string
+	Path string
+	Key  string
+}
+
+// NewRepository returns the repository described by spec.
+func NewRepository(spec *genev1alpha1.ArtifactRepository) (Repository, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("artifact repository is not specified")
+	}
+	switch {
+	case spec.Local != nil && spec.S3 != nil:
+		return nil, fmt.Errorf("only one of local, s3 artifact repository can be specified")
+	case spec.Local != nil:
+		return newLocalRepository(spec.Local), nil
+	case spec.S3 != nil:
+		return newS3Repository(spec.S3), nil
+	}
+	return nil, fmt.Errorf("one of local, s3 artifact repository must be specified")
+}
+
+// Key returns the key of the output artifact name of a job of task.
+// Index is omitted to get the prefix of the artifact of all the jobs.
+func Key(namespace, execution, task, name, index string) string {
+	return path.Join(namespace, execution, task, name, index)
+}
+
+// CommandFinished returns the shell test telling that the command whose
+// exit code is recorded in exitCodeFile will not run again. A container
+// restarted on failure runs the command again until a run succeeds, so the
+// command has only finished once it succeeded. Otherwise it has finished
+// once it has exited, whatever its exit code.
+func CommandFinished(exitCodeFile string, restartPolicy v1.RestartPolicy) string {
+	if restartPolicy == v1.RestartPolicyNever {
+		return fmt.Sprintf("[ -s %s ]", exitCodeFile)
+	}
+	return fmt.Sprintf("[ \"$(cat %s 2>/dev/null)\" = \"0\" ]", exitCodeFile)
+}
+
+// Inject adds to the pod the containers passing the input and output artifacts
+// of the container at index 0 through the repository, and wraps the command of
+// that container so the exit code is recorded for the uploading sidecar.
+func Inject(podSpec *v1.PodSpec, repo Repository, image string, inputs, outputs []Location) {
+	if len(inputs) == 0 && len(outputs) == 0 {
+		return
+	}
+	if len(image) == 0 {
+		image = repo.Image()
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         VolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	podSpec.Volumes = append(podSpec.Volumes, repo.Volumes()...)
+
+	mounts := append([]v1.VolumeMount{{Name: VolumeName, MountPath: MountPath}}, repo.VolumeMounts()...)
+
+	main := &podSpec.Containers[0]
+	main.VolumeMounts = append(main.VolumeMounts, v1.VolumeMount{Name: VolumeName, MountPath: MountPath})
+	for _, input := range inputs {
+		main.VolumeMounts = append(main.VolumeMounts, v1.VolumeMount{
+			Name:      VolumeName,
+			MountPath: input.Path,
+			SubPath:   path.Join(inputsDir, input.Name),
+			ReadOnly:  true,
+		})
+	}
+	for _, output := range outputs {
+		main.VolumeMounts = append(main.VolumeMounts, v1.VolumeMount{
+			Name:      VolumeName,
+			MountPath: output.Path,
+			SubPath:   path.Join(outputsDir, output.Name),
+		})
+	}
+
+	if len(inputs) != 0 {
+		scripts := []string{"set -e"}
+		if setup := repo.SetupScript(); len(setup) != 0 {
+			scripts = append(scripts, setup)
+		}
+		for _, input := range inputs {
+			scripts = append(scripts, repo.LoadScript(input.Key, path.Join(MountPath, inputsDir, input.Name)))
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, v1.Container{
+			Name:            loadContainerName,
+			Image:           image,
+			Command:         []string{"sh", "-c", strings.Join(scripts, "\n")},
+			Env:             repo.Env(),
+			VolumeMounts:    mounts,
+			ImagePullPolicy: v1.PullIfNotPresent,
+		})
+	}
+
+	if len(outputs) != 0 {
+		// the outputs are only uploaded once a run of the task command
+		// succeeded, the sidecar stops without uploading otherwise.
+		scripts := []string{
+			"set -e",
+			fmt.Sprintf("until %s; do sleep 1; done", CommandFinished(ExitCodeFile, podSpec.RestartPolicy)),
+			fmt.Sprintf("if [ \"$(cat %s)\" != \"0\" ]; then exit 0; fi", ExitCodeFile),
+		}
+		if setup := repo.SetupScript(); len(setup) != 0 {
+			scripts = append(scripts, setup)
+		}
+		for _, output := range outputs {
+			dir := path.Join(MountPath, outputsDir, output.Name)
+			scripts = append(scripts, fmt.Sprintf("mkdir -p '%s'", dir), repo.SaveScript(dir, output.Key))
+		}
+		podSpec.Containers = append(podSpec.Containers, v1.Container{
+			Name:            saveContainerName,
+			Image:           image,
+			Command:         []string{"sh", "-c", strings.Join(scripts, "\n")},
+			Env:             repo.Env(),
+			VolumeMounts:    mounts,
+			ImagePullPolicy: v1.PullIfNotPresent,
+		})
+		main = &podSpec.Containers[0]
+	}
+
+	if len(main.Command) == 3 {
+		main.Command[2] = fmt.Sprintf("(\n%s\n)\nrc=$?\necho $rc > %s\nexit $rc", main.Command[2], ExitCodeFile)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestNewRepository(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Spec      *genev1alpha1.ArtifactRepository
+		ExpectErr bool
+	}{
+		{
+			Name:      "nil repository",
+			ExpectErr: true,
+		},
+		{
+			Name:      "empty repository",
+			Spec:      &genev1alpha1.ArtifactRepository{},
+			ExpectErr: true,
+		},
+		{
+			Name: "local repository",
+			Spec: &genev1alpha1.ArtifactRepository{
+				Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "s3 repository",
+			Spec: &genev1alpha1.ArtifactRepository{
+				S3: &genev1alpha1.S3ArtifactRepository{Endpoint: "minio:9000", Bucket: "artifacts"},
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "both local and s3 repository",
+			Spec: &genev1alpha1.ArtifactRepository{
+				Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				S3:    &genev1alpha1.S3ArtifactRepository{Endpoint: "minio:9000", Bucket: "artifacts"},
+			},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := NewRepository(testCase.Spec)
+		if testCase.ExpectErr == true && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	repo, _ := NewRepository(&genev1alpha1.ArtifactRepository{
+		Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+	})
+
+	testCases := []struct {
+		Name           string
+		Inputs         []Location
+		Outputs        []Location
+		InitContainers int
+		Containers     int
+		Volumes        int
+		MainMounts     int
+	}{
+		{
+			Name:       "no artifacts",
+			Containers: 1,
+		},
+		{
+			Name:           "input artifacts",
+			Inputs:         []Location{{Name: "in", Path: "/data/in", Key: "ns/exec/a/out/0"}},
+			InitContainers: 1,
+			Containers:     1,
+			Volumes:        2,
+			MainMounts:     2,
+		},
+		{
+			Name:       "output artifacts",
+			Outputs:    []Location{{Name: "out", Path: "/data/out", Key: "ns/exec/b/out/0"}},
+			Containers: 2,
+			Volumes:    2,
+			MainMounts: 2,
+		},
+		{
+			Name:           "input and output artifacts",
+			Inputs:         []Location{{Name: "in", Path: "/data/in", Key: "ns/exec/a/out/0"}},
+			Outputs:        []Location{{Name: "out", Path: "/data/out", Key: "ns/exec/b/out/0"}},
+			InitContainers: 1,
+			Containers:     2,
+			Volumes:        2,
+			MainMounts:     3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		podSpec := &v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:    "main",
+					Command: []string{"sh", "-c", "echo hello"},
+				},
+			},
+		}
+		Inject(podSpec, repo, "", testCase.Inputs, testCase.Outputs)
+
+		if len(podSpec.InitContainers) != testCase.InitContainers {
+			t.Errorf("%s: Expect %d init containers, but got %d", testCase.Name, testCase.InitContainers, len(podSpec.InitContainers))
+		}
+		if len(podSpec.Containers) != testCase.Containers {
+			t.Errorf("%s: Expect %d containers, but got %d", testCase.Name, testCase.Containers, len(podSpec.Containers))
+		}
+		if len(podSpec.Volumes) != testCase.Volumes {
+			t.Errorf("%s: Expect %d volumes, but got %d", testCase.Name, testCase.Volumes, len(podSpec.Volumes))
+		}
+		main := podSpec.Containers[0]
+		if main.Name != "main" {
+			t.Errorf("%s: Expect task container first, but got %s", testCase.Name, main.Name)
+		}
+		if len(main.VolumeMounts) != testCase.MainMounts {
+			t.Errorf("%s: Expect %d volume mounts, but got %d", testCase.Name, testCase.MainMounts, len(main.VolumeMounts))
+		}
+		wrapped := strings.Contains(main.Command[2], ExitCodeFile)
+		if wrapped != (len(testCase.Inputs) != 0 || len(testCase.Outputs) != 0) {
+			t.Errorf("%s: unexpected task command %q", testCase.Name, main.Command[2])
+		}
+		for _, c := range append(podSpec.InitContainers, podSpec.Containers[1:]...) {
+			if c.Image != localImage {
+				t.Errorf("%s: Expect image %s, but got %s", testCase.Name, localImage, c.Image)
+			}
+		}
+	}
+}
+
+func TestS3SetupScript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mc")
+	if err != nil {
+		t.Fatalf("create temp dir error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	// the stub client records its arguments one per line.
+	stub := fmt.Sprintf("#!/bin/sh\nfor arg in \"$@\"; do echo \"$arg\"; done > %s/args\n", dir)
+	if err := ioutil.WriteFile(path.Join(dir, "mc"), []byte(stub), 0755); err != nil {
+		t.Fatalf("write stub error: %v", err)
+	}
+
+	testCases := []struct {
+		Name       string
+		Insecure   bool
+		AccessKey  string
+		SecretKey  string
+		ExpectArgs []string
+	}{
+		{
+			Name:       "plain secret",
+			AccessKey:  "minio",
+			SecretKey:  "minio123",
+			ExpectArgs: []string{"config", "host", "add", s3Alias, "https://minio:9000", "minio", "minio123"},
+		},
+		{
+			Name:       "secret with special characters",
+			Insecure:   true,
+			AccessKey:  "AKIA+EXAMPLE",
+			SecretKey:  "wJalr/XUtn+FEMI$K7MDENG@bPx RfiCY'\"",
+			ExpectArgs: []string{"config", "host", "add", s3Alias, "http://minio:9000", "AKIA+EXAMPLE", "wJalr/XUtn+FEMI$K7MDENG@bPx RfiCY'\""},
+		},
+	}
+
+	for _, testCase := range testCases {
+		repo := newS3Repository(&genev1alpha1.S3ArtifactRepository{
+			Endpoint: "minio:9000",
+			Bucket:   "artifacts",
+			Insecure: testCase.Insecure,
+		})
+		cmd := exec.Command("sh", "-c", repo.SetupScript())
+		cmd.Env = []string{
+			"PATH=" + dir + ":" + os.Getenv("PATH"),
+			s3AccessKeyEnv + "=" + testCase.AccessKey,
+			s3SecretKeyEnv + "=" + testCase.SecretKey,
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("%s: run setup script error: %v, %s", testCase.Name, err, out)
+			continue
+		}
+		out, err := ioutil.ReadFile(path.Join(dir, "args"))
+		if err != nil {
+			t.Errorf("%s: read arguments error: %v", testCase.Name, err)
+			continue
+		}
+		args := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		if !reflect.DeepEqual(args, testCase.ExpectArgs) {
+			t.Errorf("%s: Expect arguments %q, but got %q", testCase.Name, testCase.ExpectArgs, args)
+		}
+		for _, env := range repo.Env() {
+			if env.ValueFrom == nil {
+				t.Errorf("%s: Expect the environment to only reference secrets, but got %s=%s", testCase.Name, env.Name, env.Value)
+			}
+		}
+	}
+}
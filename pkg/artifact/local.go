@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/api/core/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+const (
+	localVolumeName = "kubegene-artifact-repository"
+	localMountPath  = "/kubegene/repository"
+	localImage      = "busybox:1.31"
+)
+
+// localRepository stores artifacts in a persistent volume claim
+// mounted into the containers moving artifacts.
+type localRepository struct {
+	pvc     string
+	subPath string
+}
+
+var _ Repository = &localRepository{}
+
+func newLocalRepository(spec *genev1alpha1.LocalArtifactRepository) *localRepository {
+	return &localRepository{
+		pvc:     spec.Pvc,
+		subPath: spec.SubPath,
+	}
+}
+
+func (l *localRepository) Image() string {
+	return localImage
+}
+
+func (l *localRepository) Volumes() []v1.Volume {
+	return []v1.Volume{
+		{
+			Name: localVolumeName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: l.pvc,
+				},
+			},
+		},
+	}
+}
+
+func (l *localRepository) VolumeMounts() []v1.VolumeMount {
+	return []v1.VolumeMount{
+		{
+			Name:      localVolumeName,
+			MountPath: localMountPath,
+			SubPath:   l.subPath,
+		},
+	}
+}
+
+func (l *localRepository) Env() []v1.EnvVar {
+	return nil
+}
+
+func (l *localRepository) SetupScript() string {
+	return ""
+}
+
+func (l *localRepository) LoadScript(key, dir string) string {
+	return fmt.Sprintf("mkdir -p '%s' && cp -r '%s/.' '%s/'", dir, path.Join(localMountPath, key), dir)
+}
+
+func (l *localRepository) SaveScript(dir, key string) string {
+	target := path.Join(localMountPath, key)
+	return fmt.Sprintf("rm -rf '%s' && mkdir -p '%s' && cp -r '%s/.' '%s/'", target, target, dir, target)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/api/core/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+const (
+	s3Alias        = "kubegene"
+	s3Image        = "minio/mc:RELEASE.2020-04-04T05-28-55Z"
+	s3AccessKeyEnv = "KUBEGENE_S3_ACCESS_KEY"
+	s3SecretKeyEnv = "KUBEGENE_S3_SECRET_KEY"
+)
+
+// s3Repository stores artifacts in an S3 compatible object storage.
+// Artifacts are moved with the MinIO client, which is configured
+// by the setup script from the credentials in the environment.
+type s3Repository struct {
+	endpoint  string
+	bucket    string
+	keyPrefix string
+	insecure  bool
+	accessKey v1.SecretKeySelector
+	secretKey v1.SecretKeySelector
+}
+
+var _ Repository = &s3Repository{}
+
+func newS3Repository(spec *genev1alpha1.S3ArtifactRepository) *s3Repository {
+	return &s3Repository{
+		endpoint:  spec.Endpoint,
+		bucket:    spec.Bucket,
+		keyPrefix: spec.KeyPrefix,
+		insecure:  spec.Insecure,
+		accessKey: spec.AccessKeySecret,
+		secretKey: spec.SecretKeySecret,
+	}
+}
+
+func (s *s3Repository) Image() string {
+	return s3Image
+}
+
+func (s *s3Repository) Volumes() []v1.Volume {
+	return nil
+}
+
+func (s *s3Repository) VolumeMounts() []v1.VolumeMount {
+	return nil
+}
+
+func (s *s3Repository) Env() []v1.EnvVar {
+	accessKey := s.accessKey
+	secretKey := s.secretKey
+	return []v1.EnvVar{
+		{
+			Name:      s3AccessKeyEnv,
+			ValueFrom: &v1.EnvVarSource{SecretKeyRef: &accessKey},
+		},
+		{
+			Name:      s3SecretKeyEnv,
+			ValueFrom: &v1.EnvVarSource{SecretKeyRef: &secretKey},
+		},
+	}
+}
+
+// SetupScript adds the alias of the repository to the client. The
+// credentials are passed as arguments rather than in the URL of the
+// MC_HOST_<alias> variable, which breaks on keys containing / or +.
+func (s *s3Repository) SetupScript() string {
+	scheme := "https"
+	if s.insecure {
+		scheme = "http"
+	}
+	return fmt.Sprintf("mc config host add %s '%s://%s' \"$%s\" \"$%s\" > /dev/null",
+		s3Alias, scheme, s.endpoint, s3AccessKeyEnv, s3SecretKeyEnv)
+}
+
+func (s *s3Repository) object(key string) string {
+	return path.Join(s3Alias, s.bucket, s.keyPrefix, key)
+}
+
+func (s *s3Repository) LoadScript(key, dir string) string {
+	return fmt.Sprintf("mkdir -p '%s' && mc mirror --quiet '%s' '%s'", dir, s.object(key), dir)
+}
+
+func (s *s3Repository) SaveScript(dir, key string) string {
+	return fmt.Sprintf("mc mirror --quiet --overwrite --remove '%s' '%s'", dir, s.object(key))
+}
@@ -25,11 +25,13 @@ import (
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
 	executionv1alpha1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1"
+	executionv1beta1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1beta1"
 )
 
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	ExecutionV1alpha1() executionv1alpha1.ExecutionV1alpha1Interface
+	ExecutionV1beta1() executionv1beta1.ExecutionV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -37,6 +39,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	executionV1alpha1 *executionv1alpha1.ExecutionV1alpha1Client
+	executionV1beta1  *executionv1beta1.ExecutionV1beta1Client
 }
 
 // ExecutionV1alpha1 retrieves the ExecutionV1alpha1Client
@@ -44,6 +47,11 @@ func (c *Clientset) ExecutionV1alpha1() executionv1alpha1.ExecutionV1alpha1Inter
 	return c.executionV1alpha1
 }
 
+// ExecutionV1beta1 retrieves the ExecutionV1beta1Client
+func (c *Clientset) ExecutionV1beta1() executionv1beta1.ExecutionV1beta1Interface {
+	return c.executionV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -69,6 +77,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.executionV1beta1, err = executionv1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -82,6 +94,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.executionV1alpha1 = executionv1alpha1.NewForConfigOrDie(c)
+	cs.executionV1beta1 = executionv1beta1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -91,6 +104,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.executionV1alpha1 = executionv1alpha1.New(c)
+	cs.executionV1beta1 = executionv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
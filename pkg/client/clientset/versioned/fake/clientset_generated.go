@@ -27,6 +27,8 @@ import (
 	clientset "kubegene.io/kubegene/pkg/client/clientset/versioned"
 	executionv1alpha1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1"
 	fakeexecutionv1alpha1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1/fake"
+	executionv1beta1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1beta1"
+	fakeexecutionv1beta1 "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1beta1/fake"
 )
 
 // NewSimpleClientset returns a clientset that will respond with the provided objects.
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) ExecutionV1alpha1() executionv1alpha1.ExecutionV1alpha1Interface {
 	return &fakeexecutionv1alpha1.FakeExecutionV1alpha1{Fake: &c.Fake}
 }
+
+// ExecutionV1beta1 retrieves the ExecutionV1beta1Client
+func (c *Clientset) ExecutionV1beta1() executionv1beta1.ExecutionV1beta1Interface {
+	return &fakeexecutionv1beta1.FakeExecutionV1beta1{Fake: &c.Fake}
+}
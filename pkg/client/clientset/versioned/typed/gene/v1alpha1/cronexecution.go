@@ -0,0 +1,195 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	scheme "kubegene.io/kubegene/pkg/client/clientset/versioned/scheme"
+)
+
+// CronExecutionsGetter has a method to return a CronExecutionInterface.
+// A group's client should implement this interface.
+type CronExecutionsGetter interface {
+	CronExecutions(namespace string) CronExecutionInterface
+}
+
+// CronExecutionInterface has methods to work with CronExecution resources.
+type CronExecutionInterface interface {
+	Create(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.CreateOptions) (*v1alpha1.CronExecution, error)
+	Update(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (*v1alpha1.CronExecution, error)
+	UpdateStatus(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (*v1alpha1.CronExecution, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.CronExecution, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.CronExecutionList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.CronExecution, err error)
+	CronExecutionExpansion
+}
+
+// cronExecutions implements CronExecutionInterface
+type cronExecutions struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCronExecutions returns a CronExecutions
+func newCronExecutions(c *ExecutionV1alpha1Client, namespace string) *cronExecutions {
+	return &cronExecutions{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cronExecution, and returns the corresponding cronExecution object, and an error if there is any.
+func (c *cronExecutions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.CronExecution, err error) {
+	result = &v1alpha1.CronExecution{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CronExecutions that match those selectors.
+func (c *cronExecutions) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.CronExecutionList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.CronExecutionList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cronExecutions.
+func (c *cronExecutions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cronExecution and creates it.  Returns the server's representation of the cronExecution, and an error, if there is any.
+func (c *cronExecutions) Create(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.CreateOptions) (result *v1alpha1.CronExecution, err error) {
+	result = &v1alpha1.CronExecution{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cronExecution).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cronExecution and updates it. Returns the server's representation of the cronExecution, and an error, if there is any.
+func (c *cronExecutions) Update(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (result *v1alpha1.CronExecution, err error) {
+	result = &v1alpha1.CronExecution{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		Name(cronExecution.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cronExecution).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *cronExecutions) UpdateStatus(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (result *v1alpha1.CronExecution, err error) {
+	result = &v1alpha1.CronExecution{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		Name(cronExecution.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cronExecution).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cronExecution and deletes it. Returns an error if one occurs.
+func (c *cronExecutions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cronExecutions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cronExecution.
+func (c *cronExecutions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.CronExecution, err error) {
+	result = &v1alpha1.CronExecution{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cronexecutions").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
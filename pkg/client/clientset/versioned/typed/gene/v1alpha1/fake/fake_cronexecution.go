@@ -0,0 +1,142 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+// FakeCronExecutions implements CronExecutionInterface
+type FakeCronExecutions struct {
+	Fake *FakeExecutionV1alpha1
+	ns   string
+}
+
+var cronexecutionsResource = schema.GroupVersionResource{Group: "execution.kubegene.io", Version: "v1alpha1", Resource: "cronexecutions"}
+
+var cronexecutionsKind = schema.GroupVersionKind{Group: "execution.kubegene.io", Version: "v1alpha1", Kind: "CronExecution"}
+
+// Get takes name of the cronExecution, and returns the corresponding cronExecution object, and an error if there is any.
+func (c *FakeCronExecutions) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.CronExecution, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cronexecutionsResource, c.ns, name), &v1alpha1.CronExecution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronExecution), err
+}
+
+// List takes label and field selectors, and returns the list of CronExecutions that match those selectors.
+func (c *FakeCronExecutions) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.CronExecutionList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cronexecutionsResource, cronexecutionsKind, c.ns, opts), &v1alpha1.CronExecutionList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.CronExecutionList{ListMeta: obj.(*v1alpha1.CronExecutionList).ListMeta}
+	for _, item := range obj.(*v1alpha1.CronExecutionList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cronExecutions.
+func (c *FakeCronExecutions) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cronexecutionsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cronExecution and creates it.  Returns the server's representation of the cronExecution, and an error, if there is any.
+func (c *FakeCronExecutions) Create(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.CreateOptions) (result *v1alpha1.CronExecution, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cronexecutionsResource, c.ns, cronExecution), &v1alpha1.CronExecution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronExecution), err
+}
+
+// Update takes the representation of a cronExecution and updates it. Returns the server's representation of the cronExecution, and an error, if there is any.
+func (c *FakeCronExecutions) Update(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (result *v1alpha1.CronExecution, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cronexecutionsResource, c.ns, cronExecution), &v1alpha1.CronExecution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronExecution), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCronExecutions) UpdateStatus(ctx context.Context, cronExecution *v1alpha1.CronExecution, opts v1.UpdateOptions) (*v1alpha1.CronExecution, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cronexecutionsResource, "status", c.ns, cronExecution), &v1alpha1.CronExecution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronExecution), err
+}
+
+// Delete takes name of the cronExecution and deletes it. Returns an error if one occurs.
+func (c *FakeCronExecutions) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cronexecutionsResource, c.ns, name), &v1alpha1.CronExecution{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCronExecutions) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cronexecutionsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.CronExecutionList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cronExecution.
+func (c *FakeCronExecutions) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.CronExecution, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cronexecutionsResource, c.ns, name, pt, data, subresources...), &v1alpha1.CronExecution{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronExecution), err
+}
@@ -28,10 +28,26 @@ type FakeExecutionV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeExecutionV1alpha1) CronExecutions(namespace string) v1alpha1.CronExecutionInterface {
+	return &FakeCronExecutions{c, namespace}
+}
+
 func (c *FakeExecutionV1alpha1) Executions(namespace string) v1alpha1.ExecutionInterface {
 	return &FakeExecutions{c, namespace}
 }
 
+func (c *FakeExecutionV1alpha1) Tools() v1alpha1.ToolInterface {
+	return &FakeTools{c}
+}
+
+func (c *FakeExecutionV1alpha1) WorkflowRuns(namespace string) v1alpha1.WorkflowRunInterface {
+	return &FakeWorkflowRuns{c, namespace}
+}
+
+func (c *FakeExecutionV1alpha1) WorkflowTemplates(namespace string) v1alpha1.WorkflowTemplateInterface {
+	return &FakeWorkflowTemplates{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeExecutionV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,122 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+// FakeTools implements ToolInterface
+type FakeTools struct {
+	Fake *FakeExecutionV1alpha1
+}
+
+var toolsResource = schema.GroupVersionResource{Group: "execution.kubegene.io", Version: "v1alpha1", Resource: "tools"}
+
+var toolsKind = schema.GroupVersionKind{Group: "execution.kubegene.io", Version: "v1alpha1", Kind: "Tool"}
+
+// Get takes name of the tool, and returns the corresponding tool object, and an error if there is any.
+func (c *FakeTools) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Tool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(toolsResource, name), &v1alpha1.Tool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Tool), err
+}
+
+// List takes label and field selectors, and returns the list of Tools that match those selectors.
+func (c *FakeTools) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ToolList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(toolsResource, toolsKind, opts), &v1alpha1.ToolList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ToolList{ListMeta: obj.(*v1alpha1.ToolList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ToolList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested tools.
+func (c *FakeTools) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(toolsResource, opts))
+}
+
+// Create takes the representation of a tool and creates it.  Returns the server's representation of the tool, and an error, if there is any.
+func (c *FakeTools) Create(ctx context.Context, tool *v1alpha1.Tool, opts v1.CreateOptions) (result *v1alpha1.Tool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(toolsResource, tool), &v1alpha1.Tool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Tool), err
+}
+
+// Update takes the representation of a tool and updates it. Returns the server's representation of the tool, and an error, if there is any.
+func (c *FakeTools) Update(ctx context.Context, tool *v1alpha1.Tool, opts v1.UpdateOptions) (result *v1alpha1.Tool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(toolsResource, tool), &v1alpha1.Tool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Tool), err
+}
+
+// Delete takes name of the tool and deletes it. Returns an error if one occurs.
+func (c *FakeTools) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(toolsResource, name), &v1alpha1.Tool{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeTools) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(toolsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ToolList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched tool.
+func (c *FakeTools) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Tool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(toolsResource, name, pt, data, subresources...), &v1alpha1.Tool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Tool), err
+}
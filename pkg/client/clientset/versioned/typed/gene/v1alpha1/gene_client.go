@@ -26,7 +26,11 @@ import (
 
 type ExecutionV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CronExecutionsGetter
 	ExecutionsGetter
+	ToolsGetter
+	WorkflowRunsGetter
+	WorkflowTemplatesGetter
 }
 
 // ExecutionV1alpha1Client is used to interact with features provided by the execution.kubegene.io group.
@@ -34,10 +38,26 @@ type ExecutionV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *ExecutionV1alpha1Client) CronExecutions(namespace string) CronExecutionInterface {
+	return newCronExecutions(c, namespace)
+}
+
 func (c *ExecutionV1alpha1Client) Executions(namespace string) ExecutionInterface {
 	return newExecutions(c, namespace)
 }
 
+func (c *ExecutionV1alpha1Client) Tools() ToolInterface {
+	return newTools(c)
+}
+
+func (c *ExecutionV1alpha1Client) WorkflowRuns(namespace string) WorkflowRunInterface {
+	return newWorkflowRuns(c, namespace)
+}
+
+func (c *ExecutionV1alpha1Client) WorkflowTemplates(namespace string) WorkflowTemplateInterface {
+	return newWorkflowTemplates(c, namespace)
+}
+
 // NewForConfig creates a new ExecutionV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*ExecutionV1alpha1Client, error) {
 	config := *c
@@ -18,4 +18,12 @@ limitations under the License.
 
 package v1alpha1
 
+type CronExecutionExpansion interface{}
+
 type ExecutionExpansion interface{}
+
+type ToolExpansion interface{}
+
+type WorkflowRunExpansion interface{}
+
+type WorkflowTemplateExpansion interface{}
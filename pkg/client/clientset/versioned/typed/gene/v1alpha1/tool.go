@@ -0,0 +1,168 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	scheme "kubegene.io/kubegene/pkg/client/clientset/versioned/scheme"
+)
+
+// ToolsGetter has a method to return a ToolInterface.
+// A group's client should implement this interface.
+type ToolsGetter interface {
+	Tools() ToolInterface
+}
+
+// ToolInterface has methods to work with Tool resources.
+type ToolInterface interface {
+	Create(ctx context.Context, tool *v1alpha1.Tool, opts v1.CreateOptions) (*v1alpha1.Tool, error)
+	Update(ctx context.Context, tool *v1alpha1.Tool, opts v1.UpdateOptions) (*v1alpha1.Tool, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Tool, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ToolList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Tool, err error)
+	ToolExpansion
+}
+
+// tools implements ToolInterface
+type tools struct {
+	client rest.Interface
+}
+
+// newTools returns a Tools
+func newTools(c *ExecutionV1alpha1Client) *tools {
+	return &tools{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the tool, and returns the corresponding tool object, and an error if there is any.
+func (c *tools) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Tool, err error) {
+	result = &v1alpha1.Tool{}
+	err = c.client.Get().
+		Resource("tools").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Tools that match those selectors.
+func (c *tools) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ToolList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ToolList{}
+	err = c.client.Get().
+		Resource("tools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested tools.
+func (c *tools) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("tools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a tool and creates it.  Returns the server's representation of the tool, and an error, if there is any.
+func (c *tools) Create(ctx context.Context, tool *v1alpha1.Tool, opts v1.CreateOptions) (result *v1alpha1.Tool, err error) {
+	result = &v1alpha1.Tool{}
+	err = c.client.Post().
+		Resource("tools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tool).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a tool and updates it. Returns the server's representation of the tool, and an error, if there is any.
+func (c *tools) Update(ctx context.Context, tool *v1alpha1.Tool, opts v1.UpdateOptions) (result *v1alpha1.Tool, err error) {
+	result = &v1alpha1.Tool{}
+	err = c.client.Put().
+		Resource("tools").
+		Name(tool.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tool).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the tool and deletes it. Returns an error if one occurs.
+func (c *tools) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("tools").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *tools) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("tools").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched tool.
+func (c *tools) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Tool, err error) {
+	result = &v1alpha1.Tool{}
+	err = c.client.Patch(pt).
+		Resource("tools").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
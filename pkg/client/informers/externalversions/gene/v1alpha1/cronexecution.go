@@ -0,0 +1,90 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	versioned "kubegene.io/kubegene/pkg/client/clientset/versioned"
+	internalinterfaces "kubegene.io/kubegene/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+)
+
+// CronExecutionInformer provides access to a shared informer and lister for
+// CronExecutions.
+type CronExecutionInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.CronExecutionLister
+}
+
+type cronExecutionInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewCronExecutionInformer constructs a new informer for CronExecution type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewCronExecutionInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredCronExecutionInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredCronExecutionInformer constructs a new informer for CronExecution type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredCronExecutionInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ExecutionV1alpha1().CronExecutions(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ExecutionV1alpha1().CronExecutions(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&genev1alpha1.CronExecution{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *cronExecutionInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredCronExecutionInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *cronExecutionInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&genev1alpha1.CronExecution{}, f.defaultInformer)
+}
+
+func (f *cronExecutionInformer) Lister() v1alpha1.CronExecutionLister {
+	return v1alpha1.NewCronExecutionLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	versioned "kubegene.io/kubegene/pkg/client/clientset/versioned"
+	internalinterfaces "kubegene.io/kubegene/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+)
+
+// ToolInformer provides access to a shared informer and lister for
+// Tools.
+type ToolInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.ToolLister
+}
+
+type toolInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewToolInformer constructs a new informer for Tool type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewToolInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredToolInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredToolInformer constructs a new informer for Tool type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredToolInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ExecutionV1alpha1().Tools().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ExecutionV1alpha1().Tools().Watch(context.TODO(), options)
+			},
+		},
+		&genev1alpha1.Tool{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *toolInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredToolInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *toolInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&genev1alpha1.Tool{}, f.defaultInformer)
+}
+
+func (f *toolInformer) Lister() v1alpha1.ToolLister {
+	return v1alpha1.NewToolLister(f.Informer().GetIndexer())
+}
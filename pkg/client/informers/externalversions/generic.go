@@ -24,6 +24,7 @@ import (
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	v1beta1 "kubegene.io/kubegene/pkg/apis/gene/v1beta1"
 )
 
 // GenericInformer is type of SharedIndexInformer which will locate and delegate to other
@@ -53,8 +54,20 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=execution.kubegene.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("cronexecutions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1alpha1().CronExecutions().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("executions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1alpha1().Executions().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("tools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1alpha1().Tools().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("workflowruns"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1alpha1().WorkflowRuns().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("workflowtemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1alpha1().WorkflowTemplates().Informer()}, nil
+
+		// Group=execution.kubegene.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("executions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Execution().V1beta1().Executions().Informer()}, nil
 
 	}
 
@@ -0,0 +1,94 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+// CronExecutionLister helps list CronExecutions.
+type CronExecutionLister interface {
+	// List lists all CronExecutions in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.CronExecution, err error)
+	// CronExecutions returns an object that can list and get CronExecutions.
+	CronExecutions(namespace string) CronExecutionNamespaceLister
+	CronExecutionListerExpansion
+}
+
+// cronExecutionLister implements the CronExecutionLister interface.
+type cronExecutionLister struct {
+	indexer cache.Indexer
+}
+
+// NewCronExecutionLister returns a new CronExecutionLister.
+func NewCronExecutionLister(indexer cache.Indexer) CronExecutionLister {
+	return &cronExecutionLister{indexer: indexer}
+}
+
+// List lists all CronExecutions in the indexer.
+func (s *cronExecutionLister) List(selector labels.Selector) (ret []*v1alpha1.CronExecution, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CronExecution))
+	})
+	return ret, err
+}
+
+// CronExecutions returns an object that can list and get CronExecutions.
+func (s *cronExecutionLister) CronExecutions(namespace string) CronExecutionNamespaceLister {
+	return cronExecutionNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CronExecutionNamespaceLister helps list and get CronExecutions.
+type CronExecutionNamespaceLister interface {
+	// List lists all CronExecutions in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.CronExecution, err error)
+	// Get retrieves the CronExecution from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.CronExecution, error)
+	CronExecutionNamespaceListerExpansion
+}
+
+// cronExecutionNamespaceLister implements the CronExecutionNamespaceLister
+// interface.
+type cronExecutionNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CronExecutions in the indexer for a given namespace.
+func (s cronExecutionNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.CronExecution, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CronExecution))
+	})
+	return ret, err
+}
+
+// Get retrieves the CronExecution from the indexer for a given namespace and name.
+func (s cronExecutionNamespaceLister) Get(name string) (*v1alpha1.CronExecution, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("cronexecution"), name)
+	}
+	return obj.(*v1alpha1.CronExecution), nil
+}
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// CronExecutionListerExpansion allows custom methods to be added to
+// CronExecutionLister.
+type CronExecutionListerExpansion interface{}
+
+// CronExecutionNamespaceListerExpansion allows custom methods to be added to
+// CronExecutionNamespaceLister.
+type CronExecutionNamespaceListerExpansion interface{}
+
 // ExecutionListerExpansion allows custom methods to be added to
 // ExecutionLister.
 type ExecutionListerExpansion interface{}
@@ -25,3 +33,23 @@ type ExecutionListerExpansion interface{}
 // ExecutionNamespaceListerExpansion allows custom methods to be added to
 // ExecutionNamespaceLister.
 type ExecutionNamespaceListerExpansion interface{}
+
+// ToolListerExpansion allows custom methods to be added to
+// ToolLister.
+type ToolListerExpansion interface{}
+
+// WorkflowRunListerExpansion allows custom methods to be added to
+// WorkflowRunLister.
+type WorkflowRunListerExpansion interface{}
+
+// WorkflowRunNamespaceListerExpansion allows custom methods to be added to
+// WorkflowRunNamespaceLister.
+type WorkflowRunNamespaceListerExpansion interface{}
+
+// WorkflowTemplateListerExpansion allows custom methods to be added to
+// WorkflowTemplateLister.
+type WorkflowTemplateListerExpansion interface{}
+
+// WorkflowTemplateNamespaceListerExpansion allows custom methods to be added to
+// WorkflowTemplateNamespaceLister.
+type WorkflowTemplateNamespaceListerExpansion interface{}
@@ -0,0 +1,65 @@
+/*
+Copyright The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+// ToolLister helps list Tools.
+type ToolLister interface {
+	// List lists all Tools in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.Tool, err error)
+	// Get retrieves the Tool from the index for a given name.
+	Get(name string) (*v1alpha1.Tool, error)
+	ToolListerExpansion
+}
+
+// toolLister implements the ToolLister interface.
+type toolLister struct {
+	indexer cache.Indexer
+}
+
+// NewToolLister returns a new ToolLister.
+func NewToolLister(indexer cache.Indexer) ToolLister {
+	return &toolLister{indexer: indexer}
+}
+
+// List lists all Tools in the indexer.
+func (s *toolLister) List(selector labels.Selector) (ret []*v1alpha1.Tool, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.Tool))
+	})
+	return ret, err
+}
+
+// Get retrieves the Tool from the index for a given name.
+func (s *toolLister) Get(name string) (*v1alpha1.Tool, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("tool"), name)
+	}
+	return obj.(*v1alpha1.Tool), nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	genelisters "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+// Admission limits the number of running executions, in the cluster and
+// in every namespace. The executions beyond the limits are queued and
+// admitted by priority, then by creation time, as the running ones finish.
+// An execution is admitted once its status is running, so that all the
+// replicas count the executions admitted by the others. The controllers of
+// the namespaces watched share one admission, which counts the executions
+// of all of them.
+type Admission struct {
+	sync.Mutex
+	// maxRunning is the max number of running executions in the cluster, 0 means no limit.
+	maxRunning int
+	// maxRunningPerNamespace is the max number of running executions in a namespace, 0 means no limit.
+	maxRunningPerNamespace int
+	// admitted are the keys of the executions admitted by this replica whose
+	// running status may not have reached the lister yet.
+	admitted map[string]bool
+	// members are the controllers sharing the admission.
+	members []admissionMember
+}
+
+// admissionMember is a controller sharing the admission, which lists its
+// executions and syncs them through its queue.
+type admissionMember struct {
+	lister genelisters.ExecutionLister
+	queue  workqueue.Interface
+}
+
+// NewAdmission returns the admission of the executions with the limits, 0 means no limit.
+func NewAdmission(maxRunning, maxRunningPerNamespace int) *Admission {
+	return &Admission{
+		maxRunning:             maxRunning,
+		maxRunningPerNamespace: maxRunningPerNamespace,
+		admitted:               make(map[string]bool),
+	}
+}
+
+// addMember has the controller of the lister and the queue share the admission.
+func (a *Admission) addMember(lister genelisters.ExecutionLister, queue workqueue.Interface) {
+	a.Lock()
+	defer a.Unlock()
+	a.members = append(a.members, admissionMember{lister: lister, queue: queue})
+}
+
+// executions returns the executions of all the controllers sharing the admission.
+func (a *Admission) executions() ([]*genev1alpha1.Execution, error) {
+	a.Lock()
+	members := a.members
+	a.Unlock()
+
+	executions := []*genev1alpha1.Execution{}
+	for _, member := range members {
+		listed, err := member.lister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, listed...)
+	}
+	return executions, nil
+}
+
+// enqueueQueued syncs the queued executions of all the controllers sharing
+// the admission, in the order they are admitted.
+func (a *Admission) enqueueQueued() error {
+	a.Lock()
+	members := a.members
+	a.Unlock()
+
+	for _, member := range members {
+		executions, err := member.lister.List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, key := range queuedExecutions(executions) {
+			member.queue.Add(key)
+		}
+	}
+	return nil
+}
+
+// isQueued returns true if the execution has not been admitted yet, as far
+// as its status tells.
+func isQueued(exec *genev1alpha1.Execution) bool {
+	return len(exec.Status.Phase) == 0 || exec.Status.Phase == genev1alpha1.VertexQueued
+}
+
+// limited returns true if the number of running executions is limited.
+func (a *Admission) limited() bool {
+	return a.maxRunning > 0 || a.maxRunningPerNamespace > 0
+}
+
+// admit returns true if the execution may run, given all the executions.
+// An execution which has started is always admitted, so that the executions
+// running before a restart of the controller keep running. The caller marks
+// an admitted execution as running in its status, or forgets it if it fails to.
+func (a *Admission) admit(exec *genev1alpha1.Execution, executions []*genev1alpha1.Execution) bool {
+	if !a.limited() {
+		return true
+	}
+	a.Lock()
+	defer a.Unlock()
+
+	key := util.KeyOf(exec)
+	if a.admitted[key] || !isQueued(exec) {
+		a.admitted[key] = true
+		return true
+	}
+
+	running := 0
+	runningInNamespace := make(map[string]int)
+	queued := []*genev1alpha1.Execution{}
+	admitted := make(map[string]bool, len(a.admitted))
+	for _, e := range executions {
+		if util.IsExecutionCompleted(e) {
+			continue
+		}
+		eKey := util.KeyOf(e)
+		if a.admitted[eKey] || !isQueued(e) {
+			admitted[eKey] = true
+			running++
+			runningInNamespace[e.Namespace]++
+			continue
+		}
+		if eKey != key {
+			queued = append(queued, e)
+		}
+	}
+	// forget the executions which have finished or been deleted.
+	a.admitted = admitted
+	queued = append(queued, exec)
+	sortQueue(queued)
+
+	// the executions ahead in the queue take the room left first, skipping
+	// those whose namespace is full.
+	for _, e := range queued {
+		if a.maxRunning > 0 && running >= a.maxRunning {
+			return false
+		}
+		if a.maxRunningPerNamespace > 0 && runningInNamespace[e.Namespace] >= a.maxRunningPerNamespace {
+			continue
+		}
+		if e == exec {
+			a.admitted[key] = true
+			return true
+		}
+		running++
+		runningInNamespace[e.Namespace]++
+	}
+	return false
+}
+
+// forget forgets the admission of the execution of the key, whose running
+// status could not be recorded.
+func (a *Admission) forget(key string) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.admitted, key)
+}
+
+// queuedExecutions returns the keys of the queued executions in the order they are admitted.
+func queuedExecutions(executions []*genev1alpha1.Execution) []string {
+	queued := []*genev1alpha1.Execution{}
+	for _, exec := range executions {
+		if isQueued(exec) {
+			queued = append(queued, exec)
+		}
+	}
+	sortQueue(queued)
+	keys := make([]string, 0, len(queued))
+	for _, exec := range queued {
+		keys = append(keys, util.KeyOf(exec))
+	}
+	return keys
+}
+
+// sortQueue sorts the executions by priority, then by creation time.
+func sortQueue(queue []*genev1alpha1.Execution) {
+	sort.SliceStable(queue, func(i, j int) bool {
+		iPriority, jPriority := executionPriority(queue[i]), executionPriority(queue[j])
+		if iPriority != jPriority {
+			return iPriority > jPriority
+		}
+		iCreated, jCreated := queue[i].CreationTimestamp, queue[j].CreationTimestamp
+		if !iCreated.Equal(&jCreated) {
+			return iCreated.Before(&jCreated)
+		}
+		return util.KeyOf(queue[i]) < util.KeyOf(queue[j])
+	})
+}
+
+func executionPriority(exec *genev1alpha1.Execution) int32 {
+	if exec.Spec.Priority == nil {
+		return 0
+	}
+	return *exec.Spec.Priority
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+func newQueuedExecution(namespace, name string, priority int32, age time.Duration, phase genev1alpha1.VertexPhase) *genev1alpha1.Execution {
+	return &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec:   genev1alpha1.ExecutionSpec{Priority: &priority},
+		Status: genev1alpha1.ExecutionStatus{Phase: phase},
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	running := newQueuedExecution("ns1", "running", 0, time.Hour, genev1alpha1.VertexRunning)
+	succeeded := newQueuedExecution("ns1", "succeeded", 0, time.Hour, genev1alpha1.VertexSucceeded)
+	old := newQueuedExecution("ns1", "old", 0, time.Minute, genev1alpha1.VertexQueued)
+	young := newQueuedExecution("ns1", "young", 0, time.Second, "")
+	urgent := newQueuedExecution("ns1", "urgent", 10, time.Second, genev1alpha1.VertexQueued)
+	other := newQueuedExecution("ns2", "other", 0, time.Second, "")
+
+	testCases := []struct {
+		Name                   string
+		MaxRunning             int
+		MaxRunningPerNamespace int
+		Exec                   *genev1alpha1.Execution
+		Executions             []*genev1alpha1.Execution
+		Expect                 bool
+	}{
+		{
+			Name:       "no limit",
+			Exec:       young,
+			Executions: []*genev1alpha1.Execution{running, old, young},
+			Expect:     true,
+		},
+		{
+			Name:       "running execution is always admitted",
+			MaxRunning: 1,
+			Exec:       running,
+			Executions: []*genev1alpha1.Execution{running, newQueuedExecution("ns1", "r2", 0, time.Hour, genev1alpha1.VertexRunning)},
+			Expect:     true,
+		},
+		{
+			Name:       "finished executions leave room",
+			MaxRunning: 2,
+			Exec:       old,
+			Executions: []*genev1alpha1.Execution{running, succeeded, old},
+			Expect:     true,
+		},
+		{
+			Name:       "cluster is full",
+			MaxRunning: 1,
+			Exec:       old,
+			Executions: []*genev1alpha1.Execution{running, old},
+			Expect:     false,
+		},
+		{
+			Name:       "older execution first",
+			MaxRunning: 2,
+			Exec:       young,
+			Executions: []*genev1alpha1.Execution{running, old, young},
+			Expect:     false,
+		},
+		{
+			Name:       "higher priority first",
+			MaxRunning: 2,
+			Exec:       urgent,
+			Executions: []*genev1alpha1.Execution{running, old, urgent},
+			Expect:     true,
+		},
+		{
+			Name:                   "namespace is full",
+			MaxRunningPerNamespace: 1,
+			Exec:                   old,
+			Executions:             []*genev1alpha1.Execution{running, old, other},
+			Expect:                 false,
+		},
+		{
+			Name:                   "full namespace does not block other namespaces",
+			MaxRunning:             2,
+			MaxRunningPerNamespace: 1,
+			Exec:                   other,
+			Executions:             []*genev1alpha1.Execution{running, old, other},
+			Expect:                 true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		a := NewAdmission(testCase.MaxRunning, testCase.MaxRunningPerNamespace)
+		if admitted := a.admit(testCase.Exec, testCase.Executions); admitted != testCase.Expect {
+			t.Errorf("%s: Expect admitted %v, but got %v", testCase.Name, testCase.Expect, admitted)
+		}
+	}
+}
+
+func TestAdmitRemembersAdmitted(t *testing.T) {
+	first := newQueuedExecution("ns1", "first", 0, time.Minute, "")
+	second := newQueuedExecution("ns1", "second", 0, time.Second, "")
+	executions := []*genev1alpha1.Execution{first, second}
+
+	a := NewAdmission(1, 0)
+	if !a.admit(first, executions) {
+		t.Fatalf("Expect the first execution admitted")
+	}
+	// the phase of the first execution is not running yet.
+	if a.admit(second, executions) {
+		t.Errorf("Expect the second execution queued while the first one runs")
+	}
+	if !a.admit(first, executions) {
+		t.Errorf("Expect the first execution still admitted")
+	}
+
+	first.Status.Phase = genev1alpha1.VertexSucceeded
+	if !a.admit(second, executions) {
+		t.Errorf("Expect the second execution admitted once the first one has finished")
+	}
+}
+
+func TestQueuedExecutions(t *testing.T) {
+	executions := []*genev1alpha1.Execution{
+		newQueuedExecution("ns1", "running", 0, time.Hour, genev1alpha1.VertexRunning),
+		newQueuedExecution("ns1", "young", 0, time.Second, ""),
+		newQueuedExecution("ns1", "old", 0, time.Minute, genev1alpha1.VertexQueued),
+		newQueuedExecution("ns2", "urgent", 5, time.Second, genev1alpha1.VertexQueued),
+	}
+	expect := []string{"ns2/urgent", "ns1/old", "ns1/young"}
+	if keys := queuedExecutions(executions); !reflect.DeepEqual(keys, expect) {
+		t.Errorf("Expect queue %v, but got %v", expect, keys)
+	}
+}
+
+func TestAdmitAcrossReplicas(t *testing.T) {
+	first := newQueuedExecution("default", "first", 0, time.Minute, "")
+	first.Spec.Tasks = []genev1alpha1.Task{{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo A"}}}
+	second := first.DeepCopy()
+	second.Name = "second"
+	second.CreationTimestamp = metav1.NewTime(time.Now())
+
+	// the first replica admits the first execution and records it in its status.
+	c := newTestExecutionController(first)
+	c.admission = NewAdmission(1, 0)
+	c.admission.addMember(c.execLister, c.execQueue)
+	if err := c.syncExecution(util.KeyOf(first)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	admitted := c.execution(t, first)
+	if admitted.Status.Phase != genev1alpha1.VertexRunning {
+		t.Fatalf("Expect the first execution running, but got phase %q", admitted.Status.Phase)
+	}
+
+	// the second replica has not admitted anything, it counts the first execution from its status.
+	other := newTestExecutionController(second)
+	other.admission = NewAdmission(1, 0)
+	other.admission.addMember(other.execLister, other.execQueue)
+	other.execIndexer.Add(admitted)
+	if err := other.syncExecution(util.KeyOf(second)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if phase := other.execution(t, second).Status.Phase; phase != genev1alpha1.VertexQueued {
+		t.Errorf("Expect the second execution queued, but got phase %q", phase)
+	}
+}
+
+func TestAdmissionSharedByNamespaces(t *testing.T) {
+	first := newQueuedExecution("ns1", "first", 0, time.Minute, "")
+	first.Spec.Tasks = []genev1alpha1.Task{{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo A"}}}
+	second := first.DeepCopy()
+	second.Namespace = "ns2"
+	second.Name = "second"
+
+	// the controllers of the two namespaces share the limit of one running execution.
+	admission := NewAdmission(1, 0)
+	c1 := newTestExecutionController(first)
+	c1.admission = admission
+	admission.addMember(c1.execLister, c1.execQueue)
+	c2 := newTestExecutionController(second)
+	c2.admission = admission
+	admission.addMember(c2.execLister, c2.execQueue)
+
+	if err := c1.syncExecution(util.KeyOf(first)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if phase := c1.execution(t, first).Status.Phase; phase != genev1alpha1.VertexRunning {
+		t.Fatalf("Expect the first execution running, but got phase %q", phase)
+	}
+	if err := c2.syncExecution(util.KeyOf(second)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if phase := c2.execution(t, second).Status.Phase; phase != genev1alpha1.VertexQueued {
+		t.Errorf("Expect the second execution queued, but got phase %q", phase)
+	}
+
+	// the first execution finishing syncs the execution queued in the other namespace.
+	for c2.execQueue.Len() > 0 {
+		key, _ := c2.execQueue.Get()
+		c2.execQueue.Done(key)
+	}
+	finished := c1.execution(t, first).DeepCopy()
+	finished.Status.Phase = genev1alpha1.VertexSucceeded
+	c1.execIndexer.Update(finished)
+	c1.enqueueQueuedExecutions()
+	if c2.execQueue.Len() != 1 {
+		t.Fatalf("Expect 1 execution synced, but got %d", c2.execQueue.Len())
+	}
+	if key, _ := c2.execQueue.Get(); key != util.KeyOf(second) {
+		t.Errorf("Expect execution %s synced, but got %v", util.KeyOf(second), key)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/artifact"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// injectArtifacts adds to the job the containers fetching the input artifacts
+// and uploading the output artifacts of the task.
+func injectArtifacts(job *workload.Workload, exec *genev1alpha1.Execution, task *genev1alpha1.Task) {
+	if len(task.InputArtifacts) == 0 && len(task.OutputArtifacts) == 0 {
+		return
+	}
+	repo, err := artifact.NewRepository(exec.Spec.ArtifactRepository)
+	if err != nil {
+		// should not happen, the execution has been validated.
+		klog.Errorf("Get artifact repository of execution %s/%s error: %v", exec.Namespace, exec.Name, err)
+		return
+	}
+
+	items := strings.Split(job.Name, Separator)
+	index := items[len(items)-1]
+
+	inputs := make([]artifact.Location, 0, len(task.InputArtifacts))
+	for _, input := range task.InputArtifacts {
+		source := strings.SplitN(input.From, Separator, 2)
+		inputs = append(inputs, artifact.Location{
+			Name: input.Name,
+			Path: input.Path,
+			Key:  artifact.Key(exec.Namespace, exec.Name, source[0], source[1], sourceIndex(exec, task, source[0], index)),
+		})
+	}
+
+	outputs := make([]artifact.Location, 0, len(task.OutputArtifacts))
+	for _, output := range task.OutputArtifacts {
+		outputs = append(outputs, artifact.Location{
+			Name: output.Name,
+			Path: output.Path,
+			Key:  artifact.Key(exec.Namespace, exec.Name, task.Name, output.Name, index),
+		})
+	}
+
+	artifact.Inject(&job.Spec.Template.Spec, repo, exec.Spec.ArtifactRepository.Image, inputs, outputs)
+}
+
+// sourceIndex returns the index of the job of the source task whose output
+// artifact is fetched. Empty means the artifacts of all the jobs are fetched.
+func sourceIndex(exec *genev1alpha1.Execution, task *genev1alpha1.Task, source string, index string) string {
+	for _, dependent := range task.Dependents {
+		if dependent.Target == source && dependent.Type == genev1alpha1.DependTypeIterate {
+			return index
+		}
+	}
+	if ok, sourceTask := getTaskByName(exec.Spec.Tasks, source); ok &&
+		sourceTask.CommandsIter == nil && len(sourceTask.CommandSet) == 1 {
+		return "0"
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestInjectArtifacts(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		ModifyFunc ModifyExecution
+		Task       int
+		JobName    string
+		ExpectKey  string
+	}{
+		{
+			Name: "whole dependent on a single job",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "a.out"}}
+			},
+			Task:      1,
+			JobName:   "simple-example.b.0",
+			ExpectKey: "exec-system/simple-example/a/out/0",
+		},
+		{
+			Name: "whole dependent on several jobs",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].CommandSet = []string{"echo A", "echo B"}
+				exec.Spec.Tasks[1].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "a.out"}}
+			},
+			Task:      1,
+			JobName:   "simple-example.b.0",
+			ExpectKey: "exec-system/simple-example/a/out/.'",
+		},
+		{
+			Name: "iterate dependent",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].CommandSet = []string{"echo A", "echo B"}
+				exec.Spec.Tasks[1].CommandSet = []string{"echo A", "echo B"}
+				exec.Spec.Tasks[1].Dependents[0].Type = genev1alpha1.DependTypeIterate
+				exec.Spec.Tasks[1].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "a.out"}}
+			},
+			Task:      1,
+			JobName:   "simple-example.b.1",
+			ExpectKey: "exec-system/simple-example/a/out/1",
+		},
+		{
+			Name:       "output artifact",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {},
+			Task:       0,
+			JobName:    "simple-example.a.0",
+			ExpectKey:  "exec-system/simple-example/a/out/0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+			Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+		}
+		exec.Spec.Tasks[0].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+		testCase.ModifyFunc(exec)
+		if err := ValidateExecution(exec); err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+			continue
+		}
+
+		task := &exec.Spec.Tasks[testCase.Task]
+		job := newJob(testCase.JobName, "echo hello", exec, task)
+		scripts := ""
+		for _, c := range job.Spec.Template.Spec.InitContainers {
+			scripts += c.Command[2]
+		}
+		for _, c := range job.Spec.Template.Spec.Containers[1:] {
+			scripts += c.Command[2]
+		}
+		if !strings.Contains(scripts, testCase.ExpectKey) {
+			t.Errorf("%s: Expect key %s in scripts, but got %q", testCase.Name, testCase.ExpectKey, scripts)
+		}
+	}
+}
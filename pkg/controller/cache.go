@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+)
+
+// cacheKeyAnnotation records the cache key on the jobs whose result is cached once they succeed.
+const cacheKeyAnnotation = "kubegene.io/cache-key"
+
+// cacheKeyVersion is part of every cache key, bump it when the content of the key changes.
+const cacheKeyVersion = "v5"
+
+// getVertexTask returns the task the vertex belongs to, or nil if the
+// vertex does not belong to a task of the execution.
+func getVertexTask(exec *genev1alpha1.Execution, vertex *graph.Vertex) *genev1alpha1.Task {
+	taskName := vertexTaskName(vertex)
+	if len(taskName) == 0 {
+		return nil
+	}
+	for i := range exec.Spec.Tasks {
+		if exec.Spec.Tasks[i].Name == taskName {
+			return &exec.Spec.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// vertexTaskName returns the name of the task the vertex belongs to. The
+// jobs of a task are named after the execution, the task and a suffix,
+// an empty name is returned for a job named otherwise.
+func vertexTaskName(vertex *graph.Vertex) string {
+	items := strings.Split(vertex.Data.Job.Name, Separator)
+	if len(items) < 2 {
+		return ""
+	}
+	return items[len(items)-2]
+}
+
+// cacheEnabled returns true if call caching is turned on for the task.
+func cacheEnabled(exec *genev1alpha1.Execution, task *genev1alpha1.Task) bool {
+	// the indexes of an Indexed Job all run, whether they are cached or not.
+	if task.Type == genev1alpha1.IndexedJobTaskType {
+		return false
+	}
+	if task.Cache != nil {
+		return *task.Cache
+	}
+	// the content of the volumes is only part of the key through the input
+	// digests, so the tasks reading volumes without them have to turn the
+	// cache on themselves.
+	if len(task.InputDigests) == 0 && readsVolumes(task) {
+		return false
+	}
+	return exec.Spec.Cache != nil && *exec.Spec.Cache
+}
+
+// readsVolumes returns true if the task mounts volumes whose content
+// outlives the job, i.e. volumes other than emptyDir and ephemeral ones.
+func readsVolumes(task *genev1alpha1.Task) bool {
+	for _, volume := range task.Volumes {
+		if volumeSourceKey(volume) != scratchVolumeKey {
+			return true
+		}
+	}
+	return false
+}
+
+// resultRead returns true if a task of the execution reads the result of
+// the task the vertex belongs to.
+func resultRead(exec *genev1alpha1.Execution, vertex *graph.Vertex) bool {
+	taskName := vertexTaskName(vertex)
+	for i := range exec.Spec.Tasks {
+		if readsResultOf(&exec.Spec.Tasks[i], taskName) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey returns the cache key of the vertex. The key is computed from the
+// image, the command, the environment, the init containers, the resources,
+// the volumes and the input digests of the job, together with the keys of the
+// vertices it depends on, whose results are its inputs.
+// An empty key means the result of the vertex can not be cached: the jobs of
+// dynamic vertices are only known at runtime, and the output artifacts of a
+// job are stored per execution.
+func cacheKey(exec *genev1alpha1.Execution, g *graph.Graph, vertex *graph.Vertex, keys map[*graph.Vertex]string) string {
+	if key, ok := keys[vertex]; ok {
+		return key
+	}
+	key := computeCacheKey(exec, g, vertex, keys)
+	keys[vertex] = key
+	return key
+}
+
+func computeCacheKey(exec *genev1alpha1.Execution, g *graph.Graph, vertex *graph.Vertex, keys map[*graph.Vertex]string) string {
+	if vertex.IsDynamic() {
+		return ""
+	}
+	task := getVertexTask(exec, vertex)
+	if task == nil || len(task.OutputArtifacts) != 0 {
+		return ""
+	}
+
+	parents := []string{}
+	for _, index := range g.FindDependentsByName(vertex.Data.Job.Name) {
+		parent := cacheKey(exec, g, g.FindVertex(index), keys)
+		if len(parent) == 0 {
+			return ""
+		}
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	volumes := []string{}
+	for _, volume := range task.Volumes {
+		volumes = append(volumes, volume.MountPath+"="+volumeSourceKey(volume))
+	}
+	sort.Strings(volumes)
+
+	inputs := []string{}
+	for _, input := range task.InputArtifacts {
+		inputs = append(inputs, input.Path+"="+input.From)
+	}
+	sort.Strings(inputs)
+
+	digests := append([]string{}, task.InputDigests...)
+	sort.Strings(digests)
+
+	container := vertex.Data.Job.Spec.Template.Spec.Containers[0]
+	env := []string{}
+	for _, e := range container.Env {
+		if e.ValueFrom != nil {
+			env = append(env, e.Name+"<-"+e.ValueFrom.String())
+			continue
+		}
+		env = append(env, e.Name+"="+e.Value)
+	}
+	envFrom := []string{}
+	for _, e := range container.EnvFrom {
+		envFrom = append(envFrom, e.String())
+	}
+	initContainers := []string{}
+	for _, c := range task.InitContainers {
+		initContainers = append(initContainers, c.Image, strings.Join(append(c.Command, c.Args...), " "))
+	}
+
+	h := sha256.New()
+	write := func(field string, values ...string) {
+		io.WriteString(h, fmt.Sprintf("%s:%d\n", field, len(values)))
+		for _, value := range values {
+			io.WriteString(h, fmt.Sprintf("%d:%s\n", len(value), value))
+		}
+	}
+	write("version", cacheKeyVersion)
+	write("type", string(task.Type))
+	write("image", container.Image)
+	write("command", container.Command...)
+	write("env", env...)
+	write("envFrom", envFrom...)
+	write("initContainers", initContainers...)
+	write("resources", task.Resources.Cpu.String(), task.Resources.Memory.String())
+	write("volumes", volumes...)
+	write("inputs", inputs...)
+	write("inputDigests", digests...)
+	write("parents", parents...)
+	return hex.EncodeToString(h.Sum(nil))
+}
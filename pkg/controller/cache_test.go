@@ -0,0 +1,266 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/jobcache"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+func getCacheKey(exec *genev1alpha1.Execution, jobName string) string {
+	g := newGraph(exec)
+	return cacheKey(exec, g, g.FindVertexByName(jobName), make(map[*graph.Vertex]string))
+}
+
+func TestCacheKey(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ModifyFunc  ModifyExecution
+		JobName     string
+		ExpectEqual bool
+		ExpectEmpty bool
+	}{
+		{
+			Name: "execution name does not change the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Name = "other-example"
+			},
+			JobName:     "b.0",
+			ExpectEqual: true,
+		},
+		{
+			Name: "command changes the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].CommandSet = []string{"echo BB >> /tmp/hostvolume/"}
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "image changes the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].Image = "hello-world:2"
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "resources change the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].Resources.Cpu = resource.MustParse("2")
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "env changes the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Env = []v1.EnvVar{{Name: "FOO", Value: "bar"}}
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "input digests change the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].InputDigests = []string{"sha256:abc"}
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "dependent input digests change the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].InputDigests = []string{"sha256:abc"}
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "dependent changes the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].CommandSet = []string{"echo AA >> /tmp/hostvolume/"}
+			},
+			JobName: "b.0",
+		},
+		{
+			Name: "task not depending on the changed task keeps the key",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].CommandSet = []string{"echo BB >> /tmp/hostvolume/"}
+			},
+			JobName:     "a.0",
+			ExpectEqual: true,
+		},
+		{
+			Name: "task with output artifacts can not be cached",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+			},
+			JobName:     "b.0",
+			ExpectEmpty: true,
+		},
+		{
+			Name: "task depending on a dynamic task can not be cached",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].CommandsIter = &genev1alpha1.CommandsIter{
+					Command:  "echo ${1}",
+					VarsIter: []interface{}{[]interface{}{"1", "2"}},
+				}
+			},
+			JobName:     "b.0",
+			ExpectEmpty: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		origin := getCacheKey(exec, exec.Name+Separator+testCase.JobName)
+		if len(origin) == 0 {
+			t.Errorf("%s: Expect key of the origin execution, but got empty", testCase.Name)
+			continue
+		}
+
+		testCase.ModifyFunc(exec)
+		key := getCacheKey(exec, exec.Name+Separator+testCase.JobName)
+		if testCase.ExpectEmpty {
+			if len(key) != 0 {
+				t.Errorf("%s: Expect empty key, but got %s", testCase.Name, key)
+			}
+			continue
+		}
+		if testCase.ExpectEqual && key != origin {
+			t.Errorf("%s: Expect key %s, but got %s", testCase.Name, origin, key)
+		}
+		if !testCase.ExpectEqual && key == origin {
+			t.Errorf("%s: Expect key other than %s", testCase.Name, origin)
+		}
+	}
+}
+
+func TestCacheEnabled(t *testing.T) {
+	enabled, disabled := true, false
+	testCases := []struct {
+		Name         string
+		ExecCache    *bool
+		TaskCache    *bool
+		InputDigests []string
+		Scratch      bool
+		Expect       bool
+	}{
+		{Name: "disabled by default", Expect: false},
+		{Name: "not enabled by execution without input digests", ExecCache: &enabled, Expect: false},
+		{Name: "enabled by execution with input digests", ExecCache: &enabled, InputDigests: []string{"sha256:abc"}, Expect: true},
+		{Name: "enabled by execution for scratch volumes", ExecCache: &enabled, Scratch: true, Expect: true},
+		{Name: "enabled by task", TaskCache: &enabled, Expect: true},
+		{Name: "task overrides execution", ExecCache: &enabled, TaskCache: &disabled, InputDigests: []string{"sha256:abc"}, Expect: false},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.Cache = testCase.ExecCache
+		exec.Spec.Tasks[0].Cache = testCase.TaskCache
+		exec.Spec.Tasks[0].InputDigests = testCase.InputDigests
+		if testCase.Scratch {
+			exec.Spec.Tasks[0].Volumes["volumea"] = genev1alpha1.Volume{
+				MountPath: "/tmp/hostvolume",
+				MountFrom: genev1alpha1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+			}
+		}
+		if got := cacheEnabled(exec, &exec.Spec.Tasks[0]); got != testCase.Expect {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, got)
+		}
+	}
+}
+
+func TestCachedResult(t *testing.T) {
+	done, other := "done", "other"
+	testCases := []struct {
+		Name string
+		// Result is the result of a recorded in the cache.
+		Result       *string
+		ExpectJobs   []string
+		ExpectPhases map[string]genev1alpha1.VertexPhase
+	}{
+		{
+			Name:       "cached result satisfies the condition",
+			Result:     &done,
+			ExpectJobs: []string{"cached.b.0"},
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"cached.a.0": genev1alpha1.VertexSucceeded,
+				"cached.b.0": genev1alpha1.VertexSucceeded,
+			},
+		},
+		{
+			Name:   "cached result does not satisfy the condition",
+			Result: &other,
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"cached.a.0": genev1alpha1.VertexSucceeded,
+				"cached.b.":  genev1alpha1.VertexSkipped,
+			},
+		},
+		{
+			Name:       "entry without the result runs the job",
+			ExpectJobs: []string{"cached.a.0"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		enabled := true
+		exec := &genev1alpha1.Execution{
+			ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "default", UID: "cached-uid"},
+			Spec: genev1alpha1.ExecutionSpec{
+				Cache: &enabled,
+				Tasks: []genev1alpha1.Task{
+					{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo done"}},
+					{
+						Name: "b", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo B"},
+						Dependents: []genev1alpha1.Dependent{{Target: "a", Type: genev1alpha1.DependTypeWhole}},
+						Condition:  &genev1alpha1.Condition{Condition: []interface{}{"check_result", "a", "done"}},
+					},
+				},
+			},
+		}
+		c := newTestExecutionController(exec)
+		entry := &jobcache.Entry{
+			Key:       getCacheKey(exec, "cached.a.0"),
+			Execution: "earlier",
+			Job:       "earlier.a.0",
+			CreatedAt: time.Now(),
+			Result:    testCase.Result,
+		}
+		if err := c.cacheStore.Put(exec.Namespace, entry); err != nil {
+			t.Fatalf("%s: put cache entry error: %v", testCase.Name, err)
+		}
+
+		jobs := c.run(t, exec)
+		if !reflect.DeepEqual(jobs, testCase.ExpectJobs) {
+			t.Errorf("%s: Expect jobs %v, but got %v", testCase.Name, testCase.ExpectJobs, jobs)
+		}
+
+		updated := c.execution(t, exec)
+		for name, phase := range testCase.ExpectPhases {
+			vertexStatus := util.GetVertexStatus(updated, name)
+			if vertexStatus == nil || vertexStatus.Phase != phase {
+				t.Errorf("%s: Expect vertex %s %s, but got %v", testCase.Name, name, phase, vertexStatus)
+			}
+		}
+	}
+}
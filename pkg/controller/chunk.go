@@ -0,0 +1,239 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+const (
+	// chunkVolumeName is the name of the emptyDir volume recording the exit
+	// code of every command of a chunk, it outlives the restarts of the task container.
+	chunkVolumeName = "kubegene-chunk"
+	chunkMountPath  = "/kubegene/chunk"
+
+	// maxChunkSize keeps the exit codes of a chunk within the termination message of a container.
+	maxChunkSize = 256
+)
+
+// chunkSize returns the number of commands run by each job of the task.
+func chunkSize(task *genev1alpha1.Task) int {
+	if task.ChunkSize == nil || *task.ChunkSize < 1 {
+		return 1
+	}
+	return int(*task.ChunkSize)
+}
+
+// newTaskJobs returns the jobs running the commands of the task. A job runs
+// a chunk of commands when the task has a chunk size, and is named after
+// the index of its first command.
+func newTaskJobs(jobNamePrefix string, exec *genev1alpha1.Execution, task *genev1alpha1.Task) []*workload.Workload {
+	size := chunkSize(task)
+	jobs := make([]*workload.Workload, 0, (len(task.CommandSet)+size-1)/size)
+	for first := 0; first < len(task.CommandSet); first += size {
+		jobName := jobNamePrefix + strconv.Itoa(first)
+		// make up k8s job resource
+		switch {
+		case task.Type == genev1alpha1.IndexedJobTaskType:
+			jobs = append(jobs, newIndexJob(jobName, exec, task))
+		case size > 1:
+			last := first + size
+			if last > len(task.CommandSet) {
+				last = len(task.CommandSet)
+			}
+			jobs = append(jobs, newChunkJob(jobName, task.CommandSet[first:last], first, exec, task))
+		default:
+			jobs = append(jobs, newJob(jobName, task.CommandSet[first], exec, task))
+		}
+	}
+	return jobs
+}
+
+// newChunkJob returns the job running the commands one after another, the
+// first of which is the command of index first of the task.
+func newChunkJob(name string, commands []string, first int, exec *genev1alpha1.Execution, task *genev1alpha1.Task) *workload.Workload {
+	job := newJob(name, chunkScript(commands, first), exec, task)
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         chunkVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      chunkVolumeName,
+		MountPath: chunkMountPath,
+	})
+	return job
+}
+
+// chunkScript runs all the commands, each in its own subshell, and records
+// the exit code of every command in the chunk volume. A restarted container
+// only runs the commands which have not succeeded yet. The exit codes are
+// written to the termination message as lines of "<index>=<exit code>", and
+// the script fails if any of the commands has failed.
+func chunkScript(commands []string, first int) string {
+	lines := make([]string, 0, 5*len(commands)+7)
+	indexes := make([]string, 0, len(commands))
+	for i, command := range commands {
+		codeFile := fmt.Sprintf("%s/%d", chunkMountPath, first+i)
+		lines = append(lines,
+			fmt.Sprintf(`if [ "$(cat %s 2>/dev/null)" != "0" ]; then`, codeFile),
+			"(",
+			command,
+			")",
+			fmt.Sprintf("echo $? > %s", codeFile),
+			"fi",
+		)
+		indexes = append(indexes, strconv.Itoa(first+i))
+	}
+	lines = append(lines,
+		"failed=0",
+		fmt.Sprintf(": > %s", v1.TerminationMessagePathDefault),
+		fmt.Sprintf("for i in %s; do", strings.Join(indexes, " ")),
+		fmt.Sprintf(`  code=$(cat %s/$i)`, chunkMountPath),
+		fmt.Sprintf(`  echo "$i=$code" >> %s`, v1.TerminationMessagePathDefault),
+		`  [ "$code" = "0" ] || failed=1`,
+		"done",
+		"exit $failed",
+	)
+	return strings.Join(lines, "\n")
+}
+
+// commandRange returns the indexes of the first command and of the one past
+// the last command run by the job of the task with the suffix.
+func commandRange(task *genev1alpha1.Task, suffix string) (int, int, bool) {
+	first, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0, 0, false
+	}
+	return first, first + chunkSize(task), true
+}
+
+// commandsOverlap returns true if the job of the task with the suffix runs
+// a command with the same index as a command run by the job of the target
+// task with the target suffix. This is how `iterate` dependents are matched
+// when the jobs run chunks of commands.
+func commandsOverlap(task *genev1alpha1.Task, suffix string, target *genev1alpha1.Task, targetSuffix string) bool {
+	first, last, ok := commandRange(task, suffix)
+	targetFirst, targetLast, targetOk := commandRange(target, targetSuffix)
+	if !ok || !targetOk {
+		return suffix == targetSuffix
+	}
+	return first < targetLast && targetFirst < last
+}
+
+// parseCommandStatuses parses the exit codes of the termination message of a chunk.
+func parseCommandStatuses(message string) []genev1alpha1.CommandStatus {
+	statuses := []genev1alpha1.CommandStatus{}
+	for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		code, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		statuses = append(statuses, genev1alpha1.CommandStatus{Index: int32(index), ExitCode: int32(code)})
+	}
+	return statuses
+}
+
+// getCommandStatuses returns the exit codes of the commands of the job
+// recorded by the last run of its task container.
+func getCommandStatuses(kubeClient clientset.Interface, job *workload.Workload) ([]genev1alpha1.CommandStatus, error) {
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClient.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	containerName := job.Spec.Template.Spec.Containers[0].Name
+
+	var last *v1.ContainerStateTerminated
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != containerName {
+				continue
+			}
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated != nil && (last == nil || last.FinishedAt.Before(&terminated.FinishedAt)) {
+				last = terminated
+			}
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("no container of job %s/%s has terminated", job.Namespace, job.Name)
+	}
+	return parseCommandStatuses(last.Message), nil
+}
+
+// recordCommandStatuses records the exit codes of the commands of the job in
+// the status of its vertex when the job runs a chunk of commands. A failure
+// only leaves them out of the status, so it is not retried.
+func (c *ExecutionController) recordCommandStatuses(exec *genev1alpha1.Execution, vertex *graph.Vertex, job *workload.Workload) {
+	task := getVertexTask(exec, vertex)
+	if task == nil || chunkSize(task) == 1 {
+		return
+	}
+	statuses, err := getCommandStatuses(c.kubeClient, job)
+	if err != nil {
+		klog.Errorf("get command statuses of job %s error: %v", util.KeyOf(job), err)
+		return
+	}
+	vertexStatus := util.GetVertexStatus(exec, job.Name)
+	vertexStatus.Commands = statuses
+	exec.Status.Vertices[vertexStatus.ID] = *vertexStatus
+}
+
+// validateChunkSize checks that the commands of the task can run in chunks.
+func validateChunkSize(task genev1alpha1.Task) error {
+	if task.ChunkSize == nil {
+		return nil
+	}
+	if *task.ChunkSize < 1 || *task.ChunkSize > maxChunkSize {
+		return fmt.Errorf("task %s: chunkSize must be between 1 and %d", task.Name, maxChunkSize)
+	}
+	if task.Type != genev1alpha1.JobTaskType && task.Type != genev1alpha1.SparkTaskType {
+		return fmt.Errorf("task %s: chunkSize can only be specified for tasks of type Job or Spark", task.Name)
+	}
+	if len(task.InputArtifacts) != 0 || len(task.OutputArtifacts) != 0 {
+		return fmt.Errorf("task %s: the jobs of a task with a chunkSize can not have artifacts", task.Name)
+	}
+	return nil
+}
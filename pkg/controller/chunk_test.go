@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestNewGraphChunks(t *testing.T) {
+	chunk := int32(2)
+	exec := validateExecution()
+	exec.Spec.Tasks = exec.Spec.Tasks[:2]
+	exec.Spec.Tasks[0].CommandSet = []string{"echo 0", "echo 1", "echo 2", "echo 3", "echo 4"}
+	exec.Spec.Tasks[0].ChunkSize = &chunk
+	exec.Spec.Tasks[1].CommandSet = []string{"echo 0", "echo 1", "echo 2", "echo 3", "echo 4"}
+	exec.Spec.Tasks[1].Dependents = []genev1alpha1.Dependent{{Target: "a", Type: genev1alpha1.DependTypeIterate}}
+
+	g := newGraph(exec)
+
+	testCases := []struct {
+		JobName        string
+		ExpectChildren []string
+	}{
+		{
+			JobName:        "a.0",
+			ExpectChildren: []string{"b.0", "b.1"},
+		},
+		{
+			JobName:        "a.2",
+			ExpectChildren: []string{"b.2", "b.3"},
+		},
+		{
+			JobName:        "a.4",
+			ExpectChildren: []string{"b.4"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		vertex := g.FindVertexByName(exec.Name + Separator + testCase.JobName)
+		if vertex == nil {
+			t.Errorf("%s: Expect vertex, but got nil", testCase.JobName)
+			continue
+		}
+		children := []string{}
+		for _, child := range vertex.Children {
+			children = append(children, strings.TrimPrefix(child.Data.Job.Name, exec.Name+Separator))
+		}
+		if !reflect.DeepEqual(children, testCase.ExpectChildren) {
+			t.Errorf("%s: Expect children %v, but got %v", testCase.JobName, testCase.ExpectChildren, children)
+		}
+	}
+	if vertex := g.FindVertexByName(exec.Name + Separator + "a.1"); vertex != nil {
+		t.Errorf("Expect no job for the second command of a chunk, but got %s", vertex.Data.Job.Name)
+	}
+}
+
+func TestNewChunkJob(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	job := newChunkJob("a.4", []string{"echo 4", "exit 3"}, 4, exec, task)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	script := container.Command[2]
+	for _, expect := range []string{
+		"echo 4\n)\necho $? > " + chunkMountPath + "/4",
+		"exit 3\n)\necho $? > " + chunkMountPath + "/5",
+		"for i in 4 5; do",
+	} {
+		if !strings.Contains(script, expect) {
+			t.Errorf("Expect script to contain %q, but got %q", expect, script)
+		}
+	}
+	mounted := false
+	for _, mount := range container.VolumeMounts {
+		if mount.Name == chunkVolumeName && mount.MountPath == chunkMountPath {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("Expect chunk volume mounted in the task container, but got %v", container.VolumeMounts)
+	}
+}
+
+func TestCommandsOverlap(t *testing.T) {
+	two, three := int32(2), int32(3)
+	testCases := []struct {
+		Name         string
+		Chunk        *int32
+		Suffix       string
+		TargetChunk  *int32
+		TargetSuffix string
+		Expect       bool
+	}{
+		{
+			Name:         "same index",
+			Suffix:       "1",
+			TargetSuffix: "1",
+			Expect:       true,
+		},
+		{
+			Name:         "other index",
+			Suffix:       "1",
+			TargetSuffix: "2",
+			Expect:       false,
+		},
+		{
+			Name:         "index in the chunk of the target",
+			Suffix:       "3",
+			TargetChunk:  &two,
+			TargetSuffix: "2",
+			Expect:       true,
+		},
+		{
+			Name:         "chunks sharing an index",
+			Chunk:        &three,
+			Suffix:       "3",
+			TargetChunk:  &two,
+			TargetSuffix: "4",
+			Expect:       true,
+		},
+		{
+			Name:         "chunks next to each other",
+			Chunk:        &three,
+			Suffix:       "0",
+			TargetChunk:  &three,
+			TargetSuffix: "3",
+			Expect:       false,
+		},
+		{
+			Name:         "dynamic jobs",
+			Suffix:       "",
+			TargetSuffix: "",
+			Expect:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		task := &genev1alpha1.Task{ChunkSize: testCase.Chunk}
+		target := &genev1alpha1.Task{ChunkSize: testCase.TargetChunk}
+		if overlap := commandsOverlap(task, testCase.Suffix, target, testCase.TargetSuffix); overlap != testCase.Expect {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, overlap)
+		}
+	}
+}
+
+func TestGetCommandStatuses(t *testing.T) {
+	exec := validateExecution()
+	job := newChunkJob("a.0", []string{"echo 0", "echo 1"}, 0, exec, &exec.Spec.Tasks[0])
+	job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": job.Name}}
+	containerName := job.Spec.Template.Spec.Containers[0].Name
+
+	now := time.Now()
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-0-x", Namespace: job.Namespace, Labels: map[string]string{"job-name": job.Name}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+			Name: containerName,
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				FinishedAt: metav1.NewTime(now), Message: "0=0\n1=0\n",
+			}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				FinishedAt: metav1.NewTime(now.Add(-time.Minute)), Message: "0=0\n1=2\n",
+			}},
+		}}},
+	}
+
+	statuses, err := getCommandStatuses(fake.NewSimpleClientset(pod), job)
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	expect := []genev1alpha1.CommandStatus{{Index: 0, ExitCode: 0}, {Index: 1, ExitCode: 0}}
+	if !reflect.DeepEqual(statuses, expect) {
+		t.Errorf("Expect statuses %v, but got %v", expect, statuses)
+	}
+
+	if _, err := getCommandStatuses(fake.NewSimpleClientset(), job); err == nil {
+		t.Errorf("Expect error for a job without terminated container, but got nil")
+	}
+}
@@ -21,6 +21,24 @@ const (
 	UpdateRetries           = 3
 	executionSuccessMessage = "execution has run successfully"
 	executionRunningMessage = "execution is running"
+	executionQueuedMessage  = "execution is queued, waiting for running executions to finish"
 	missVertexMessage       = "execution is running but can not find vertex in the graph"
 	vertexRunningMessage    = "vertex is running"
+
+	vertexSkippedMessage            = "vertex is skipped because its condition is not satisfied"
+	vertexSkippedByDependentMessage = "vertex is skipped because a vertex it depends on is skipped"
+
+	vertexCachedMessage = "cached: the same work has succeeded in job %s of execution %s"
+
+	executorMissingMessage = "tasks of type %s can not run, no executor is enabled for the type"
+
+	verifyingOutputsMessage = "all vertices have finished, verifying outputs"
+	missingOutputsMessage   = "outputs are missing: %s"
+
+	podStuckMarker        = " is stuck: "
+	podStuckMessage       = "pod %s" + podStuckMarker + "%s"
+	podStuckFailedMessage = "pod %s has been stuck for more than %v: %s"
+
+	memoryEscalatedMessage = "pod %s ran out of memory, starting again with memory %s"
+	podDisruptedMessage    = "pod %s has been disrupted by %s, retrying it without counting the attempt"
 )
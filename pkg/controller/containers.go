@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/artifact"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+const (
+	// statusVolumeName is the name of the emptyDir volume through which the
+	// sidecars learn that the task command has finished.
+	statusVolumeName = "kubegene-status"
+	statusMountPath  = "/kubegene/status"
+	// exitCodeFile records the exit code of the task command.
+	exitCodeFile = statusMountPath + "/exitcode"
+
+	// reservedContainerPrefix prefixes the names of the containers added by the controller.
+	reservedContainerPrefix = "kubegene-"
+)
+
+// sidecarScript returns the script running the command of a sidecar in the
+// background until the task command has finished, see artifact.CommandFinished.
+// A task container restarted on failure runs the command again, so the sidecar
+// keeps running until a run succeeds. Otherwise the sidecar stops once the
+// command has exited, so that the pod terminates when the command fails.
+func sidecarScript(restartPolicy v1.RestartPolicy) string {
+	return strings.Join([]string{
+		`"$@" &`,
+		`pid=$!`,
+		fmt.Sprintf(`until %s; do`, artifact.CommandFinished(exitCodeFile, restartPolicy)),
+		`  if ! kill -0 $pid 2>/dev/null; then wait $pid; exit $?; fi`,
+		`  sleep 1`,
+		`done`,
+		`kill $pid 2>/dev/null`,
+		`wait $pid`,
+		`exit 0`,
+	}, "\n")
+}
+
+// injectContainers adds the init containers and the sidecars of the task to
+// the job. The task volumes are mounted in the containers which do not specify
+// volumeMounts, and the command of the task container is wrapped to record its
+// exit code for the sidecars.
+func injectContainers(job *workload.Workload, task *genev1alpha1.Task) {
+	if len(task.InitContainers) == 0 && len(task.Sidecars) == 0 {
+		return
+	}
+	podSpec := &job.Spec.Template.Spec
+	taskMounts := make([]v1.VolumeMount, 0, len(task.Volumes))
+	for _, mount := range podSpec.Containers[0].VolumeMounts {
+		if _, ok := task.Volumes[mount.Name]; ok {
+			taskMounts = append(taskMounts, mount)
+		}
+	}
+
+	initContainers := make([]v1.Container, 0, len(task.InitContainers)+len(podSpec.InitContainers))
+	for _, c := range task.InitContainers {
+		container := *c.DeepCopy()
+		if len(container.VolumeMounts) == 0 {
+			container.VolumeMounts = append(container.VolumeMounts, taskMounts...)
+		}
+		initContainers = append(initContainers, container)
+	}
+	// the containers of the task stage the data before the artifacts are loaded.
+	podSpec.InitContainers = append(initContainers, podSpec.InitContainers...)
+
+	if len(task.Sidecars) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         statusVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	statusMount := v1.VolumeMount{Name: statusVolumeName, MountPath: statusMountPath}
+
+	for _, c := range task.Sidecars {
+		container := *c.DeepCopy()
+		if len(container.VolumeMounts) == 0 {
+			container.VolumeMounts = append(container.VolumeMounts, taskMounts...)
+		}
+		container.VolumeMounts = append(container.VolumeMounts, statusMount)
+		command := append([]string{"sh", "-c", sidecarScript(podSpec.RestartPolicy), container.Name}, container.Command...)
+		container.Command = append(command, container.Args...)
+		container.Args = nil
+		podSpec.Containers = append(podSpec.Containers, container)
+	}
+
+	main := &podSpec.Containers[0]
+	main.VolumeMounts = append(main.VolumeMounts, statusMount)
+	if len(main.Command) == 3 {
+		main.Command[2] = fmt.Sprintf("(\n%s\n)\nrc=$?\necho $rc > %s\nexit $rc", main.Command[2], exitCodeFile)
+	}
+}
+
+func validateContainers(task genev1alpha1.Task) error {
+	names := map[string]struct{}{}
+	validate := func(field string, c v1.Container) error {
+		if msgs := validation.IsDNS1123Label(c.Name); len(msgs) > 0 {
+			return fmt.Errorf("task %s: name of %s %s is not valid %v", task.Name, field, c.Name, msgs)
+		}
+		if strings.HasPrefix(c.Name, reservedContainerPrefix) {
+			return fmt.Errorf("task %s: name of %s %s must not start with %s", task.Name, field, c.Name, reservedContainerPrefix)
+		}
+		if _, exist := names[c.Name]; exist {
+			return fmt.Errorf("task %s: container name %s duplicated", task.Name, c.Name)
+		}
+		names[c.Name] = struct{}{}
+		if len(c.Image) == 0 {
+			return fmt.Errorf("task %s: image of %s %s must not be empty", task.Name, field, c.Name)
+		}
+		for _, mount := range c.VolumeMounts {
+			if _, ok := task.Volumes[mount.Name]; !ok {
+				return fmt.Errorf("task %s: volume %s mounted in %s %s is not a volume of the task", task.Name, mount.Name, field, c.Name)
+			}
+		}
+		return nil
+	}
+
+	for _, c := range task.InitContainers {
+		if err := validate("initContainer", c); err != nil {
+			return err
+		}
+	}
+	for _, c := range task.Sidecars {
+		if err := validate("sidecar", c); err != nil {
+			return err
+		}
+		if len(c.Command) == 0 {
+			return fmt.Errorf("task %s: command of sidecar %s must be specified", task.Name, c.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+func TestInjectContainers(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.InitContainers = []v1.Container{
+		{Name: "fetch-ref", Image: "busybox", Command: []string{"sh", "-c", "wget -O- http://ref | tar -xz -C /tmp/hostvolume"}},
+	}
+	task.Sidecars = []v1.Container{
+		{Name: "log-shipper", Image: "busybox", Command: []string{"tail"}, Args: []string{"-f", "/tmp/hostvolume/log"}},
+	}
+
+	job := newJob("example.a.0", "echo A", exec, task)
+	podSpec := job.Spec.Template.Spec
+
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != "fetch-ref" {
+		t.Fatalf("Expect init container fetch-ref, but got %v", podSpec.InitContainers)
+	}
+	expectMounts := []v1.VolumeMount{{Name: "volumea", MountPath: "/tmp/hostvolume"}}
+	if mounts := podSpec.InitContainers[0].VolumeMounts; !reflect.DeepEqual(mounts, expectMounts) {
+		t.Errorf("Expect init container to mount the task volumes %v, but got %v", expectMounts, mounts)
+	}
+
+	if len(podSpec.Containers) != 2 {
+		t.Fatalf("Expect 2 containers, but got %d", len(podSpec.Containers))
+	}
+	sidecar := podSpec.Containers[1]
+	command := sidecar.Command
+	if command[2] != sidecarScript(v1.RestartPolicyOnFailure) || !reflect.DeepEqual(command[4:], []string{"tail", "-f", "/tmp/hostvolume/log"}) {
+		t.Errorf("Expect wrapped sidecar command, but got %v", command)
+	}
+	if len(sidecar.Args) != 0 {
+		t.Errorf("Expect args merged into the command, but got %v", sidecar.Args)
+	}
+	expectMounts = append(expectMounts, v1.VolumeMount{Name: statusVolumeName, MountPath: statusMountPath})
+	if !reflect.DeepEqual(sidecar.VolumeMounts, expectMounts) {
+		t.Errorf("Expect sidecar mounts %v, but got %v", expectMounts, sidecar.VolumeMounts)
+	}
+
+	main := podSpec.Containers[0]
+	if !strings.Contains(main.Command[2], "echo $rc > "+exitCodeFile) {
+		t.Errorf("Expect task command recording the exit code, but got %s", main.Command[2])
+	}
+	// the spec of the task is not modified.
+	if len(task.Sidecars[0].Args) != 2 || len(task.Sidecars[0].VolumeMounts) != 0 {
+		t.Errorf("Expect sidecar of the task unchanged, but got %v", task.Sidecars[0])
+	}
+}
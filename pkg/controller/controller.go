@@ -18,16 +18,22 @@ package controller
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	batch "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	batchinformers "k8s.io/client-go/informers/batch/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
-	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -37,8 +43,13 @@ import (
 	geneclientset "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1"
 	geneinformers "kubegene.io/kubegene/pkg/client/informers/externalversions/gene/v1alpha1"
 	genelisters "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/jobcache"
 	"kubegene.io/kubegene/pkg/util"
 	"kubegene.io/kubegene/pkg/version"
+	"kubegene.io/kubegene/pkg/volcano"
+	"kubegene.io/kubegene/pkg/workload"
 )
 
 // controllerKind contains the schema.GroupVersionKind for this controller type.
@@ -51,6 +62,50 @@ type ControllerParameters struct {
 	ExecutionClient   geneclientset.ExecutionsGetter
 	JobInformer       batchinformers.JobInformer
 	ExecutionInformer geneinformers.ExecutionInformer
+	// CacheStore records the jobs which have succeeded for call caching.
+	// Defaults to a store keeping the records in configmaps.
+	CacheStore jobcache.Store
+	// DynamicClient creates the Volcano Jobs and the Indexed Jobs, required
+	// with VolcanoJobInformer or IndexedJobInformer.
+	DynamicClient dynamic.Interface
+	// VolcanoJobInformer watches the Volcano Jobs running the tasks of type
+	// VolcanoJob. Those tasks can not run if it is nil.
+	VolcanoJobInformer informers.GenericInformer
+	// PodInformer watches the pods running the tasks of type Pod.
+	// Those tasks can not run if it is nil.
+	PodInformer coreinformers.PodInformer
+	// IndexedJobInformer watches the batch Jobs labeled as Indexed Jobs running
+	// the tasks of type IndexedJob. Those tasks can not run if it is nil.
+	IndexedJobInformer informers.GenericInformer
+	// Admission limits the executions running, shared by the controllers of
+	// all the namespaces watched. Defaults to an admission of the executions
+	// of this controller alone, with the limits below.
+	Admission *Admission
+	// MaxRunningExecutions is the max number of executions running in the
+	// cluster, the others are queued. 0 means no limit.
+	MaxRunningExecutions int
+	// MaxRunningExecutionsPerNamespace is the max number of executions
+	// running in a namespace, the others are queued. 0 means no limit.
+	MaxRunningExecutionsPerNamespace int
+	// LaunchOrder orders the jobs ready to start at the same time.
+	// Defaults to FIFO.
+	LaunchOrder LaunchOrder
+	// JobPodInformer watches the pods labeled with the uid of an execution to
+	// find the pods which can not run or run out of memory. The pods are not
+	// watched if it is nil.
+	JobPodInformer coreinformers.PodInformer
+	// StuckPodGracePeriod is how long a pod may be stuck before its vertex
+	// fails, 0 means the vertices never fail for stuck pods.
+	StuckPodGracePeriod time.Duration
+	// Sharder tells the executions this replica owns when several replicas
+	// share the executions. This replica owns every execution if it is nil.
+	Sharder Sharder
+}
+
+// Sharder tells whether this replica owns an execution.
+type Sharder interface {
+	// Owns returns true if this replica owns the execution of the key.
+	Owns(key string) bool
 }
 
 type ExecutionController struct {
@@ -66,8 +121,11 @@ type ExecutionController struct {
 	execLister genelisters.ExecutionLister
 	execSynced cache.InformerSynced
 
-	jobLister batchv1listers.JobLister
-	jobSynced cache.InformerSynced
+	// jobLister lists the workloads of all the executors.
+	jobLister unionJobLister
+	jobSynced []cache.InformerSynced
+	// executors run the jobs of the tasks, keyed by task type.
+	executors map[genev1alpha1.TaskType]TaskExecutor
 
 	execQueue  workqueue.RateLimitingInterface
 	jobQueue   workqueue.RateLimitingInterface
@@ -81,6 +139,22 @@ type ExecutionController struct {
 	execJobController *ExecutionJobController
 
 	execStatusUpdater ExecutionUpdater
+
+	cacheStore jobcache.Store
+
+	// admission queues the executions beyond the limits of running executions.
+	admission *Admission
+
+	// podLister lists the pods running the jobs, nil if the pods are not watched.
+	podLister           corelisters.PodLister
+	podSynced           cache.InformerSynced
+	podQueue            workqueue.RateLimitingInterface
+	stuckPodGracePeriod time.Duration
+	// restartedPods are the uids of the disrupted pods whose job has been started again.
+	restartedPods *utilcache.LRUExpireCache
+
+	// sharder tells the executions this replica owns, nil if it owns all of them.
+	sharder Sharder
 }
 
 func NewExecutionController(p *ControllerParameters) *ExecutionController {
@@ -90,38 +164,144 @@ func NewExecutionController(p *ControllerParameters) *ExecutionController {
 		execClient:    p.ExecutionClient,
 		execLister:    p.ExecutionInformer.Lister(),
 		execSynced:    p.ExecutionInformer.Informer().HasSynced,
-		jobLister:     p.JobInformer.Lister(),
-		jobSynced:     p.JobInformer.Informer().HasSynced,
+		executors:     make(map[genev1alpha1.TaskType]TaskExecutor),
 		execQueue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "execution"),
 		jobQueue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "execution-job"),
 		eventQueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "job-event"),
+		cacheStore:    p.CacheStore,
+		admission:     p.Admission,
+		sharder:       p.Sharder,
+	}
+	if controller.admission == nil {
+		controller.admission = NewAdmission(p.MaxRunningExecutions, p.MaxRunningExecutionsPerNamespace)
+	}
+	controller.admission.addMember(controller.execLister, controller.execQueue)
+	if controller.cacheStore == nil {
+		controller.cacheStore = jobcache.NewConfigMapStore(p.KubeClient)
 	}
 
 	p.ExecutionInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    func(obj interface{}) { controller.enqueueObj(controller.execQueue, obj) },
+			UpdateFunc: controller.updateExecution,
 			DeleteFunc: func(obj interface{}) { controller.enqueueObj(controller.execQueue, obj) },
 		},
 	)
 
-	p.JobInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc:    controller.addJob,
-			UpdateFunc: controller.updateJob,
-			DeleteFunc: controller.deleteJob,
-		},
-	)
+	// the tasks of type Spark run as batch Jobs as well.
+	controller.addExecutor(&batchJobExecutor{kubeClient: p.KubeClient}, workload.NewBatchLister(p.JobInformer.Lister(), isPlainBatchJob),
+		p.JobInformer.Informer(), oneJob(batchJob), genev1alpha1.JobTaskType, genev1alpha1.SparkTaskType)
+	if p.VolcanoJobInformer != nil {
+		controller.addExecutor(&volcanoExecutor{kubeClient: p.KubeClient, dynamicClient: p.DynamicClient},
+			volcano.NewJobLister(p.VolcanoJobInformer.Lister()), p.VolcanoJobInformer.Informer(),
+			oneJob(volcanoWorkload), genev1alpha1.VolcanoJobTaskType)
+	}
+	if p.PodInformer != nil {
+		controller.addExecutor(&podExecutor{kubeClient: p.KubeClient}, &podJobLister{lister: p.PodInformer.Lister()},
+			p.PodInformer.Informer(), oneJob(executionPodWorkload), genev1alpha1.PodTaskType)
+	}
+	if p.IndexedJobInformer != nil {
+		controller.addExecutor(&indexedJobExecutor{kubeClient: p.KubeClient, dynamicClient: p.DynamicClient},
+			indexedjob.NewJobLister(p.IndexedJobInformer.Lister()), p.IndexedJobInformer.Informer(),
+			indexWorkloads, genev1alpha1.IndexedJobTaskType)
+	}
+
+	if p.JobPodInformer != nil {
+		controller.podLister = p.JobPodInformer.Lister()
+		controller.podSynced = p.JobPodInformer.Informer().HasSynced
+		controller.podQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "execution-pod")
+		controller.restartedPods = utilcache.NewLRUExpireCache(maxRestartedPods)
+		controller.stuckPodGracePeriod = p.StuckPodGracePeriod
+		p.JobPodInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    controller.addPod,
+				UpdateFunc: controller.updatePod,
+			},
+		)
+	}
 
 	controller.syncJobHandler = controller.syncJob
 	controller.syncExecHandler = controller.syncExecution
 	controller.execGraphBuilder = NewGraphBuilder()
 	controller.execStatusUpdater = NewExecutionStatusUpdater(p.ExecutionClient)
-	controller.execJobController = NewExecutionJobController(p.KubeClient, controller.jobLister, controller.execLister,
-		controller.eventQueue, controller.execGraphBuilder, controller.execStatusUpdater)
+	controller.execJobController = NewExecutionJobController(p.KubeClient, controller.executors, controller.jobLister, controller.execLister,
+		controller.eventQueue, controller.execGraphBuilder, controller.execStatusUpdater, controller.cacheStore, p.LaunchOrder)
 
 	return controller
 }
 
+// addExecutor has the executor run the tasks of the types. The workloads of
+// the executor are listed by lister and watched through informer, toJobs
+// returns the workloads the object of an event stands for, if any.
+func (c *ExecutionController) addExecutor(executor TaskExecutor, lister workload.Lister,
+	informer cache.SharedIndexInformer, toJobs func(obj interface{}) []*workload.Workload, taskTypes ...genev1alpha1.TaskType) {
+	for _, taskType := range taskTypes {
+		c.executors[taskType] = executor
+	}
+	c.jobLister = append(c.jobLister, lister)
+	c.jobSynced = append(c.jobSynced, informer.HasSynced)
+	informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				for _, job := range toJobs(obj) {
+					c.addJob(job)
+				}
+			},
+			UpdateFunc: func(old, cur interface{}) {
+				oldJobs := make(map[string]*workload.Workload)
+				for _, job := range toJobs(old) {
+					oldJobs[job.Name] = job
+				}
+				for _, curJob := range toJobs(cur) {
+					if oldJob, ok := oldJobs[curJob.Name]; ok {
+						c.updateJob(oldJob, curJob)
+					} else {
+						c.addJob(curJob)
+					}
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				for _, job := range toJobs(obj) {
+					c.deleteJob(job)
+				}
+			},
+		},
+	)
+}
+
+// oneJob returns toJobs of the objects running one workload each.
+func oneJob(toJob func(obj interface{}) *workload.Workload) func(obj interface{}) []*workload.Workload {
+	return func(obj interface{}) []*workload.Workload {
+		if job := toJob(obj); job != nil {
+			return []*workload.Workload{job}
+		}
+		return nil
+	}
+}
+
+// batchJob returns the workload the batch Job of an informer event runs.
+// The Indexed Jobs are watched through their indexes instead.
+func batchJob(obj interface{}) *workload.Workload {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	job, ok := obj.(*batch.Job)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("object %T is not a job", obj))
+		return nil
+	}
+	if !isPlainBatchJob(job) {
+		return nil
+	}
+	return workload.FromBatchJob(job)
+}
+
+// isPlainBatchJob returns true if the batch Job runs one workload, rather
+// than the indexes of an Indexed Job.
+func isPlainBatchJob(job *batch.Job) bool {
+	return !indexedjob.IsIndexedJob(job)
+}
+
 // Run the main goroutine responsible for watching and syncing executions.
 func (c *ExecutionController) Run(workers int, stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
@@ -131,7 +311,12 @@ func (c *ExecutionController) Run(workers int, stopCh <-chan struct{}) {
 	klog.Infof("Starting execution controller with version %s", version.GetVersion())
 	defer klog.Infof("Shutting down execution controller")
 
-	if !cache.WaitForCacheSync(stopCh, c.execSynced, c.jobSynced) {
+	cacheSyncs := append([]cache.InformerSynced{c.execSynced}, c.jobSynced...)
+	if c.podLister != nil {
+		defer c.podQueue.ShutDown()
+		cacheSyncs = append(cacheSyncs, c.podSynced)
+	}
+	if !cache.WaitForCacheSync(stopCh, cacheSyncs...) {
 		klog.Errorf("Cannot sync caches")
 		return
 	}
@@ -142,6 +327,9 @@ func (c *ExecutionController) Run(workers int, stopCh <-chan struct{}) {
 	for i := 0; i < workers; i++ {
 		go wait.Until(c.execWorker, time.Second, stopCh)
 		go wait.Until(c.jobWorker, time.Second, stopCh)
+		if c.podLister != nil {
+			go wait.Until(c.podWorker, time.Second, stopCh)
+		}
 	}
 
 	<-stopCh
@@ -212,7 +400,7 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 		return false, fmt.Errorf("invalid job key %q: either namespace or name is missing", key)
 	}
 
-	sharedJob, err := c.jobLister.Jobs(ns).Get(name)
+	sharedJob, err := c.jobLister.Workloads(ns).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			klog.V(4).Infof("Job has been deleted: %v", key)
@@ -242,8 +430,20 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 	if util.IsExecutionCompleted(exec) {
 		return true, nil
 	}
+	// Another replica syncs the jobs of the execution.
+	if !c.owns(util.KeyOf(exec)) {
+		return true, nil
+	}
+
+	if isVerifyOutputsJob(job) {
+		return c.syncVerifyOutputsJob(job, exec, sharedExec)
+	}
 
 	graph := c.execGraphBuilder.GetGraph(util.KeyOf(exec))
+	if graph == nil && c.sharder != nil {
+		// The execution has just moved to this replica, wait for its graph.
+		return false, fmt.Errorf("graph of execution %s is not built yet", util.KeyOf(exec))
+	}
 	if graph == nil {
 		// The execution has been running but the graph has been deleted.
 		util.MarkExecutionError(exec, fmt.Errorf("graph of execution %s do not exist", util.KeyOf(exec)))
@@ -272,8 +472,18 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 		return true, nil
 	}
 
-	// get job condition
-	jobConditionType, message := util.GetJobCondition(job)
+	executor, err := c.execJobController.executorFor(vertex.Data.TaskType)
+	if err != nil {
+		util.MarkExecutionError(exec, err)
+		if err = c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+			klog.V(3).Infof("update execution %s status error: %#v", key, err)
+			return false, err
+		}
+		return true, nil
+	}
+
+	// get job phase
+	phase, message := executor.Status(job)
 
 	// in case missing the running event, following status set will cause panic
 	if util.GetVertexStatus(exec, job.Name) == nil {
@@ -284,10 +494,19 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 		exec.Status.Vertices[vertexStatus.ID] = vertexStatus
 	}
 
-	switch jobConditionType {
-	case batch.JobFailed:
+	switch phase {
+	case workload.Failed:
+		// the job failed on the attempt of a disrupted pod, which does not count.
+		if pod := c.uncountedDisruption(job); pod != nil {
+			if err := c.restartDisruptedJob(exec, sharedExec, job, pod, podDisruption(pod)); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+
 		// Job is failed, mark the vertex as failed.
 		util.MarkVertexFailed(exec, job.Name, message)
+		c.recordCommandStatuses(exec, vertex, job)
 
 		// Job is failed, the execution is marked failed and will not retry.
 		util.MarkExecutionFailed(exec, message)
@@ -300,7 +519,7 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 
 		return true, nil
 
-	case batch.JobComplete:
+	case workload.Succeeded:
 		// if vertex is dynamic just increment the succ count
 		// if success count == to dynamic job count then made the
 		// vertex finished flag tru so that then only other depend jobs can start
@@ -319,11 +538,24 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 			message = "success"
 		}
 		util.MarkVertexSuccess(exec, job.Name, message)
+		c.recordCommandStatuses(exec, vertex, job)
+		c.recordCache(job, exec, vertex, executor)
+		if task := getVertexTask(exec, vertex); task != nil {
+			c.execJobController.durations.record(exec.Namespace, task, job)
+			// a failure only keeps the resources until the execution is deleted.
+			if err := executor.Cleanup(job, task); err != nil {
+				klog.Errorf("cleanup job %s error: %v", key, err)
+			}
+		}
 		// The number of successful vertex plus 1.
 		graph.PlusNumOfSuccess()
-		if graph.GetNumOfSuccess() == (graph.VertexCount + graph.DynamicJobCnt) {
-			// All of the vertex has been successful, then mark the execution as successful.
-			util.MarkExecutionSuccess(exec, executionSuccessMessage)
+		setExecutionProgress(exec, graph, 0)
+		if graph.IsCompleted() {
+			// All of the vertex has been successful or skipped, then complete the execution.
+			if err = completeExecution(c.kubeClient, exec); err != nil {
+				klog.V(3).Infof("complete execution %s error: %v", util.KeyOf(exec), err)
+				return false, err
+			}
 		}
 
 		// Ask api server to update etcd data.
@@ -332,7 +564,10 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 			return false, err
 		}
 
-		if graph.GetNumOfSuccess() != (graph.VertexCount + graph.DynamicJobCnt) {
+		if !graph.IsCompleted() {
+			// the pods waiting for room under the limits of the execution may start.
+			c.execJobController.scaleIndexedJobs(exec, graph)
+
 			// if vertex is dynamic we can add JobAfterEvent once completing all the
 			// k8s jobs related to the dynamic job
 			if vertex.IsDynamic() {
@@ -369,6 +604,7 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 		// this execution, then mark the phase of execution running.
 		if len(exec.Status.Phase) == 0 {
 			util.MarkExecutionRunning(exec, executionRunningMessage)
+			setExecutionProgress(exec, graph, 0)
 		}
 		// Ask api server to update etcd data.
 		if err = c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
@@ -380,6 +616,68 @@ func (c *ExecutionController) syncJob(key string) (bool, error) {
 	}
 }
 
+// recordCache records the succeeded job in the cache store if its result is cached.
+// The result of the job is only fetched and recorded if a task reads it.
+// A failure only means the job will run again next time, so it is not retried.
+func (c *ExecutionController) recordCache(job *workload.Workload, exec *genev1alpha1.Execution, vertex *graph.Vertex, executor TaskExecutor) {
+	key, ok := job.Annotations[cacheKeyAnnotation]
+	if !ok {
+		return
+	}
+	entry := &jobcache.Entry{
+		Key:       key,
+		Execution: exec.Name,
+		Job:       job.Name,
+		CreatedAt: time.Now(),
+	}
+	if resultRead(exec, vertex) {
+		result, err := readJobResult(executor, job)
+		if err != nil {
+			klog.Errorf("get result of job %s error: %v", util.KeyOf(job), err)
+			return
+		}
+		entry.Result = &result
+	}
+	if err := c.cacheStore.Put(job.Namespace, entry); err != nil {
+		klog.Errorf("record cache of job %s error: %v", util.KeyOf(job), err)
+	}
+}
+
+// syncVerifyOutputsJob completes the execution according to the result of
+// the job verifying its outputs.
+func (c *ExecutionController) syncVerifyOutputsJob(job *workload.Workload, exec, sharedExec *genev1alpha1.Execution) (bool, error) {
+	switch job.Status.Phase {
+	case workload.Failed:
+		util.MarkExecutionFailed(exec, fmt.Sprintf("verify outputs failed: %s", job.Status.Message))
+	case workload.Succeeded:
+		result, err := getJobLogs(c.kubeClient, job, verifyOutputsLogLimit)
+		if err != nil {
+			klog.V(3).Infof("get result of job %s error: %v", util.KeyOf(job), err)
+			return false, err
+		}
+		outputs, missing, err := parseVerifyOutputsResult(exec, result)
+		if err != nil {
+			util.MarkExecutionError(exec, err)
+			break
+		}
+		exec.Status.Outputs = outputs
+		if len(missing) != 0 {
+			util.MarkExecutionFailed(exec, fmt.Sprintf(missingOutputsMessage, strings.Join(missing, ", ")))
+		} else {
+			util.MarkExecutionSuccess(exec, executionSuccessMessage)
+		}
+	default:
+		return true, nil
+	}
+
+	// Ask api server to update etcd data.
+	if err := c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+		klog.V(3).Infof("update execution %s status error: %#v", util.KeyOf(exec), err)
+		return false, err
+	}
+	return true, nil
+}
+
 // syncExecution will sync the execution with the given key.
 // This function is not meant to be invoked concurrently with the same key.
 func (c *ExecutionController) syncExecution(key string) error {
@@ -398,16 +696,28 @@ func (c *ExecutionController) syncExecution(key string) error {
 	if errors.IsNotFound(err) {
 		klog.V(2).Infof("execution %v has been deleted", key)
 		c.execGraphBuilder.DeleteGraph(key)
+		// the execution may have left room for a queued one.
+		c.enqueueQueuedExecutions()
 		return nil
 	}
 	if err != nil {
 		return err
 	}
 
+	if !c.owns(key) {
+		// the execution has moved to another replica, which syncs it from now on.
+		c.execGraphBuilder.DeleteGraph(key)
+		return nil
+	}
+
 	// Deep-copy otherwise we are mutating our cache.
 	exec := execution.DeepCopy()
 
-	if err := ValidateExecution(exec); err != nil {
+	err = ValidateExecution(exec)
+	if err == nil {
+		err = c.execJobController.validateExecutors(exec)
+	}
+	if err != nil {
 		util.MarkExecutionError(exec, err)
 		c.execStatusUpdater.UpdateExecutionStatus(exec, execution)
 		return err
@@ -415,6 +725,10 @@ func (c *ExecutionController) syncExecution(key string) error {
 
 	graph := c.execGraphBuilder.GetGraph(key)
 	if graph == nil {
+		admitted, err := c.admitExecution(exec, execution)
+		if err != nil || !admitted {
+			return err
+		}
 		klog.V(2).Infof("generate graph for execution %v", key)
 		c.execGraphBuilder.AddGraph(exec)
 	}
@@ -426,6 +740,89 @@ func (c *ExecutionController) syncExecution(key string) error {
 	return nil
 }
 
+// owns returns true if this replica syncs the execution of the key.
+func (c *ExecutionController) owns(key string) bool {
+	return c.sharder == nil || c.sharder.Owns(key)
+}
+
+// Rebalance syncs the executions again after the replicas sharing them
+// have changed. The executions this replica has taken over are synced
+// with their jobs as on a restart, the ones it has lost are forgotten.
+func (c *ExecutionController) Rebalance() {
+	executions, err := c.execLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("list executions error: %v", err))
+		return
+	}
+	for _, exec := range executions {
+		key := util.KeyOf(exec)
+		c.execQueue.Add(key)
+		if !c.owns(key) || c.execGraphBuilder.GetGraph(key) != nil || util.IsExecutionCompleted(exec) {
+			continue
+		}
+		jobs, err := c.jobLister.List(labels.Set{"controller-uid": string(exec.UID)}.AsSelector())
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("list jobs of execution %s error: %v", key, err))
+			continue
+		}
+		for _, job := range jobs {
+			c.enqueueObj(c.jobQueue, job)
+		}
+	}
+}
+
+// admitExecution returns true if the execution may start running, and
+// marks it as queued otherwise.
+func (c *ExecutionController) admitExecution(exec, sharedExec *genev1alpha1.Execution) (bool, error) {
+	executions, err := c.admission.executions()
+	if err != nil {
+		return false, err
+	}
+	if !c.admission.admit(exec, executions) {
+		klog.V(3).Infof("execution %s is queued", util.KeyOf(exec))
+		util.MarkExecutionPhase(exec, genev1alpha1.VertexQueued, executionQueuedMessage)
+		return false, c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec)
+	}
+	// the admission is recorded in the status before the graph is built, the
+	// admission of every replica counts the executions running in the lister.
+	if exec.Status.Phase == genev1alpha1.VertexQueued || (isQueued(exec) && c.admission.limited()) {
+		util.MarkExecutionRunning(exec, executionRunningMessage)
+		if err := c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+			c.admission.forget(util.KeyOf(exec))
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// enqueueQueuedExecutions syncs the queued executions in the order they are
+// admitted, those of the other controllers sharing the admission as well.
+func (c *ExecutionController) enqueueQueuedExecutions() {
+	if err := c.admission.enqueueQueued(); err != nil {
+		utilruntime.HandleError(fmt.Errorf("list executions error: %v", err))
+	}
+}
+
+// updateExecution syncs the queued executions once an execution finishes,
+// and retries a queued execution on every resync.
+func (c *ExecutionController) updateExecution(old, cur interface{}) {
+	oldExec, ok := old.(*genev1alpha1.Execution)
+	if !ok {
+		return
+	}
+	curExec, ok := cur.(*genev1alpha1.Execution)
+	if !ok {
+		return
+	}
+	if util.IsExecutionCompleted(curExec) && !util.IsExecutionCompleted(oldExec) {
+		c.enqueueQueuedExecutions()
+		return
+	}
+	if oldExec.ResourceVersion == curExec.ResourceVersion && isQueued(curExec) {
+		c.enqueueObj(c.execQueue, curExec)
+	}
+}
+
 // enqueueObj adds execution or job to given work queue.
 func (c *ExecutionController) enqueueObj(queue workqueue.Interface, obj interface{}) {
 	// Beware of "xxx deleted" events
@@ -440,7 +837,7 @@ func (c *ExecutionController) enqueueObj(queue workqueue.Interface, obj interfac
 }
 
 func (c *ExecutionController) addJob(obj interface{}) {
-	job := obj.(*batch.Job)
+	job := obj.(*workload.Workload)
 	if job.DeletionTimestamp != nil {
 		// on a restart of the controller controller, it's possible a new pod shows up in a state that
 		// is already pending deletion. Prevent the pod from being a creation observation.
@@ -462,7 +859,7 @@ func (c *ExecutionController) addJob(obj interface{}) {
 }
 
 func (c *ExecutionController) deleteJob(obj interface{}) {
-	job, ok := obj.(*batch.Job)
+	job, ok := obj.(*workload.Workload)
 
 	// When a delete is dropped, the relist will notice a job in the store not
 	// in the list, leading to the insertion of a tombstone object which contains
@@ -474,7 +871,7 @@ func (c *ExecutionController) deleteJob(obj interface{}) {
 			utilruntime.HandleError(fmt.Errorf("couldn't get object from tombstone %+v", obj))
 			return
 		}
-		job, ok = tombstone.Obj.(*batch.Job)
+		job, ok = tombstone.Obj.(*workload.Workload)
 		if !ok {
 			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a job %+v", obj))
 			return
@@ -495,13 +892,18 @@ func (c *ExecutionController) deleteJob(obj interface{}) {
 }
 
 func (c *ExecutionController) updateJob(old, cur interface{}) {
-	curJob := cur.(*batch.Job)
-	oldJob := old.(*batch.Job)
+	curJob := cur.(*workload.Workload)
+	oldJob := old.(*workload.Workload)
 	if curJob.ResourceVersion == oldJob.ResourceVersion {
 		// Periodic resync will send update events for all known pods.
 		// Two different versions of the same job will always have different RVs.
 		return
 	}
+	if curJob.Status.Phase == oldJob.Status.Phase && curJob.DeletionTimestamp == nil {
+		// Only a change of phase is synced. The indexes of an Indexed Job share
+		// its resource version, and most of them are unchanged by an update.
+		return
+	}
 	if curJob.DeletionTimestamp != nil {
 		c.deleteJob(curJob)
 		return
@@ -0,0 +1,416 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	geneclientset "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1"
+	geneinformers "kubegene.io/kubegene/pkg/client/informers/externalversions/gene/v1alpha1"
+	genelisters "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/cron"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+var cronExecKind = genev1alpha1.SchemeGroupVersion.WithKind("CronExecution")
+
+const (
+	// scheduledTimeAnnotation is the scheduled time of an execution created by a cron execution.
+	scheduledTimeAnnotation = "kubegene.io/scheduled-time"
+
+	defaultSuccessfulExecutionsHistoryLimit = 3
+	defaultFailedExecutionsHistoryLimit     = 1
+
+	// tooManyMissedSchedules is the number of missed schedules beyond which
+	// a warning is recorded, only the most recent one is started anyway.
+	tooManyMissedSchedules = 100
+)
+
+// CronExecutionParameters contains arguments for creation of a new CronExecutionController.
+type CronExecutionParameters struct {
+	EventRecorder         record.EventRecorder
+	ExecutionClient       geneclientset.ExecutionV1alpha1Interface
+	CronExecutionInformer geneinformers.CronExecutionInformer
+	ExecutionInformer     geneinformers.ExecutionInformer
+	// Sharder tells the cron executions this replica owns when several replicas
+	// share them. This replica owns every cron execution if it is nil.
+	Sharder Sharder
+}
+
+// CronExecutionController creates the executions of the cron executions on their
+// schedules, and deletes the finished ones beyond their history limits.
+type CronExecutionController struct {
+	eventRecorder record.EventRecorder
+	client        geneclientset.ExecutionV1alpha1Interface
+
+	cronLister genelisters.CronExecutionLister
+	cronSynced cache.InformerSynced
+	execLister genelisters.ExecutionLister
+	execSynced cache.InformerSynced
+
+	queue   workqueue.RateLimitingInterface
+	sharder Sharder
+	now     func() time.Time
+}
+
+func NewCronExecutionController(p *CronExecutionParameters) *CronExecutionController {
+	controller := &CronExecutionController{
+		eventRecorder: p.EventRecorder,
+		client:        p.ExecutionClient,
+		cronLister:    p.CronExecutionInformer.Lister(),
+		cronSynced:    p.CronExecutionInformer.Informer().HasSynced,
+		execLister:    p.ExecutionInformer.Lister(),
+		execSynced:    p.ExecutionInformer.Informer().HasSynced,
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "cron-execution"),
+		sharder:       p.Sharder,
+		now:           time.Now,
+	}
+
+	p.CronExecutionInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueue,
+			UpdateFunc: func(old, cur interface{}) { controller.enqueue(cur) },
+			DeleteFunc: controller.enqueue,
+		},
+	)
+	// the cron execution of an execution is synced when the execution is
+	// created, finishes or is deleted.
+	p.ExecutionInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueueExecutionOwner,
+			UpdateFunc: func(old, cur interface{}) { controller.enqueueExecutionOwner(cur) },
+			DeleteFunc: controller.enqueueExecutionOwner,
+		},
+	)
+
+	return controller
+}
+
+// Run the main goroutine responsible for syncing the cron executions.
+func (c *CronExecutionController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting cron execution controller")
+	defer klog.Infof("Shutting down cron execution controller")
+
+	if !cache.WaitForCacheSync(stopCh, c.cronSynced, c.execSynced) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// Rebalance syncs the cron executions again after the replicas sharing them have changed.
+func (c *CronExecutionController) Rebalance() {
+	crons, err := c.cronLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("list cron executions error: %v", err))
+		return
+	}
+	for _, cron := range crons {
+		c.enqueue(cron)
+	}
+}
+
+func (c *CronExecutionController) worker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *CronExecutionController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.syncCronExecution(key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing cron execution %v: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *CronExecutionController) enqueue(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	c.queue.Add(util.KeyOf(obj))
+}
+
+func (c *CronExecutionController) enqueueExecutionOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	exec, ok := obj.(*genev1alpha1.Execution)
+	if !ok {
+		return
+	}
+	if ref := metav1.GetControllerOf(exec); ref != nil && ref.Kind == cronExecKind.Kind {
+		c.queue.Add(exec.Namespace + "/" + ref.Name)
+	}
+}
+
+// syncCronExecution creates the execution of the most recent schedule of the
+// cron execution if it is due, and returns when the cron execution is synced
+// again for its next schedule.
+func (c *CronExecutionController) syncCronExecution(key string) (time.Duration, error) {
+	if c.sharder != nil && !c.sharder.Owns(key) {
+		return 0, nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, err
+	}
+	sharedCron, err := c.cronLister.CronExecutions(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		// the executions are deleted along with their owner.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cronExec := sharedCron.DeepCopy()
+
+	active, err := c.syncHistory(cronExec)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule, err := cron.Parse(cronExec.Spec.Schedule)
+	if err != nil {
+		// the schedule has to be fixed, no need to retry.
+		c.eventRecorder.Eventf(cronExec, apiv1.EventTypeWarning, "InvalidSchedule", "invalid schedule %q: %v", cronExec.Spec.Schedule, err)
+		return 0, c.updateStatus(cronExec, sharedCron)
+	}
+	if cronExec.Spec.Suspend != nil && *cronExec.Spec.Suspend {
+		return 0, c.updateStatus(cronExec, sharedCron)
+	}
+
+	now := c.now()
+	var requeueAfter time.Duration
+	if next := schedule.Next(now); !next.IsZero() {
+		requeueAfter = next.Sub(now)
+	}
+
+	scheduledTime, missed := mostRecentSchedule(cronExec, schedule, now)
+	if scheduledTime.IsZero() {
+		return requeueAfter, c.updateStatus(cronExec, sharedCron)
+	}
+	if missed > tooManyMissedSchedules {
+		c.eventRecorder.Eventf(cronExec, apiv1.EventTypeWarning, "TooManyMissedTimes",
+			"missed %d schedules, starting the one of %v only", missed, scheduledTime)
+	}
+
+	switch cronExec.Spec.ConcurrencyPolicy {
+	case genev1alpha1.AllowConcurrent, "":
+	case genev1alpha1.ForbidConcurrent:
+		if len(active) != 0 {
+			// the schedule is started once the active executions finish,
+			// unless its starting deadline has passed by then.
+			klog.V(2).Infof("cron execution %s skips the schedule of %v, an execution is still running", key, scheduledTime)
+			return requeueAfter, c.updateStatus(cronExec, sharedCron)
+		}
+	case genev1alpha1.ReplaceConcurrent:
+		for _, exec := range active {
+			err := c.client.Executions(exec.Namespace).Delete(context.TODO(), exec.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return 0, fmt.Errorf("delete execution %s error: %v", util.KeyOf(exec), err)
+			}
+			c.eventRecorder.Eventf(cronExec, apiv1.EventTypeNormal, "SuccessfulDelete", "deleted running execution %s", exec.Name)
+		}
+		cronExec.Status.Active = nil
+	default:
+		c.eventRecorder.Eventf(cronExec, apiv1.EventTypeWarning, "InvalidConcurrencyPolicy",
+			"invalid concurrency policy %q, must be one of Allow, Forbid and Replace", cronExec.Spec.ConcurrencyPolicy)
+		return 0, c.updateStatus(cronExec, sharedCron)
+	}
+
+	exec := newScheduledExecution(cronExec, scheduledTime)
+	created, err := c.client.Executions(exec.Namespace).Create(context.TODO(), exec, metav1.CreateOptions{})
+	switch {
+	case errors.IsAlreadyExists(err):
+		// created by a sync whose status update failed.
+	case err != nil:
+		c.eventRecorder.Eventf(cronExec, apiv1.EventTypeWarning, "FailedCreate", "create execution %s error: %v", exec.Name, err)
+		return 0, fmt.Errorf("create execution %s error: %v", util.KeyOf(exec), err)
+	default:
+		c.eventRecorder.Eventf(cronExec, apiv1.EventTypeNormal, "SuccessfulCreate", "created execution %s", exec.Name)
+		cronExec.Status.Active = append(cronExec.Status.Active, apiv1.ObjectReference{
+			Kind:       execKind.Kind,
+			APIVersion: execKind.GroupVersion().String(),
+			Namespace:  created.Namespace,
+			Name:       created.Name,
+			UID:        created.UID,
+		})
+	}
+	cronExec.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+
+	return requeueAfter, c.updateStatus(cronExec, sharedCron)
+}
+
+// syncHistory records the running executions of the cron execution in its
+// status, deletes the finished ones beyond the history limits, and returns
+// the running executions.
+func (c *CronExecutionController) syncHistory(cronExec *genev1alpha1.CronExecution) ([]*genev1alpha1.Execution, error) {
+	executions, err := c.execLister.Executions(cronExec.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var active, succeeded, failed []*genev1alpha1.Execution
+	for _, exec := range executions {
+		if ref := metav1.GetControllerOf(exec); ref == nil || ref.UID != cronExec.UID {
+			continue
+		}
+		switch exec.Status.Phase {
+		case genev1alpha1.VertexSucceeded:
+			succeeded = append(succeeded, exec)
+			finishedAt := exec.Status.FinishedAt
+			if last := cronExec.Status.LastSuccessfulTime; last == nil || last.Before(&finishedAt) {
+				cronExec.Status.LastSuccessfulTime = &finishedAt
+			}
+		case genev1alpha1.VertexFailed, genev1alpha1.VertexError:
+			failed = append(failed, exec)
+		default:
+			if exec.DeletionTimestamp == nil {
+				active = append(active, exec)
+			}
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool { return active[i].Name < active[j].Name })
+	cronExec.Status.Active = nil
+	for _, exec := range active {
+		cronExec.Status.Active = append(cronExec.Status.Active, apiv1.ObjectReference{
+			Kind:       execKind.Kind,
+			APIVersion: execKind.GroupVersion().String(),
+			Namespace:  exec.Namespace,
+			Name:       exec.Name,
+			UID:        exec.UID,
+		})
+	}
+
+	successfulLimit := int32(defaultSuccessfulExecutionsHistoryLimit)
+	if cronExec.Spec.SuccessfulExecutionsHistoryLimit != nil {
+		successfulLimit = *cronExec.Spec.SuccessfulExecutionsHistoryLimit
+	}
+	failedLimit := int32(defaultFailedExecutionsHistoryLimit)
+	if cronExec.Spec.FailedExecutionsHistoryLimit != nil {
+		failedLimit = *cronExec.Spec.FailedExecutionsHistoryLimit
+	}
+	for _, exec := range append(oldExecutions(succeeded, successfulLimit), oldExecutions(failed, failedLimit)...) {
+		err := c.client.Executions(exec.Namespace).Delete(context.TODO(), exec.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("delete execution %s error: %v", util.KeyOf(exec), err)
+		}
+		klog.V(2).Infof("cron execution %s deleted finished execution %s", util.KeyOf(cronExec), exec.Name)
+	}
+	return active, nil
+}
+
+func (c *CronExecutionController) updateStatus(cronExec, sharedCron *genev1alpha1.CronExecution) error {
+	if equality.Semantic.DeepEqual(cronExec.Status, sharedCron.Status) {
+		return nil
+	}
+	_, err := c.client.CronExecutions(cronExec.Namespace).UpdateStatus(context.TODO(), cronExec, metav1.UpdateOptions{})
+	return err
+}
+
+// oldExecutions returns the executions beyond the limit, the oldest ones first.
+func oldExecutions(executions []*genev1alpha1.Execution, limit int32) []*genev1alpha1.Execution {
+	if limit < 0 || int32(len(executions)) <= limit {
+		return nil
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].CreationTimestamp.Before(&executions[j].CreationTimestamp)
+	})
+	return executions[:int32(len(executions))-limit]
+}
+
+// mostRecentSchedule returns the most recent schedule of the cron execution
+// not started yet, and the number of schedules missed since the last one
+// started. Schedules older than the starting deadline are ignored.
+func mostRecentSchedule(cronExec *genev1alpha1.CronExecution, schedule *cron.Schedule, now time.Time) (time.Time, int) {
+	earliest := cronExec.CreationTimestamp.Time
+	if cronExec.Status.LastScheduleTime != nil {
+		earliest = cronExec.Status.LastScheduleTime.Time
+	}
+	if deadline := cronExec.Spec.StartingDeadlineSeconds; deadline != nil {
+		if start := now.Add(-time.Duration(*deadline) * time.Second); start.After(earliest) {
+			earliest = start
+		}
+	}
+
+	var recent time.Time
+	missed := 0
+	for t := schedule.Next(earliest); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		recent = t
+		missed++
+	}
+	return recent, missed
+}
+
+// newScheduledExecution returns the execution of the cron execution scheduled at the time.
+func newScheduledExecution(cronExec *genev1alpha1.CronExecution, scheduledTime time.Time) *genev1alpha1.Execution {
+	template := cronExec.Spec.ExecutionTemplate.DeepCopy()
+	annotations := template.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[scheduledTimeAnnotation] = scheduledTime.UTC().Format(time.RFC3339)
+
+	return &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{
+			// the name is the same for a schedule, so that it is never created twice.
+			Name:            fmt.Sprintf("%s-%d", cronExec.Name, scheduledTime.Unix()/60),
+			Namespace:       cronExec.Namespace,
+			Labels:          template.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronExec, cronExecKind)},
+		},
+		Spec: template.Spec,
+	}
+}
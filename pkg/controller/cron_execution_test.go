@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/client/clientset/versioned/fake"
+	execinformers "kubegene.io/kubegene/pkg/client/informers/externalversions"
+	"kubegene.io/kubegene/pkg/cron"
+)
+
+var cronCreated = time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+func newTestCronExecution(policy genev1alpha1.ConcurrencyPolicy) *genev1alpha1.CronExecution {
+	return &genev1alpha1.CronExecution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			UID:               "cron-uid",
+			CreationTimestamp: metav1.Time{Time: cronCreated},
+		},
+		Spec: genev1alpha1.CronExecutionSpec{
+			Schedule:          "0 * * * *",
+			ConcurrencyPolicy: policy,
+			ExecutionTemplate: genev1alpha1.ExecutionTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pipeline": "qc"}},
+				Spec:       genev1alpha1.ExecutionSpec{Tasks: []genev1alpha1.Task{{Name: "a", Image: "busybox"}}},
+			},
+		},
+	}
+}
+
+func newCronOwnedExecution(cronExec *genev1alpha1.CronExecution, name string, phase genev1alpha1.VertexPhase, created time.Time) *genev1alpha1.Execution {
+	return &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         cronExec.Namespace,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.Time{Time: created},
+			OwnerReferences:   []metav1.OwnerReference{*metav1.NewControllerRef(cronExec, cronExecKind)},
+		},
+		Status: genev1alpha1.ExecutionStatus{Phase: phase, FinishedAt: metav1.Time{Time: created.Add(time.Minute)}},
+	}
+}
+
+func newTestCronController(now time.Time, cronExec *genev1alpha1.CronExecution, executions ...*genev1alpha1.Execution) (*CronExecutionController, *fake.Clientset) {
+	objects := []runtime.Object{cronExec}
+	for _, exec := range executions {
+		objects = append(objects, exec)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	factory := execinformers.NewSharedInformerFactory(client, 0)
+	cronInformer := factory.Execution().V1alpha1().CronExecutions()
+	execInformer := factory.Execution().V1alpha1().Executions()
+	cronInformer.Informer().GetIndexer().Add(cronExec)
+	for _, exec := range executions {
+		execInformer.Informer().GetIndexer().Add(exec)
+	}
+
+	c := NewCronExecutionController(&CronExecutionParameters{
+		EventRecorder:         record.NewFakeRecorder(10),
+		ExecutionClient:       client.ExecutionV1alpha1(),
+		CronExecutionInformer: cronInformer,
+		ExecutionInformer:     execInformer,
+	})
+	c.now = func() time.Time { return now }
+	client.ClearActions()
+	return c, client
+}
+
+// clientActions returns the verb and the name of the resource of the actions of the client.
+func clientActions(client *fake.Clientset) []string {
+	var actions []string
+	for _, action := range client.Actions() {
+		actions = append(actions, action.GetVerb()+" "+action.GetResource().Resource)
+	}
+	return actions
+}
+
+func TestSyncCronExecution(t *testing.T) {
+	now := cronCreated.Add(2*time.Hour + 30*time.Minute)
+	scheduledName := fmt.Sprintf("nightly-%d", cronCreated.Add(2*time.Hour).Unix()/60)
+
+	testCases := []struct {
+		Name          string
+		Policy        genev1alpha1.ConcurrencyPolicy
+		Suspend       bool
+		Deadline      *int64
+		Executions    []*genev1alpha1.Execution
+		ExpectActions []string
+		ExpectCreated bool
+	}{
+		{
+			Name:          "due schedule",
+			ExpectActions: []string{"create executions", "update cronexecutions"},
+			ExpectCreated: true,
+		},
+		{
+			Name:   "forbid with a running execution",
+			Policy: genev1alpha1.ForbidConcurrent,
+			Executions: []*genev1alpha1.Execution{
+				newCronOwnedExecution(newTestCronExecution(""), "nightly-1", genev1alpha1.VertexRunning, cronCreated),
+			},
+			ExpectActions: []string{"update cronexecutions"},
+		},
+		{
+			Name:   "replace a running execution",
+			Policy: genev1alpha1.ReplaceConcurrent,
+			Executions: []*genev1alpha1.Execution{
+				newCronOwnedExecution(newTestCronExecution(""), "nightly-1", genev1alpha1.VertexRunning, cronCreated),
+			},
+			ExpectActions: []string{"delete executions", "create executions", "update cronexecutions"},
+			ExpectCreated: true,
+		},
+		{
+			Name:          "allow a running execution",
+			Policy:        genev1alpha1.AllowConcurrent,
+			Executions:    []*genev1alpha1.Execution{newCronOwnedExecution(newTestCronExecution(""), "nightly-1", "", cronCreated)},
+			ExpectActions: []string{"create executions", "update cronexecutions"},
+			ExpectCreated: true,
+		},
+		{
+			Name:    "suspended",
+			Suspend: true,
+		},
+		{
+			Name:     "starting deadline passed",
+			Deadline: func() *int64 { d := int64(60); return &d }(),
+		},
+		{
+			Name: "history limits",
+			Executions: []*genev1alpha1.Execution{
+				newCronOwnedExecution(newTestCronExecution(""), "s1", genev1alpha1.VertexSucceeded, cronCreated.Add(-5*time.Hour)),
+				newCronOwnedExecution(newTestCronExecution(""), "s2", genev1alpha1.VertexSucceeded, cronCreated.Add(-4*time.Hour)),
+				newCronOwnedExecution(newTestCronExecution(""), "s3", genev1alpha1.VertexSucceeded, cronCreated.Add(-3*time.Hour)),
+				newCronOwnedExecution(newTestCronExecution(""), "s4", genev1alpha1.VertexSucceeded, cronCreated.Add(-2*time.Hour)),
+				newCronOwnedExecution(newTestCronExecution(""), "f1", genev1alpha1.VertexFailed, cronCreated.Add(-2*time.Hour)),
+				newCronOwnedExecution(newTestCronExecution(""), "f2", genev1alpha1.VertexError, cronCreated.Add(-1*time.Hour)),
+			},
+			ExpectActions: []string{"delete executions", "delete executions", "create executions", "update cronexecutions"},
+			ExpectCreated: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		cronExec := newTestCronExecution(testCase.Policy)
+		cronExec.Spec.Suspend = &testCase.Suspend
+		cronExec.Spec.StartingDeadlineSeconds = testCase.Deadline
+		c, client := newTestCronController(now, cronExec, testCase.Executions...)
+
+		requeueAfter, err := c.syncCronExecution("default/nightly")
+		if err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+			continue
+		}
+		if actions := clientActions(client); fmt.Sprint(actions) != fmt.Sprint(testCase.ExpectActions) {
+			t.Errorf("%s: Expect actions %v, but got %v", testCase.Name, testCase.ExpectActions, actions)
+		}
+		if !testCase.Suspend && requeueAfter != 30*time.Minute {
+			t.Errorf("%s: Expect to requeue after 30m, but got %v", testCase.Name, requeueAfter)
+		}
+		if !testCase.ExpectCreated {
+			continue
+		}
+		exec, err := client.ExecutionV1alpha1().Executions("default").Get(context.TODO(), scheduledName, metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("%s: Expect execution %s, but got %v", testCase.Name, scheduledName, err)
+			continue
+		}
+		if exec.Labels["pipeline"] != "qc" || len(exec.Spec.Tasks) != 1 || metav1.GetControllerOf(exec).UID != cronExec.UID {
+			t.Errorf("%s: unexpected execution %#v", testCase.Name, exec)
+		}
+		updated, _ := client.ExecutionV1alpha1().CronExecutions("default").Get(context.TODO(), "nightly", metav1.GetOptions{})
+		if updated.Status.LastScheduleTime == nil || !updated.Status.LastScheduleTime.Time.Equal(cronCreated.Add(2*time.Hour)) {
+			t.Errorf("%s: Expect last schedule time %v, but got %v", testCase.Name, cronCreated.Add(2*time.Hour), updated.Status.LastScheduleTime)
+		}
+		if active := updated.Status.Active; len(active) == 0 || active[len(active)-1].Name != scheduledName {
+			t.Errorf("%s: Expect execution %s active, but got %v", testCase.Name, scheduledName, active)
+		}
+	}
+}
+
+func TestMostRecentSchedule(t *testing.T) {
+	schedule, err := cron.Parse("0 * * * *")
+	if err != nil {
+		t.Fatalf("parse schedule error: %v", err)
+	}
+	now := cronCreated.Add(2*time.Hour + 30*time.Minute)
+	lastSchedule := metav1.Time{Time: cronCreated.Add(2 * time.Hour)}
+	deadline := int64(3600)
+
+	testCases := []struct {
+		Name         string
+		LastSchedule *metav1.Time
+		Deadline     *int64
+		ExpectTime   time.Time
+		ExpectMissed int
+	}{
+		{
+			Name:         "missed since creation",
+			ExpectTime:   cronCreated.Add(2 * time.Hour),
+			ExpectMissed: 2,
+		},
+		{
+			Name:         "started already",
+			LastSchedule: &lastSchedule,
+		},
+		{
+			Name:         "within deadline",
+			Deadline:     &deadline,
+			ExpectTime:   cronCreated.Add(2 * time.Hour),
+			ExpectMissed: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		cronExec := newTestCronExecution("")
+		cronExec.Status.LastScheduleTime = testCase.LastSchedule
+		cronExec.Spec.StartingDeadlineSeconds = testCase.Deadline
+		scheduledTime, missed := mostRecentSchedule(cronExec, schedule, now)
+		if !scheduledTime.Equal(testCase.ExpectTime) || missed != testCase.ExpectMissed {
+			t.Errorf("%s: Expect %v and %d missed, but got %v and %d", testCase.Name, testCase.ExpectTime,
+				testCase.ExpectMissed, scheduledTime, missed)
+		}
+	}
+}
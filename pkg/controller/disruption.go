@@ -0,0 +1,269 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+const (
+	// disruptionTargetCondition is set on the pods about to be terminated
+	// because of a disruption, on Kubernetes 1.26 or later.
+	disruptionTargetCondition v1.PodConditionType = "DisruptionTarget"
+	// disruptedPodsAnnotation lists the disrupted pods of a job which have
+	// been added to its backoff limit, separated by commas.
+	disruptedPodsAnnotation = "kubegene.io/disrupted-pods"
+	// defaultBackoffLimit is the backoff limit of a job which does not set one.
+	defaultBackoffLimit int32 = 6
+	// restartedPodsTTL is how long the disrupted pods whose job has been
+	// started again are remembered, longer than their job takes to go.
+	restartedPodsTTL = 10 * time.Minute
+	// maxRestartedPods is the number of restarted pods remembered at most.
+	maxRestartedPods = 4096
+)
+
+// errJobFailed is returned when the backoff limit of a job which has failed is raised.
+var errJobFailed = fmt.Errorf("job has failed")
+
+// disruptionReasons are the reasons of the pods terminated by the cluster
+// rather than by a failure of the task, on Kubernetes releases without the
+// DisruptionTarget condition.
+var disruptionReasons = map[string]bool{
+	"Evicted":      true,
+	"NodeLost":     true,
+	"Shutdown":     true,
+	"NodeShutdown": true,
+	"Terminated":   true,
+}
+
+// podDisruption returns why the pod has been disrupted, or an empty reason
+// if it has not.
+func podDisruption(pod *v1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == disruptionTargetCondition && condition.Status == v1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	if disruptionReasons[pod.Status.Reason] {
+		return pod.Status.Reason
+	}
+	return ""
+}
+
+// disruptedJobName returns the name of the batch Job controlling the pod,
+// or an empty name if the pod is not controlled by a batch Job. The pods of
+// an Indexed Job are controlled by the Indexed Job rather than by the
+// vertex of their index.
+func disruptedJobName(pod *v1.Pod) string {
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil || controllerRef.Kind != "Job" || controllerRef.APIVersion != batch.SchemeGroupVersion.String() {
+		return ""
+	}
+	return controllerRef.Name
+}
+
+// runsJob returns true if the pod is the pod of the job, or is controlled by it.
+func runsJob(pod *v1.Pod, job *workload.Workload) bool {
+	if pod.UID == job.UID {
+		return true
+	}
+	controllerRef := metav1.GetControllerOf(pod)
+	return controllerRef != nil && controllerRef.UID == job.UID
+}
+
+// countJobDisruption raises the backoff limit of the batch Job by one for
+// the disrupted pod, so that its failure does not count. It returns false
+// if the pod has been counted before, and errJobFailed if the Job controller
+// has failed the job on the attempt of the pod before its backoff limit
+// could be raised. The patch carries the resource version of the job, so
+// that it is not applied over a failure of the job it has not seen.
+func countJobDisruption(kubeClient clientset.Interface, namespace, jobName, podName string) (bool, error) {
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var disrupted []string
+	if value := job.Annotations[disruptedPodsAnnotation]; len(value) != 0 {
+		disrupted = strings.Split(value, ",")
+	}
+	for _, name := range disrupted {
+		if name == podName {
+			return false, nil
+		}
+	}
+	if workload.FromBatchJob(job).Status.Phase == workload.Failed {
+		return false, errJobFailed
+	}
+
+	backoffLimit := defaultBackoffLimit
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+	backoffLimit++
+	// a merge patch rather than an update, an update would drop the
+	// completion mode of an Indexed Job, which the batch Job type lacks.
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": job.ResourceVersion,
+			"annotations": map[string]string{
+				disruptedPodsAnnotation: strings.Join(append(disrupted, podName), ","),
+			},
+		},
+		"spec": map[string]interface{}{"backoffLimit": backoffLimit},
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = kubeClient.BatchV1().Jobs(namespace).Patch(context.TODO(), jobName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// countDisruption retries the disrupted pod of the vertex without counting
+// it toward the backoff limit, and reports the disruption in the status of
+// the vertex. The batch Jobs and the Indexed Jobs retry the pod once their
+// backoff limit has been raised, the bare pods and the Volcano Jobs are
+// started again, as are the batch Jobs failed on the attempt of the pod.
+// An Indexed Job failed on the attempt of the pod fails all its indexes, it
+// is not started again since it would run the indexes already succeeded.
+func (c *ExecutionController) countDisruption(exec, sharedExec *genev1alpha1.Execution, vertexName string, pod *v1.Pod, reason string) error {
+	job, err := c.jobLister.Workloads(pod.Namespace).Get(vertexName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// the pod of an earlier attempt.
+	if !runsJob(pod, job) {
+		return nil
+	}
+
+	jobName := disruptedJobName(pod)
+	if len(jobName) == 0 {
+		return c.restartDisruptedJob(exec, sharedExec, job, pod, reason)
+	}
+	counted, err := countJobDisruption(c.kubeClient, pod.Namespace, jobName, pod.Name)
+	if err == errJobFailed {
+		if indexedjob.IsIndexedJob(job) {
+			return nil
+		}
+		return c.restartDisruptedJob(exec, sharedExec, job, pod, reason)
+	}
+	if err != nil || !counted {
+		return err
+	}
+	return c.recordDisruption(exec, sharedExec, vertexName, pod, reason)
+}
+
+// uncountedDisruption returns a disrupted pod of the failed job which has
+// not been counted yet, or nil if there is none. The Job controller may fail
+// a batch Job on the attempt of a disrupted pod before the disruption has
+// been counted.
+func (c *ExecutionController) uncountedDisruption(job *workload.Workload) *v1.Pod {
+	if c.podLister == nil || job.Spec.Selector == nil || indexedjob.IsIndexedJob(job) {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil
+	}
+	pods, err := c.podLister.Pods(job.Namespace).List(selector)
+	if err != nil {
+		return nil
+	}
+	counted := sets.NewString(strings.Split(job.Annotations[disruptedPodsAnnotation], ",")...)
+	for _, pod := range pods {
+		if podVertexName(pod) != job.Name || !runsJob(pod, job) || len(podDisruption(pod)) == 0 {
+			continue
+		}
+		if _, restarted := c.restartedPods.Get(pod.UID); restarted || counted.Has(pod.Name) {
+			continue
+		}
+		return pod
+	}
+	return nil
+}
+
+// restartDisruptedJob starts the job of the vertex again for its disrupted
+// pod, the way a job out of memory is started again.
+func (c *ExecutionController) restartDisruptedJob(exec, sharedExec *genev1alpha1.Execution, job *workload.Workload, pod *v1.Pod, reason string) error {
+	// the stale events of the pod come after its job has been started again.
+	if _, restarted := c.restartedPods.Get(pod.UID); restarted {
+		return nil
+	}
+	graph := c.execGraphBuilder.GetGraph(util.KeyOf(exec))
+	if graph == nil {
+		return nil
+	}
+	vertex := graph.FindVertexByName(job.Name)
+	if vertex == nil {
+		return nil
+	}
+	executor, err := c.execJobController.executorFor(vertex.Data.TaskType)
+	if err != nil {
+		return err
+	}
+
+	if err := c.recordDisruption(exec, sharedExec, job.Name, pod, reason); err != nil {
+		return err
+	}
+	klog.Infof("job %s has been disrupted, start it again", util.KeyOf(job))
+	// the failed jobs are deleted as well, Cancel leaves the finished jobs alone.
+	running := job.DeepCopy()
+	running.Status = workload.Status{Phase: workload.Running}
+	if err := executor.Cancel(running); err != nil {
+		return err
+	}
+	c.restartedPods.Add(pod.UID, true, restartedPodsTTL)
+	c.eventQueue.Add(Event{Type: JobRestart, Name: job.Name, Key: util.KeyOf(exec)})
+	return nil
+}
+
+// recordDisruption reports the disruption of the pod in the status of the vertex.
+func (c *ExecutionController) recordDisruption(exec, sharedExec *genev1alpha1.Execution, vertexName string, pod *v1.Pod, reason string) error {
+	klog.Infof("pod %s of execution %s has been disrupted: %s", util.KeyOf(pod), util.KeyOf(exec), reason)
+	vertexStatus := util.GetVertexStatus(exec, vertexName)
+	vertexStatus.Message = fmt.Sprintf(podDisruptedMessage, pod.Name, reason)
+	vertexStatus.Disruptions++
+	exec.Status.Vertices[vertexStatus.ID] = *vertexStatus
+	return c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec)
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func TestPodDisruption(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Status v1.PodStatus
+		Expect string
+	}{
+		{
+			Name:   "failed pod",
+			Status: v1.PodStatus{Phase: v1.PodFailed},
+			Expect: "",
+		},
+		{
+			Name: "preempted pod",
+			Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+				Type:   disruptionTargetCondition,
+				Status: v1.ConditionTrue,
+				Reason: "PreemptionByScheduler",
+			}}},
+			Expect: "PreemptionByScheduler",
+		},
+		{
+			Name:   "evicted pod",
+			Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"},
+			Expect: "Evicted",
+		},
+		{
+			Name:   "pod on a lost node",
+			Status: v1.PodStatus{Reason: "NodeLost"},
+			Expect: "NodeLost",
+		},
+	}
+
+	for _, testCase := range testCases {
+		pod := &v1.Pod{Status: testCase.Status}
+		if reason := podDisruption(pod); reason != testCase.Expect {
+			t.Errorf("%s: Expect reason %q, but got %q", testCase.Name, testCase.Expect, reason)
+		}
+	}
+}
+
+func TestCountJobDisruption(t *testing.T) {
+	backoffLimit := int32(2)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "exec.a.0", Namespace: "default"},
+		Spec:       batch.JobSpec{BackoffLimit: &backoffLimit},
+	}
+	failedJob := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "exec.c.0", Namespace: "default"},
+		Spec:       batch.JobSpec{BackoffLimit: &backoffLimit},
+		Status: batch.JobStatus{Conditions: []batch.JobCondition{{
+			Type:   batch.JobFailed,
+			Status: v1.ConditionTrue,
+			Reason: "BackoffLimitExceeded",
+		}}},
+	}
+	kubeClient := fake.NewSimpleClientset(job, failedJob)
+
+	testCases := []struct {
+		Name         string
+		JobName      string
+		PodName      string
+		ExpectCount  bool
+		ExpectErr    error
+		ExpectLimit  int32
+		ExpectedPods string
+	}{
+		{
+			Name:         "first disruption",
+			JobName:      "exec.a.0",
+			PodName:      "exec.a.0-abcde",
+			ExpectCount:  true,
+			ExpectLimit:  3,
+			ExpectedPods: "exec.a.0-abcde",
+		},
+		{
+			Name:         "same pod again",
+			JobName:      "exec.a.0",
+			PodName:      "exec.a.0-abcde",
+			ExpectCount:  false,
+			ExpectLimit:  3,
+			ExpectedPods: "exec.a.0-abcde",
+		},
+		{
+			Name:         "second disruption",
+			JobName:      "exec.a.0",
+			PodName:      "exec.a.0-fghij",
+			ExpectCount:  true,
+			ExpectLimit:  4,
+			ExpectedPods: "exec.a.0-abcde,exec.a.0-fghij",
+		},
+		{
+			Name:        "pod not run by a batch job",
+			JobName:     "exec.b.0",
+			PodName:     "exec.b.0",
+			ExpectCount: false,
+		},
+		{
+			Name:        "job failed on the last allowed attempt",
+			JobName:     "exec.c.0",
+			PodName:     "exec.c.0-abcde",
+			ExpectCount: false,
+			ExpectErr:   errJobFailed,
+			ExpectLimit: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		counted, err := countJobDisruption(kubeClient, "default", testCase.JobName, testCase.PodName)
+		if err != testCase.ExpectErr {
+			t.Errorf("%s: Expect error %v, but got error %v", testCase.Name, testCase.ExpectErr, err)
+			continue
+		}
+		if counted != testCase.ExpectCount {
+			t.Errorf("%s: Expect counted %v, but got %v", testCase.Name, testCase.ExpectCount, counted)
+		}
+		updated, err := kubeClient.BatchV1().Jobs("default").Get(context.TODO(), testCase.JobName, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if *updated.Spec.BackoffLimit != testCase.ExpectLimit {
+			t.Errorf("%s: Expect backoff limit %d, but got %d", testCase.Name, testCase.ExpectLimit, *updated.Spec.BackoffLimit)
+		}
+		if pods := updated.Annotations[disruptedPodsAnnotation]; pods != testCase.ExpectedPods {
+			t.Errorf("%s: Expect disrupted pods %q, but got %q", testCase.Name, testCase.ExpectedPods, pods)
+		}
+	}
+}
+
+func TestDisruptedJobName(t *testing.T) {
+	isController := true
+	testCases := []struct {
+		Name   string
+		Owner  *metav1.OwnerReference
+		Expect string
+	}{
+		{
+			Name:   "pod of a batch job",
+			Owner:  &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "exec.a.0", Controller: &isController},
+			Expect: "exec.a.0",
+		},
+		{
+			Name:   "pod of an indexed job",
+			Owner:  &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "exec.b", Controller: &isController},
+			Expect: "exec.b",
+		},
+		{
+			Name:   "pod of a volcano job",
+			Owner:  &metav1.OwnerReference{APIVersion: "batch.volcano.sh/v1alpha1", Kind: "Job", Name: "exec.c.0", Controller: &isController},
+			Expect: "",
+		},
+		{
+			Name:   "bare pod",
+			Owner:  &metav1.OwnerReference{APIVersion: "execution.kubegene.io/v1alpha1", Kind: "Execution", Name: "exec", Controller: &isController},
+			Expect: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{*testCase.Owner}}}
+		if name := disruptedJobName(pod); name != testCase.Expect {
+			t.Errorf("%s: Expect job %q, but got %q", testCase.Name, testCase.Expect, name)
+		}
+	}
+}
+
+func TestSyncJobFailedOnDisruption(t *testing.T) {
+	exec := &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{Name: "disrupt", Namespace: "default", UID: "disrupt-uid"},
+		Spec: genev1alpha1.ExecutionSpec{
+			Tasks: []genev1alpha1.Task{
+				{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo A"}},
+			},
+		},
+	}
+	c := newTestExecutionController(exec)
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	c.podLister = corelisters.NewPodLister(podIndexer)
+	c.restartedPods = utilcache.NewLRUExpireCache(maxRestartedPods)
+	if err := c.syncExecution(util.KeyOf(exec)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	c.processEvents()
+
+	// the Job controller fails the job on the attempt of the evicted pod,
+	// before the disruption has been counted.
+	job, err := c.kubeClient.BatchV1().Jobs("default").Get(context.TODO(), "disrupt.a.0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect job disrupt.a.0, but got error %v", err)
+	}
+	job.UID = "job-uid"
+	job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "job-uid"}}
+	job.Status.Conditions = []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+	c.jobIndexer.Add(job)
+	isController := true
+	podIndexer.Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "disrupt.a.0-abcde",
+			Namespace:   "default",
+			Labels:      map[string]string{"controller-uid": "job-uid"},
+			Annotations: map[string]string{vertexAnnotation: "disrupt.a.0"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "Job", Name: "disrupt.a.0", UID: "job-uid", Controller: &isController},
+			},
+		},
+		Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"},
+	})
+
+	if _, err := c.syncJob(util.KeyOf(job)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	vertexStatus := util.GetVertexStatus(c.execution(t, exec), "disrupt.a.0")
+	if vertexStatus.Phase != genev1alpha1.VertexRunning || vertexStatus.Disruptions != 1 {
+		t.Errorf("Expect vertex running with 1 disruption, but got %s with %d", vertexStatus.Phase, vertexStatus.Disruptions)
+	}
+	if _, err := c.kubeClient.BatchV1().Jobs("default").Get(context.TODO(), "disrupt.a.0", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expect the failed job to be deleted, but got error %v", err)
+	}
+	if c.eventQueue.Len() != 1 {
+		t.Errorf("Expect the job to start again, but got %d events", c.eventQueue.Len())
+	}
+
+	// the pod is not counted twice.
+	if pod := c.uncountedDisruption(workload.FromBatchJob(job)); pod != nil {
+		t.Errorf("Expect no uncounted disruption, but got pod %s", pod.Name)
+	}
+}
@@ -20,11 +20,9 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
-	"strconv"
 	"strings"
 	"time"
 
-	batch "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,15 +30,17 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
-	batchv1listers "k8s.io/client-go/listers/batch/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 	genelisters "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
 	"kubegene.io/kubegene/pkg/common"
 	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/jobcache"
 	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
 )
 
 type EventType string
@@ -56,9 +56,11 @@ const (
 	NewAdded EventType = "NewAdded"
 	// start execute jobs that depend on a job
 	JobsAfter EventType = "JobsAfter"
+	// start again the job of a vertex with more memory
+	JobRestart EventType = "JobRestart"
 )
 
-var ExceedParallelismError = fmt.Errorf("running jobs have reached the execution parallelism limit")
+var ExceedParallelismError = fmt.Errorf("running jobs have reached the execution parallelism or parallel resources limit")
 
 type Event struct {
 	Type EventType
@@ -70,28 +72,41 @@ type Event struct {
 
 type ExecutionJobController struct {
 	kubeClient       clientset.Interface
-	jobLister        batchv1listers.JobLister
+	executors        map[genev1alpha1.TaskType]TaskExecutor
+	jobLister        workload.Lister
 	executionLister  genelisters.ExecutionLister
 	queue            workqueue.RateLimitingInterface
 	execGraphBuilder *GraphBuilder
 	execUpdater      ExecutionUpdater
+	cacheStore       jobcache.Store
+	// launchOrder orders the jobs ready to start at the same time.
+	launchOrder LaunchOrder
+	// durations estimates the durations of the jobs for the critical path.
+	durations *durationHistory
 }
 
 func NewExecutionJobController(
 	kubeClient clientset.Interface,
-	jobLister batchv1listers.JobLister,
+	executors map[genev1alpha1.TaskType]TaskExecutor,
+	jobLister workload.Lister,
 	executionLister genelisters.ExecutionLister,
 	eventQueue workqueue.RateLimitingInterface,
 	execGraphBuilder *GraphBuilder,
 	execUpdater ExecutionUpdater,
+	cacheStore jobcache.Store,
+	launchOrder LaunchOrder,
 ) *ExecutionJobController {
 	return &ExecutionJobController{
 		queue:            eventQueue,
 		kubeClient:       kubeClient,
+		executors:        executors,
 		jobLister:        jobLister,
 		executionLister:  executionLister,
 		execGraphBuilder: execGraphBuilder,
 		execUpdater:      execUpdater,
+		cacheStore:       cacheStore,
+		launchOrder:      launchOrder,
+		durations:        newDurationHistory(),
 	}
 }
 
@@ -158,23 +173,22 @@ func (e *ExecutionJobController) syncHandler(event Event) error {
 	case NewAdded:
 		klog.V(2).Infof("execution %v start running.", event.Key)
 
-		rootVertexs := graph.GetRootVertex()
+		rootVertexs := e.orderVertices(graph.GetRootVertex(), event.Key)
 		for _, rootVertex := range rootVertexs {
-			if e.shouldStartJob(event.Key, rootVertex.Data.Job) {
-				// root vertex, create job
-				if err := e.createJob(rootVertex.Data.Job); err != nil {
-					key := util.KeyOf(rootVertex.Data.Job)
-					return fmt.Errorf("create job %s error: %v", key, err)
-				}
-			} else {
-				return ExceedParallelismError
+			// root vertex, create job
+			if err := e.startVertex(rootVertex, graph, event.Key); err != nil {
+				return err
 			}
 		}
 	case JobsAfter:
 		klog.V(2).Infof("job %v has run successfully.", event.Name)
 
 		vertex := graph.FindVertexByName(event.Name)
-		for _, child := range vertex.Children {
+		for _, child := range e.orderVertices(vertex.Children, event.Key) {
+			// the child has already been skipped through another dependent.
+			if child.Data.Skipped {
+				continue
+			}
 			allDependentsFinished := true
 
 			dependents := graph.FindDependentsByName(child.Data.Job.Name)
@@ -189,38 +203,53 @@ func (e *ExecutionJobController) syncHandler(event Event) error {
 				klog.V(2).Infof("all dependent of job %v has run successfully, start running.", child.Data.Job.Name)
 
 				if child.Data.DynamicJob != nil {
+					// the result of a skipped job is not available, so the jobs
+					// that are evaluated against it can not run either. The
+					// others run as if the skipped job had succeeded.
+					readsResult := readsResultOf(child.Data.DynamicJob, vertexTaskName(vertex))
+					if vertex.Data.Skipped && readsResult {
+						if err := e.skipVertex(child, graph, event.Key, vertexSkippedByDependentMessage); err != nil {
+							return fmt.Errorf("skip job %s error: %v", child.Data.Job.Name, err)
+						}
+						continue
+					}
+
 					// flag setting true to handle if Condition is nil
 					flag := true
 					var err error
 
 					if child.Data.DynamicJob.GenericCondition != nil {
 						klog.V(2).Infof(" conditional based job GenericCondition:%v", child.Data.DynamicJob.GenericCondition)
-						flag, err = e.evalGenericConditionResult(vertex.Data.Job, child, graph, event.Key)
+						flag, err = e.evalGenericConditionResult(vertex, child, graph, event.Key)
 						if err != nil {
 							return fmt.Errorf("evalGenericConditionResult failed : %v", err)
 						}
 						if !flag {
 							// if the condition or check_result validation is false
-							// then we don't create the k8s job and make the job is Finished true
+							// then we don't create the k8s job and mark the job as skipped
 							// so that other jobs will continue or execution will complete
 							klog.V(2).Infof(" The final condition is false")
-							child.Data.Finished = true
+							if err := e.skipVertex(child, graph, event.Key, vertexSkippedMessage); err != nil {
+								return fmt.Errorf("skip job %s error: %v", child.Data.Job.Name, err)
+							}
 							continue
 						}
 					}
 
 					if child.Data.DynamicJob.Condition != nil {
 						klog.V(2).Infof(" conditional based job condition:%v", child.Data.DynamicJob.Condition)
-						flag, err = e.evalConditionResult(vertex.Data.Job, child, graph, event.Key)
+						flag, err = e.evalConditionResult(vertex, child, graph, event.Key)
 						if err != nil {
 							return fmt.Errorf("evalConditionResult failed : %v", err)
 						}
 						if !flag {
 							// if the condition or check_result validation is false
-							// then we don't create the k8s job and make the job is Finished true
+							// then we don't create the k8s job and mark the job as skipped
 							// so that other jobs will continue or execution will complete
 							klog.V(2).Infof(" The final condition is false")
-							child.Data.Finished = true
+							if err := e.skipVertex(child, graph, event.Key, vertexSkippedMessage); err != nil {
+								return fmt.Errorf("skip job %s error: %v", child.Data.Job.Name, err)
+							}
 							continue
 						}
 					}
@@ -238,9 +267,12 @@ func (e *ExecutionJobController) syncHandler(event Event) error {
 					if child.Data.DynamicJob.CommandsIter != nil {
 
 						// get the result of the dependent job
-						result, err := e.getJobResult(vertex.Data.Job)
-						if err != nil {
-							return fmt.Errorf("getJobResult failed : %v", err)
+						result := ""
+						if readsResult {
+							result, err = e.getJobResult(vertex, graph, event.Key)
+							if err != nil {
+								return fmt.Errorf("getJobResult failed : %v", err)
+							}
 						}
 						// construct the dynamic job based on get_result
 						err = e.createDynamicJob(child, result, graph, event.Key)
@@ -251,28 +283,72 @@ func (e *ExecutionJobController) syncHandler(event Event) error {
 					}
 
 				}
-				if e.shouldStartJob(event.Key, child.Data.Job) {
-					if err := e.createJob(child.Data.Job); err != nil {
-						key := util.KeyOf(child.Data.Job)
-						return fmt.Errorf("create job %s error: %v", key, err)
-					}
-				} else {
-					return ExceedParallelismError
+				if err := e.startVertex(child, graph, event.Key); err != nil {
+					return err
 				}
 			}
 		}
+	case JobRestart:
+		klog.V(2).Infof("job %v starts again.", event.Name)
+
+		vertex := graph.FindVertexByName(event.Name)
+		if vertex == nil {
+			return nil
+		}
+		return e.restartVertex(vertex, event.Key)
+	}
+	return nil
+}
+
+// restartVertex creates the job of the vertex again once the job of the
+// last attempt is gone.
+func (e *ExecutionJobController) restartVertex(vertex *graph.Vertex, key string) error {
+	job := vertex.Data.Job
+	if _, err := e.jobLister.Workloads(job.Namespace).Get(job.Name); err == nil {
+		return fmt.Errorf("job %s of the last attempt is still being deleted", util.KeyOf(job))
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	execution, err := e.executionLister.Executions(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if util.IsExecutionCompleted(execution) {
+		return nil
+	}
+	if err := e.createJob(job, getVertexTask(execution, vertex)); err != nil {
+		return fmt.Errorf("create job %s error: %v", util.KeyOf(job), err)
 	}
 	return nil
 }
 
-func (e *ExecutionJobController) evalGenericConditionResult(dependJob *batch.Job, vertex *graph.Vertex, graph *graph.Graph, key string) (bool, error) {
+// orderVertices returns the vertices ready to start in the launch order of the controller.
+func (e *ExecutionJobController) orderVertices(vertices []*graph.Vertex, key string) []*graph.Vertex {
+	if e.launchOrder != LaunchOrderPriority && e.launchOrder != LaunchOrderCriticalPath {
+		return vertices
+	}
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	execution, err := e.executionLister.Executions(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("Get execution %s error: %v", key, err)
+		return vertices
+	}
+	return orderVertices(e.launchOrder, e.durations, execution, vertices)
+}
+
+func (e *ExecutionJobController) evalGenericConditionResult(dependVertex *graph.Vertex, vertex *graph.Vertex, graph *graph.Graph, key string) (bool, error) {
 
 	klog.V(6).Infof("In evalGenericConditionResult GenericCondition:%v", vertex.Data.DynamicJob.GenericCondition)
 
 	genericCond := vertex.Data.DynamicJob.GenericCondition
 
 	// get the result of the dependent job
-	result, err := e.getJobResult(dependJob)
+	result, err := e.getJobResult(dependVertex, graph, key)
 	if err != nil {
 		return false, fmt.Errorf("getJobResult failed in evalGenericConditionResult: %v", err)
 	}
@@ -300,10 +376,10 @@ func (e *ExecutionJobController) evalGenericConditionResult(dependJob *batch.Job
 		}
 	}
 	klog.V(2).Infof("In evalGenericConditionResult Rules are not matched")
-	return false, fmt.Errorf("Rules are not matched")
+	return false, nil
 }
 
-func (e *ExecutionJobController) evalConditionResult(dependJob *batch.Job, vertex *graph.Vertex, graph *graph.Graph, key string) (bool, error) {
+func (e *ExecutionJobController) evalConditionResult(dependVertex *graph.Vertex, vertex *graph.Vertex, graph *graph.Graph, key string) (bool, error) {
 
 	klog.V(6).Infof("In evalConditionResult condition:%v", vertex.Data.DynamicJob.Condition.Condition)
 
@@ -314,22 +390,27 @@ func (e *ExecutionJobController) evalConditionResult(dependJob *batch.Job, verte
 	case bool:
 		return v[0].(bool), nil
 	case string:
+		if item, ok := v[0].(string); ok && item == "expression" && len(v) == 2 {
+			expression, _ := v[1].(string)
+			return evalExpression(expression, func(taskName string) (string, error) {
+				return e.getDependentResult(vertex, taskName, graph, key)
+			})
+		}
 		if item, ok := v[0].(string); ok && item == "check_result" {
 
 			parentJobName := v[1].(string)
 			exp := v[2].(string)
 			klog.V(6).Infof("In evalConditionResult jobName: %s exp:%s", parentJobName, exp)
 			// get the result of the dependent job
-			result, err := e.getJobResult(dependJob)
+			result, err := e.getJobResult(dependVertex, graph, key)
 			if err != nil {
 				return false, fmt.Errorf("getJobResult failed in evalConditionResult: %v", err)
 			}
 
-			if exp == result {
-				return true, nil
-			} else {
-				return false, fmt.Errorf("In evalConditionResult job result is %v but expected value is  %v", result, exp)
+			if exp != result {
+				klog.V(2).Infof("In evalConditionResult job result is %v but expected value is %v", result, exp)
 			}
+			return exp == result, nil
 		} else {
 			return false, fmt.Errorf("In evalConditionResult Invalid condition %v", vertex.Data.DynamicJob.Condition.Condition)
 		}
@@ -338,6 +419,264 @@ func (e *ExecutionJobController) evalConditionResult(dependJob *batch.Job, verte
 	return false, fmt.Errorf("In evalConditionResult Invalid condition %v", vertex.Data.DynamicJob.Condition.Condition)
 }
 
+// getDependentResult returns the result of the job of the task of the name
+// the vertex depends on.
+func (e *ExecutionJobController) getDependentResult(vertex *graph.Vertex, taskName string, g *graph.Graph, key string) (string, error) {
+	for _, dependent := range g.FindDependentsByName(vertex.Data.Job.Name) {
+		if dependVertex := g.FindVertex(dependent); vertexTaskName(dependVertex) == taskName {
+			return e.getJobResult(dependVertex, g, key)
+		}
+	}
+	return "", fmt.Errorf("job %s does not depend on task %s", vertex.Data.Job.Name, taskName)
+}
+
+// skipVertex marks the vertex as skipped and records it in the execution status.
+// Depending on the skip policy of its task, the dependents of a skipped vertex are
+// either skipped as well or started as if the vertex had succeeded.
+func (e *ExecutionJobController) skipVertex(vertex *graph.Vertex, g *graph.Graph, key string, message string) error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	execution, err := e.executionLister.Executions(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("Get execution %s error: %v", key, err)
+		return err
+	}
+	exec := execution.DeepCopy()
+
+	messages := make(map[*graph.Vertex]string)
+	collectSkippedVertices(exec, vertex, message, messages)
+
+	if exec.Status.Vertices == nil {
+		exec.Status.Vertices = make(map[string]genev1alpha1.VertexStatus)
+	}
+	for skipped, msg := range messages {
+		jobName := skipped.Data.Job.Name
+		vertexStatus := util.InitializeVertexStatus(jobName, genev1alpha1.VertexSkipped, msg, skipped.Children)
+		exec.Status.Vertices[vertexStatus.ID] = vertexStatus
+		util.MarkVertexSkipped(exec, jobName, msg)
+	}
+
+	setExecutionProgress(exec, g, len(messages))
+	completed := g.GetNumOfSuccess()+g.GetNumOfSkipped()+len(messages) == g.VertexCount+g.DynamicJobCnt
+	if completed {
+		if err := completeExecution(e.kubeClient, exec); err != nil {
+			klog.Errorf("complete execution %s error: %v", key, err)
+			return err
+		}
+	}
+
+	// Ask api server to update etcd data.
+	if err := e.execUpdater.UpdateExecutionStatus(exec, execution); err != nil {
+		klog.V(3).Infof("update execution %s status error: %#v", key, err)
+		return err
+	}
+
+	for skipped := range messages {
+		skipped.Data.Skipped = true
+		skipped.Data.Finished = true
+		g.PlusNumOfSkipped()
+	}
+	if completed {
+		return nil
+	}
+
+	// the dependents of the vertices that do not cascade the skip are
+	// triggered the same way as the dependents of a succeeded vertex.
+	for skipped := range messages {
+		if getSkipPolicy(exec, skipped) != genev1alpha1.SkipPolicyCascade {
+			e.queue.Add(Event{Type: JobsAfter, Name: skipped.Data.Job.Name, Key: key})
+		}
+	}
+
+	return nil
+}
+
+// collectSkippedVertices walks from the given vertex along the tasks whose skip
+// policy is Cascade, and records every vertex that has to be skipped together
+// with the reason.
+func collectSkippedVertices(exec *genev1alpha1.Execution, vertex *graph.Vertex, message string, messages map[*graph.Vertex]string) {
+	if _, ok := messages[vertex]; ok || vertex.Data.Finished {
+		return
+	}
+	messages[vertex] = message
+
+	if getSkipPolicy(exec, vertex) != genev1alpha1.SkipPolicyCascade {
+		return
+	}
+	for _, child := range vertex.Children {
+		collectSkippedVertices(exec, child, vertexSkippedByDependentMessage, messages)
+	}
+}
+
+// readsResultOf returns true if the dynamic task evaluates its condition or
+// its commands against the result of the task of the name.
+func readsResultOf(task *genev1alpha1.Task, taskName string) bool {
+	if task.GenericCondition != nil && task.GenericCondition.DependJobName == taskName {
+		return true
+	}
+	if task.Condition != nil && isResultFunc(task.Condition.Condition, "check_result", taskName) {
+		return true
+	}
+	if task.Condition != nil && expressionReadsResultOf(task.Condition.Condition, taskName) {
+		return true
+	}
+	if task.CommandsIter != nil {
+		for _, vars := range task.CommandsIter.VarsIter {
+			if isResultFunc(vars, "get_result", taskName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expressionReadsResultOf returns true if the condition is an expression
+// reading the result of the task of the name.
+func expressionReadsResultOf(condition interface{}, taskName string) bool {
+	v, ok := condition.([]interface{})
+	if !ok || len(v) != 2 || v[0] != "expression" {
+		return false
+	}
+	expression, _ := v[1].(string)
+	taskNames, _ := expressionResults(expression)
+	for _, name := range taskNames {
+		if name == taskName {
+			return true
+		}
+	}
+	return false
+}
+
+// isResultFunc returns true if value calls the function on the result of the task.
+func isResultFunc(value interface{}, funcName, taskName string) bool {
+	v, ok := value.([]interface{})
+	if !ok || len(v) < 2 {
+		return false
+	}
+	name, _ := v[0].(string)
+	target, _ := v[1].(string)
+	return name == funcName && target == taskName
+}
+
+// getSkipPolicy returns the skip policy of the task the vertex belongs to.
+func getSkipPolicy(exec *genev1alpha1.Execution, vertex *graph.Vertex) genev1alpha1.SkipPolicy {
+	if task := getVertexTask(exec, vertex); task != nil && len(task.SkipPolicy) != 0 {
+		return task.SkipPolicy
+	}
+	return genev1alpha1.SkipPolicyContinue
+}
+
+// startVertex creates the job of the vertex. If call caching is turned on for
+// the task and the same work has already succeeded, the vertex is marked as
+// succeeded instead.
+func (e *ExecutionJobController) startVertex(vertex *graph.Vertex, g *graph.Graph, key string) error {
+	job := vertex.Data.Job
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	execution, err := e.executionLister.Executions(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("Get execution %s error: %v", key, err)
+		return err
+	}
+
+	cacheKey, entry, err := e.lookupCache(execution, vertex, g)
+	if err != nil {
+		return fmt.Errorf("get cache of job %s error: %v", util.KeyOf(job), err)
+	}
+	// the tasks reading the result of the vertex need it to be recorded.
+	if entry != nil && (entry.Result != nil || !resultRead(execution, vertex)) {
+		return e.markVertexCached(vertex, g, key, entry)
+	}
+	if len(cacheKey) != 0 {
+		if job.Annotations == nil {
+			job.Annotations = make(map[string]string)
+		}
+		job.Annotations[cacheKeyAnnotation] = cacheKey
+	}
+
+	if !e.shouldStartJob(key, job) {
+		return ExceedParallelismError
+	}
+	task := getVertexTask(execution, vertex)
+	if task != nil && task.Type == genev1alpha1.IndexedJobTaskType {
+		if job, err = e.limitIndexedJob(execution, job); err != nil {
+			return err
+		}
+	}
+	if err := e.createJob(job, task); err != nil {
+		return fmt.Errorf("create job %s error: %v", util.KeyOf(job), err)
+	}
+	return nil
+}
+
+// lookupCache returns the cache key of the vertex and the entry recorded for
+// it, or an empty key if the result of the vertex is not cached.
+func (e *ExecutionJobController) lookupCache(exec *genev1alpha1.Execution, vertex *graph.Vertex, g *graph.Graph) (string, *jobcache.Entry, error) {
+	task := getVertexTask(exec, vertex)
+	if task == nil || !cacheEnabled(exec, task) {
+		return "", nil, nil
+	}
+	key := cacheKey(exec, g, vertex, make(map[*graph.Vertex]string))
+	if len(key) == 0 {
+		return "", nil, nil
+	}
+	entry, err := e.cacheStore.Get(exec.Namespace, key)
+	return key, entry, err
+}
+
+// markVertexCached marks the vertex as succeeded with the result of the job
+// recorded in the cache entry, and triggers its dependents.
+func (e *ExecutionJobController) markVertexCached(vertex *graph.Vertex, g *graph.Graph, key string, entry *jobcache.Entry) error {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+	execution, err := e.executionLister.Executions(namespace).Get(name)
+	if err != nil {
+		klog.Errorf("Get execution %s error: %v", key, err)
+		return err
+	}
+	exec := execution.DeepCopy()
+
+	jobName := vertex.Data.Job.Name
+	message := fmt.Sprintf(vertexCachedMessage, entry.Job, entry.Execution)
+	if exec.Status.Vertices == nil {
+		exec.Status.Vertices = make(map[string]genev1alpha1.VertexStatus)
+	}
+	vertexStatus := util.InitializeVertexStatus(jobName, genev1alpha1.VertexSucceeded, message, vertex.Children)
+	exec.Status.Vertices[vertexStatus.ID] = vertexStatus
+	util.MarkVertexSuccess(exec, jobName, message)
+	if len(exec.Status.Phase) == 0 {
+		util.MarkExecutionRunning(exec, executionRunningMessage)
+	}
+
+	setExecutionProgress(exec, g, 1)
+	completed := g.GetNumOfSuccess()+g.GetNumOfSkipped()+1 == g.VertexCount+g.DynamicJobCnt
+	if completed {
+		if err := completeExecution(e.kubeClient, exec); err != nil {
+			klog.Errorf("complete execution %s error: %v", key, err)
+			return err
+		}
+	}
+
+	// Ask api server to update etcd data.
+	if err := e.execUpdater.UpdateExecutionStatus(exec, execution); err != nil {
+		klog.V(3).Infof("update execution %s status error: %#v", key, err)
+		return err
+	}
+
+	klog.V(2).Infof("job %s is cached by job %s of execution %s", jobName, entry.Job, entry.Execution)
+	vertex.Data.Finished = true
+	g.PlusNumOfSuccess()
+	if !completed {
+		e.queue.Add(Event{Type: JobsAfter, Name: jobName, Key: key})
+	}
+	return nil
+}
+
+// setExecutionProgress records in the status of the execution how many vertices
+// of the graph have finished, pending is the number of finished vertices not yet
+// counted by the graph.
+func setExecutionProgress(exec *genev1alpha1.Execution, g *graph.Graph, pending int) {
+	finished := g.GetNumOfSuccess() + g.GetNumOfSkipped() + pending
+	exec.Status.Progress = fmt.Sprintf("%d/%d", finished, g.VertexCount+g.DynamicJobCnt)
+}
+
 func evalJobResult(jobResult string, vars []interface{}) ([]common.Var, error) {
 	result := make([]common.Var, 0, len(vars))
 	klog.V(6).Infof("In evalJobResult vars:%v", vars)
@@ -396,17 +735,16 @@ func (e *ExecutionJobController) createDynamicJobsBasedOnConditionalChk(vertex *
 		klog.Errorf("Get execution %s error: %v", key, err)
 		return err
 	}
-	//set the dynamic job Count of this vertex
-	vertex.SetDynamicJobCnt(len(task.CommandSet))
-
 	// create all the jobs here
 	jobNamePrefix := execution.Name + Separator + task.Name + Separator
-	for index, command := range task.CommandSet {
-		jobName := jobNamePrefix + strconv.Itoa(index)
-		// make up k8s job resource
-		job := newJob(jobName, command, execution, task)
+	jobs := newTaskJobs(jobNamePrefix, execution, task)
 
-		if err := e.createJob(job); err != nil {
+	//set the dynamic job Count of this vertex
+	vertex.SetDynamicJobCnt(len(jobs))
+
+	for _, job := range jobs {
+		restoreJobMemory(execution, job)
+		if err := e.createJob(job, task); err != nil {
 			klog.Errorf("createJob failed error: %v", err)
 			key := util.KeyOf(job)
 			return fmt.Errorf("create job %s error: %v", key, err)
@@ -414,7 +752,7 @@ func (e *ExecutionJobController) createDynamicJobsBasedOnConditionalChk(vertex *
 	}
 	//set the dynamic job Count of this vertex
 	// -1 because already one vertex  related to dynamic job is considered as one job
-	graph.AddDynamicJobCnt(len(task.CommandSet) - 1)
+	graph.AddDynamicJobCnt(len(jobs) - 1)
 
 	return nil
 }
@@ -454,17 +792,16 @@ func (e *ExecutionJobController) createDynamicJob(vertex *graph.Vertex, jobResul
 		klog.Errorf("Get execution %s error: %v", key, err)
 		return err
 	}
-	//set the dynamic job Count of this vertex
-	vertex.SetDynamicJobCnt(len(task.CommandSet))
-
 	// create all the jobs here
 	jobNamePrefix := execution.Name + Separator + task.Name + Separator
-	for index, command := range task.CommandSet {
-		jobName := jobNamePrefix + strconv.Itoa(index)
-		// make up k8s job resource
-		job := newJob(jobName, command, execution, task)
+	jobs := newTaskJobs(jobNamePrefix, execution, task)
+
+	//set the dynamic job Count of this vertex
+	vertex.SetDynamicJobCnt(len(jobs))
 
-		if err := e.createJob(job); err != nil {
+	for _, job := range jobs {
+		restoreJobMemory(execution, job)
+		if err := e.createJob(job, task); err != nil {
 			klog.Errorf("createJob failed error: %v", err)
 			key := util.KeyOf(job)
 			return fmt.Errorf("create job %s error: %v", key, err)
@@ -472,76 +809,134 @@ func (e *ExecutionJobController) createDynamicJob(vertex *graph.Vertex, jobResul
 	}
 	//set the dynamic job Count of this vertex
 	// -1 because already one vertex  related to dynamic job is considered as one job
-	graph.AddDynamicJobCnt(len(task.CommandSet) - 1)
+	graph.AddDynamicJobCnt(len(jobs) - 1)
 
 	return nil
 }
 
-func (e *ExecutionJobController) createJob(job *batch.Job) error {
-	_, err := e.jobLister.Jobs(job.Namespace).Get(job.Name)
-	// job has been already created
-	if !errors.IsNotFound(err) {
-		return nil
+// createJob creates the workload of the job through the executor of the task type.
+func (e *ExecutionJobController) createJob(job *workload.Workload, task *genev1alpha1.Task) error {
+	taskType := genev1alpha1.JobTaskType
+	if task != nil {
+		taskType = task.Type
+	}
+	executor, err := e.executorFor(taskType)
+	if err != nil {
+		return err
 	}
 
-	_, err = e.kubeClient.BatchV1().Jobs(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
-	if err != nil && errors.IsAlreadyExists(err) {
+	created, err := e.jobLister.Workloads(job.Namespace).Get(job.Name)
+	// job has not been created yet
+	if errors.IsNotFound(err) {
+		created, err = executor.Create(job, task)
+	}
+	if err != nil {
+		return err
+	}
+
+	if task == nil {
 		return nil
 	}
+	return createEphemeralClaims(e.kubeClient, created, task)
+}
+
+// getJobResult returns the result of the job of the vertex. The job of a
+// vertex served from the cache is not created, its result is read from the
+// cache entry instead.
+func (e *ExecutionJobController) getJobResult(vertex *graph.Vertex, g *graph.Graph, key string) (string, error) {
+	executor, err := e.executorFor(vertex.Data.TaskType)
+	if err != nil {
+		return "", err
+	}
+	job, err := e.jobLister.Workloads(vertex.Data.Job.Namespace).Get(vertex.Data.Job.Name)
+	if errors.IsNotFound(err) {
+		namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+		execution, getErr := e.executionLister.Executions(namespace).Get(name)
+		if getErr != nil {
+			return "", getErr
+		}
+		_, entry, cacheErr := e.lookupCache(execution, vertex, g)
+		if cacheErr != nil {
+			return "", cacheErr
+		}
+		if entry != nil && entry.Result != nil {
+			klog.V(2).Infof("the result of job %s is cached by job %s of execution %s", vertex.Data.Job.Name, entry.Job, entry.Execution)
+			return *entry.Result, nil
+		}
+	}
+	if err != nil {
+		klog.V(2).Infof("In getJobResult func get job failed: %v", err)
+		return "", err
+	}
 
-	return err
+	result, err := readJobResult(executor, job)
+	if err != nil {
+		return result, err
+	}
+	klog.V(2).Infof("the succful getJobResult is: %s", result)
+	return result, err
 }
 
-func (e *ExecutionJobController) getJobResult(job *batch.Job) (string, error) {
-	result := ""
-	job, err := e.jobLister.Jobs(job.Namespace).Get(job.Name)
+// jobResultLimit is the max size of the result of a job, 1k bytes with 100 bytes extra.
+const jobResultLimit = 1024 + 100
 
+// readJobResult returns the result of the job without the trailing newline.
+func readJobResult(executor TaskExecutor, job *workload.Workload) (string, error) {
+	result, err := executor.Result(job, jobResultLimit)
 	if err != nil {
-		klog.V(2).Infof("In getJobResult func get job failed: %v", err)
 		return result, err
 	}
+	return strings.TrimSuffix(result, "\n"), nil
+}
 
+// getJobLogs returns at most limitBytes of the logs of the task container
+// of the only pod of the job.
+func getJobLogs(kubeClient clientset.Interface, job *workload.Workload, limitBytes int64) (string, error) {
 	sel, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
 	if err != nil {
-		klog.V(2).Infof("In getJobResult func LabelSelectorAsSelector failed: %v", err)
-		return result, err
+		klog.V(2).Infof("In getJobLogs func LabelSelectorAsSelector failed: %v", err)
+		return "", err
 	}
 	var opts metav1.ListOptions
 
 	opts.LabelSelector = sel.String()
-	podList, err := e.kubeClient.CoreV1().Pods(job.Namespace).List(context.TODO(), opts)
+	podList, err := kubeClient.CoreV1().Pods(job.Namespace).List(context.TODO(), opts)
 	if err != nil {
-		klog.V(2).Infof("In getJobResult func get pods list failed: %v", err)
-		return result, err
+		klog.V(2).Infof("In getJobLogs func get pods list failed: %v", err)
+		return "", err
 	}
 
 	if len(podList.Items) != 1 {
-		klog.V(2).Infof("In getJobResult func  pods list has more than one pod ")
+		klog.V(2).Infof("In getJobLogs func  pods list has more than one pod ")
 		err := fmt.Errorf("Received  podList has more than one pod")
-		return result, err
+		return "", err
 	}
 
-	//size limit 1k bytes extra 100 bytes added
-	var sizeLimit int64
-	sizeLimit = 1024 + 100
+	return getPodLogs(kubeClient, &podList.Items[0], limitBytes)
+}
 
-	opt := v1.PodLogOptions{LimitBytes: &sizeLimit, SinceTime: &metav1.Time{}}
+// getPodLogs returns at most limitBytes of the logs of the task container of the pod.
+func getPodLogs(kubeClient clientset.Interface, pod *v1.Pod, limitBytes int64) (string, error) {
+	// the task container always comes first, the pod may also run
+	// the sidecar uploading output artifacts.
+	opt := v1.PodLogOptions{
+		Container:  pod.Spec.Containers[0].Name,
+		LimitBytes: &limitBytes,
+		SinceTime:  &metav1.Time{},
+	}
 
-	res, err := e.kubeClient.CoreV1().Pods(job.Namespace).GetLogs(podList.Items[0].Name,
-		&opt).Stream(context.TODO())
+	res, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &opt).Stream(context.TODO())
 	if err != nil {
-		klog.V(2).Infof("In getJobResult with opt func get logs failed: %v", err)
-		return result, err
+		klog.V(2).Infof("In getPodLogs with opt func get logs failed: %v", err)
+		return "", err
 	}
+	defer res.Close()
 	bytes, err := ioutil.ReadAll(res)
 	if err != nil {
-		klog.V(2).Infof("In getJobResult func ioutil.ReadAll failed: %v", err)
-		return result, err
+		klog.V(2).Infof("In getPodLogs func ioutil.ReadAll failed: %v", err)
+		return "", err
 	}
-	result = string(bytes)
-	result = strings.TrimSuffix(result, "\n")
-	klog.V(2).Infof("the succful getJobResult is: %s", result)
-	return result, err
+	return string(bytes), nil
 }
 
 func (e *ExecutionJobController) handleErr(err error, event Event) {
@@ -563,24 +958,25 @@ func (e *ExecutionJobController) handleErr(err error, event Event) {
 
 // getActiveJobsForExecution returns the set of running jobs that this Execution should manage.
 // Note that the returned Pods are pointers into the cache.
-func (e *ExecutionJobController) getActiveJobsForExecution(namespace string, selector labels.Selector) ([]*batch.Job, error) {
+func (e *ExecutionJobController) getActiveJobsForExecution(namespace string, selector labels.Selector) ([]*workload.Workload, error) {
 	// List all pods to include those that don't match the selector anymore
 	// but have a ControllerRef pointing to this controller.
-	jobs, err := e.jobLister.Jobs(namespace).List(selector)
+	jobs, err := e.jobLister.Workloads(namespace).List(selector)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*batch.Job
+	var result []*workload.Workload
 	for _, job := range jobs {
-		if !util.IsJobFinished(job) {
+		// the pending jobs have no pod yet.
+		if !job.Finished() && job.Status.Phase != workload.Pending {
 			result = append(result, job)
 		}
 	}
 	return result, nil
 }
 
-func (e *ExecutionJobController) shouldStartJob(key string, job *batch.Job) bool {
+func (e *ExecutionJobController) shouldStartJob(key string, job *workload.Workload) bool {
 	namespace, name, _ := cache.SplitMetaNamespaceKey(key)
 	execution, err := e.executionLister.Executions(namespace).Get(name)
 	if errors.IsNotFound(err) {
@@ -591,14 +987,22 @@ func (e *ExecutionJobController) shouldStartJob(key string, job *batch.Job) bool
 		klog.Errorf("Get execution %s error: %v", key, err)
 		return false
 	}
-	if execution.Spec.Parallelism != nil {
+	// the job is already running, e.g. an index of an Indexed Job.
+	if _, err := e.jobLister.Workloads(job.Namespace).Get(job.Name); err == nil {
+		return true
+	}
+	if execution.Spec.Parallelism != nil || execution.Spec.ParallelResources != nil {
 		jobs, err := e.getActiveJobsForExecution(job.Namespace, labels.Set(job.Labels).AsSelector())
 		if err != nil {
 			klog.Errorf("Get active jobs for execution %s error: %v", key, err)
 			return false
 		}
 
-		if len(jobs) >= int(*execution.Spec.Parallelism) {
+		if execution.Spec.Parallelism != nil && len(jobs) >= int(*execution.Spec.Parallelism) {
+			return false
+		}
+		// the requests of the running jobs leave no room for the job.
+		if !fitsParallelResources(execution.Spec.ParallelResources, jobs, job) {
 			return false
 		}
 	}
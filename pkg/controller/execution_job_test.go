@@ -0,0 +1,322 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/client/clientset/versioned/fake"
+	execinformers "kubegene.io/kubegene/pkg/client/informers/externalversions"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// indexerUpdater updates the executions in the indexer of the informer,
+// as the informer would once the api server has updated them.
+type indexerUpdater struct {
+	indexer cache.Indexer
+}
+
+func (u *indexerUpdater) UpdateExecutionStatus(modified *genev1alpha1.Execution, original *genev1alpha1.Execution) error {
+	return u.indexer.Update(modified)
+}
+
+func (u *indexerUpdater) UpdateExecution(modified *genev1alpha1.Execution, original *genev1alpha1.Execution) error {
+	return u.indexer.Update(modified)
+}
+
+// testExecutionController runs the jobs of an execution against fake clients.
+type testExecutionController struct {
+	*ExecutionController
+	kubeClient  *kubefake.Clientset
+	jobIndexer  cache.Indexer
+	execIndexer cache.Indexer
+}
+
+func newTestExecutionController(exec *genev1alpha1.Execution) *testExecutionController {
+	kubeClient := kubefake.NewSimpleClientset()
+	jobInformer := informers.NewSharedInformerFactory(kubeClient, 0).Batch().V1().Jobs()
+	execClient := fake.NewSimpleClientset()
+	execInformer := execinformers.NewSharedInformerFactory(execClient, 0).Execution().V1alpha1().Executions()
+	execInformer.Informer().GetIndexer().Add(exec)
+
+	c := NewExecutionController(&ControllerParameters{
+		EventRecorder:     record.NewFakeRecorder(10),
+		KubeClient:        kubeClient,
+		ExecutionClient:   execClient.ExecutionV1alpha1(),
+		JobInformer:       jobInformer,
+		ExecutionInformer: execInformer,
+	})
+	updater := &indexerUpdater{indexer: execInformer.Informer().GetIndexer()}
+	c.execStatusUpdater = updater
+	c.execJobController.execUpdater = updater
+	return &testExecutionController{
+		ExecutionController: c,
+		kubeClient:          kubeClient,
+		jobIndexer:          jobInformer.Informer().GetIndexer(),
+		execIndexer:         execInformer.Informer().GetIndexer(),
+	}
+}
+
+// processEvents runs the events of the event queue until it is empty.
+func (c *testExecutionController) processEvents() {
+	for c.eventQueue.Len() > 0 {
+		c.execJobController.processNextWorkItem()
+	}
+}
+
+// run syncs the execution, then completes the jobs created for it one
+// after the other until no job is left running. It returns the names of
+// the jobs created, in the order they have completed.
+func (c *testExecutionController) run(t *testing.T, exec *genev1alpha1.Execution) []string {
+	if err := c.syncExecution(util.KeyOf(exec)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	c.processEvents()
+
+	var completed []string
+	for {
+		jobs, err := c.kubeClient.BatchV1().Jobs(exec.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		sort.Slice(jobs.Items, func(i, j int) bool { return jobs.Items[i].Name < jobs.Items[j].Name })
+		var job *batch.Job
+		for i := range jobs.Items {
+			// the jobs in the indexer have completed.
+			if _, exists, _ := c.jobIndexer.GetByKey(util.KeyOf(&jobs.Items[i])); !exists {
+				job = &jobs.Items[i]
+				break
+			}
+		}
+		if job == nil {
+			return completed
+		}
+		job.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}}
+		c.jobIndexer.Add(job)
+		if _, err := c.syncJob(util.KeyOf(job)); err != nil {
+			t.Fatalf("sync job %s: expect no error, but got error %v", job.Name, err)
+		}
+		completed = append(completed, job.Name)
+		c.processEvents()
+	}
+}
+
+// execution returns the execution as last updated by the controller.
+func (c *testExecutionController) execution(t *testing.T, exec *genev1alpha1.Execution) *genev1alpha1.Execution {
+	obj, exists, err := c.execIndexer.GetByKey(util.KeyOf(exec))
+	if err != nil || !exists {
+		t.Fatalf("Expect execution %s, but got %v", util.KeyOf(exec), err)
+	}
+	return obj.(*genev1alpha1.Execution)
+}
+
+// newSkipExecution returns an execution in which b runs after a if its
+// condition is true, c runs after b, and d runs after b if its condition
+// is true.
+func newSkipExecution(bCondition interface{}, policy genev1alpha1.SkipPolicy, dCondition interface{}) *genev1alpha1.Execution {
+	after := func(target string) []genev1alpha1.Dependent {
+		return []genev1alpha1.Dependent{{Target: target, Type: genev1alpha1.DependTypeWhole}}
+	}
+	return &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{Name: "skip", Namespace: "default", UID: "skip-uid"},
+		Spec: genev1alpha1.ExecutionSpec{
+			Tasks: []genev1alpha1.Task{
+				{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo A"}},
+				{
+					Name: "b", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo B"},
+					Dependents: after("a"),
+					Condition:  &genev1alpha1.Condition{Condition: bCondition},
+					SkipPolicy: policy,
+				},
+				{Name: "c", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo C"}, Dependents: after("b")},
+				{
+					Name: "d", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo D"},
+					Dependents: after("b"),
+					Condition:  &genev1alpha1.Condition{Condition: dCondition},
+				},
+			},
+		},
+	}
+}
+
+func TestSkipVertices(t *testing.T) {
+	isTrue := []interface{}{true}
+	isFalse := []interface{}{false}
+	checkB := []interface{}{"check_result", "b", "done"}
+
+	testCases := []struct {
+		Name       string
+		BCondition interface{}
+		Policy     genev1alpha1.SkipPolicy
+		DCondition interface{}
+		// ExpectJobs are the jobs created, in the order they complete.
+		ExpectJobs []string
+		// ExpectPhases are the phases of the vertices.
+		ExpectPhases map[string]genev1alpha1.VertexPhase
+	}{
+		{
+			Name:       "nothing skipped",
+			BCondition: isTrue,
+			DCondition: isTrue,
+			ExpectJobs: []string{"skip.a.0", "skip.b.0", "skip.c.0", "skip.d.0"},
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"skip.a.0": genev1alpha1.VertexSucceeded,
+				"skip.b.0": genev1alpha1.VertexSucceeded,
+				"skip.c.0": genev1alpha1.VertexSucceeded,
+				"skip.d.0": genev1alpha1.VertexSucceeded,
+			},
+		},
+		{
+			Name:       "skip continues",
+			BCondition: isFalse,
+			Policy:     genev1alpha1.SkipPolicyContinue,
+			DCondition: isTrue,
+			ExpectJobs: []string{"skip.a.0", "skip.c.0", "skip.d.0"},
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"skip.a.0": genev1alpha1.VertexSucceeded,
+				"skip.b.":  genev1alpha1.VertexSkipped,
+				"skip.c.0": genev1alpha1.VertexSucceeded,
+				"skip.d.0": genev1alpha1.VertexSucceeded,
+			},
+		},
+		{
+			Name:       "skip continues except for the result",
+			BCondition: isFalse,
+			DCondition: checkB,
+			ExpectJobs: []string{"skip.a.0", "skip.c.0"},
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"skip.a.0": genev1alpha1.VertexSucceeded,
+				"skip.b.":  genev1alpha1.VertexSkipped,
+				"skip.c.0": genev1alpha1.VertexSucceeded,
+				"skip.d.":  genev1alpha1.VertexSkipped,
+			},
+		},
+		{
+			Name:       "skip cascades",
+			BCondition: isFalse,
+			Policy:     genev1alpha1.SkipPolicyCascade,
+			DCondition: isTrue,
+			ExpectJobs: []string{"skip.a.0"},
+			ExpectPhases: map[string]genev1alpha1.VertexPhase{
+				"skip.a.0": genev1alpha1.VertexSucceeded,
+				"skip.b.":  genev1alpha1.VertexSkipped,
+				"skip.c.0": genev1alpha1.VertexSkipped,
+				"skip.d.":  genev1alpha1.VertexSkipped,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := newSkipExecution(testCase.BCondition, testCase.Policy, testCase.DCondition)
+		c := newTestExecutionController(exec)
+
+		jobs := c.run(t, exec)
+		if !reflect.DeepEqual(jobs, testCase.ExpectJobs) {
+			t.Errorf("%s: Expect jobs %v, but got %v", testCase.Name, testCase.ExpectJobs, jobs)
+		}
+
+		updated := c.execution(t, exec)
+		for name, phase := range testCase.ExpectPhases {
+			vertexStatus := util.GetVertexStatus(updated, name)
+			if vertexStatus == nil || vertexStatus.Phase != phase {
+				t.Errorf("%s: Expect vertex %s %s, but got %v", testCase.Name, name, phase, vertexStatus)
+			}
+		}
+		if updated.Status.Phase != genev1alpha1.VertexSucceeded {
+			t.Errorf("%s: Expect execution succeeded, but got %s: %s", testCase.Name, updated.Status.Phase, updated.Status.Message)
+		}
+	}
+}
+
+func TestGetVertexTask(t *testing.T) {
+	exec := validateExecution()
+	testCases := []struct {
+		JobName string
+		Expect  string
+	}{
+		{JobName: "simple-example.b.0", Expect: "b"},
+		{JobName: "simple-example.b.", Expect: "b"},
+		{JobName: "simple-example.z.0", Expect: ""},
+		{JobName: "b", Expect: ""},
+		{JobName: "", Expect: ""},
+	}
+
+	for _, testCase := range testCases {
+		vertex := graph.NewVertex(graph.NewJobInfo(&workload.Workload{ObjectMeta: metav1.ObjectMeta{Name: testCase.JobName}}, false, "", nil), false)
+		name := ""
+		if task := getVertexTask(exec, vertex); task != nil {
+			name = task.Name
+		}
+		if name != testCase.Expect {
+			t.Errorf("%s: Expect task %q, but got %q", testCase.JobName, testCase.Expect, name)
+		}
+	}
+}
+
+func TestFailedJobKeepsOtherJobs(t *testing.T) {
+	exec := &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{Name: "fail", Namespace: "default", UID: "fail-uid"},
+		Spec: genev1alpha1.ExecutionSpec{
+			Tasks: []genev1alpha1.Task{
+				{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"exit 1"}},
+				{Name: "b", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo B"}},
+			},
+		},
+	}
+	c := newTestExecutionController(exec)
+	if err := c.syncExecution(util.KeyOf(exec)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	c.processEvents()
+
+	running, err := c.kubeClient.BatchV1().Jobs(exec.Namespace).Get(context.TODO(), "fail.b.0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect job fail.b.0, but got error %v", err)
+	}
+	c.jobIndexer.Add(running)
+	job, err := c.kubeClient.BatchV1().Jobs(exec.Namespace).Get(context.TODO(), "fail.a.0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect job fail.a.0, but got error %v", err)
+	}
+	job.Status.Conditions = []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue}}
+	c.jobIndexer.Add(job)
+	if _, err := c.syncJob(util.KeyOf(job)); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+
+	if updated := c.execution(t, exec); updated.Status.Phase != genev1alpha1.VertexFailed {
+		t.Errorf("Expect execution failed, but got %s", updated.Status.Phase)
+	}
+	// the jobs already running go on after another job has failed.
+	if _, err := c.kubeClient.BatchV1().Jobs(exec.Namespace).Get(context.TODO(), "fail.b.0", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expect job fail.b.0 to keep running, but got error %v", err)
+	}
+}
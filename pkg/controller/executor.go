@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// TaskExecutor runs the jobs of the tasks of a type. newJob describes every
+// job as a workload, the executor runs it as the object of its backend and
+// presents the object back as a workload, so that building the graph and
+// scheduling the jobs do not depend on the backend.
+type TaskExecutor interface {
+	// Create creates the object running the job, and returns it as a workload.
+	// The object already existing is not an error.
+	Create(job *workload.Workload, task *genev1alpha1.Task) (*workload.Workload, error)
+	// Status returns Succeeded or Failed with a message once the job has
+	// finished, and Running while it is running.
+	Status(job *workload.Workload) (workload.Phase, string)
+	// Result returns at most limitBytes of the output of the job command.
+	Result(job *workload.Workload, limitBytes int64) (string, error)
+	// Cancel stops the job if it is still running.
+	Cancel(job *workload.Workload) error
+	// Cleanup releases what the job holds once it has succeeded,
+	// e.g. the claims of its ephemeral volumes.
+	Cleanup(job *workload.Workload, task *genev1alpha1.Task) error
+}
+
+// backgroundDeletion deletes the pods of a workload after the workload.
+var backgroundDeletion = metav1.DeletePropagationBackground
+
+// batchJobExecutor runs the jobs as batch Jobs.
+type batchJobExecutor struct {
+	kubeClient clientset.Interface
+}
+
+var _ TaskExecutor = &batchJobExecutor{}
+
+func (b *batchJobExecutor) Create(job *workload.Workload, task *genev1alpha1.Task) (*workload.Workload, error) {
+	created, err := b.kubeClient.BatchV1().Jobs(job.Namespace).Create(context.TODO(), workload.NewBatchJob(job), metav1.CreateOptions{})
+	if err != nil && errors.IsAlreadyExists(err) {
+		created, err = b.kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return workload.FromBatchJob(created), nil
+}
+
+func (b *batchJobExecutor) Status(job *workload.Workload) (workload.Phase, string) {
+	return job.Status.Phase, job.Status.Message
+}
+
+func (b *batchJobExecutor) Result(job *workload.Workload, limitBytes int64) (string, error) {
+	return getJobLogs(b.kubeClient, job, limitBytes)
+}
+
+func (b *batchJobExecutor) Cancel(job *workload.Workload) error {
+	if job.Finished() {
+		return nil
+	}
+	err := b.kubeClient.BatchV1().Jobs(job.Namespace).Delete(context.TODO(), job.Name,
+		metav1.DeleteOptions{PropagationPolicy: &backgroundDeletion})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (b *batchJobExecutor) Cleanup(job *workload.Workload, task *genev1alpha1.Task) error {
+	return deleteEphemeralClaims(b.kubeClient, job, task)
+}
+
+// executorFor returns the executor running the jobs of the tasks of the type.
+func (e *ExecutionJobController) executorFor(taskType genev1alpha1.TaskType) (TaskExecutor, error) {
+	executor, ok := e.executors[taskType]
+	if !ok {
+		return nil, fmt.Errorf(executorMissingMessage, taskType)
+	}
+	return executor, nil
+}
+
+// validateExecutors returns an error if no executor runs the tasks of some type of the execution.
+func (e *ExecutionJobController) validateExecutors(exec *genev1alpha1.Execution) error {
+	for _, task := range exec.Spec.Tasks {
+		if _, err := e.executorFor(task.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func TestBatchJobExecutor(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	job := newJob("example.a.0", "echo A", exec, task)
+	kubeClient := fake.NewSimpleClientset()
+	executor := &batchJobExecutor{kubeClient: kubeClient}
+
+	// creating the job twice returns the existing job.
+	for i := 0; i < 2; i++ {
+		if _, err := executor.Create(job, task); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+	}
+
+	finished := job.DeepCopy()
+	finished.Status = workload.Status{Phase: workload.Failed, Message: "failed"}
+	if phase, message := executor.Status(finished); phase != workload.Failed || message != "failed" {
+		t.Errorf("Expect job failed, but got %s %s", phase, message)
+	}
+	// a finished job is kept.
+	if err := executor.Cancel(finished); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expect the finished job to be kept, but got %v", err)
+	}
+	// a running job is deleted, cancelling it twice is fine.
+	for i := 0; i < 2; i++ {
+		if err := executor.Cancel(job); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+	}
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expect the running job to be deleted, but got %v", err)
+	}
+}
+
+func TestValidateExecutors(t *testing.T) {
+	e := &ExecutionJobController{executors: map[genev1alpha1.TaskType]TaskExecutor{
+		genev1alpha1.JobTaskType: &batchJobExecutor{},
+	}}
+	exec := validateExecution()
+	if err := e.validateExecutors(exec); err != nil {
+		t.Errorf("Expect no error, but got error %v", err)
+	}
+	exec.Spec.Tasks[0].Type = genev1alpha1.PodTaskType
+	if err := e.validateExecutors(exec); err == nil {
+		t.Errorf("Expect error, but got nil")
+	}
+}
+
+func TestUnionJobLister(t *testing.T) {
+	exec := validateExecution()
+	jobInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Batch().V1().Jobs()
+	jobInformer.Informer().GetIndexer().Add(&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: exec.Namespace}})
+	// the Indexed Jobs are listed through their indexes instead.
+	jobInformer.Informer().GetIndexer().Add(&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: exec.Namespace,
+		Labels: map[string]string{indexedjob.Label: "true"}}})
+	podInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Pods()
+	podInformer.Informer().GetIndexer().Add(newPod(newJob("b", "echo B", exec, &exec.Spec.Tasks[0])))
+
+	lister := unionJobLister{workload.NewBatchLister(jobInformer.Lister(), isPlainBatchJob), &podJobLister{lister: podInformer.Lister()}}
+	jobs, err := lister.Workloads(exec.Namespace).List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Errorf("Expect 2 jobs, but got %d", len(jobs))
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, err := lister.Workloads(exec.Namespace).Get(name); err != nil {
+			t.Errorf("Expect job %s, but got error %v", name, err)
+		}
+	}
+	for _, name := range []string{"c", "d"} {
+		if _, err := lister.Workloads(exec.Namespace).Get(name); !errors.IsNotFound(err) {
+			t.Errorf("Expect not found error for job %s, but got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// resultFunc returns the result of the job of the task of the name.
+type resultFunc func(taskName string) (string, error)
+
+// evalExpression evaluates the expression of a condition. It compares numbers
+// and strings with ==, !=, <, <=, > and >=, and combines booleans with &&, ||
+// and !, all with the go syntax. result("a") is the result of the job of task
+// a, a string, and number(s) is the number the string s holds, e.g.
+// `number(result("depth")) > 30 && result("ref") == "hg38"`. The inputs of
+// the workflow are replaced in the expression before it gets here.
+func evalExpression(expression string, result resultFunc) (bool, error) {
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %q: %v", expression, err)
+	}
+	value, err := evalValue(expr, result)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %q: %v", expression, err)
+	}
+	if value.Kind() != constant.Bool {
+		return false, fmt.Errorf("expression %q is not a boolean", expression)
+	}
+	return constant.BoolVal(value), nil
+}
+
+// expressionResults returns the names of the tasks whose results the
+// expression reads, or an error if the expression is invalid.
+func expressionResults(expression string) ([]string, error) {
+	var taskNames []string
+	// the results are numbers in strings, so that number() succeeds.
+	_, err := evalExpression(expression, func(taskName string) (string, error) {
+		taskNames = append(taskNames, taskName)
+		return "0", nil
+	})
+	return taskNames, err
+}
+
+func evalValue(expr ast.Expr, result resultFunc) (constant.Value, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if value := constant.MakeFromLiteral(e.Value, e.Kind, 0); value.Kind() != constant.Unknown {
+			return value, nil
+		}
+		return nil, fmt.Errorf("invalid literal %s", e.Value)
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return constant.MakeBool(true), nil
+		case "false":
+			return constant.MakeBool(false), nil
+		}
+		return nil, fmt.Errorf("unknown identifier %s", e.Name)
+	case *ast.ParenExpr:
+		return evalValue(e.X, result)
+	case *ast.CallExpr:
+		return evalCall(e, result)
+	case *ast.UnaryExpr:
+		x, err := evalValue(e.X, result)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case e.Op == token.NOT && x.Kind() == constant.Bool,
+			(e.Op == token.SUB || e.Op == token.ADD) && isNumeric(x):
+			return constant.UnaryOp(e.Op, x, 0), nil
+		}
+		return nil, fmt.Errorf("invalid operation %s%s", e.Op, x)
+	case *ast.BinaryExpr:
+		x, err := evalValue(e.X, result)
+		if err != nil {
+			return nil, err
+		}
+		y, err := evalValue(e.Y, result)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case token.LAND, token.LOR:
+			if x.Kind() == constant.Bool && y.Kind() == constant.Bool {
+				return constant.BinaryOp(x, e.Op, y), nil
+			}
+		case token.EQL, token.NEQ:
+			if x.Kind() == y.Kind() || isNumeric(x) && isNumeric(y) {
+				return constant.MakeBool(constant.Compare(x, e.Op, y)), nil
+			}
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			if x.Kind() == constant.String && y.Kind() == constant.String || isNumeric(x) && isNumeric(y) {
+				return constant.MakeBool(constant.Compare(x, e.Op, y)), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid operation %s %s %s", x, e.Op, y)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", expr)
+}
+
+// evalCall evaluates the calls of the functions result and number.
+func evalCall(call *ast.CallExpr, result resultFunc) (constant.Value, error) {
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || len(call.Args) != 1 {
+		return nil, fmt.Errorf("unsupported call of %T", call.Fun)
+	}
+	arg, err := evalValue(call.Args[0], result)
+	if err != nil {
+		return nil, err
+	}
+	if arg.Kind() != constant.String {
+		return nil, fmt.Errorf("invalid argument %s of %s, expect a string", arg, fun.Name)
+	}
+	switch fun.Name {
+	case "result":
+		value, err := result(constant.StringVal(arg))
+		if err != nil {
+			return nil, err
+		}
+		return constant.MakeString(value), nil
+	case "number":
+		return parseNumber(constant.StringVal(arg))
+	}
+	return nil, fmt.Errorf("unknown function %s", fun.Name)
+}
+
+// parseNumber returns the number the string holds, surrounding blanks aside.
+func parseNumber(s string) (constant.Value, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return constant.MakeInt64(n), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return constant.MakeFloat64(f), nil
+	}
+	return nil, fmt.Errorf("%q is not a number", s)
+}
+
+func isNumeric(value constant.Value) bool {
+	return value.Kind() == constant.Int || value.Kind() == constant.Float
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/jobcache"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+func TestEvalExpression(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Expression string
+		Expect     bool
+		ExpectErr  bool
+	}{
+		{
+			Name:       "number comparison",
+			Expression: "45 > 30",
+			Expect:     true,
+		},
+		{
+			Name:       "int and float comparison",
+			Expression: "2 <= 1.5",
+			Expect:     false,
+		},
+		{
+			Name:       "string comparison",
+			Expression: `"hg38" == "hg38" && "a" < "b"`,
+			Expect:     true,
+		},
+		{
+			Name:       "negation and parentheses",
+			Expression: "!(1 == 1 || false)",
+			Expect:     false,
+		},
+		{
+			Name:       "negative number",
+			Expression: "-3 < 0",
+			Expect:     true,
+		},
+		{
+			Name:       "not a boolean",
+			Expression: "1",
+			ExpectErr:  true,
+		},
+		{
+			Name:       "string and number comparison",
+			Expression: `"1" == 1`,
+			ExpectErr:  true,
+		},
+		{
+			Name:       "arithmetic",
+			Expression: "1 + 1 == 2",
+			ExpectErr:  true,
+		},
+		{
+			Name:       "unknown identifier",
+			Expression: "depth > 30",
+			ExpectErr:  true,
+		},
+		{
+			Name:       "syntax error",
+			Expression: "1 >",
+			ExpectErr:  true,
+		},
+		{
+			Name:       "results",
+			Expression: `number(result("depth")) > 30 && result("ref") == "hg38"`,
+			Expect:     true,
+		},
+		{
+			Name:       "float result",
+			Expression: `number(result("ratio")) < 0.5`,
+			Expect:     false,
+		},
+		{
+			Name:       "result compared with a number",
+			Expression: `result("depth") > 30`,
+			ExpectErr:  true,
+		},
+		{
+			Name:       "result not a number",
+			Expression: `number(result("ref")) > 30`,
+			ExpectErr:  true,
+		},
+		{
+			Name:       "missing result",
+			Expression: `result("missing") == ""`,
+			ExpectErr:  true,
+		},
+		{
+			Name:       "unknown function",
+			Expression: `len(result("ref")) > 1`,
+			ExpectErr:  true,
+		},
+		{
+			Name:       "result of a number",
+			Expression: `result(1) == ""`,
+			ExpectErr:  true,
+		},
+	}
+
+	results := map[string]string{"depth": " 45", "ref": "hg38", "ratio": "0.75"}
+	resultOf := func(taskName string) (string, error) {
+		if result, ok := results[taskName]; ok {
+			return result, nil
+		}
+		return "", fmt.Errorf("no result of task %s", taskName)
+	}
+	for _, testCase := range testCases {
+		result, err := evalExpression(testCase.Expression, resultOf)
+		if testCase.ExpectErr && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if !testCase.ExpectErr && err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+		}
+		if err == nil && result != testCase.Expect {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, result)
+		}
+	}
+}
+
+func TestExpressionResults(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Expression string
+		Expect     []string
+		ExpectErr  bool
+	}{
+		{
+			Name:       "no result",
+			Expression: "1 < 2",
+		},
+		{
+			Name:       "results",
+			Expression: `number(result("a")) > 30 || result("b") != "done"`,
+			Expect:     []string{"a", "b"},
+		},
+		{
+			Name:       "invalid expression",
+			Expression: `result("a") > 30`,
+			ExpectErr:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		taskNames, err := expressionResults(testCase.Expression)
+		if testCase.ExpectErr != (err != nil) {
+			t.Errorf("%s: Expect error %v, but got %v", testCase.Name, testCase.ExpectErr, err)
+		}
+		if err == nil && !reflect.DeepEqual(taskNames, testCase.Expect) {
+			t.Errorf("%s: Expect results of %v, but got %v", testCase.Name, testCase.Expect, taskNames)
+		}
+	}
+}
+
+func TestExpressionCondition(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Expression string
+		// ExpectJobs are the jobs created, in the order they complete.
+		ExpectJobs  []string
+		ExpectPhase genev1alpha1.VertexPhase
+	}{
+		{
+			Name:        "results satisfy the expression",
+			Expression:  `number(result("a")) > 30 && result("b") == "hg38"`,
+			ExpectJobs:  []string{"expr.c.0"},
+			ExpectPhase: genev1alpha1.VertexSucceeded,
+		},
+		{
+			Name:        "results do not satisfy the expression",
+			Expression:  `number(result("a")) > 50 || result("b") != "hg38"`,
+			ExpectPhase: genev1alpha1.VertexSkipped,
+		},
+	}
+
+	for _, testCase := range testCases {
+		enabled := true
+		exec := &genev1alpha1.Execution{
+			ObjectMeta: metav1.ObjectMeta{Name: "expr", Namespace: "default", UID: "expr-uid"},
+			Spec: genev1alpha1.ExecutionSpec{
+				Cache: &enabled,
+				Tasks: []genev1alpha1.Task{
+					{Name: "a", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo 45"}},
+					{Name: "b", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo hg38"}},
+					{
+						Name: "c", Type: genev1alpha1.JobTaskType, Image: "busybox", CommandSet: []string{"echo C"},
+						Dependents: []genev1alpha1.Dependent{
+							{Target: "a", Type: genev1alpha1.DependTypeWhole},
+							{Target: "b", Type: genev1alpha1.DependTypeWhole},
+						},
+						Condition: &genev1alpha1.Condition{Condition: []interface{}{"expression", testCase.Expression}},
+					},
+				},
+			},
+		}
+		c := newTestExecutionController(exec)
+		// the parents are served from the cache with their results.
+		for name, result := range map[string]string{"a": "45", "b": "hg38"} {
+			result := result
+			entry := &jobcache.Entry{
+				Key:       getCacheKey(exec, "expr."+name+".0"),
+				Execution: "earlier",
+				Job:       "earlier." + name + ".0",
+				CreatedAt: time.Now(),
+				Result:    &result,
+			}
+			if err := c.cacheStore.Put(exec.Namespace, entry); err != nil {
+				t.Fatalf("%s: put cache entry error: %v", testCase.Name, err)
+			}
+		}
+
+		jobs := c.run(t, exec)
+		if !reflect.DeepEqual(jobs, testCase.ExpectJobs) {
+			t.Errorf("%s: Expect jobs %v, but got %v", testCase.Name, testCase.ExpectJobs, jobs)
+		}
+		name := "expr.c.0"
+		if testCase.ExpectPhase == genev1alpha1.VertexSkipped {
+			name = "expr.c."
+		}
+		if vertexStatus := util.GetVertexStatus(c.execution(t, exec), name); vertexStatus == nil || vertexStatus.Phase != testCase.ExpectPhase {
+			t.Errorf("%s: Expect vertex %s %s, but got %v", testCase.Name, name, testCase.ExpectPhase, vertexStatus)
+		}
+	}
+}
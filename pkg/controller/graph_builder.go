@@ -17,15 +17,14 @@ limitations under the License.
 package controller
 
 import (
-	"strconv"
 	"strings"
 	"sync"
 
-	batch "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/workload"
 )
 
 // Separator used to construct job name.
@@ -81,6 +80,23 @@ func newGraph(execution *genev1alpha1.Execution) *graph.Graph {
 		if task.Affinity == nil {
 			task.Affinity = execution.Spec.Affinity
 		}
+		task.Env = mergeEnv(execution.Spec.Env, task.Env)
+		if len(task.EnvFrom) == 0 {
+			task.EnvFrom = execution.Spec.EnvFrom
+		}
+		if len(task.ServiceAccountName) == 0 {
+			task.ServiceAccountName = execution.Spec.ServiceAccountName
+		}
+		if len(task.ImagePullSecrets) == 0 {
+			task.ImagePullSecrets = execution.Spec.ImagePullSecrets
+		}
+		if len(task.ImagePullPolicy) == 0 {
+			task.ImagePullPolicy = execution.Spec.ImagePullPolicy
+		}
+		if task.SecurityContext == nil {
+			task.SecurityContext = execution.Spec.SecurityContext
+		}
+		task.Volcano = mergeVolcanoOptions(execution.Spec.Volcano, task.Volcano)
 
 		jobNamePrefix := execution.Name + Separator + task.Name + Separator
 
@@ -95,10 +111,8 @@ func newGraph(execution *genev1alpha1.Execution) *graph.Graph {
 
 		} else {
 
-			for index, command := range task.CommandSet {
-				jobName := jobNamePrefix + strconv.Itoa(index)
-				// make up k8s job resource
-				job := newJob(jobName, command, execution, &task)
+			for _, job := range newTaskJobs(jobNamePrefix, execution, &task) {
+				restoreJobMemory(execution, job)
 				jobInfo := graph.NewJobInfo(job, false, task.Type, nil)
 				jobInfos = append(jobInfos, jobInfo)
 				vertices = append(vertices, graph.NewVertex(jobInfo, false))
@@ -125,9 +139,11 @@ func newGraph(execution *genev1alpha1.Execution) *graph.Graph {
 						}
 					}
 				case genev1alpha1.DependTypeIterate:
+					_, target := getTaskByName(execution.Spec.Tasks, dependent.Target)
 					for index, jobInfo := range jobInfos {
 						items := strings.Split(jobInfo.Job.Name, Separator)
-						if dependent.Target == items[len(items)-2] && jobSuffix == items[len(items)-1] {
+						if dependent.Target == items[len(items)-2] &&
+							commandsOverlap(&task, jobSuffix, &target, items[len(items)-1]) {
 							vertices[index].AddChild(vertices[vertexIndex])
 						}
 					}
@@ -151,55 +167,103 @@ func newGraph(execution *genev1alpha1.Execution) *graph.Graph {
 	return g
 }
 
-func newJob(name, command string, exec *genev1alpha1.Execution, task *genev1alpha1.Task) *batch.Job {
+// mergeEnv returns the env of the execution extended by the env of the task,
+// the variables of the task override the ones of the execution with the same name.
+func mergeEnv(execEnv, taskEnv []v1.EnvVar) []v1.EnvVar {
+	if len(execEnv) == 0 {
+		return taskEnv
+	}
+	if len(taskEnv) == 0 {
+		return execEnv
+	}
+	overridden := make(map[string]bool, len(taskEnv))
+	for _, env := range taskEnv {
+		overridden[env.Name] = true
+	}
+	merged := []v1.EnvVar{}
+	for _, env := range execEnv {
+		if !overridden[env.Name] {
+			merged = append(merged, env)
+		}
+	}
+	return append(merged, taskEnv...)
+}
+
+// restartPolicy returns the restart policy of the pods running the jobs of
+// the task. The tasks run as bare pods or as Indexed Jobs retry a failed
+// command in a new pod rather than in place.
+func restartPolicy(task *genev1alpha1.Task) v1.RestartPolicy {
+	if task.Type == genev1alpha1.PodTaskType || task.Type == genev1alpha1.IndexedJobTaskType {
+		return v1.RestartPolicyNever
+	}
+	return v1.RestartPolicyOnFailure
+}
+
+func newJob(name, command string, exec *genev1alpha1.Execution, task *genev1alpha1.Task) *workload.Workload {
 	volumes := []v1.Volume{}
 	volumeMounts := []v1.VolumeMount{}
-	for name, volume := range task.Volumes {
+	for volumeName, volume := range task.Volumes {
 		volumes = append(volumes, v1.Volume{
-			Name: name,
-			VolumeSource: v1.VolumeSource{
-				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
-					ClaimName: volume.MountFrom.Pvc,
-				},
-			},
+			Name:         volumeName,
+			VolumeSource: newVolumeSource(name, volumeName, volume),
 		})
 
 		volumeMounts = append(volumeMounts, v1.VolumeMount{
-			Name:      name,
+			Name:      volumeName,
 			MountPath: volume.MountPath,
+			ReadOnly:  volume.ReadOnly,
 		})
 	}
 
+	imagePullPolicy := task.ImagePullPolicy
+	if len(imagePullPolicy) == 0 {
+		imagePullPolicy = v1.PullIfNotPresent
+	}
+
 	controllerRef := metav1.NewControllerRef(exec, execKind)
 	containerName := strings.Replace(name, ".", "-", -1)
 
-	return &batch.Job{
-		TypeMeta: metav1.TypeMeta{Kind: "Job"},
+	job := &workload.Workload{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       exec.Namespace,
 			Labels:          map[string]string{"controller-uid": string(exec.UID)},
 			OwnerReferences: []metav1.OwnerReference{*controllerRef},
 		},
-		Spec: batch.JobSpec{
+		Spec: workload.Spec{
 			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{ExecutionUIDLabel: string(exec.UID)},
+					Annotations: map[string]string{executionAnnotation: exec.Name, vertexAnnotation: name},
+				},
 				Spec: v1.PodSpec{
-					RestartPolicy: v1.RestartPolicyOnFailure,
+					RestartPolicy: restartPolicy(task),
 					Containers: []v1.Container{
 						{
 							Name:            containerName,
 							Image:           task.Image,
 							Command:         []string{"sh", "-c", command},
+							Env:             task.Env,
+							EnvFrom:         task.EnvFrom,
 							VolumeMounts:    volumeMounts,
-							ImagePullPolicy: v1.PullIfNotPresent,
+							ImagePullPolicy: imagePullPolicy,
+							SecurityContext: task.SecurityContext,
+							Resources:       v1.ResourceRequirements{Requests: taskRequests(task)},
 						},
 					},
-					NodeSelector: task.NodeSelector,
-					Affinity:     task.Affinity,
-					Tolerations:  task.Tolerations,
-					Volumes:      volumes,
+					ServiceAccountName: task.ServiceAccountName,
+					ImagePullSecrets:   task.ImagePullSecrets,
+					NodeSelector:       task.NodeSelector,
+					Affinity:           task.Affinity,
+					Tolerations:        task.Tolerations,
+					Volumes:            volumes,
 				},
 			},
 		},
 	}
+
+	injectArtifacts(job, exec, task)
+	injectContainers(job, task)
+
+	return job
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+func TestNewGraphPodOptions(t *testing.T) {
+	exec := validateExecution()
+	exec.Spec.Env = []v1.EnvVar{{Name: "FOO", Value: "exec"}, {Name: "BAR", Value: "exec"}}
+	exec.Spec.ServiceAccountName = "exec-sa"
+	exec.Spec.ImagePullSecrets = []v1.LocalObjectReference{{Name: "registry"}}
+	exec.Spec.ImagePullPolicy = v1.PullAlways
+	exec.Spec.Tasks[1].Env = []v1.EnvVar{{Name: "FOO", Value: "task"}}
+	exec.Spec.Tasks[1].ServiceAccountName = "task-sa"
+	exec.Spec.Tasks[1].ImagePullPolicy = v1.PullNever
+
+	g := newGraph(exec)
+
+	testCases := []struct {
+		Name                  string
+		JobName               string
+		ExpectEnv             []v1.EnvVar
+		ExpectServiceAccount  string
+		ExpectImagePullPolicy v1.PullPolicy
+	}{
+		{
+			Name:                  "task inherits the options of the execution",
+			JobName:               "a.0",
+			ExpectEnv:             []v1.EnvVar{{Name: "FOO", Value: "exec"}, {Name: "BAR", Value: "exec"}},
+			ExpectServiceAccount:  "exec-sa",
+			ExpectImagePullPolicy: v1.PullAlways,
+		},
+		{
+			Name:                  "task overrides the options of the execution",
+			JobName:               "b.0",
+			ExpectEnv:             []v1.EnvVar{{Name: "BAR", Value: "exec"}, {Name: "FOO", Value: "task"}},
+			ExpectServiceAccount:  "task-sa",
+			ExpectImagePullPolicy: v1.PullNever,
+		},
+	}
+
+	for _, testCase := range testCases {
+		vertex := g.FindVertexByName(exec.Name + Separator + testCase.JobName)
+		if vertex == nil {
+			t.Errorf("%s: Expect vertex %s, but got nil", testCase.Name, testCase.JobName)
+			continue
+		}
+		podSpec := vertex.Data.Job.Spec.Template.Spec
+		container := podSpec.Containers[0]
+		if !reflect.DeepEqual(container.Env, testCase.ExpectEnv) {
+			t.Errorf("%s: Expect env %v, but got %v", testCase.Name, testCase.ExpectEnv, container.Env)
+		}
+		if podSpec.ServiceAccountName != testCase.ExpectServiceAccount {
+			t.Errorf("%s: Expect serviceAccountName %s, but got %s", testCase.Name, testCase.ExpectServiceAccount, podSpec.ServiceAccountName)
+		}
+		if container.ImagePullPolicy != testCase.ExpectImagePullPolicy {
+			t.Errorf("%s: Expect imagePullPolicy %s, but got %s", testCase.Name, testCase.ExpectImagePullPolicy, container.ImagePullPolicy)
+		}
+		if !reflect.DeepEqual(podSpec.ImagePullSecrets, exec.Spec.ImagePullSecrets) {
+			t.Errorf("%s: Expect imagePullSecrets %v, but got %v", testCase.Name, exec.Spec.ImagePullSecrets, podSpec.ImagePullSecrets)
+		}
+	}
+}
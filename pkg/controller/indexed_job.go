@@ -0,0 +1,280 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// indexedJobExecutor runs all the jobs of a task as one Indexed Job. The
+// job of each index is created together with the Indexed Job by the
+// first index started, creating the other indexes finds it existing.
+type indexedJobExecutor struct {
+	kubeClient    clientset.Interface
+	dynamicClient dynamic.Interface
+}
+
+var _ TaskExecutor = &indexedJobExecutor{}
+
+func (i *indexedJobExecutor) Create(job *workload.Workload, task *genev1alpha1.Task) (*workload.Workload, error) {
+	name, index, err := indexedjob.ParseIndexName(job.Name)
+	if err != nil {
+		return nil, err
+	}
+	indexed := indexedjob.NewJob(job, name)
+
+	for _, commands := range indexedjob.NewCommands(indexed, task.CommandSet) {
+		_, err = i.kubeClient.CoreV1().ConfigMaps(commands.Namespace).Create(context.TODO(), commands, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("create configmap %s/%s error: %v", commands.Namespace, commands.Name, err)
+		}
+	}
+
+	obj, err := indexedjob.ToUnstructured(indexed)
+	if err != nil {
+		return nil, err
+	}
+	client := i.dynamicClient.Resource(indexedjob.JobResource).Namespace(indexed.Namespace)
+	created, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if err != nil && errors.IsAlreadyExists(err) {
+		created, err = client.Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return indexedjob.IndexWorkload(created, index)
+}
+
+// Status returns the phase the state of the index has been mapped onto.
+func (i *indexedJobExecutor) Status(job *workload.Workload) (workload.Phase, string) {
+	return job.Status.Phase, job.Status.Message
+}
+
+// Result returns the logs of the pod which has completed the index.
+func (i *indexedJobExecutor) Result(job *workload.Workload, limitBytes int64) (string, error) {
+	_, index, err := indexedjob.ParseIndexName(job.Name)
+	if err != nil {
+		return "", err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	pod := indexedjob.SucceededPod(pods.Items, index)
+	if pod == nil {
+		return "", fmt.Errorf("no pod has completed job %s", util.KeyOf(job))
+	}
+	return getPodLogs(i.kubeClient, pod, limitBytes)
+}
+
+// Cancel stops all the indexes together, they share the Indexed Job.
+func (i *indexedJobExecutor) Cancel(job *workload.Workload) error {
+	if job.Finished() {
+		return nil
+	}
+	name, _, err := indexedjob.ParseIndexName(job.Name)
+	if err != nil {
+		return err
+	}
+	err = i.kubeClient.BatchV1().Jobs(job.Namespace).Delete(context.TODO(), name,
+		metav1.DeleteOptions{PropagationPolicy: &backgroundDeletion})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Cleanup does nothing, the tasks run as Indexed Jobs have no ephemeral volumes.
+func (i *indexedJobExecutor) Cleanup(job *workload.Workload, task *genev1alpha1.Task) error {
+	return nil
+}
+
+// newIndexJob returns the job of an index of the task, whose pod of the
+// Indexed Job of the task looks up the command of the index.
+func newIndexJob(name string, exec *genev1alpha1.Execution, task *genev1alpha1.Task) *workload.Workload {
+	job := newJob(name, indexedjob.Command, exec, task)
+	indexedjob.MountCommands(job, exec.Name+Separator+task.Name, indexedjob.CommandShards(task.CommandSet))
+
+	completions := int32(len(task.CommandSet))
+	parallelism := int64(completions)
+	if task.Parallelism != nil && *task.Parallelism > 0 && *task.Parallelism < parallelism {
+		parallelism = *task.Parallelism
+	}
+	if exec.Spec.Parallelism != nil && *exec.Spec.Parallelism > 0 && *exec.Spec.Parallelism < parallelism {
+		parallelism = *exec.Spec.Parallelism
+	}
+	podParallelism := int32(parallelism)
+	job.Spec.Completions = &completions
+	job.Spec.Parallelism = &podParallelism
+	return job
+}
+
+// limitIndexedJob returns the job of the index with the parallelism of its
+// Indexed Job capped at the room left under the limits of the execution, if
+// the Indexed Job is created with it.
+func (e *ExecutionJobController) limitIndexedJob(exec *genev1alpha1.Execution, job *workload.Workload) (*workload.Workload, error) {
+	if exec.Spec.Parallelism == nil && exec.Spec.ParallelResources == nil {
+		return job, nil
+	}
+	if _, err := e.jobLister.Workloads(job.Namespace).Get(job.Name); err == nil {
+		return job, nil
+	}
+	active, err := e.getActiveJobsForExecution(job.Namespace, labels.Set(job.Labels).AsSelector())
+	if err != nil {
+		return nil, err
+	}
+	// the job has been let start, so one pod at least runs.
+	parallelism := int32(1)
+	if room := parallelRoom(exec, active, job, int(*job.Spec.Parallelism)); room > 1 {
+		parallelism = int32(room)
+	}
+	limited := job.DeepCopy()
+	limited.Spec.Parallelism = &parallelism
+	return limited, nil
+}
+
+// scaleIndexedJobs raises the parallelism of the Indexed Jobs of the execution
+// whose indexes wait for a pod, up to the room left by the finished jobs under
+// the limits of the execution and the parallelism of their task.
+func (e *ExecutionJobController) scaleIndexedJobs(exec *genev1alpha1.Execution, g *graph.Graph) {
+	if exec.Spec.Parallelism == nil && exec.Spec.ParallelResources == nil {
+		return
+	}
+	selector := labels.Set{"controller-uid": string(exec.UID)}.AsSelector()
+	jobs, err := e.jobLister.Workloads(exec.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("Get jobs for execution %s error: %v", util.KeyOf(exec), err)
+		return
+	}
+	var active []*workload.Workload
+	running := make(map[string]int)
+	pending := make(map[string][]*workload.Workload)
+	for _, job := range jobs {
+		if job.Status.Phase == workload.Running {
+			active = append(active, job)
+		}
+		name, _, err := indexedjob.ParseIndexName(job.Name)
+		if err != nil || !indexedjob.IsIndexedJob(job) {
+			continue
+		}
+		switch job.Status.Phase {
+		case workload.Running:
+			running[name]++
+		case workload.Pending:
+			pending[name] = append(pending[name], job)
+		}
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		indexes := pending[name]
+		limit := running[name] + len(indexes)
+		if vertex := g.FindVertexByName(indexes[0].Name); vertex != nil && vertex.Data.Job.Spec.Parallelism != nil {
+			if taskLimit := int(*vertex.Data.Job.Spec.Parallelism); taskLimit < limit {
+				limit = taskLimit
+			}
+		}
+		room := parallelRoom(exec, active, indexes[0], limit-running[name])
+		if room <= 0 {
+			continue
+		}
+		if err := scaleIndexedJob(e.kubeClient, exec.Namespace, name, int32(running[name]+room)); err != nil {
+			klog.Errorf("scale indexed job %s/%s error: %v", exec.Namespace, name, err)
+			continue
+		}
+		// the pods about to start take the room.
+		for i := 0; i < room; i++ {
+			active = append(active, indexes[0])
+		}
+	}
+}
+
+// scaleIndexedJob sets the parallelism of the Indexed Job. The job is patched
+// rather than updated, the client would drop its completion mode.
+func scaleIndexedJob(kubeClient clientset.Interface, namespace, name string, parallelism int32) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"parallelism": parallelism},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.BatchV1().Jobs(namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// indexWorkloads returns the workloads standing for the indexes of the
+// Indexed Job of an informer event.
+func indexWorkloads(obj interface{}) []*workload.Workload {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		klog.Errorf("object %T is not an indexed job", obj)
+		return nil
+	}
+	jobs, err := indexedjob.IndexWorkloads(runtimeObj)
+	if err != nil {
+		klog.Errorf("convert indexed job error: %v", err)
+		return nil
+	}
+	return jobs
+}
+
+// validateIndexedTask checks that the jobs of the task can share one Indexed Job.
+func validateIndexedTask(task genev1alpha1.Task) error {
+	if task.CommandsIter != nil || task.Condition != nil || task.GenericCondition != nil {
+		return fmt.Errorf("task %s: the commands of a task of type IndexedJob must not depend on other tasks", task.Name)
+	}
+	if len(task.InputArtifacts) != 0 || len(task.OutputArtifacts) != 0 {
+		return fmt.Errorf("task %s: a task of type IndexedJob can not have artifacts", task.Name)
+	}
+	if len(ephemeralVolumeNames(&task)) != 0 {
+		return fmt.Errorf("task %s: a task of type IndexedJob can not have ephemeral volumes", task.Name)
+	}
+	if err := indexedjob.ValidateCommands(task.CommandSet); err != nil {
+		return fmt.Errorf("task %s: %v", task.Name, err)
+	}
+	return nil
+}
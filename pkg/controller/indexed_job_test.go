@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func TestIndexedJobExecutorCreate(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Type = genev1alpha1.IndexedJobTaskType
+	task.CommandSet = []string{"echo A", "echo B", "echo C"}
+
+	executor := &indexedJobExecutor{
+		kubeClient:    fake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	}
+	// the first index creates the indexed job, the others find it existing.
+	// No pod of the new indexed job is active yet, so the indexes are pending.
+	for index := range task.CommandSet {
+		name := indexedjob.IndexName(exec.Name+Separator+task.Name, index)
+		created, err := executor.Create(newIndexJob(name, exec, task), task)
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		if created.Name != name || created.Status.Phase != workload.Pending {
+			t.Errorf("Expect pending job %s, but got job %s in phase %s", name, created.Name, created.Status.Phase)
+		}
+	}
+
+	indexedName := exec.Name + Separator + task.Name
+	obj, err := executor.dynamicClient.Resource(indexedjob.JobResource).Namespace(exec.Namespace).Get(
+		context.TODO(), indexedName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect indexed job %s, but got error %v", indexedName, err)
+	}
+	completions, _, _ := unstructured.NestedInt64(obj.Object, "spec", "completions")
+	if completions != 3 {
+		t.Errorf("Expect 3 completions, but got %d", completions)
+	}
+	commands, err := executor.kubeClient.CoreV1().ConfigMaps(exec.Namespace).Get(
+		context.TODO(), indexedjob.CommandsName(indexedName, 0), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect configmap of the commands, but got error %v", err)
+	}
+	if commands.Data["1"] != "echo B" {
+		t.Errorf("Expect command echo B for index 1, but got %q", commands.Data["1"])
+	}
+}
+
+func TestNewIndexJobParallelism(t *testing.T) {
+	two, three := int64(2), int64(3)
+	testCases := []struct {
+		Name            string
+		TaskParallelism *int64
+		ExecParallelism *int64
+		Parallelism     int32
+	}{
+		{
+			Name:        "all the commands",
+			Parallelism: 4,
+		},
+		{
+			Name:            "task parallelism",
+			TaskParallelism: &three,
+			Parallelism:     3,
+		},
+		{
+			Name:            "execution parallelism",
+			TaskParallelism: &three,
+			ExecParallelism: &two,
+			Parallelism:     2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.Parallelism = testCase.ExecParallelism
+		task := &exec.Spec.Tasks[0]
+		task.CommandSet = []string{"echo A", "echo B", "echo C", "echo D"}
+		task.Parallelism = testCase.TaskParallelism
+
+		job := newIndexJob(indexedjob.IndexName("a", 0), exec, task)
+		if *job.Spec.Completions != 4 {
+			t.Errorf("%s: Expect 4 completions, but got %d", testCase.Name, *job.Spec.Completions)
+		}
+		if *job.Spec.Parallelism != testCase.Parallelism {
+			t.Errorf("%s: Expect parallelism %d, but got %d", testCase.Name, testCase.Parallelism, *job.Spec.Parallelism)
+		}
+	}
+}
+
+func TestUpdateIndexJob(t *testing.T) {
+	exec := validateExecution()
+	exec.UID = "uid"
+	task := &exec.Spec.Tasks[0]
+	task.Type = genev1alpha1.IndexedJobTaskType
+
+	testCases := []struct {
+		Name     string
+		Old      workload.Phase
+		Cur      workload.Phase
+		Deleting bool
+		Synced   bool
+	}{
+		{Name: "index unchanged", Old: workload.Running, Cur: workload.Running},
+		{Name: "index completed", Old: workload.Running, Cur: workload.Succeeded, Synced: true},
+		{Name: "index deleted", Old: workload.Running, Cur: workload.Running, Deleting: true, Synced: true},
+	}
+
+	for _, testCase := range testCases {
+		c := newTestExecutionController(exec)
+		// the indexes share the resource version of the Indexed Job.
+		old := newIndexJob(indexedjob.IndexName(exec.Name+Separator+task.Name, 0), exec, task)
+		old.ResourceVersion = "1"
+		old.Status.Phase = testCase.Old
+		cur := old.DeepCopy()
+		cur.ResourceVersion = "2"
+		cur.Status.Phase = testCase.Cur
+		if testCase.Deleting {
+			now := metav1.Now()
+			cur.DeletionTimestamp = &now
+		}
+
+		c.updateJob(old, cur)
+		if synced := c.jobQueue.Len() == 1; synced != testCase.Synced {
+			t.Errorf("%s: Expect synced to be %v, but got %v", testCase.Name, testCase.Synced, synced)
+		}
+	}
+}
+
+func TestLimitIndexedJob(t *testing.T) {
+	one, three := int64(1), int64(3)
+	testCases := []struct {
+		Name        string
+		Parallelism *int64
+		Active      int
+		Expect      int32
+	}{
+		{Name: "no limits", Active: 1, Expect: 4},
+		{Name: "room left", Parallelism: &three, Active: 1, Expect: 2},
+		{Name: "no room left", Parallelism: &one, Active: 1, Expect: 1},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.UID = "uid"
+		exec.Spec.Parallelism = testCase.Parallelism
+		task := &exec.Spec.Tasks[0]
+		task.Type = genev1alpha1.IndexedJobTaskType
+		task.CommandSet = []string{"echo A", "echo B", "echo C", "echo D"}
+		c := newTestExecutionController(exec)
+		for i := 0; i < testCase.Active; i++ {
+			c.jobIndexer.Add(&batch.Job{ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("active-%d", i),
+				Namespace: exec.Namespace,
+				Labels:    map[string]string{"controller-uid": string(exec.UID)},
+			}})
+		}
+
+		job := newIndexJob(indexedjob.IndexName(exec.Name+Separator+task.Name, 0), exec, task)
+		limited, err := c.execJobController.limitIndexedJob(exec, job)
+		if err != nil {
+			t.Fatalf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+		if *limited.Spec.Parallelism != testCase.Expect {
+			t.Errorf("%s: Expect parallelism %d, but got %d", testCase.Name, testCase.Expect, *limited.Spec.Parallelism)
+		}
+	}
+}
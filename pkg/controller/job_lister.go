@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// unionJobLister lists the workloads of all the listers, one per executor.
+type unionJobLister []workload.Lister
+
+var _ workload.Lister = unionJobLister{}
+
+func (l unionJobLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	var jobs []*workload.Workload
+	for _, lister := range l {
+		listed, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, listed...)
+	}
+	return jobs, nil
+}
+
+func (l unionJobLister) Workloads(namespace string) workload.NamespaceLister {
+	listers := make(unionJobNamespaceLister, 0, len(l))
+	for _, lister := range l {
+		listers = append(listers, lister.Workloads(namespace))
+	}
+	return listers
+}
+
+type unionJobNamespaceLister []workload.NamespaceLister
+
+func (l unionJobNamespaceLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	var jobs []*workload.Workload
+	for _, lister := range l {
+		listed, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, listed...)
+	}
+	return jobs, nil
+}
+
+// Get returns the workload of the first lister which has it.
+func (l unionJobNamespaceLister) Get(name string) (*workload.Workload, error) {
+	var notFound error
+	for _, lister := range l {
+		job, err := lister.Get(name)
+		if err == nil {
+			return job, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if notFound == nil {
+			notFound = err
+		}
+	}
+	return nil, notFound
+}
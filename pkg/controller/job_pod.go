@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/indexedjob"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+const (
+	// ExecutionUIDLabel marks the pods running the jobs of an execution,
+	// the controller watches the pods with this label only.
+	ExecutionUIDLabel = "kubegene.io/execution-uid"
+	// executionAnnotation is the name of the execution a pod runs a job of.
+	executionAnnotation = "kubegene.io/execution"
+	// vertexAnnotation is the name of the vertex a pod runs the job of.
+	vertexAnnotation = "kubegene.io/vertex"
+)
+
+// stuckWaitingReasons are the waiting reasons of a container which do
+// not go away unless the task or the cluster is fixed.
+var stuckWaitingReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// podStuckReason returns why the pod can not run and since when, or an
+// empty reason if the pod is not stuck.
+func podStuckReason(pod *v1.Pod) (string, time.Time) {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodPending && pod.Status.Phase != v1.PodRunning {
+		return "", time.Time{}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse &&
+			condition.Reason == v1.PodReasonUnschedulable {
+			return fmt.Sprintf("%s: %s", condition.Reason, condition.Message), condition.LastTransitionTime.Time
+		}
+	}
+
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || !stuckWaitingReasons[waiting.Reason] {
+			continue
+		}
+		// the containers have not been ready since the container started waiting.
+		since := pod.CreationTimestamp.Time
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.ContainersReady && condition.Status == v1.ConditionFalse {
+				since = condition.LastTransitionTime.Time
+			}
+		}
+		reason := fmt.Sprintf("container %s: %s", status.Name, waiting.Reason)
+		if len(waiting.Message) != 0 {
+			reason = reason + ": " + waiting.Message
+		}
+		return reason, since
+	}
+	return "", time.Time{}
+}
+
+// podVertexName returns the name of the vertex the pod runs the job of.
+// The pods of an Indexed Job run the vertices of the indexes.
+func podVertexName(pod *v1.Pod) string {
+	name := pod.Annotations[vertexAnnotation]
+	indexAnnotation, ok := pod.Annotations[indexedjob.CompletionIndexAnnotation]
+	if !ok {
+		return name
+	}
+	prefix, _, err := indexedjob.ParseIndexName(name)
+	if err != nil {
+		return name
+	}
+	index, err := strconv.Atoi(indexAnnotation)
+	if err != nil {
+		return name
+	}
+	return indexedjob.IndexName(prefix, index)
+}
+
+// isStuckMessage returns true if the message of a vertex tells a pod of it is stuck.
+func isStuckMessage(message string) bool {
+	return strings.HasPrefix(message, "pod ") && strings.Contains(message, podStuckMarker)
+}
+
+func (c *ExecutionController) addPod(obj interface{}) {
+	c.enqueueObj(c.podQueue, obj)
+}
+
+func (c *ExecutionController) updatePod(old, cur interface{}) {
+	oldPod := old.(*v1.Pod)
+	curPod := cur.(*v1.Pod)
+	if oldPod.ResourceVersion == curPod.ResourceVersion {
+		// Periodic resync will send update events for all known pods.
+		return
+	}
+	c.enqueueObj(c.podQueue, curPod)
+}
+
+// podWorker runs a worker thread that just dequeues pods, processes them, and marks them done.
+func (c *ExecutionController) podWorker() {
+	for c.processNextPodItem() {
+	}
+}
+
+func (c *ExecutionController) processNextPodItem() bool {
+	key, quit := c.podQueue.Get()
+	if quit {
+		return false
+	}
+	defer c.podQueue.Done(key)
+
+	if err := c.syncPod(key.(string)); err != nil {
+		klog.Errorf("error syncing pod %v: %v", key, err)
+		c.podQueue.AddRateLimited(key)
+		return true
+	}
+	c.podQueue.Forget(key)
+	return true
+}
+
+// syncPod retries the disrupted pods of a running vertex without counting
+// them, and starts the job again with more memory when its pod is out of
+// memory. Otherwise it shows why the pod is stuck in the message of the
+// vertex, and fails the vertex once the pod has been stuck for the grace
+// period.
+func (c *ExecutionController) syncPod(key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pod, err := c.podLister.Pods(ns).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sharedExec, err := c.execLister.Executions(ns).Get(pod.Annotations[executionAnnotation])
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if string(sharedExec.UID) != pod.Labels[ExecutionUIDLabel] || util.IsExecutionCompleted(sharedExec) ||
+		!c.owns(util.KeyOf(sharedExec)) {
+		return nil
+	}
+	exec := sharedExec.DeepCopy()
+
+	vertexName := podVertexName(pod)
+	vertexStatus := util.GetVertexStatus(exec, vertexName)
+	if vertexStatus == nil || vertexStatus.Phase != genev1alpha1.VertexRunning {
+		return nil
+	}
+
+	if reason := podDisruption(pod); len(reason) != 0 {
+		return c.countDisruption(exec, sharedExec, vertexName, pod, reason)
+	}
+	if oomKilled(pod) {
+		return c.escalateMemory(exec, sharedExec, vertexName, pod)
+	}
+	if c.stuckPodGracePeriod == 0 {
+		return nil
+	}
+
+	reason, since := podStuckReason(pod)
+	if len(reason) == 0 {
+		// the pod is no longer stuck.
+		if isStuckMessage(vertexStatus.Message) {
+			util.MarkVertexPhase(exec, vertexName, genev1alpha1.VertexRunning, vertexRunningMessage)
+			return c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec)
+		}
+		return nil
+	}
+
+	if stuck := time.Since(since); stuck < c.stuckPodGracePeriod {
+		util.MarkVertexPhase(exec, vertexName, genev1alpha1.VertexRunning, fmt.Sprintf(podStuckMessage, pod.Name, reason))
+		if err := c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+			return err
+		}
+		// check the pod again when the grace period is over.
+		c.podQueue.AddAfter(key, c.stuckPodGracePeriod-stuck)
+		return nil
+	}
+
+	// the pod is stuck for good, the vertex fails as if its job had failed.
+	err = fmt.Errorf(podStuckFailedMessage, pod.Name, c.stuckPodGracePeriod, reason)
+	klog.Infof("vertex %s of execution %s: %v", vertexName, util.KeyOf(exec), err)
+	util.MarkVertexError(exec, vertexName, err)
+	util.MarkExecutionFailed(exec, err.Error())
+	if err := c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+		return err
+	}
+	// the job of the stuck pod would never finish, the other jobs go on.
+	c.cancelVertexJob(exec, vertexName)
+	return nil
+}
+
+// cancelVertexJob stops the job of the vertex through the executor of its
+// task. Failing to stop the job is only logged.
+func (c *ExecutionController) cancelVertexJob(exec *genev1alpha1.Execution, vertexName string) {
+	taskType := genev1alpha1.JobTaskType
+	if graph := c.execGraphBuilder.GetGraph(util.KeyOf(exec)); graph != nil {
+		if vertex := graph.FindVertexByName(vertexName); vertex != nil {
+			taskType = vertex.Data.TaskType
+		}
+	}
+	job, err := c.jobLister.Workloads(exec.Namespace).Get(vertexName)
+	if errors.IsNotFound(err) {
+		return
+	}
+	if err == nil {
+		var executor TaskExecutor
+		if executor, err = c.execJobController.executorFor(taskType); err == nil {
+			err = executor.Cancel(job)
+		}
+	}
+	if err != nil {
+		klog.Errorf("cancel job %s/%s error: %v", exec.Namespace, vertexName, err)
+	}
+}
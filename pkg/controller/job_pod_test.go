@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubegene.io/kubegene/pkg/indexedjob"
+)
+
+func TestPodStuckReason(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	notReady := metav1.NewTime(time.Now().Add(-time.Minute))
+	waiting := func(reason string) []v1.ContainerStatus {
+		return []v1.ContainerStatus{{Name: "task", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}}}
+	}
+
+	testCases := []struct {
+		Name         string
+		Pod          v1.Pod
+		ExpectReason string
+		ExpectSince  time.Time
+	}{
+		{
+			Name: "running pod",
+			Pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "task", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+			}},
+		},
+		{
+			Name: "unschedulable pod",
+			Pod: v1.Pod{Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				Conditions: []v1.PodCondition{{
+					Type:               v1.PodScheduled,
+					Status:             v1.ConditionFalse,
+					Reason:             v1.PodReasonUnschedulable,
+					Message:            "0/3 nodes are available",
+					LastTransitionTime: notReady,
+				}},
+			}},
+			ExpectReason: "Unschedulable: 0/3 nodes are available",
+			ExpectSince:  notReady.Time,
+		},
+		{
+			Name: "image pull back off",
+			Pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status: v1.PodStatus{
+					Phase:             v1.PodPending,
+					Conditions:        []v1.PodCondition{{Type: v1.ContainersReady, Status: v1.ConditionFalse, LastTransitionTime: notReady}},
+					ContainerStatuses: waiting("ImagePullBackOff"),
+				},
+			},
+			ExpectReason: "container task: ImagePullBackOff",
+			ExpectSince:  notReady.Time,
+		},
+		{
+			Name: "create container config error",
+			Pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status:     v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: waiting("CreateContainerConfigError")},
+			},
+			ExpectReason: "container task: CreateContainerConfigError",
+			ExpectSince:  created.Time,
+		},
+		{
+			Name: "container creating",
+			Pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodPending,
+				ContainerStatuses: waiting("ContainerCreating"),
+			}},
+		},
+		{
+			Name: "failed pod",
+			Pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodFailed,
+				ContainerStatuses: waiting("ImagePullBackOff"),
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		reason, since := podStuckReason(&testCase.Pod)
+		if reason != testCase.ExpectReason {
+			t.Errorf("%s: Expect reason %q, but got %q", testCase.Name, testCase.ExpectReason, reason)
+		}
+		if !since.Equal(testCase.ExpectSince) {
+			t.Errorf("%s: Expect since %v, but got %v", testCase.Name, testCase.ExpectSince, since)
+		}
+	}
+}
+
+func TestPodVertexName(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		Expect      string
+	}{
+		{
+			Name:        "pod of a job",
+			Annotations: map[string]string{vertexAnnotation: "exec.a.2"},
+			Expect:      "exec.a.2",
+		},
+		{
+			Name:        "pod of an index",
+			Annotations: map[string]string{vertexAnnotation: "exec.a.0", indexedjob.CompletionIndexAnnotation: "3"},
+			Expect:      "exec.a.3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: testCase.Annotations}}
+		if name := podVertexName(pod); name != testCase.Expect {
+			t.Errorf("%s: Expect vertex %s, but got %s", testCase.Name, testCase.Expect, name)
+		}
+	}
+}
+
+func TestNewJobPodTemplate(t *testing.T) {
+	exec := validateExecution()
+	exec.UID = "uid"
+	job := newJob(exec.Name+".a.0", "echo", exec, &exec.Spec.Tasks[0])
+	template := job.Spec.Template
+	if template.Labels[ExecutionUIDLabel] != "uid" {
+		t.Errorf("Expect label %s of the execution uid, but got %v", ExecutionUIDLabel, template.Labels)
+	}
+	if template.Annotations[executionAnnotation] != exec.Name || template.Annotations[vertexAnnotation] != job.Name {
+		t.Errorf("Expect annotations of the execution and the vertex, but got %v", template.Annotations)
+	}
+}
+
+func TestIsStuckMessage(t *testing.T) {
+	if !isStuckMessage(fmt.Sprintf(podStuckMessage, "exec.a.0-xyz", "container task: ImagePullBackOff")) {
+		t.Errorf("Expect a stuck message")
+	}
+	if isStuckMessage(vertexRunningMessage) || isStuckMessage(strings.TrimPrefix(podStuckMarker, " ")) {
+		t.Errorf("Expect not a stuck message")
+	}
+}
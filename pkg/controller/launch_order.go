@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// LaunchOrder is the order in which the jobs ready at the same time are started.
+type LaunchOrder string
+
+const (
+	// LaunchOrderFIFO starts the jobs in the order of the tasks of the execution.
+	LaunchOrderFIFO LaunchOrder = "FIFO"
+	// LaunchOrderPriority starts first the jobs of the tasks with a higher priority.
+	LaunchOrderPriority LaunchOrder = "Priority"
+	// LaunchOrderCriticalPath starts first the jobs with the longest
+	// estimated remaining path, then the ones with a higher priority.
+	LaunchOrderCriticalPath LaunchOrder = "CriticalPath"
+)
+
+// LaunchOrders are the supported launch orders.
+var LaunchOrders = []LaunchOrder{LaunchOrderFIFO, LaunchOrderPriority, LaunchOrderCriticalPath}
+
+// IsValidLaunchOrder returns whether the launch order is supported.
+func IsValidLaunchOrder(order LaunchOrder) bool {
+	for _, o := range LaunchOrders {
+		if o == order {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultJobDuration is the estimated duration of the jobs of a task which
+// has neither a duration hint nor earlier runs, so that the critical path
+// is the longest chain of jobs.
+const defaultJobDuration = time.Minute
+
+// durationHistory records the average duration of the succeeded jobs of
+// every task, keyed by the namespace, the name and the image of the task.
+type durationHistory struct {
+	sync.Mutex
+	durations map[string]time.Duration
+	counts    map[string]int
+}
+
+func newDurationHistory() *durationHistory {
+	return &durationHistory{
+		durations: make(map[string]time.Duration),
+		counts:    make(map[string]int),
+	}
+}
+
+func durationKey(namespace string, task *genev1alpha1.Task) string {
+	return namespace + "/" + task.Name + "/" + task.Image
+}
+
+// record adds the duration of the succeeded job of the task to the average.
+func (h *durationHistory) record(namespace string, task *genev1alpha1.Task, job *workload.Workload) {
+	if job.Status.StartTime == nil || job.Status.CompletionTime == nil {
+		return
+	}
+	duration := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+	key := durationKey(namespace, task)
+
+	h.Lock()
+	defer h.Unlock()
+	count := h.counts[key]
+	h.durations[key] = (h.durations[key]*time.Duration(count) + duration) / time.Duration(count+1)
+	h.counts[key] = count + 1
+}
+
+// estimate returns the expected duration of a job of the task.
+func (h *durationHistory) estimate(namespace string, task *genev1alpha1.Task) time.Duration {
+	if task.EstimatedDuration != nil {
+		return task.EstimatedDuration.Duration
+	}
+	h.Lock()
+	defer h.Unlock()
+	if duration, ok := h.durations[durationKey(namespace, task)]; ok {
+		return duration
+	}
+	return defaultJobDuration
+}
+
+// orderVertices returns the vertices ready to start in the order they are started.
+func orderVertices(order LaunchOrder, history *durationHistory, exec *genev1alpha1.Execution, vertices []*graph.Vertex) []*graph.Vertex {
+	if order != LaunchOrderPriority && order != LaunchOrderCriticalPath || len(vertices) < 2 {
+		return vertices
+	}
+	ordered := make([]*graph.Vertex, len(vertices))
+	copy(ordered, vertices)
+
+	paths := make(map[*graph.Vertex]time.Duration)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if order == LaunchOrderCriticalPath {
+			iPath := criticalPath(history, exec, ordered[i], paths)
+			jPath := criticalPath(history, exec, ordered[j], paths)
+			if iPath != jPath {
+				return iPath > jPath
+			}
+		}
+		return vertexPriority(exec, ordered[i]) > vertexPriority(exec, ordered[j])
+	})
+	return ordered
+}
+
+// criticalPath returns the estimated duration of the longest path from the
+// start of the vertex to the end of the workflow.
+func criticalPath(history *durationHistory, exec *genev1alpha1.Execution, vertex *graph.Vertex, paths map[*graph.Vertex]time.Duration) time.Duration {
+	if path, ok := paths[vertex]; ok {
+		return path
+	}
+	var longest time.Duration
+	for _, child := range vertex.Children {
+		if path := criticalPath(history, exec, child, paths); path > longest {
+			longest = path
+		}
+	}
+	path := longest + defaultJobDuration
+	if task := getVertexTask(exec, vertex); task != nil {
+		path = longest + history.estimate(exec.Namespace, task)
+	}
+	paths[vertex] = path
+	return path
+}
+
+func vertexPriority(exec *genev1alpha1.Execution, vertex *graph.Vertex) int32 {
+	task := getVertexTask(exec, vertex)
+	if task == nil || task.Priority == nil {
+		return 0
+	}
+	return *task.Priority
+}
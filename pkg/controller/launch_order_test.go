@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/graph"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func newLaunchOrderVertex(exec *genev1alpha1.Execution, name string, children ...*graph.Vertex) *graph.Vertex {
+	job := &workload.Workload{ObjectMeta: metav1.ObjectMeta{Name: exec.Name + Separator + name}}
+	return graph.NewVertex(graph.NewJobInfo(job, false, genev1alpha1.JobTaskType, nil), false, children...)
+}
+
+func TestOrderVertices(t *testing.T) {
+	high := int32(5)
+	exec := &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{Name: "exec", Namespace: "default"},
+		Spec: genev1alpha1.ExecutionSpec{
+			Tasks: []genev1alpha1.Task{
+				{Name: "a"},
+				{Name: "b", Priority: &high},
+				{Name: "c", EstimatedDuration: &metav1.Duration{Duration: time.Hour}},
+				{Name: "d"},
+			},
+		},
+	}
+	// a is followed by the long job c, b has a higher priority than a and d.
+	c := newLaunchOrderVertex(exec, "c.0")
+	vertices := []*graph.Vertex{
+		newLaunchOrderVertex(exec, "d.0"),
+		newLaunchOrderVertex(exec, "a.0", c),
+		newLaunchOrderVertex(exec, "b.0"),
+	}
+
+	testCases := []struct {
+		Order  LaunchOrder
+		Expect []string
+	}{
+		{
+			Order:  LaunchOrderFIFO,
+			Expect: []string{"d.0", "a.0", "b.0"},
+		},
+		{
+			Order:  LaunchOrderPriority,
+			Expect: []string{"b.0", "d.0", "a.0"},
+		},
+		{
+			Order:  LaunchOrderCriticalPath,
+			Expect: []string{"a.0", "b.0", "d.0"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ordered := orderVertices(testCase.Order, newDurationHistory(), exec, vertices)
+		names := []string{}
+		for _, vertex := range ordered {
+			names = append(names, strings.TrimPrefix(vertex.Data.Job.Name, exec.Name+Separator))
+		}
+		if !reflect.DeepEqual(names, testCase.Expect) {
+			t.Errorf("%s: Expect order %v, but got %v", testCase.Order, testCase.Expect, names)
+		}
+	}
+}
+
+func TestDurationHistory(t *testing.T) {
+	task := &genev1alpha1.Task{Name: "a", Image: "busybox"}
+	history := newDurationHistory()
+	if duration := history.estimate("default", task); duration != defaultJobDuration {
+		t.Errorf("Expect default duration %v, but got %v", defaultJobDuration, duration)
+	}
+
+	start := metav1.Now()
+	for _, minutes := range []int{2, 4} {
+		completion := metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+		job := &workload.Workload{Status: workload.Status{StartTime: &start, CompletionTime: &completion}}
+		history.record("default", task, job)
+	}
+	// a job without completion time is ignored.
+	history.record("default", task, &workload.Workload{Status: workload.Status{StartTime: &start}})
+
+	if duration := history.estimate("default", task); duration != 3*time.Minute {
+		t.Errorf("Expect average duration %v, but got %v", 3*time.Minute, duration)
+	}
+	if duration := history.estimate("other", task); duration != defaultJobDuration {
+		t.Errorf("Expect default duration in another namespace, but got %v", duration)
+	}
+	task.EstimatedDuration = &metav1.Duration{Duration: time.Hour}
+	if duration := history.estimate("default", task); duration != time.Hour {
+		t.Errorf("Expect estimated duration %v, but got %v", time.Hour, duration)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+const (
+	// oomKilledReason is the reason of the termination of a container killed for running out of memory.
+	oomKilledReason = "OOMKilled"
+	// mebibyte is the unit the escalated memory is rounded up to.
+	mebibyte = 1024 * 1024
+)
+
+// oomKilled returns true if the task container of the pod has been killed
+// for running out of memory, whether it has been restarted since or not.
+func oomKilled(pod *v1.Pod) bool {
+	if len(pod.Spec.Containers) == 0 {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != pod.Spec.Containers[0].Name {
+			continue
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason == oomKilledReason {
+			return true
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == oomKilledReason {
+			return true
+		}
+	}
+	return false
+}
+
+// containerMemory returns the memory requested by the task container of the pod spec.
+func containerMemory(spec *v1.PodSpec) resource.Quantity {
+	if len(spec.Containers) == 0 {
+		return resource.Quantity{}
+	}
+	return spec.Containers[0].Resources.Requests[v1.ResourceMemory]
+}
+
+// setJobMemory has the task container of the job request the memory, the
+// memory limit is raised as well if the container has one.
+func setJobMemory(job *workload.Workload, memory resource.Quantity) {
+	resources := &job.Spec.Template.Spec.Containers[0].Resources
+	if resources.Requests == nil {
+		resources.Requests = v1.ResourceList{}
+	}
+	resources.Requests[v1.ResourceMemory] = memory
+	if _, ok := resources.Limits[v1.ResourceMemory]; ok {
+		resources.Limits[v1.ResourceMemory] = memory
+	}
+}
+
+// restoreJobMemory has the job request the memory its last attempt has been
+// escalated to, as recorded in the status of its vertex, so that a job of a
+// graph rebuilt from the execution does not run out of memory again.
+func restoreJobMemory(exec *genev1alpha1.Execution, job *workload.Workload) {
+	vertexStatus := util.GetVertexStatus(exec, job.Name)
+	if vertexStatus == nil || vertexStatus.MemoryEscalations == 0 {
+		return
+	}
+	if memory, ok := vertexStatus.Resources[v1.ResourceMemory]; ok {
+		setJobMemory(job, memory)
+	}
+}
+
+// escalatedMemory returns the memory of the attempt after an attempt with
+// the current memory, rounded up to a whole mebibyte and at most the max.
+func escalatedMemory(current resource.Quantity, escalation *genev1alpha1.MemoryEscalation) resource.Quantity {
+	next := int64(math.Ceil(float64(current.Value()) * escalation.Multiplier))
+	next = (next + mebibyte - 1) / mebibyte * mebibyte
+	if next >= escalation.Max.Value() {
+		return escalation.Max.DeepCopy()
+	}
+	return *resource.NewQuantity(next, resource.BinarySI)
+}
+
+// escalateMemory starts the job of the vertex again with more memory when
+// the pod of the job has been killed for running out of memory.
+func (c *ExecutionController) escalateMemory(exec, sharedExec *genev1alpha1.Execution, vertexName string, pod *v1.Pod) error {
+	graph := c.execGraphBuilder.GetGraph(util.KeyOf(exec))
+	if graph == nil {
+		return nil
+	}
+	vertex := graph.FindVertexByName(vertexName)
+	if vertex == nil {
+		return nil
+	}
+	task := getVertexTask(exec, vertex)
+	if task == nil || task.MemoryEscalation == nil {
+		return nil
+	}
+
+	current := containerMemory(&pod.Spec)
+	// the pod of an earlier attempt is still being deleted.
+	if templateMemory := containerMemory(&vertex.Data.Job.Spec.Template.Spec); current.Cmp(templateMemory) < 0 {
+		return nil
+	}
+	next := escalatedMemory(current, task.MemoryEscalation)
+	if next.Cmp(current) <= 0 {
+		// the max has been reached, the job restarts as usual.
+		return nil
+	}
+
+	job, err := c.jobLister.Workloads(exec.Namespace).Get(vertexName)
+	if err != nil {
+		return err
+	}
+	executor, err := c.execJobController.executorFor(vertex.Data.TaskType)
+	if err != nil {
+		return err
+	}
+
+	setJobMemory(vertex.Data.Job, next)
+	vertexStatus := util.GetVertexStatus(exec, vertexName)
+	vertexStatus.Message = fmt.Sprintf(memoryEscalatedMessage, pod.Name, next.String())
+	vertexStatus.Resources = vertex.Data.Job.Spec.Template.Spec.Containers[0].Resources.Requests.DeepCopy()
+	vertexStatus.MemoryEscalations++
+	exec.Status.Vertices[vertexStatus.ID] = *vertexStatus
+	if err := c.execStatusUpdater.UpdateExecutionStatus(exec, sharedExec); err != nil {
+		return err
+	}
+
+	klog.Infof("job %s is out of memory, start it again with memory %s", util.KeyOf(job), next.String())
+	if err := executor.Cancel(job); err != nil {
+		return err
+	}
+	c.eventQueue.Add(Event{Type: JobRestart, Name: vertexName, Key: util.KeyOf(exec)})
+	return nil
+}
+
+// validateMemoryEscalation checks that the memory of the jobs of the task can be escalated.
+func validateMemoryEscalation(task genev1alpha1.Task) error {
+	escalation := task.MemoryEscalation
+	if escalation == nil {
+		return nil
+	}
+	if task.Type != genev1alpha1.JobTaskType && task.Type != genev1alpha1.SparkTaskType {
+		return fmt.Errorf("task %s: memoryEscalation can only be specified for tasks of type Job or Spark", task.Name)
+	}
+	if task.Resources.Memory.IsZero() {
+		return fmt.Errorf("task %s: memoryEscalation requires the memory of the task", task.Name)
+	}
+	if escalation.Multiplier <= 1 {
+		return fmt.Errorf("task %s: memoryEscalation.multiplier must be greater than 1", task.Name)
+	}
+	if escalation.Max.Cmp(task.Resources.Memory) < 0 {
+		return fmt.Errorf("task %s: memoryEscalation.max must not be less than the memory of the task", task.Name)
+	}
+	return nil
+}
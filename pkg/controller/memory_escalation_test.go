@@ -0,0 +1,244 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func TestOOMKilled(t *testing.T) {
+	oom := &v1.ContainerStateTerminated{Reason: oomKilledReason}
+	testCases := []struct {
+		Name   string
+		Status v1.ContainerStatus
+		Expect bool
+	}{
+		{
+			Name:   "running container",
+			Status: v1.ContainerStatus{Name: "task", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			Expect: false,
+		},
+		{
+			Name:   "killed container",
+			Status: v1.ContainerStatus{Name: "task", State: v1.ContainerState{Terminated: oom}},
+			Expect: true,
+		},
+		{
+			Name:   "restarted container",
+			Status: v1.ContainerStatus{Name: "task", LastTerminationState: v1.ContainerState{Terminated: oom}},
+			Expect: true,
+		},
+		{
+			Name:   "killed sidecar",
+			Status: v1.ContainerStatus{Name: "sidecar", State: v1.ContainerState{Terminated: oom}},
+			Expect: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		pod := &v1.Pod{
+			Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "task"}, {Name: "sidecar"}}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{testCase.Status}},
+		}
+		if killed := oomKilled(pod); killed != testCase.Expect {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, killed)
+		}
+	}
+}
+
+func TestEscalatedMemory(t *testing.T) {
+	escalation := &genev1alpha1.MemoryEscalation{Multiplier: 1.5, Max: resource.MustParse("8Gi")}
+	testCases := []struct {
+		Current string
+		Expect  string
+	}{
+		{
+			Current: "2Gi",
+			Expect:  "3Gi",
+		},
+		{
+			Current: "1G",
+			Expect:  "1431Mi",
+		},
+		{
+			Current: "6Gi",
+			Expect:  "8Gi",
+		},
+		{
+			Current: "8Gi",
+			Expect:  "8Gi",
+		},
+	}
+
+	for _, testCase := range testCases {
+		next := escalatedMemory(resource.MustParse(testCase.Current), escalation)
+		if next.Cmp(resource.MustParse(testCase.Expect)) != 0 {
+			t.Errorf("%s: Expect memory %s, but got %s", testCase.Current, testCase.Expect, next.String())
+		}
+	}
+}
+
+func TestSetJobMemory(t *testing.T) {
+	job := &workload.Workload{}
+	job.Spec.Template.Spec.Containers = []v1.Container{{
+		Name: "task",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi"), v1.ResourceCPU: resource.MustParse("1")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+	}}
+	setJobMemory(job, resource.MustParse("2Gi"))
+
+	resources := job.Spec.Template.Spec.Containers[0].Resources
+	if memory := resources.Requests[v1.ResourceMemory]; memory.String() != "2Gi" {
+		t.Errorf("Expect memory request 2Gi, but got %s", memory.String())
+	}
+	if memory := resources.Limits[v1.ResourceMemory]; memory.String() != "2Gi" {
+		t.Errorf("Expect memory limit 2Gi, but got %s", memory.String())
+	}
+	if cpu := resources.Requests[v1.ResourceCPU]; cpu.String() != "1" {
+		t.Errorf("Expect cpu request 1, but got %s", cpu.String())
+	}
+}
+
+func TestRestoreJobMemory(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Status *genev1alpha1.VertexStatus
+		Expect string
+	}{
+		{
+			Name:   "no status",
+			Expect: "1Gi",
+		},
+		{
+			Name:   "not escalated",
+			Status: &genev1alpha1.VertexStatus{},
+			Expect: "1Gi",
+		},
+		{
+			Name: "escalated",
+			Status: &genev1alpha1.VertexStatus{
+				Resources:         v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+				MemoryEscalations: 1,
+			},
+			Expect: "2Gi",
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := &genev1alpha1.Execution{}
+		exec.Status.Vertices = map[string]genev1alpha1.VertexStatus{}
+		if testCase.Status != nil {
+			exec.Status.Vertices[util.VertexId("exec.task.0")] = *testCase.Status
+		}
+		job := &workload.Workload{}
+		job.Name = "exec.task.0"
+		job.Spec.Template.Spec.Containers = []v1.Container{{
+			Name: "task",
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		}}
+		restoreJobMemory(exec, job)
+
+		memory := job.Spec.Template.Spec.Containers[0].Resources.Requests[v1.ResourceMemory]
+		if memory.String() != testCase.Expect {
+			t.Errorf("%s: Expect memory request %s, but got %s", testCase.Name, testCase.Expect, memory.String())
+		}
+	}
+}
+
+func TestValidateMemoryEscalation(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Task      genev1alpha1.Task
+		ExpectErr bool
+	}{
+		{
+			Name:      "no memory escalation",
+			Task:      genev1alpha1.Task{Name: "a", Type: genev1alpha1.PodTaskType},
+			ExpectErr: false,
+		},
+		{
+			Name: "doubled up to 16Gi",
+			Task: genev1alpha1.Task{
+				Name:             "a",
+				Type:             genev1alpha1.JobTaskType,
+				Resources:        genev1alpha1.ResourceRequirements{Memory: resource.MustParse("4Gi")},
+				MemoryEscalation: &genev1alpha1.MemoryEscalation{Multiplier: 2, Max: resource.MustParse("16Gi")},
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "task of type Pod",
+			Task: genev1alpha1.Task{
+				Name:             "a",
+				Type:             genev1alpha1.PodTaskType,
+				Resources:        genev1alpha1.ResourceRequirements{Memory: resource.MustParse("4Gi")},
+				MemoryEscalation: &genev1alpha1.MemoryEscalation{Multiplier: 2, Max: resource.MustParse("16Gi")},
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "no memory",
+			Task: genev1alpha1.Task{
+				Name:             "a",
+				Type:             genev1alpha1.JobTaskType,
+				MemoryEscalation: &genev1alpha1.MemoryEscalation{Multiplier: 2, Max: resource.MustParse("16Gi")},
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "multiplier of 1",
+			Task: genev1alpha1.Task{
+				Name:             "a",
+				Type:             genev1alpha1.JobTaskType,
+				Resources:        genev1alpha1.ResourceRequirements{Memory: resource.MustParse("4Gi")},
+				MemoryEscalation: &genev1alpha1.MemoryEscalation{Multiplier: 1, Max: resource.MustParse("16Gi")},
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "max less than memory",
+			Task: genev1alpha1.Task{
+				Name:             "a",
+				Type:             genev1alpha1.JobTaskType,
+				Resources:        genev1alpha1.ResourceRequirements{Memory: resource.MustParse("4Gi")},
+				MemoryEscalation: &genev1alpha1.MemoryEscalation{Multiplier: 2, Max: resource.MustParse("2Gi")},
+			},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := validateMemoryEscalation(testCase.Task)
+		if testCase.ExpectErr == true && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+const (
+	// verifyOutputsLabel marks the job verifying the outputs of an execution.
+	verifyOutputsLabel = "kubegene.io/verify-outputs"
+	// verifyOutputsImage is the image of the job verifying outputs.
+	verifyOutputsImage = "busybox:1.31"
+	// verifyOutputsLogLimit limits the size of the verification result.
+	verifyOutputsLogLimit = 1024 * 1024
+	// missingOutput is reported by the verification job for a missing path.
+	missingOutput = "missing"
+
+	// verifyOutputsScript prints a line of <path> <size> <sha256> for every
+	// existing path and <path> missing for the others.
+	verifyOutputsScript = `for p in "$@"; do
+  if [ -f "$p" ]; then
+    echo "$p	$(wc -c < "$p")	$(sha256sum "$p" | cut -d ' ' -f 1)"
+  elif [ -d "$p" ]; then
+    echo "$p	$(find "$p" -type f -exec cat {} + | wc -c)	$(cd "$p" && find . -type f | sort | xargs -r sha256sum | sha256sum | cut -d ' ' -f 1)"
+  else
+    echo "$p	missing"
+  fi
+done`
+)
+
+// completeExecution is called once all the vertices of the execution have
+// succeeded or been skipped. The execution is marked as successful right away
+// if it declares no outputs, otherwise the job verifying the outputs is created
+// and the execution is completed when the job finishes.
+func completeExecution(kubeClient clientset.Interface, exec *genev1alpha1.Execution) error {
+	if len(exec.Spec.Outputs) == 0 {
+		util.MarkExecutionSuccess(exec, executionSuccessMessage)
+		return nil
+	}
+
+	job := newVerifyOutputsJob(exec)
+	_, err := kubeClient.BatchV1().Jobs(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create job %s error: %v", util.KeyOf(job), err)
+	}
+	util.MarkExecutionRunning(exec, verifyingOutputsMessage)
+	return nil
+}
+
+// isVerifyOutputsJob returns true if the job verifies the outputs of an execution.
+func isVerifyOutputsJob(job metav1.Object) bool {
+	_, ok := job.GetLabels()[verifyOutputsLabel]
+	return ok
+}
+
+func newVerifyOutputsJob(exec *genev1alpha1.Execution) *batch.Job {
+	// mount every volume used by the tasks, the outputs
+	// must have been written into one of them.
+	volumes := []v1.Volume{}
+	volumeMounts := []v1.VolumeMount{}
+	mounted := make(map[string]struct{})
+	for _, task := range exec.Spec.Tasks {
+		for _, volume := range task.Volumes {
+			if !isSharedVolume(volume) {
+				continue
+			}
+			if _, ok := mounted[volume.MountPath]; ok {
+				continue
+			}
+			mounted[volume.MountPath] = struct{}{}
+			volumeName := "volume-" + strconv.Itoa(len(volumes))
+			volumes = append(volumes, v1.Volume{
+				Name:         volumeName,
+				VolumeSource: newVolumeSource("", volumeName, volume),
+			})
+			volumeMounts = append(volumeMounts, v1.VolumeMount{
+				Name:      volumeName,
+				MountPath: volume.MountPath,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	args := []string{"sh", "-c", verifyOutputsScript, "verify-outputs"}
+	for _, output := range sortedOutputs(exec) {
+		args = append(args, output.Paths...)
+	}
+
+	controllerRef := metav1.NewControllerRef(exec, execKind)
+	backoffLimit := int32(2)
+
+	return &batch.Job{
+		TypeMeta: metav1.TypeMeta{Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      exec.Name + Separator + "verify-outputs",
+			Namespace: exec.Namespace,
+			Labels: map[string]string{
+				"controller-uid":   string(exec.UID),
+				verifyOutputsLabel: "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{*controllerRef},
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{
+						{
+							Name:            "verify-outputs",
+							Image:           verifyOutputsImage,
+							Command:         args,
+							VolumeMounts:    volumeMounts,
+							ImagePullPolicy: v1.PullIfNotPresent,
+						},
+					},
+					NodeSelector: exec.Spec.NodeSelector,
+					Affinity:     exec.Spec.Affinity,
+					Tolerations:  exec.Spec.Tolerations,
+					Volumes:      volumes,
+				},
+			},
+		},
+	}
+}
+
+type namedOutput struct {
+	Name  string
+	Paths []string
+}
+
+// sortedOutputs returns the outputs of the execution ordered by name.
+func sortedOutputs(exec *genev1alpha1.Execution) []namedOutput {
+	outputs := make([]namedOutput, 0, len(exec.Spec.Outputs))
+	for name, output := range exec.Spec.Outputs {
+		outputs = append(outputs, namedOutput{Name: name, Paths: output.Paths})
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+	return outputs
+}
+
+// parseVerifyOutputsResult parses the logs of the verification job into the
+// status of the outputs, and returns the paths which are missing.
+func parseVerifyOutputsResult(exec *genev1alpha1.Execution, result string) ([]genev1alpha1.OutputStatus, []string, error) {
+	found := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(result), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		found[fields[0]] = fields[1:]
+	}
+
+	statuses := []genev1alpha1.OutputStatus{}
+	missing := []string{}
+	for _, output := range sortedOutputs(exec) {
+		for _, path := range output.Paths {
+			fields, ok := found[path]
+			if !ok || (len(fields) == 1 && fields[0] == missingOutput) {
+				missing = append(missing, path)
+				continue
+			}
+			if len(fields) != 2 {
+				return nil, nil, fmt.Errorf("unexpected verification result of %s: %v", path, fields)
+			}
+			size, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unexpected size of %s: %v", path, err)
+			}
+			statuses = append(statuses, genev1alpha1.OutputStatus{
+				Name:     output.Name,
+				Path:     path,
+				Size:     size,
+				Checksum: "sha256:" + fields[1],
+			})
+		}
+	}
+	return statuses, missing, nil
+}
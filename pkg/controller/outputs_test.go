@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestParseVerifyOutputsResult(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Result        string
+		ExpectOutputs []genev1alpha1.OutputStatus
+		ExpectMissing []string
+		ExpectErr     bool
+	}{
+		{
+			Name:   "all outputs exist",
+			Result: "/tmp/hostvolume/result.txt\t   12\tabc\n/tmp/hostvolume/dir\t24\tdef\n",
+			ExpectOutputs: []genev1alpha1.OutputStatus{
+				{Name: "result", Path: "/tmp/hostvolume/dir", Size: 24, Checksum: "sha256:def"},
+				{Name: "result", Path: "/tmp/hostvolume/result.txt", Size: 12, Checksum: "sha256:abc"},
+			},
+			ExpectMissing: []string{},
+		},
+		{
+			Name:   "output is missing",
+			Result: "/tmp/hostvolume/result.txt\t12\tabc\n/tmp/hostvolume/dir\tmissing\n",
+			ExpectOutputs: []genev1alpha1.OutputStatus{
+				{Name: "result", Path: "/tmp/hostvolume/result.txt", Size: 12, Checksum: "sha256:abc"},
+			},
+			ExpectMissing: []string{"/tmp/hostvolume/dir"},
+		},
+		{
+			Name:   "output is not reported",
+			Result: "/tmp/hostvolume/result.txt\t12\tabc\n",
+			ExpectOutputs: []genev1alpha1.OutputStatus{
+				{Name: "result", Path: "/tmp/hostvolume/result.txt", Size: 12, Checksum: "sha256:abc"},
+			},
+			ExpectMissing: []string{"/tmp/hostvolume/dir"},
+		},
+		{
+			Name:      "unexpected size",
+			Result:    "/tmp/hostvolume/result.txt\tabc\tabc\n/tmp/hostvolume/dir\t24\tdef\n",
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.Outputs = map[string]genev1alpha1.Output{
+			"result": {Paths: []string{"/tmp/hostvolume/dir", "/tmp/hostvolume/result.txt"}},
+		}
+		outputs, missing, err := parseVerifyOutputsResult(exec, testCase.Result)
+		if testCase.ExpectErr == true && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+		if testCase.ExpectErr {
+			continue
+		}
+		if !reflect.DeepEqual(outputs, testCase.ExpectOutputs) {
+			t.Errorf("%s: Expect outputs %v, but got %v", testCase.Name, testCase.ExpectOutputs, outputs)
+		}
+		if !reflect.DeepEqual(missing, testCase.ExpectMissing) {
+			t.Errorf("%s: Expect missing %v, but got %v", testCase.Name, testCase.ExpectMissing, missing)
+		}
+	}
+}
+
+func TestCompleteExecution(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Outputs     map[string]genev1alpha1.Output
+		ExpectPhase genev1alpha1.VertexPhase
+		ExpectJobs  int
+	}{
+		{
+			Name:        "execution without outputs",
+			ExpectPhase: genev1alpha1.VertexSucceeded,
+			ExpectJobs:  0,
+		},
+		{
+			Name: "execution with outputs",
+			Outputs: map[string]genev1alpha1.Output{
+				"result": {Paths: []string{"/tmp/hostvolume/result.txt"}},
+			},
+			ExpectPhase: genev1alpha1.VertexRunning,
+			ExpectJobs:  1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.Outputs = testCase.Outputs
+		kubeClient := fake.NewSimpleClientset()
+
+		if err := completeExecution(kubeClient, exec); err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+			continue
+		}
+		if exec.Status.Phase != testCase.ExpectPhase {
+			t.Errorf("%s: Expect phase %s, but got %s", testCase.Name, testCase.ExpectPhase, exec.Status.Phase)
+		}
+		jobs, _ := kubeClient.BatchV1().Jobs(exec.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if len(jobs.Items) != testCase.ExpectJobs {
+			t.Errorf("%s: Expect %d jobs, but got %d", testCase.Name, testCase.ExpectJobs, len(jobs.Items))
+			continue
+		}
+		if testCase.ExpectJobs == 0 {
+			continue
+		}
+		job := &jobs.Items[0]
+		if !isVerifyOutputsJob(job) {
+			t.Errorf("%s: Expect verify outputs job, but got %s", testCase.Name, job.Name)
+		}
+		// the tasks mount the same path from different volumes, only one is mounted.
+		if n := len(job.Spec.Template.Spec.Volumes); n != 1 {
+			t.Errorf("%s: Expect 1 volume, but got %d", testCase.Name, n)
+		}
+		command := job.Spec.Template.Spec.Containers[0].Command
+		if command[len(command)-1] != "/tmp/hostvolume/result.txt" {
+			t.Errorf("%s: Expect output path in the command, but got %v", testCase.Name, command)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// podExecutor runs the jobs as bare Pods. The pod is not restarted when
+// the command fails, so the backoffLimit of the task does not apply.
+type podExecutor struct {
+	kubeClient clientset.Interface
+}
+
+var _ TaskExecutor = &podExecutor{}
+
+func (p *podExecutor) Create(job *workload.Workload, task *genev1alpha1.Task) (*workload.Workload, error) {
+	pod := newPod(job)
+	created, err := p.kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	if err != nil && errors.IsAlreadyExists(err) {
+		created, err = p.kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return podWorkload(created), nil
+}
+
+// Status returns the phase the phase of the pod has been mapped onto, see podWorkload.
+func (p *podExecutor) Status(job *workload.Workload) (workload.Phase, string) {
+	return job.Status.Phase, job.Status.Message
+}
+
+func (p *podExecutor) Result(job *workload.Workload, limitBytes int64) (string, error) {
+	pod, err := p.kubeClient.CoreV1().Pods(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return getPodLogs(p.kubeClient, pod, limitBytes)
+}
+
+func (p *podExecutor) Cancel(job *workload.Workload) error {
+	if job.Finished() {
+		return nil
+	}
+	err := p.kubeClient.CoreV1().Pods(job.Namespace).Delete(context.TODO(), job.Name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (p *podExecutor) Cleanup(job *workload.Workload, task *genev1alpha1.Task) error {
+	return deleteEphemeralClaims(p.kubeClient, job, task)
+}
+
+// newPod returns the pod running the pod template of the workload.
+func newPod(job *workload.Workload) *v1.Pod {
+	template := job.Spec.Template.DeepCopy()
+	podLabels := make(map[string]string, len(template.Labels)+len(job.Labels))
+	for key, value := range template.Labels {
+		podLabels[key] = value
+	}
+	for key, value := range job.Labels {
+		podLabels[key] = value
+	}
+	annotations := make(map[string]string, len(template.Annotations)+len(job.Annotations))
+	for key, value := range template.Annotations {
+		annotations[key] = value
+	}
+	for key, value := range job.Annotations {
+		annotations[key] = value
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            job.Name,
+			Namespace:       job.Namespace,
+			Labels:          podLabels,
+			Annotations:     annotations,
+			OwnerReferences: job.OwnerReferences,
+		},
+		Spec: template.Spec,
+	}
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+	if pod.Spec.ActiveDeadlineSeconds == nil {
+		pod.Spec.ActiveDeadlineSeconds = job.Spec.ActiveDeadlineSeconds
+	}
+	return pod
+}
+
+// podWorkload returns the workload the pod runs, the phase of the pod is
+// mapped onto the phase of the workload. A disrupted pod keeps its workload
+// running.
+func podWorkload(pod *v1.Pod) *workload.Workload {
+	job := &workload.Workload{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "Pod"},
+		ObjectMeta: *pod.ObjectMeta.DeepCopy(),
+		Spec: workload.Spec{
+			Template:              v1.PodTemplateSpec{Spec: *pod.Spec.DeepCopy()},
+			ActiveDeadlineSeconds: pod.Spec.ActiveDeadlineSeconds,
+		},
+		Status: workload.Status{
+			Phase:     workload.Running,
+			Reason:    pod.Status.Reason,
+			Message:   pod.Status.Message,
+			StartTime: pod.Status.StartTime.DeepCopy(),
+		},
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		job.Status.Phase = workload.Succeeded
+	case v1.PodFailed:
+		// the disrupted pod is started again rather than failing its job, see countDisruption.
+		if len(podDisruption(pod)) != 0 {
+			return job
+		}
+		job.Status.Phase = workload.Failed
+		if len(job.Status.Message) == 0 {
+			job.Status.Message = fmt.Sprintf("pod is %s", pod.Status.Phase)
+		}
+	default:
+		return job
+	}
+	job.Status.CompletionTime = podCompletionTime(pod)
+	return job
+}
+
+// podCompletionTime returns the time the last container of the pod has terminated.
+func podCompletionTime(pod *v1.Pod) *metav1.Time {
+	var completionTime *metav1.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil &&
+			(completionTime == nil || completionTime.Before(&terminated.FinishedAt)) {
+			completionTime = terminated.FinishedAt.DeepCopy()
+		}
+	}
+	return completionTime
+}
+
+// isExecutionPod returns true if the pod runs a job of an execution, the
+// pods of the batch Jobs are controlled by the batch Jobs instead.
+func isExecutionPod(pod *v1.Pod) bool {
+	controllerRef := metav1.GetControllerOf(pod)
+	return controllerRef != nil && controllerRef.Kind == execKind.Kind
+}
+
+// executionPodWorkload returns the workload the pod of an informer event
+// runs, or nil if the pod does not run a job of an execution.
+func executionPodWorkload(obj interface{}) *workload.Workload {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !ok || !isExecutionPod(pod) {
+		return nil
+	}
+	return podWorkload(pod)
+}
+
+// podJobLister lists the pods running the jobs of the executions as workloads.
+type podJobLister struct {
+	lister corelisters.PodLister
+}
+
+var _ workload.Lister = &podJobLister{}
+
+func (l *podJobLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	pods, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return podWorkloads(pods), nil
+}
+
+func (l *podJobLister) Workloads(namespace string) workload.NamespaceLister {
+	return &podJobNamespaceLister{lister: l.lister.Pods(namespace)}
+}
+
+type podJobNamespaceLister struct {
+	lister corelisters.PodNamespaceLister
+}
+
+func (l *podJobNamespaceLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	pods, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return podWorkloads(pods), nil
+}
+
+func (l *podJobNamespaceLister) Get(name string) (*workload.Workload, error) {
+	pod, err := l.lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !isExecutionPod(pod) {
+		return nil, workload.NewNotFound(name)
+	}
+	return podWorkload(pod), nil
+}
+
+func podWorkloads(pods []*v1.Pod) []*workload.Workload {
+	jobs := make([]*workload.Workload, 0, len(pods))
+	for _, pod := range pods {
+		if isExecutionPod(pod) {
+			jobs = append(jobs, podWorkload(pod))
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	osexec "os/exec"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/artifact"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func TestNewPod(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Type = genev1alpha1.PodTaskType
+	job := newJob("example.a.0", "echo A", exec, task)
+	job.Annotations = map[string]string{cacheKeyAnnotation: "key"}
+
+	pod := newPod(job)
+	if pod.Name != job.Name || pod.Spec.RestartPolicy != v1.RestartPolicyNever {
+		t.Errorf("Expect pod %s never restarted, but got %s %s", job.Name, pod.Name, pod.Spec.RestartPolicy)
+	}
+	if pod.Labels["controller-uid"] != job.Labels["controller-uid"] || pod.Annotations[cacheKeyAnnotation] != "key" {
+		t.Errorf("Expect labels and annotations of the job, but got %v %v", pod.Labels, pod.Annotations)
+	}
+	if !isExecutionPod(pod) {
+		t.Errorf("Expect pod controlled by the execution")
+	}
+}
+
+func TestPodWorkload(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Phase  v1.PodPhase
+		Reason string
+		Expect workload.Phase
+	}{
+		{Name: "pending", Phase: v1.PodPending, Expect: workload.Running},
+		{Name: "running", Phase: v1.PodRunning, Expect: workload.Running},
+		{Name: "succeeded", Phase: v1.PodSucceeded, Expect: workload.Succeeded},
+		{Name: "failed", Phase: v1.PodFailed, Expect: workload.Failed},
+		{Name: "evicted", Phase: v1.PodFailed, Reason: "Evicted", Expect: workload.Running},
+	}
+
+	exec := validateExecution()
+	pod := newPod(newJob("example.a.0", "echo A", exec, &exec.Spec.Tasks[0]))
+	for _, testCase := range testCases {
+		pod.Status.Phase = testCase.Phase
+		pod.Status.Reason = testCase.Reason
+		job := podWorkload(pod)
+		if job.Kind != "Pod" || job.Name != pod.Name {
+			t.Errorf("%s: Expect job of pod %s, but got %s %s", testCase.Name, pod.Name, job.Kind, job.Name)
+		}
+		phase, _ := (&podExecutor{}).Status(job)
+		if phase != testCase.Expect {
+			t.Errorf("%s: Expect phase %s, but got %s", testCase.Name, testCase.Expect, phase)
+		}
+	}
+}
+
+func TestPodExecutor(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Type = genev1alpha1.PodTaskType
+	job := newJob("example.a.0", "echo A", exec, task)
+	kubeClient := fake.NewSimpleClientset()
+	executor := &podExecutor{kubeClient: kubeClient}
+
+	// creating the pod twice returns the existing pod.
+	for i := 0; i < 2; i++ {
+		created, err := executor.Create(job, task)
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		if created.Kind != "Pod" || created.Name != job.Name {
+			t.Errorf("Expect pod %s, but got %s %s", job.Name, created.Kind, created.Name)
+		}
+	}
+	if err := executor.Cancel(job); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	pods, _ := kubeClient.CoreV1().Pods(exec.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if len(pods.Items) != 0 {
+		t.Errorf("Expect the cancelled pod to be deleted, but got %d pods", len(pods.Items))
+	}
+}
+
+// runContainer runs the command of the container with sh, the paths under
+// /kubegene being moved into dir.
+func runContainer(ctx context.Context, dir string, container v1.Container) error {
+	args := make([]string, 0, len(container.Command))
+	for _, arg := range container.Command {
+		args = append(args, strings.Replace(arg, "/kubegene/", dir+"/kubegene/", -1))
+	}
+	return osexec.CommandContext(ctx, args[0], args[1:]...).Run()
+}
+
+func TestPodExecutorCommandExit(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Command        string
+		ExpectFailed   bool
+		ExpectUploaded bool
+	}{
+		{
+			Name:           "failing command",
+			Command:        "exit 3",
+			ExpectFailed:   true,
+			ExpectUploaded: false,
+		},
+		{
+			Name:           "succeeding command",
+			Command:        "true",
+			ExpectFailed:   false,
+			ExpectUploaded: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		dir, err := ioutil.TempDir("", "kubegene")
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		defer os.RemoveAll(dir)
+		for _, mountPath := range []string{statusMountPath, artifact.MountPath} {
+			if err := os.MkdirAll(dir+mountPath, 0755); err != nil {
+				t.Fatalf("Expect no error, but got error %v", err)
+			}
+		}
+
+		exec := validateExecution()
+		exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"}}
+		task := &exec.Spec.Tasks[0]
+		task.Type = genev1alpha1.PodTaskType
+		task.OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/out"}}
+		task.Sidecars = []v1.Container{{Name: "log", Image: "busybox", Command: []string{"sleep", "60"}}}
+		kubeClient := fake.NewSimpleClientset()
+		job := newJob("example.a.0", testCase.Command, exec, task)
+		if _, err := (&podExecutor{kubeClient: kubeClient}).Create(job, task); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		pod, err := kubeClient.CoreV1().Pods(exec.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+
+		// the pod is not restarted, so the sidecar and the uploading
+		// sidecar stop once the task command exits, whatever its code.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		sidecars := pod.Spec.Containers[1:]
+		errs := make(chan error, len(sidecars))
+		for _, sidecar := range sidecars {
+			go func(sidecar v1.Container) { errs <- runContainer(ctx, dir, sidecar) }(sidecar)
+		}
+		if err := runContainer(ctx, dir, pod.Spec.Containers[0]); (err != nil) != testCase.ExpectFailed {
+			t.Errorf("%s: Expect task container failed %v, but got %v", testCase.Name, testCase.ExpectFailed, err)
+		}
+		for range sidecars {
+			if err := <-errs; err != nil {
+				t.Errorf("%s: Expect the sidecars to stop, but got %v", testCase.Name, err)
+			}
+		}
+		cancel()
+
+		key := artifact.Key(exec.Namespace, exec.Name, task.Name, "out", "0")
+		_, err = os.Stat(path.Join(dir, "kubegene", "repository", key))
+		if uploaded := err == nil; uploaded != testCase.ExpectUploaded {
+			t.Errorf("%s: Expect output uploaded %v, but got %v", testCase.Name, testCase.ExpectUploaded, uploaded)
+		}
+	}
+}
+
+func TestPodJobLister(t *testing.T) {
+	exec := validateExecution()
+	podInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Pods()
+	podInformer.Informer().GetIndexer().Add(newPod(newJob("example.a.0", "echo A", exec, &exec.Spec.Tasks[0])))
+	// the pod of a batch Job is not listed.
+	podInformer.Informer().GetIndexer().Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-pod", Namespace: exec.Namespace}})
+
+	lister := &podJobLister{lister: podInformer.Lister()}
+	jobs, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "example.a.0" {
+		t.Errorf("Expect job example.a.0, but got %v", jobs)
+	}
+	if _, err := lister.Workloads(exec.Namespace).Get("job-pod"); err == nil {
+		t.Errorf("Expect error, but got nil")
+	}
+}
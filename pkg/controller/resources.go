@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// taskRequests returns the resources requested by the task container, or
+// nil if the task does not specify any.
+func taskRequests(task *genev1alpha1.Task) v1.ResourceList {
+	if task.Resources.Cpu.IsZero() && task.Resources.Memory.IsZero() {
+		return nil
+	}
+	requests := v1.ResourceList{}
+	if !task.Resources.Cpu.IsZero() {
+		requests[v1.ResourceCPU] = task.Resources.Cpu
+	}
+	if !task.Resources.Memory.IsZero() {
+		requests[v1.ResourceMemory] = task.Resources.Memory
+	}
+	return requests
+}
+
+// addJobRequests adds to total the resources requested by the pod of the
+// job. As for the scheduler, the init containers run one after the other
+// before the containers, so the pod requests the largest of the requests
+// of each init container and the sum of the requests of the containers.
+func addJobRequests(total v1.ResourceList, job *workload.Workload) {
+	podRequests := v1.ResourceList{}
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := podRequests[name]
+			sum.Add(quantity)
+			podRequests[name] = sum
+		}
+	}
+	for _, container := range job.Spec.Template.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := podRequests[name]; !ok || quantity.Cmp(current) > 0 {
+				podRequests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range podRequests {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// fitsParallelResources returns true if the job can start next to the
+// active jobs without the requests of the jobs exceeding the resources.
+// The tasks requesting more than the resources are rejected by the
+// validation, but the pod of a job also requests the resources of the
+// containers run next to the task container, e.g. to load artifacts.
+// A job whose pod requests more than the resources alone starts once no
+// other job is active, rather than never.
+func fitsParallelResources(resources *genev1alpha1.ResourceRequirements, active []*workload.Workload, job *workload.Workload) bool {
+	if resources == nil || len(active) == 0 {
+		return true
+	}
+	total := v1.ResourceList{}
+	for _, activeJob := range active {
+		addJobRequests(total, activeJob)
+	}
+	addJobRequests(total, job)
+	return fitsQuantity(resources.Cpu, total[v1.ResourceCPU]) &&
+		fitsQuantity(resources.Memory, total[v1.ResourceMemory])
+}
+
+// fitsQuantity returns true if the requested quantity fits in the limit, a zero limit is no limit.
+func fitsQuantity(limit, requested resource.Quantity) bool {
+	return limit.IsZero() || requested.Cmp(limit) <= 0
+}
+
+// validateParallelResources checks that every task fits in the parallel resources of the execution.
+func validateParallelResources(execution *genev1alpha1.Execution) error {
+	resources := execution.Spec.ParallelResources
+	if resources == nil {
+		return nil
+	}
+	if resources.Cpu.Sign() < 0 || resources.Memory.Sign() < 0 {
+		return fmt.Errorf("parallelResources must be greater than or equal to 0")
+	}
+	for _, task := range execution.Spec.Tasks {
+		if !fitsQuantity(resources.Cpu, task.Resources.Cpu) || !fitsQuantity(resources.Memory, task.Resources.Memory) {
+			return fmt.Errorf("task %s requests more resources than the parallelResources of the execution", task.Name)
+		}
+	}
+	return nil
+}
+
+// parallelRoom returns how many pods like the one of the job, up to max, can
+// start next to the active jobs under the parallelism and the parallel
+// resources of the execution.
+func parallelRoom(exec *genev1alpha1.Execution, active []*workload.Workload, job *workload.Workload, max int) int {
+	room := max
+	if exec.Spec.Parallelism != nil && *exec.Spec.Parallelism > 0 {
+		if left := int(*exec.Spec.Parallelism) - len(active); left < room {
+			room = left
+		}
+	}
+	jobs := append([]*workload.Workload{}, active...)
+	for started := 0; started < room; started++ {
+		if !fitsParallelResources(exec.Spec.ParallelResources, jobs, job) {
+			return started
+		}
+		jobs = append(jobs, job)
+	}
+	if room < 0 {
+		return 0
+	}
+	return room
+}
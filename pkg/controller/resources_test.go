@@ -0,0 +1,188 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func newRequestingJob(name, cpu, memory string) *workload.Workload {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Resources = genev1alpha1.ResourceRequirements{
+		Cpu:    resource.MustParse(cpu),
+		Memory: resource.MustParse(memory),
+	}
+	return newJob(name, "echo A", exec, task)
+}
+
+// withInitContainer returns the job with an init container requesting the cpu.
+func withInitContainer(job *workload.Workload, cpu string) *workload.Workload {
+	job = job.DeepCopy()
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, v1.Container{
+		Name:      "init",
+		Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+	})
+	return job
+}
+
+func TestFitsParallelResources(t *testing.T) {
+	big := newRequestingJob("big", "32", "64Gi")
+	small := newRequestingJob("small", "1", "2Gi")
+
+	testCases := []struct {
+		Name      string
+		Resources *genev1alpha1.ResourceRequirements
+		Active    []*workload.Workload
+		Job       *workload.Workload
+		Expect    bool
+	}{
+		{
+			Name:   "no parallel resources",
+			Active: []*workload.Workload{big, big},
+			Job:    big,
+			Expect: true,
+		},
+		{
+			Name:      "job fits next to the active jobs",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("64")},
+			Active:    []*workload.Workload{big, small},
+			Job:       small,
+			Expect:    true,
+		},
+		{
+			Name:      "cpu of the active jobs leaves no room",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("64")},
+			Active:    []*workload.Workload{big, small},
+			Job:       big,
+			Expect:    false,
+		},
+		{
+			Name:      "memory of the active jobs leaves no room",
+			Resources: &genev1alpha1.ResourceRequirements{Memory: resource.MustParse("100Gi")},
+			Active:    []*workload.Workload{big},
+			Job:       big,
+			Expect:    false,
+		},
+		{
+			Name:      "job larger than the resources runs alone",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("16")},
+			Job:       big,
+			Expect:    true,
+		},
+		{
+			Name:      "init container requests more than the containers",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("8")},
+			Active:    []*workload.Workload{small},
+			Job:       withInitContainer(small, "8"),
+			Expect:    false,
+		},
+		{
+			Name:      "init container requests less than the containers",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("2")},
+			Active:    []*workload.Workload{small},
+			Job:       withInitContainer(withInitContainer(small, "500m"), "1"),
+			Expect:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if fits := fitsParallelResources(testCase.Resources, testCase.Active, testCase.Job); fits != testCase.Expect {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, fits)
+		}
+	}
+}
+
+func TestNewJobRequests(t *testing.T) {
+	job := newRequestingJob("a", "2", "4Gi")
+	requests := job.Spec.Template.Spec.Containers[0].Resources.Requests
+	if cpu := requests.Cpu(); cpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("Expect cpu request 2, but got %s", cpu.String())
+	}
+	if memory := requests.Memory(); memory.Cmp(resource.MustParse("4Gi")) != 0 {
+		t.Errorf("Expect memory request 4Gi, but got %s", memory.String())
+	}
+
+	exec := validateExecution()
+	job = newJob("b", "echo B", exec, &exec.Spec.Tasks[0])
+	if requests := job.Spec.Template.Spec.Containers[0].Resources.Requests; requests != nil {
+		t.Errorf("Expect no requests for a task without resources, but got %v", requests)
+	}
+}
+
+func TestParallelRoom(t *testing.T) {
+	small := newRequestingJob("small", "1", "2Gi")
+	two := int64(2)
+
+	testCases := []struct {
+		Name        string
+		Parallelism *int64
+		Resources   *genev1alpha1.ResourceRequirements
+		Active      []*workload.Workload
+		Max         int
+		Expect      int
+	}{
+		{
+			Name:   "no limits",
+			Active: []*workload.Workload{small},
+			Max:    4,
+			Expect: 4,
+		},
+		{
+			Name:        "parallelism left",
+			Parallelism: &two,
+			Active:      []*workload.Workload{small},
+			Max:         4,
+			Expect:      1,
+		},
+		{
+			Name:        "parallelism taken",
+			Parallelism: &two,
+			Active:      []*workload.Workload{small, small, small},
+			Max:         4,
+			Expect:      0,
+		},
+		{
+			Name:      "resources left",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("3")},
+			Active:    []*workload.Workload{small},
+			Max:       4,
+			Expect:    2,
+		},
+		{
+			Name:      "resources of a pod alone",
+			Resources: &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("500m")},
+			Max:       4,
+			Expect:    1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec := validateExecution()
+		exec.Spec.Parallelism = testCase.Parallelism
+		exec.Spec.ParallelResources = testCase.Resources
+		if room := parallelRoom(exec, testCase.Active, small, testCase.Max); room != testCase.Expect {
+			t.Errorf("%s: Expect room %d, but got %d", testCase.Name, testCase.Expect, room)
+		}
+	}
+}
@@ -18,11 +18,15 @@ package controller
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog"
 
 	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/artifact"
 )
 
 // ValidateExecution accepts a execution and performs validation against it. If lint is specified as
@@ -38,9 +42,31 @@ func ValidateExecution(execution *genev1alpha1.Execution) error {
 	if len(execution.Spec.Tasks) == 0 {
 		return fmt.Errorf("tasks of execution must not be empty")
 	}
+	if err := validateParallelResources(execution); err != nil {
+		return err
+	}
+	if err := validatePodOptions("execution", execution.Spec.Env, execution.Spec.EnvFrom,
+		execution.Spec.ServiceAccountName, execution.Spec.ImagePullSecrets, execution.Spec.ImagePullPolicy); err != nil {
+		return err
+	}
 	if err := validateTasks(execution.Spec.Tasks); err != nil {
 		return err
 	}
+	if err := validateVolcanoOptions("execution", execution.Spec.Volcano); err != nil {
+		return err
+	}
+	for _, task := range execution.Spec.Tasks {
+		options := mergeVolcanoOptions(execution.Spec.Volcano, task.Volcano)
+		if err := validateVolcanoOptions("task "+task.Name, options); err != nil {
+			return err
+		}
+	}
+	if err := validateArtifactRepository(execution); err != nil {
+		return err
+	}
+	if err := validateOutputs(execution); err != nil {
+		return err
+	}
 	if ok := validateNoCycle(execution); !ok {
 		return fmt.Errorf("dependents of execution exist cycle")
 	}
@@ -84,9 +110,39 @@ func validateTask(task genev1alpha1.Task, tasks []genev1alpha1.Task) error {
 	if task.ActiveDeadlineSeconds != nil && *task.ActiveDeadlineSeconds < 0 {
 		return fmt.Errorf("task activeDeadlineSeconds must be greater than or equal to 0")
 	}
-	if task.Type != genev1alpha1.JobTaskType && task.Type != genev1alpha1.SparkTaskType {
+	if task.Type != genev1alpha1.JobTaskType && task.Type != genev1alpha1.SparkTaskType &&
+		task.Type != genev1alpha1.VolcanoJobTaskType && task.Type != genev1alpha1.PodTaskType &&
+		task.Type != genev1alpha1.IndexedJobTaskType {
 		return fmt.Errorf("wrong task type: %s", task.Type)
 	}
+	if task.Type == genev1alpha1.IndexedJobTaskType {
+		if err := validateIndexedTask(task); err != nil {
+			return err
+		}
+	}
+	if err := validateChunkSize(task); err != nil {
+		return err
+	}
+	if err := validateMemoryEscalation(task); err != nil {
+		return err
+	}
+	if task.Volcano != nil && task.Type != genev1alpha1.VolcanoJobTaskType {
+		return fmt.Errorf("task %s: volcano options can only be specified for tasks of type VolcanoJob", task.Name)
+	}
+	if len(task.SkipPolicy) != 0 && task.SkipPolicy != genev1alpha1.SkipPolicyContinue &&
+		task.SkipPolicy != genev1alpha1.SkipPolicyCascade {
+		return fmt.Errorf("wrong skipPolicy of task %s: %s", task.Name, task.SkipPolicy)
+	}
+	if err := validatePodOptions("task "+task.Name, task.Env, task.EnvFrom,
+		task.ServiceAccountName, task.ImagePullSecrets, task.ImagePullPolicy); err != nil {
+		return err
+	}
+	if err := validateVolumes(task); err != nil {
+		return err
+	}
+	if err := validateContainers(task); err != nil {
+		return err
+	}
 	if len(task.Dependents) != 0 {
 		if err := validateDependents(task.Name, task.Dependents, tasks); err != nil {
 			return err
@@ -107,10 +163,177 @@ func validateTask(task genev1alpha1.Task, tasks []genev1alpha1.Task) error {
 			return err
 		}
 	}
+	if err := validateArtifacts(task, tasks); err != nil {
+		return err
+	}
+	if task.Cache != nil && *task.Cache && len(task.OutputArtifacts) != 0 {
+		return fmt.Errorf("task %s with output artifacts can not be cached", task.Name)
+	}
 
 	return nil
 }
 
+// validatePodOptions validates the options of the pods set at the execution or at the task level.
+func validatePodOptions(owner string, env []v1.EnvVar, envFrom []v1.EnvFromSource, serviceAccountName string,
+	imagePullSecrets []v1.LocalObjectReference, imagePullPolicy v1.PullPolicy) error {
+	for _, e := range env {
+		if msgs := validation.IsEnvVarName(e.Name); len(msgs) > 0 {
+			return fmt.Errorf("%s: env name %s is not valid %v", owner, e.Name, msgs)
+		}
+		if e.ValueFrom != nil && len(e.Value) != 0 {
+			return fmt.Errorf("%s: env %s can not specify both value and valueFrom", owner, e.Name)
+		}
+	}
+	for _, e := range envFrom {
+		if (e.SecretRef == nil) == (e.ConfigMapRef == nil) {
+			return fmt.Errorf("%s: exactly one of secretRef, configMapRef must be specified in envFrom", owner)
+		}
+	}
+	if len(serviceAccountName) != 0 {
+		if msgs := validation.IsDNS1123Subdomain(serviceAccountName); len(msgs) > 0 {
+			return fmt.Errorf("%s: serviceAccountName %s is not valid %v", owner, serviceAccountName, msgs)
+		}
+	}
+	for _, secret := range imagePullSecrets {
+		if len(secret.Name) == 0 {
+			return fmt.Errorf("%s: name of imagePullSecrets must not be empty", owner)
+		}
+	}
+	switch imagePullPolicy {
+	case "", v1.PullAlways, v1.PullNever, v1.PullIfNotPresent:
+	default:
+		return fmt.Errorf("%s: wrong imagePullPolicy: %s", owner, imagePullPolicy)
+	}
+	return nil
+}
+
+func validateArtifactRepository(execution *genev1alpha1.Execution) error {
+	used := false
+	for _, task := range execution.Spec.Tasks {
+		if len(task.InputArtifacts) != 0 || len(task.OutputArtifacts) != 0 {
+			used = true
+			break
+		}
+	}
+	repo := execution.Spec.ArtifactRepository
+	if repo == nil {
+		if used {
+			return fmt.Errorf("artifactRepository must be specified when tasks have artifacts")
+		}
+		return nil
+	}
+	if _, err := artifact.NewRepository(repo); err != nil {
+		return err
+	}
+	if repo.Local != nil && len(repo.Local.Pvc) == 0 {
+		return fmt.Errorf("artifactRepository.local.pvc must not be empty")
+	}
+	if repo.S3 != nil {
+		if len(repo.S3.Endpoint) == 0 || len(repo.S3.Bucket) == 0 {
+			return fmt.Errorf("artifactRepository.s3 endpoint and bucket must not be empty")
+		}
+		if len(repo.S3.AccessKeySecret.Name) == 0 || len(repo.S3.AccessKeySecret.Key) == 0 ||
+			len(repo.S3.SecretKeySecret.Name) == 0 || len(repo.S3.SecretKeySecret.Key) == 0 {
+			return fmt.Errorf("artifactRepository.s3 accessKeySecret and secretKeySecret must be specified")
+		}
+	}
+	return nil
+}
+
+func validateOutputs(execution *genev1alpha1.Execution) error {
+	for name, output := range execution.Spec.Outputs {
+		if len(output.Paths) == 0 {
+			return fmt.Errorf("paths of output %s must not be empty", name)
+		}
+		for _, p := range output.Paths {
+			if !path.IsAbs(p) {
+				return fmt.Errorf("path %s of output %s must be an absolute path", p, name)
+			}
+			if !inTaskVolumes(execution.Spec.Tasks, p) {
+				return fmt.Errorf("path %s of output %s is not in any pvc or nfs volume of the tasks", p, name)
+			}
+		}
+	}
+	return nil
+}
+
+// inTaskVolumes returns true if the path is under the mount path of a shared volume of the tasks.
+func inTaskVolumes(tasks []genev1alpha1.Task, p string) bool {
+	p = path.Clean(p)
+	for _, task := range tasks {
+		for _, volume := range task.Volumes {
+			if !isSharedVolume(volume) {
+				continue
+			}
+			mountPath := path.Clean(volume.MountPath)
+			if p == mountPath || strings.HasPrefix(p, strings.TrimSuffix(mountPath, "/")+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateArtifacts(task genev1alpha1.Task, tasks []genev1alpha1.Task) error {
+	if err := validateArtifactList(task.Name, "inputArtifacts", task.InputArtifacts); err != nil {
+		return err
+	}
+	if err := validateArtifactList(task.Name, "outputArtifacts", task.OutputArtifacts); err != nil {
+		return err
+	}
+
+	for _, input := range task.InputArtifacts {
+		source := strings.SplitN(input.From, Separator, 2)
+		if len(source) != 2 {
+			return fmt.Errorf("%s: from of input artifact %s must be in the form of <task>.<artifact>", task.Name, input.Name)
+		}
+		dependent := false
+		for _, d := range task.Dependents {
+			if d.Target == source[0] {
+				dependent = true
+				break
+			}
+		}
+		if !dependent {
+			return fmt.Errorf("%s: input artifact %s is from task %s which is not a dependent", task.Name, input.Name, source[0])
+		}
+		_, sourceTask := getTaskByName(tasks, source[0])
+		found := false
+		for _, output := range sourceTask.OutputArtifacts {
+			if output.Name == source[1] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: input artifact %s is from %s which is not an output artifact", task.Name, input.Name, input.From)
+		}
+	}
+	for _, output := range task.OutputArtifacts {
+		if len(output.From) != 0 {
+			return fmt.Errorf("%s: from of output artifact %s must be empty", task.Name, output.Name)
+		}
+	}
+	return nil
+}
+
+func validateArtifactList(taskName, field string, artifacts []genev1alpha1.Artifact) error {
+	names := make(map[string]struct{}, len(artifacts))
+	for _, a := range artifacts {
+		if msgs := validation.IsDNS1123Label(a.Name); len(msgs) > 0 {
+			return fmt.Errorf("%s: %s name %s is not valid %v", taskName, field, a.Name, msgs)
+		}
+		if _, exist := names[a.Name]; exist {
+			return fmt.Errorf("%s: %s name %s duplicated", taskName, field, a.Name)
+		}
+		names[a.Name] = struct{}{}
+		if !path.IsAbs(a.Path) || strings.Contains(a.Path, "'") {
+			return fmt.Errorf("%s: %s path %s must be an absolute path", taskName, field, a.Path)
+		}
+	}
+	return nil
+}
+
 func validateDependents(taskName string, dependents []genev1alpha1.Dependent, tasks []genev1alpha1.Task) error {
 	for _, dependent := range dependents {
 		if dependent.Type != genev1alpha1.DependTypeWhole && dependent.Type != genev1alpha1.DependTypeIterate {
@@ -183,6 +406,25 @@ func validateGenericDependency(taskName string, dependJobName string, tasks []ge
 	return fmt.Errorf("the task dependency type is wrong ")
 }
 
+// validateResultDependency checks that the task depends on the whole task
+// whose result it reads, and that the latter runs a single job.
+func validateResultDependency(taskName string, dependTaskName string, tasks []genev1alpha1.Task) error {
+	found, dependTask := getTaskByName(tasks, dependTaskName)
+	if !found {
+		return fmt.Errorf("the dependecy job is missing, but the real one is %s", dependTaskName)
+	}
+	if len(dependTask.CommandSet) > 1 || dependTask.CommandsIter != nil && len(dependTask.CommandsIter.VarsIter) > 1 {
+		return fmt.Errorf("the dependecy job %s has more than one command", dependTaskName)
+	}
+	_, currentTask := getTaskByName(tasks, taskName)
+	for _, dependent := range currentTask.Dependents {
+		if dependent.Target == dependTaskName && dependent.Type == genev1alpha1.DependTypeWhole {
+			return nil
+		}
+	}
+	return fmt.Errorf("the task %s does not depend on the whole task %s", taskName, dependTaskName)
+}
+
 func validateGenericCondition(taskName string, gCondition *genev1alpha1.GenericCondition, tasks []genev1alpha1.Task) error {
 
 	err := validateGenericDependency(taskName, gCondition.DependJobName, tasks)
@@ -270,6 +512,26 @@ func validateCondition(taskName string, condition *genev1alpha1.Condition, tasks
 		return nil
 	}
 
+	if func_name, ok := v[0].(string); ok && func_name == "expression" {
+		if len(v) != 2 {
+			return fmt.Errorf("In condition  expression format is wrong in task :%s", taskName)
+		}
+		expression, ok := v[1].(string)
+		if !ok {
+			return fmt.Errorf("In condition  expression is not a string in task :%s", taskName)
+		}
+		dependTaskNames, err := expressionResults(expression)
+		if err != nil {
+			return fmt.Errorf("In condition of task %s: %v", taskName, err)
+		}
+		for _, dependTaskName := range dependTaskNames {
+			if err := validateResultDependency(taskName, dependTaskName, tasks); err != nil {
+				return fmt.Errorf("In condition of task %s: %v", taskName, err)
+			}
+		}
+		return nil
+	}
+
 	if func_name, ok := v[0].(string); ok && func_name == "check_result" {
 
 		if len(v) != 3 {
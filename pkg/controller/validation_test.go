@@ -17,11 +17,15 @@ limitations under the License.
 package controller
 
 import (
+	"strings"
 	"testing"
 
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/indexedjob"
 )
 
 type ModifyExecution func(exec *genev1alpha1.Execution)
@@ -343,6 +347,387 @@ func TestValidateExecution(t *testing.T) {
 			},
 			ExpectErr: true,
 		},
+		{
+			Name: "task with valid skipPolicy",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].SkipPolicy = genev1alpha1.SkipPolicyCascade
+				exec.Spec.Tasks[2].SkipPolicy = genev1alpha1.SkipPolicyContinue
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "wrong skipPolicy of task",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].SkipPolicy = "Ignore"
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task with valid artifacts",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				}
+				exec.Spec.Tasks[0].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+				exec.Spec.Tasks[1].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "a.out"}}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "artifactRepository must be specified when tasks have artifacts",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "only one of local, s3 artifact repository can be specified",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+					S3:    &genev1alpha1.S3ArtifactRepository{Endpoint: "minio:9000", Bucket: "artifacts"},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "s3 artifact repository secrets must be specified",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					S3: &genev1alpha1.S3ArtifactRepository{Endpoint: "minio:9000", Bucket: "artifacts"},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "artifact path must be an absolute path",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				}
+				exec.Spec.Tasks[0].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "tmp/out"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "input artifact must be from a dependent",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				}
+				exec.Spec.Tasks[1].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+				exec.Spec.Tasks[0].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "b.out"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "input artifact must be from an output artifact",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				}
+				exec.Spec.Tasks[1].InputArtifacts = []genev1alpha1.Artifact{{Name: "in", Path: "/tmp/in", From: "a.out"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "execution with valid outputs",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Outputs = map[string]genev1alpha1.Output{
+					"result": {Paths: []string{"/tmp/hostvolume/result.txt", "/tmp/hostvolume"}},
+				}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "paths of output must not be empty",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Outputs = map[string]genev1alpha1.Output{"result": {}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "path of output must be an absolute path",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Outputs = map[string]genev1alpha1.Output{
+					"result": {Paths: []string{"tmp/hostvolume/result.txt"}},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "path of output is not in any volume of the tasks",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Outputs = map[string]genev1alpha1.Output{
+					"result": {Paths: []string{"/tmp/hostvolume2/result.txt"}},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task with cache",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				cache := true
+				exec.Spec.Cache = &cache
+				exec.Spec.Tasks[1].Cache = &cache
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "task with output artifacts can not be cached",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				cache := true
+				exec.Spec.ArtifactRepository = &genev1alpha1.ArtifactRepository{
+					Local: &genev1alpha1.LocalArtifactRepository{Pvc: "artifacts"},
+				}
+				exec.Spec.Tasks[0].OutputArtifacts = []genev1alpha1.Artifact{{Name: "out", Path: "/tmp/out"}}
+				exec.Spec.Tasks[0].Cache = &cache
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "execution and task with pod options",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Env = []v1.EnvVar{{Name: "FOO", Value: "bar"}}
+				exec.Spec.ServiceAccountName = "gene"
+				exec.Spec.ImagePullPolicy = v1.PullAlways
+				exec.Spec.Tasks[0].EnvFrom = []v1.EnvFromSource{
+					{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}}},
+				}
+				exec.Spec.Tasks[0].ImagePullSecrets = []v1.LocalObjectReference{{Name: "registry"}}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "env name is not valid",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Env = []v1.EnvVar{{Name: "1=FOO", Value: "bar"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "exactly one of secretRef, configMapRef must be specified in envFrom",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.EnvFrom = []v1.EnvFromSource{{Prefix: "A_"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "wrong imagePullPolicy",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].ImagePullPolicy = "Sometimes"
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "serviceAccountName is not valid",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[1].ServiceAccountName = "Gene_SA"
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task with volumes of all types",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volumes = map[string]genev1alpha1.Volume{
+					"scratch": {MountPath: "/scratch", MountFrom: genev1alpha1.VolumeSource{
+						EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}}},
+					"nvme": {MountPath: "/nvme", MountFrom: genev1alpha1.VolumeSource{
+						HostPath: &v1.HostPathVolumeSource{Path: "/mnt/nvme"}}},
+					"config": {MountPath: "/config", ReadOnly: true, MountFrom: genev1alpha1.VolumeSource{
+						ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}}},
+					"creds": {MountPath: "/creds", ReadOnly: true, MountFrom: genev1alpha1.VolumeSource{
+						Secret: &v1.SecretVolumeSource{SecretName: "creds"}}},
+					"ref": {MountPath: "/ref", ReadOnly: true, MountFrom: genev1alpha1.VolumeSource{
+						NFS: &v1.NFSVolumeSource{Server: "nfs.example.com", Path: "/ref"}}},
+					"work": {MountPath: "/work", MountFrom: genev1alpha1.VolumeSource{
+						Ephemeral: &genev1alpha1.EphemeralVolumeSource{VolumeClaimTemplate: v1.PersistentVolumeClaimSpec{
+							Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+						}}}},
+				}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "exactly one source must be specified in volume",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volumes["volumea"] = genev1alpha1.Volume{
+					MountPath: "/tmp/hostvolume",
+					MountFrom: genev1alpha1.VolumeSource{Pvc: "test-host-path", EmptyDir: &v1.EmptyDirVolumeSource{}},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "mountPath of volume must be an absolute path",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volumes["volumea"] = genev1alpha1.Volume{
+					MountPath: "tmp/hostvolume",
+					MountFrom: genev1alpha1.VolumeSource{Pvc: "test-host-path"},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "storage request of ephemeral volume must be specified",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volumes["work"] = genev1alpha1.Volume{
+					MountPath: "/work",
+					MountFrom: genev1alpha1.VolumeSource{Ephemeral: &genev1alpha1.EphemeralVolumeSource{}},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "path of output in a scratch volume",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volumes["scratch"] = genev1alpha1.Volume{
+					MountPath: "/scratch",
+					MountFrom: genev1alpha1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+				}
+				exec.Spec.Outputs = map[string]genev1alpha1.Output{
+					"result": {Paths: []string{"/scratch/result.txt"}},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task with init containers and sidecars",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].InitContainers = []v1.Container{{Name: "fetch-ref", Image: "busybox",
+					VolumeMounts: []v1.VolumeMount{{Name: "volumea", MountPath: "/ref"}}}}
+				exec.Spec.Tasks[0].Sidecars = []v1.Container{{Name: "log-shipper", Image: "busybox", Command: []string{"tail"}}}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "command of sidecar must be specified",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Sidecars = []v1.Container{{Name: "log-shipper", Image: "busybox"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "container name duplicated",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].InitContainers = []v1.Container{{Name: "stage", Image: "busybox"}}
+				exec.Spec.Tasks[0].Sidecars = []v1.Container{{Name: "stage", Image: "busybox", Command: []string{"sleep"}}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "name of init container must not start with kubegene-",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].InitContainers = []v1.Container{{Name: "kubegene-stage", Image: "busybox"}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "volume mounted in init container is not a volume of the task",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].InitContainers = []v1.Container{{Name: "stage", Image: "busybox",
+					VolumeMounts: []v1.VolumeMount{{Name: "volumeb", MountPath: "/ref"}}}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task run as volcano job",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				replicas := int32(4)
+				exec.Spec.Volcano = &genev1alpha1.VolcanoOptions{Queue: "genomics"}
+				exec.Spec.Tasks[0].Type = genev1alpha1.VolcanoJobTaskType
+				exec.Spec.Tasks[0].Volcano = &genev1alpha1.VolcanoOptions{Replicas: &replicas}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "volcano options on a task of type Job",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Volcano = &genev1alpha1.VolcanoOptions{Queue: "genomics"}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "volcano queue is not valid",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Volcano = &genev1alpha1.VolcanoOptions{Queue: "Genomics_Queue"}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "volcano minAvailable greater than replicas",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				minAvailable := int32(2)
+				exec.Spec.Tasks[0].Type = genev1alpha1.VolcanoJobTaskType
+				exec.Spec.Tasks[0].Volcano = &genev1alpha1.VolcanoOptions{MinAvailable: &minAvailable}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task run as indexed job",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Type = genev1alpha1.IndexedJobTaskType
+				exec.Spec.Tasks[0].CommandSet = []string{"echo A", "echo B"}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "indexed job task with commands iter",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Type = genev1alpha1.IndexedJobTaskType
+				exec.Spec.Tasks[0].CommandsIter = &genev1alpha1.CommandsIter{Command: "echo ${1}", VarsIter: []interface{}{[]interface{}{"a"}}}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "indexed job task with a command longer than a configmap",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks[0].Type = genev1alpha1.IndexedJobTaskType
+				exec.Spec.Tasks[0].CommandSet = []string{"echo " + strings.Repeat("A", indexedjob.MaxCommandsBytes)}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task run in chunks",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				chunk := int32(10)
+				exec.Spec.Tasks[0].ChunkSize = &chunk
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "chunk size of a task of type Pod",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				chunk := int32(10)
+				exec.Spec.Tasks[0].Type = genev1alpha1.PodTaskType
+				exec.Spec.Tasks[0].ChunkSize = &chunk
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "chunk size is too large",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				chunk := int32(maxChunkSize + 1)
+				exec.Spec.Tasks[0].ChunkSize = &chunk
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task fits in the parallel resources",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ParallelResources = &genev1alpha1.ResourceRequirements{Cpu: resource.MustParse("256")}
+				exec.Spec.Tasks[0].Resources.Cpu = resource.MustParse("32")
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "task requests more than the parallel resources",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.ParallelResources = &genev1alpha1.ResourceRequirements{Memory: resource.MustParse("8Gi")}
+				exec.Spec.Tasks[0].Resources.Memory = resource.MustParse("16Gi")
+			},
+			ExpectErr: true,
+		},
 		{
 			Name: "dependent target must not be empty",
 			ModifyFunc: func(exec *genev1alpha1.Execution) {
@@ -879,6 +1264,87 @@ func TestValidateExecution(t *testing.T) {
 			},
 			ExpectErr: true,
 		},
+		{
+			Name: "task with valid expression condition",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks = []genev1alpha1.Task{
+					{
+						Name:       "a",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo 45"},
+						Image:      "hello-word",
+					},
+					{
+						Name:       "b",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo done"},
+						Image:      "hello-word",
+						Dependents: []genev1alpha1.Dependent{
+							{
+								Target: "a",
+								Type:   genev1alpha1.DependTypeWhole,
+							},
+						},
+						Condition: &genev1alpha1.Condition{
+							Condition: interface{}([]interface{}{"expression", "number(result(\"a\")) > 30"}),
+						},
+					},
+				}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name: "task with Invalid expression condition(syntax error)",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks = []genev1alpha1.Task{
+					{
+						Name:       "a",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo 45"},
+						Image:      "hello-word",
+					},
+					{
+						Name:       "b",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo done"},
+						Image:      "hello-word",
+						Dependents: []genev1alpha1.Dependent{
+							{
+								Target: "a",
+								Type:   genev1alpha1.DependTypeWhole,
+							},
+						},
+						Condition: &genev1alpha1.Condition{
+							Condition: interface{}([]interface{}{"expression", "number(result(\"a\")) >"}),
+						},
+					},
+				}
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "task with Invalid expression condition(result of a task not depended on)",
+			ModifyFunc: func(exec *genev1alpha1.Execution) {
+				exec.Spec.Tasks = []genev1alpha1.Task{
+					{
+						Name:       "a",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo 45"},
+						Image:      "hello-word",
+					},
+					{
+						Name:       "b",
+						Type:       genev1alpha1.JobTaskType,
+						CommandSet: []string{"echo done"},
+						Image:      "hello-word",
+						Condition: &genev1alpha1.Condition{
+							Condition: interface{}([]interface{}{"expression", "result(\"a\") == \"45\""}),
+						},
+					},
+				}
+			},
+			ExpectErr: true,
+		},
 		// CommandsIter related test cases
 		{
 			Name: "task with valid CommandsIter",
@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/volcano"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// volcanoExecutor runs the jobs as Volcano Jobs.
+type volcanoExecutor struct {
+	kubeClient    clientset.Interface
+	dynamicClient dynamic.Interface
+}
+
+var _ TaskExecutor = &volcanoExecutor{}
+
+func (v *volcanoExecutor) Create(job *workload.Workload, task *genev1alpha1.Task) (*workload.Workload, error) {
+	obj, err := volcano.ToUnstructured(volcano.NewJob(job, task.Volcano))
+	if err != nil {
+		return nil, err
+	}
+	client := v.dynamicClient.Resource(volcano.JobResource).Namespace(job.Namespace)
+	created, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if err != nil && errors.IsAlreadyExists(err) {
+		created, err = client.Get(context.TODO(), job.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	vcJob, err := volcano.FromUnstructured(created)
+	if err != nil {
+		return nil, err
+	}
+	return volcano.ToWorkload(vcJob), nil
+}
+
+// Status returns the phase the phase of the Volcano Job has been mapped onto.
+func (v *volcanoExecutor) Status(job *workload.Workload) (workload.Phase, string) {
+	return job.Status.Phase, job.Status.Message
+}
+
+func (v *volcanoExecutor) Result(job *workload.Workload, limitBytes int64) (string, error) {
+	return getJobLogs(v.kubeClient, job, limitBytes)
+}
+
+func (v *volcanoExecutor) Cancel(job *workload.Workload) error {
+	if job.Finished() {
+		return nil
+	}
+	err := v.dynamicClient.Resource(volcano.JobResource).Namespace(job.Namespace).Delete(context.TODO(), job.Name,
+		metav1.DeleteOptions{PropagationPolicy: &backgroundDeletion})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (v *volcanoExecutor) Cleanup(job *workload.Workload, task *genev1alpha1.Task) error {
+	return deleteEphemeralClaims(v.kubeClient, job, task)
+}
+
+// volcanoWorkload returns the workload the Volcano Job of an informer event runs.
+func volcanoWorkload(obj interface{}) *workload.Workload {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		klog.Errorf("object %T is not a volcano job", obj)
+		return nil
+	}
+	vcJob, err := volcano.FromUnstructured(runtimeObj)
+	if err != nil {
+		klog.Errorf("convert volcano job error: %v", err)
+		return nil
+	}
+	return volcano.ToWorkload(vcJob)
+}
+
+// mergeVolcanoOptions returns the options of the task, completed by the options of the execution.
+func mergeVolcanoOptions(execOptions, taskOptions *genev1alpha1.VolcanoOptions) *genev1alpha1.VolcanoOptions {
+	if execOptions == nil {
+		return taskOptions
+	}
+	if taskOptions == nil {
+		return execOptions
+	}
+	merged := taskOptions.DeepCopy()
+	if len(merged.Queue) == 0 {
+		merged.Queue = execOptions.Queue
+	}
+	if len(merged.SchedulerName) == 0 {
+		merged.SchedulerName = execOptions.SchedulerName
+	}
+	if len(merged.PriorityClassName) == 0 {
+		merged.PriorityClassName = execOptions.PriorityClassName
+	}
+	if merged.Replicas == nil {
+		merged.Replicas = execOptions.Replicas
+	}
+	if merged.MinAvailable == nil {
+		merged.MinAvailable = execOptions.MinAvailable
+	}
+	return merged
+}
+
+func validateVolcanoOptions(owner string, options *genev1alpha1.VolcanoOptions) error {
+	if options == nil {
+		return nil
+	}
+	for field, name := range map[string]string{
+		"queue":             options.Queue,
+		"schedulerName":     options.SchedulerName,
+		"priorityClassName": options.PriorityClassName,
+	} {
+		if len(name) == 0 {
+			continue
+		}
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			return fmt.Errorf("%s: volcano %s %s is not valid %v", owner, field, name, msgs)
+		}
+	}
+	replicas := int32(1)
+	if options.Replicas != nil {
+		replicas = *options.Replicas
+		if replicas < 1 {
+			return fmt.Errorf("%s: volcano replicas must be greater than or equal to 1", owner)
+		}
+	}
+	if options.MinAvailable != nil && (*options.MinAvailable < 1 || *options.MinAvailable > replicas) {
+		return fmt.Errorf("%s: volcano minAvailable must be between 1 and replicas", owner)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/volcano"
+)
+
+func TestVolcanoExecutorCreate(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Type = genev1alpha1.VolcanoJobTaskType
+	task.Volcano = &genev1alpha1.VolcanoOptions{Queue: "genomics"}
+	job := newJob("example.a.0", "echo A", exec, task)
+
+	executor := &volcanoExecutor{
+		kubeClient:    fake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	}
+	// creating the job twice returns the existing job.
+	for i := 0; i < 2; i++ {
+		created, err := executor.Create(job, task)
+		if err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+		if !volcano.IsVolcanoJob(created) || created.Name != job.Name {
+			t.Errorf("Expect volcano job %s, but got %v", job.Name, created)
+		}
+	}
+
+	if err := executor.Cancel(job); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	_, err := executor.dynamicClient.Resource(volcano.JobResource).Namespace(job.Namespace).Get(
+		context.TODO(), job.Name, metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expect the cancelled volcano job to be deleted, but got %v", err)
+	}
+}
+
+func TestMergeVolcanoOptions(t *testing.T) {
+	replicas := int32(2)
+	execOptions := &genev1alpha1.VolcanoOptions{Queue: "default", SchedulerName: "volcano", Replicas: &replicas}
+	merged := mergeVolcanoOptions(execOptions, &genev1alpha1.VolcanoOptions{Queue: "genomics"})
+	if merged.Queue != "genomics" || merged.SchedulerName != "volcano" || *merged.Replicas != 2 {
+		t.Errorf("Expect options of the task completed by the execution, but got %v", merged)
+	}
+	if mergeVolcanoOptions(nil, nil) != nil {
+		t.Errorf("Expect nil options")
+	}
+}
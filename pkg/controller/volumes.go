@@ -0,0 +1,197 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientset "k8s.io/client-go/kubernetes"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// ephemeralClaimName returns the name of the PersistentVolumeClaim
+// provisioned for the ephemeral volume of the job.
+func ephemeralClaimName(jobName, volumeName string) string {
+	return jobName + "-" + volumeName
+}
+
+// newVolumeSource returns the source of the pod volume of the job.
+func newVolumeSource(jobName, volumeName string, volume genev1alpha1.Volume) v1.VolumeSource {
+	from := volume.MountFrom
+	switch {
+	case from.EmptyDir != nil:
+		return v1.VolumeSource{EmptyDir: from.EmptyDir.DeepCopy()}
+	case from.HostPath != nil:
+		return v1.VolumeSource{HostPath: from.HostPath.DeepCopy()}
+	case from.ConfigMap != nil:
+		return v1.VolumeSource{ConfigMap: from.ConfigMap.DeepCopy()}
+	case from.Secret != nil:
+		return v1.VolumeSource{Secret: from.Secret.DeepCopy()}
+	case from.NFS != nil:
+		return v1.VolumeSource{NFS: from.NFS.DeepCopy()}
+	case from.Ephemeral != nil:
+		return v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: ephemeralClaimName(jobName, volumeName),
+			},
+		}
+	}
+	return v1.VolumeSource{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+			ClaimName: from.Pvc,
+		},
+	}
+}
+
+// isSharedVolume returns true if the content of the volume is shared by all
+// the jobs and outlives them.
+func isSharedVolume(volume genev1alpha1.Volume) bool {
+	return len(volume.MountFrom.Pvc) != 0 || volume.MountFrom.NFS != nil
+}
+
+// scratchVolumeKey is the key of the volumes whose content lives as long as the job.
+const scratchVolumeKey = "scratch"
+
+// volumeSourceKey identifies the content of the volume in the cache key.
+// The content of the scratch volumes is not shared, they are all alike.
+func volumeSourceKey(volume genev1alpha1.Volume) string {
+	from := volume.MountFrom
+	switch {
+	case from.EmptyDir != nil, from.Ephemeral != nil:
+		return scratchVolumeKey
+	case from.HostPath != nil:
+		return "hostPath:" + from.HostPath.Path
+	case from.ConfigMap != nil:
+		return "configMap:" + from.ConfigMap.Name
+	case from.Secret != nil:
+		return "secret:" + from.Secret.SecretName
+	case from.NFS != nil:
+		return "nfs:" + from.NFS.Server + ":" + from.NFS.Path
+	}
+	return "pvc:" + from.Pvc
+}
+
+// createEphemeralClaims creates the PersistentVolumeClaims of the ephemeral
+// volumes of the job. The claims are owned by the job, so that they are
+// garbage collected together with it.
+func createEphemeralClaims(kubeClient clientset.Interface, job *workload.Workload, task *genev1alpha1.Task) error {
+	// the workload of the job is a batch Job unless its executor says otherwise.
+	ownerAPIVersion, ownerKind := batch.SchemeGroupVersion.String(), "Job"
+	if len(job.APIVersion) != 0 && len(job.Kind) != 0 {
+		ownerAPIVersion, ownerKind = job.APIVersion, job.Kind
+	}
+	for _, name := range ephemeralVolumeNames(task) {
+		claim := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ephemeralClaimName(job.Name, name),
+				Namespace: job.Namespace,
+				Labels:    job.Labels,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: ownerAPIVersion,
+					Kind:       ownerKind,
+					Name:       job.Name,
+					UID:        job.UID,
+				}},
+			},
+			Spec: *task.Volumes[name].MountFrom.Ephemeral.VolumeClaimTemplate.DeepCopy(),
+		}
+		_, err := kubeClient.CoreV1().PersistentVolumeClaims(job.Namespace).Create(context.TODO(), claim, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create claim %s/%s error: %v", claim.Namespace, claim.Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteEphemeralClaims deletes the PersistentVolumeClaims of the ephemeral
+// volumes of the job, the claims already deleted are ignored.
+func deleteEphemeralClaims(kubeClient clientset.Interface, job *workload.Workload, task *genev1alpha1.Task) error {
+	for _, name := range ephemeralVolumeNames(task) {
+		claimName := ephemeralClaimName(job.Name, name)
+		err := kubeClient.CoreV1().PersistentVolumeClaims(job.Namespace).Delete(context.TODO(), claimName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("delete claim %s/%s error: %v", job.Namespace, claimName, err)
+		}
+	}
+	return nil
+}
+
+// ephemeralVolumeNames returns the sorted names of the ephemeral volumes of the task.
+func ephemeralVolumeNames(task *genev1alpha1.Task) []string {
+	names := make([]string, 0, len(task.Volumes))
+	for name, volume := range task.Volumes {
+		if volume.MountFrom.Ephemeral != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateVolumes(task genev1alpha1.Task) error {
+	for name, volume := range task.Volumes {
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			return fmt.Errorf("task %s: volume name %s is not valid %v", task.Name, name, msgs)
+		}
+		if !path.IsAbs(volume.MountPath) {
+			return fmt.Errorf("task %s: mountPath of volume %s must be an absolute path", task.Name, name)
+		}
+		from := volume.MountFrom
+		sources := 0
+		if len(from.Pvc) != 0 {
+			sources++
+		}
+		for _, set := range []bool{from.EmptyDir != nil, from.HostPath != nil, from.ConfigMap != nil,
+			from.Secret != nil, from.NFS != nil, from.Ephemeral != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("task %s: exactly one of pvc, emptyDir, hostPath, configMap, secret, nfs, ephemeral "+
+				"must be specified in volume %s", task.Name, name)
+		}
+		if from.HostPath != nil && !path.IsAbs(from.HostPath.Path) {
+			return fmt.Errorf("task %s: hostPath of volume %s must be an absolute path", task.Name, name)
+		}
+		if from.NFS != nil && (len(from.NFS.Server) == 0 || len(from.NFS.Path) == 0) {
+			return fmt.Errorf("task %s: server and path of nfs volume %s must not be empty", task.Name, name)
+		}
+		if from.ConfigMap != nil && len(from.ConfigMap.Name) == 0 {
+			return fmt.Errorf("task %s: name of configMap volume %s must not be empty", task.Name, name)
+		}
+		if from.Secret != nil && len(from.Secret.SecretName) == 0 {
+			return fmt.Errorf("task %s: secretName of secret volume %s must not be empty", task.Name, name)
+		}
+		if from.Ephemeral != nil {
+			if _, ok := from.Ephemeral.VolumeClaimTemplate.Resources.Requests[v1.ResourceStorage]; !ok {
+				return fmt.Errorf("task %s: storage request of ephemeral volume %s must be specified", task.Name, name)
+			}
+		}
+	}
+	return nil
+}
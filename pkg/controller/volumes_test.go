@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestNewJobVolumes(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Volumes = map[string]genev1alpha1.Volume{
+		"ref": {MountPath: "/ref", ReadOnly: true, MountFrom: genev1alpha1.VolumeSource{
+			NFS: &v1.NFSVolumeSource{Server: "nfs.example.com", Path: "/ref"}}},
+		"work": {MountPath: "/work", MountFrom: genev1alpha1.VolumeSource{
+			Ephemeral: &genev1alpha1.EphemeralVolumeSource{}}},
+	}
+
+	job := newJob("example.a.0", "echo A", exec, task)
+	volumes := map[string]v1.Volume{}
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	if volumes["ref"].NFS == nil || volumes["ref"].NFS.Server != "nfs.example.com" {
+		t.Errorf("Expect nfs volume, but got %v", volumes["ref"])
+	}
+	if claim := volumes["work"].PersistentVolumeClaim; claim == nil || claim.ClaimName != "example.a.0-work" {
+		t.Errorf("Expect claim of the job, but got %v", volumes["work"])
+	}
+	for _, mount := range job.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if mount.ReadOnly != (mount.Name == "ref") {
+			t.Errorf("Expect only volume ref to be read-only, but got %v", mount)
+		}
+	}
+}
+
+func TestCreateEphemeralClaims(t *testing.T) {
+	exec := validateExecution()
+	task := &exec.Spec.Tasks[0]
+	task.Volumes["work"] = genev1alpha1.Volume{MountPath: "/work", MountFrom: genev1alpha1.VolumeSource{
+		Ephemeral: &genev1alpha1.EphemeralVolumeSource{VolumeClaimTemplate: v1.PersistentVolumeClaimSpec{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		}}}}
+	job := newJob("example.a.0", "echo A", exec, task)
+	job.UID = "job-uid"
+	kubeClient := fake.NewSimpleClientset()
+
+	// creating the claims twice is fine.
+	for i := 0; i < 2; i++ {
+		if err := createEphemeralClaims(kubeClient, job, task); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+	}
+
+	claims, _ := kubeClient.CoreV1().PersistentVolumeClaims(exec.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if len(claims.Items) != 1 {
+		t.Fatalf("Expect 1 claim, but got %d", len(claims.Items))
+	}
+	claim := claims.Items[0]
+	if claim.Name != "example.a.0-work" {
+		t.Errorf("Expect claim example.a.0-work, but got %s", claim.Name)
+	}
+	if len(claim.OwnerReferences) != 1 || claim.OwnerReferences[0].UID != job.UID {
+		t.Errorf("Expect claim owned by the job, but got %v", claim.OwnerReferences)
+	}
+
+	// deleting the claims twice is fine.
+	for i := 0; i < 2; i++ {
+		if err := deleteEphemeralClaims(kubeClient, job, task); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+	}
+	claims, _ = kubeClient.CoreV1().PersistentVolumeClaims(exec.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if len(claims.Items) != 0 {
+		t.Errorf("Expect claims to be deleted, but got %d", len(claims.Items))
+	}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	geneclientset "kubegene.io/kubegene/pkg/client/clientset/versioned/typed/gene/v1alpha1"
+	geneinformers "kubegene.io/kubegene/pkg/client/informers/externalversions/gene/v1alpha1"
+	genelisters "kubegene.io/kubegene/pkg/client/listers/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/parser"
+	"kubegene.io/kubegene/pkg/util"
+)
+
+var workflowRunKind = genev1alpha1.SchemeGroupVersion.WithKind("WorkflowRun")
+
+// WorkflowTemplateLabel is the label of the executions expanded from a
+// workflow run, whose value is the name of the workflow template.
+const WorkflowTemplateLabel = "kubegene.io/workflow-template"
+
+// WorkflowRunParameters contains arguments for creation of a new WorkflowRunController.
+type WorkflowRunParameters struct {
+	EventRecorder            record.EventRecorder
+	ExecutionClient          geneclientset.ExecutionV1alpha1Interface
+	WorkflowRunInformer      geneinformers.WorkflowRunInformer
+	WorkflowTemplateInformer geneinformers.WorkflowTemplateInformer
+	ExecutionInformer        geneinformers.ExecutionInformer
+	// ToolInformer watches the tools of the cluster, which take precedence
+	// over Tools. Only Tools are used if it is nil.
+	ToolInformer geneinformers.ToolInformer
+	// Tools are the tools the jobs of the workflows refer to, keyed by name:version.
+	Tools map[string]parser.Tool
+	// Sharder tells the workflow runs this replica owns when several replicas
+	// share them. This replica owns every workflow run if it is nil.
+	Sharder Sharder
+}
+
+// WorkflowRunController expands the workflow runs into executions with the
+// workflows of their templates, and records the phases of the executions in
+// the status of the workflow runs.
+type WorkflowRunController struct {
+	eventRecorder record.EventRecorder
+	client        geneclientset.ExecutionV1alpha1Interface
+	tools         map[string]parser.Tool
+
+	runLister      genelisters.WorkflowRunLister
+	runSynced      cache.InformerSynced
+	templateLister genelisters.WorkflowTemplateLister
+	templateSynced cache.InformerSynced
+	execLister     genelisters.ExecutionLister
+	execSynced     cache.InformerSynced
+	toolLister     genelisters.ToolLister
+	// synced are the informers to sync before syncing the workflow runs.
+	synced []cache.InformerSynced
+
+	queue   workqueue.RateLimitingInterface
+	sharder Sharder
+}
+
+func NewWorkflowRunController(p *WorkflowRunParameters) *WorkflowRunController {
+	controller := &WorkflowRunController{
+		eventRecorder:  p.EventRecorder,
+		client:         p.ExecutionClient,
+		tools:          p.Tools,
+		runLister:      p.WorkflowRunInformer.Lister(),
+		runSynced:      p.WorkflowRunInformer.Informer().HasSynced,
+		templateLister: p.WorkflowTemplateInformer.Lister(),
+		templateSynced: p.WorkflowTemplateInformer.Informer().HasSynced,
+		execLister:     p.ExecutionInformer.Lister(),
+		execSynced:     p.ExecutionInformer.Informer().HasSynced,
+		queue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "workflow-run"),
+		sharder:        p.Sharder,
+	}
+	controller.synced = []cache.InformerSynced{controller.runSynced, controller.templateSynced, controller.execSynced}
+
+	p.WorkflowRunInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueue,
+			UpdateFunc: func(old, cur interface{}) { controller.enqueue(cur) },
+		},
+	)
+	// the workflow runs not expanded yet are synced again when their
+	// template, or the template of one of their sub workflows, is created
+	// or fixed.
+	p.WorkflowTemplateInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueueTemplateRuns,
+			UpdateFunc: func(old, cur interface{}) { controller.enqueueTemplateRuns(cur) },
+		},
+	)
+	if p.ToolInformer != nil {
+		controller.toolLister = p.ToolInformer.Lister()
+		controller.synced = append(controller.synced, p.ToolInformer.Informer().HasSynced)
+		// the workflow runs not expanded yet are synced again when a tool
+		// they may refer to is created or changed.
+		p.ToolInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    controller.enqueuePendingRuns,
+				UpdateFunc: func(old, cur interface{}) { controller.enqueuePendingRuns(cur) },
+			},
+		)
+	}
+	// the workflow run of an execution is synced when the phase of the execution changes.
+	p.ExecutionInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueueExecutionOwner,
+			UpdateFunc: func(old, cur interface{}) { controller.enqueueExecutionOwner(cur) },
+			DeleteFunc: controller.enqueueExecutionOwner,
+		},
+	)
+
+	return controller
+}
+
+// Run the main goroutine responsible for syncing the workflow runs.
+func (c *WorkflowRunController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting workflow run controller")
+	defer klog.Infof("Shutting down workflow run controller")
+
+	if !cache.WaitForCacheSync(stopCh, c.synced...) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// Rebalance syncs the workflow runs again after the replicas sharing them have changed.
+func (c *WorkflowRunController) Rebalance() {
+	runs, err := c.runLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("list workflow runs error: %v", err))
+		return
+	}
+	for _, run := range runs {
+		c.enqueue(run)
+	}
+}
+
+func (c *WorkflowRunController) worker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *WorkflowRunController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.syncWorkflowRun(key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing workflow run %v: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *WorkflowRunController) enqueue(obj interface{}) {
+	c.queue.Add(util.KeyOf(obj))
+}
+
+func (c *WorkflowRunController) enqueueTemplateRuns(obj interface{}) {
+	template, ok := obj.(*genev1alpha1.WorkflowTemplate)
+	if !ok {
+		return
+	}
+	runs, err := c.runLister.WorkflowRuns(template.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("list workflow runs error: %v", err))
+		return
+	}
+	for _, run := range runs {
+		if len(run.Status.Execution) == 0 {
+			c.enqueue(run)
+		}
+	}
+}
+
+func (c *WorkflowRunController) enqueuePendingRuns(obj interface{}) {
+	runs, err := c.runLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("list workflow runs error: %v", err))
+		return
+	}
+	for _, run := range runs {
+		if len(run.Status.Execution) == 0 {
+			c.enqueue(run)
+		}
+	}
+}
+
+func (c *WorkflowRunController) enqueueExecutionOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	exec, ok := obj.(*genev1alpha1.Execution)
+	if !ok {
+		return
+	}
+	if ref := metav1.GetControllerOf(exec); ref != nil && ref.Kind == workflowRunKind.Kind {
+		c.queue.Add(exec.Namespace + "/" + ref.Name)
+	}
+}
+
+// syncWorkflowRun expands the workflow run into its execution if it has not
+// been yet, and records the phase of the execution in its status.
+func (c *WorkflowRunController) syncWorkflowRun(key string) error {
+	if c.sharder != nil && !c.sharder.Owns(key) {
+		return nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	sharedRun, err := c.runLister.WorkflowRuns(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		// the execution is deleted along with its owner.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	run := sharedRun.DeepCopy()
+
+	// a workflow run is expanded once, the changes of its template
+	// afterwards do not affect it.
+	if len(run.Status.Execution) == 0 {
+		if err := c.expand(run); err != nil {
+			return err
+		}
+	}
+
+	if len(run.Status.Execution) != 0 {
+		exec, err := c.execLister.Executions(run.Namespace).Get(run.Status.Execution)
+		switch {
+		case errors.IsNotFound(err):
+			// not in the cache yet, or deleted.
+		case err != nil:
+			return err
+		case !metav1.IsControlledBy(exec, run):
+			run.Status.Phase = genev1alpha1.VertexError
+			run.Status.Message = fmt.Sprintf("execution %s already exists and is not controlled by the workflow run", exec.Name)
+		default:
+			run.Status.Phase = exec.Status.Phase
+		}
+	}
+
+	return c.updateStatus(run, sharedRun)
+}
+
+// expand creates the execution of the workflow run. The workflow runs whose
+// template is missing or invalid are not retried until the template changes.
+func (c *WorkflowRunController) expand(run *genev1alpha1.WorkflowRun) error {
+	template, err := c.templateLister.WorkflowTemplates(run.Namespace).Get(run.Spec.WorkflowTemplateRef)
+	if errors.IsNotFound(err) {
+		run.Status.Message = fmt.Sprintf("workflow template %s not found", run.Spec.WorkflowTemplateRef)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tools, err := c.resolveTools()
+	if err != nil {
+		return err
+	}
+
+	exec, err := newWorkflowRunExecution(run, template, tools, c.workflowLoader(run.Namespace))
+	if err != nil {
+		if run.Status.Message != err.Error() {
+			c.eventRecorder.Eventf(run, apiv1.EventTypeWarning, "InvalidWorkflow", "expand workflow template %s error: %v", template.Name, err)
+		}
+		run.Status.Phase = genev1alpha1.VertexError
+		run.Status.Message = err.Error()
+		return nil
+	}
+
+	_, err = c.client.Executions(exec.Namespace).Create(context.TODO(), exec, metav1.CreateOptions{})
+	switch {
+	case errors.IsAlreadyExists(err):
+		// created by a sync whose status update failed.
+	case err != nil:
+		c.eventRecorder.Eventf(run, apiv1.EventTypeWarning, "FailedCreate", "create execution %s error: %v", exec.Name, err)
+		return fmt.Errorf("create execution %s error: %v", util.KeyOf(exec), err)
+	default:
+		c.eventRecorder.Eventf(run, apiv1.EventTypeNormal, "SuccessfulCreate", "created execution %s", exec.Name)
+	}
+	run.Status.Phase = ""
+	run.Status.Execution = exec.Name
+	run.Status.Message = ""
+	return nil
+}
+
+// resolveTools returns the tools of the cluster and of the tool repository,
+// the tools of the cluster take precedence.
+func (c *WorkflowRunController) resolveTools() (map[string]parser.Tool, error) {
+	if c.toolLister == nil {
+		return c.tools, nil
+	}
+	clusterTools, err := c.toolLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	tools := make(map[string]parser.Tool, len(c.tools)+len(clusterTools))
+	for key, tool := range c.tools {
+		tools[key] = tool
+	}
+	for key, tool := range parser.TransClusterTools2Map(clusterTools) {
+		tools[key] = tool
+	}
+	return tools, nil
+}
+
+// workflowLoader loads the sub workflows from the workflow templates in the
+// namespace, there are no workflow files in the cluster.
+func (c *WorkflowRunController) workflowLoader(namespace string) parser.WorkflowLoader {
+	return func(sub *parser.SubWorkflow) ([]byte, error) {
+		if len(sub.Template) == 0 {
+			return nil, fmt.Errorf("workflow file %s can not be read in the cluster, use a workflow template instead", sub.Path)
+		}
+		template, err := c.templateLister.WorkflowTemplates(namespace).Get(sub.Template)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(template.Spec.Workflow), nil
+	}
+}
+
+func (c *WorkflowRunController) updateStatus(run, sharedRun *genev1alpha1.WorkflowRun) error {
+	if equality.Semantic.DeepEqual(run.Status, sharedRun.Status) {
+		return nil
+	}
+	_, err := c.client.WorkflowRuns(run.Namespace).UpdateStatus(context.TODO(), run, metav1.UpdateOptions{})
+	return err
+}
+
+// newWorkflowRunExecution returns the execution of the workflow of the
+// template instantiated with the inputs of the workflow run. The execution
+// has the name, the namespace and the labels of the workflow run.
+func newWorkflowRunExecution(run *genev1alpha1.WorkflowRun, template *genev1alpha1.WorkflowTemplate,
+	tools map[string]parser.Tool, load parser.WorkflowLoader) (*genev1alpha1.Execution, error) {
+	inputs := make(map[string]interface{}, len(run.Spec.Inputs)+2)
+	for key, value := range run.Spec.Inputs {
+		var input interface{}
+		if err := json.Unmarshal(value.Raw, &input); err != nil {
+			return nil, fmt.Errorf("invalid value of the input %s: %v", key, err)
+		}
+		inputs[key] = input
+	}
+	inputs["namespace"] = run.Namespace
+	inputs["executionName"] = run.Name
+
+	exec, err := parser.ExpandWorkflow([]byte(template.Spec.Workflow), inputs, tools, load)
+	if err != nil {
+		return nil, err
+	}
+
+	execLabels := make(map[string]string, len(run.Labels)+1)
+	for key, value := range run.Labels {
+		execLabels[key] = value
+	}
+	execLabels[WorkflowTemplateLabel] = template.Name
+
+	exec.Name = run.Name
+	exec.Namespace = run.Namespace
+	exec.Labels = execLabels
+	exec.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(run, workflowRunKind)}
+	return exec, nil
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/client/clientset/versioned/fake"
+	execinformers "kubegene.io/kubegene/pkg/client/informers/externalversions"
+	"kubegene.io/kubegene/pkg/parser"
+)
+
+var testWorkflow = `
+version: genecontainer_0_1
+inputs:
+  sample:
+    default: sample1
+    type: string
+workflow:
+  job-bwa:
+    tool: bwa:0.7.17
+    resources:
+      memory: 1G
+      cpu: 1c
+    commands:
+    - bwa mem /data/${sample}.fastq > /data/${sample}.sam
+`
+
+var testTools = map[string]parser.Tool{
+	"bwa:0.7.17": {Name: "bwa", Version: "0.7.17", Image: "bwa:0.7.17", Type: "basic"},
+}
+
+func newTestWorkflowRun(rawInputs map[string]string) *genev1alpha1.WorkflowRun {
+	var inputs map[string]apiextensionsv1.JSON
+	if rawInputs != nil {
+		inputs = make(map[string]apiextensionsv1.JSON, len(rawInputs))
+		for key, value := range rawInputs {
+			inputs[key] = apiextensionsv1.JSON{Raw: []byte(value)}
+		}
+	}
+	return &genev1alpha1.WorkflowRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "germline-1",
+			Namespace: "default",
+			UID:       "run-uid",
+			Labels:    map[string]string{"pipeline": "germline"},
+		},
+		Spec: genev1alpha1.WorkflowRunSpec{
+			WorkflowTemplateRef: "germline",
+			Inputs:              inputs,
+		},
+	}
+}
+
+func newTestWorkflowRunController(run *genev1alpha1.WorkflowRun, template *genev1alpha1.WorkflowTemplate,
+	tools []*genev1alpha1.Tool, executions ...*genev1alpha1.Execution) (*WorkflowRunController, *fake.Clientset) {
+	objects := []runtime.Object{run}
+	if template != nil {
+		objects = append(objects, template)
+	}
+	for _, tool := range tools {
+		objects = append(objects, tool)
+	}
+	for _, exec := range executions {
+		objects = append(objects, exec)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	factory := execinformers.NewSharedInformerFactory(client, 0)
+	runInformer := factory.Execution().V1alpha1().WorkflowRuns()
+	templateInformer := factory.Execution().V1alpha1().WorkflowTemplates()
+	execInformer := factory.Execution().V1alpha1().Executions()
+	toolInformer := factory.Execution().V1alpha1().Tools()
+	runInformer.Informer().GetIndexer().Add(run)
+	for _, tool := range tools {
+		toolInformer.Informer().GetIndexer().Add(tool)
+	}
+	if template != nil {
+		templateInformer.Informer().GetIndexer().Add(template)
+	}
+	for _, exec := range executions {
+		execInformer.Informer().GetIndexer().Add(exec)
+	}
+
+	c := NewWorkflowRunController(&WorkflowRunParameters{
+		EventRecorder:            record.NewFakeRecorder(10),
+		ExecutionClient:          client.ExecutionV1alpha1(),
+		WorkflowRunInformer:      runInformer,
+		WorkflowTemplateInformer: templateInformer,
+		ExecutionInformer:        execInformer,
+		ToolInformer:             toolInformer,
+		Tools:                    testTools,
+	})
+	client.ClearActions()
+	return c, client
+}
+
+func TestSyncWorkflowRun(t *testing.T) {
+	template := &genev1alpha1.WorkflowTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "germline", Namespace: "default"},
+		Spec:       genev1alpha1.WorkflowTemplateSpec{Workflow: testWorkflow},
+	}
+	invalidTemplate := template.DeepCopy()
+	invalidTemplate.Spec.Workflow = "version: genecontainer_0_1"
+
+	expanded := newTestWorkflowRun(nil)
+	expanded.Status.Execution = expanded.Name
+	runningExec := &genev1alpha1.Execution{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            expanded.Name,
+			Namespace:       expanded.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(expanded, workflowRunKind)},
+		},
+		Status: genev1alpha1.ExecutionStatus{Phase: genev1alpha1.VertexRunning},
+	}
+	foreignExec := runningExec.DeepCopy()
+	foreignExec.OwnerReferences = nil
+
+	newTool := func(name, version, image string) *genev1alpha1.Tool {
+		return &genev1alpha1.Tool{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-" + version},
+			Spec:       genev1alpha1.ToolSpec{Name: name, Version: version, Image: image, Type: "basic"},
+		}
+	}
+	fileTemplate := template.DeepCopy()
+	fileTemplate.Spec.Workflow = `
+version: genecontainer_0_1
+workflow:
+  germline:
+    sub_workflow:
+      path: germline.yaml
+`
+	clusterTemplate := template.DeepCopy()
+	clusterTemplate.Spec.Workflow = strings.Replace(testWorkflow, "bwa:0.7.17", "bwa:0.7.18", 1)
+
+	testCases := []struct {
+		Name          string
+		Run           *genev1alpha1.WorkflowRun
+		Template      *genev1alpha1.WorkflowTemplate
+		Tools         []*genev1alpha1.Tool
+		Executions    []*genev1alpha1.Execution
+		ExpectActions []string
+		ExpectCommand string
+		ExpectImage   string
+		ExpectStatus  genev1alpha1.WorkflowRunStatus
+	}{
+		{
+			Name:          "defaults",
+			Run:           newTestWorkflowRun(nil),
+			Template:      template,
+			ExpectActions: []string{"create executions", "update workflowruns"},
+			ExpectCommand: "bwa mem /data/sample1.fastq > /data/sample1.sam",
+			ExpectImage:   "bwa:0.7.17",
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Execution: "germline-1"},
+		},
+		{
+			Name:          "inputs",
+			Run:           newTestWorkflowRun(map[string]string{"sample": `"sample2"`}),
+			Template:      template,
+			ExpectActions: []string{"create executions", "update workflowruns"},
+			ExpectCommand: "bwa mem /data/sample2.fastq > /data/sample2.sam",
+			ExpectImage:   "bwa:0.7.17",
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Execution: "germline-1"},
+		},
+		{
+			Name:          "cluster tool takes precedence",
+			Run:           newTestWorkflowRun(nil),
+			Template:      template,
+			Tools:         []*genev1alpha1.Tool{newTool("bwa", "0.7.17", "registry/bwa:0.7.17")},
+			ExpectActions: []string{"create executions", "update workflowruns"},
+			ExpectCommand: "bwa mem /data/sample1.fastq > /data/sample1.sam",
+			ExpectImage:   "registry/bwa:0.7.17",
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Execution: "germline-1"},
+		},
+		{
+			Name:          "cluster tool only",
+			Run:           newTestWorkflowRun(nil),
+			Template:      clusterTemplate,
+			Tools:         []*genev1alpha1.Tool{newTool("bwa", "0.7.18", "registry/bwa:0.7.18")},
+			ExpectActions: []string{"create executions", "update workflowruns"},
+			ExpectCommand: "bwa mem /data/sample1.fastq > /data/sample1.sam",
+			ExpectImage:   "registry/bwa:0.7.18",
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Execution: "germline-1"},
+		},
+		{
+			Name:          "unknown tool",
+			Run:           newTestWorkflowRun(nil),
+			Template:      clusterTemplate,
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:   genev1alpha1.VertexError,
+				Message: "workflows.job-bwa.tool [bwa:0.7.18] does not exist",
+			},
+		},
+		{
+			Name:          "template not found",
+			Run:           newTestWorkflowRun(nil),
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Message: "workflow template germline not found"},
+		},
+		{
+			Name:          "invalid input",
+			Run:           newTestWorkflowRun(map[string]string{"sample": "1"}),
+			Template:      template,
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:   genev1alpha1.VertexError,
+				Message: "type error: inputs.sample.type is string, but the given input value is 1",
+			},
+		},
+		{
+			Name:          "malformed input",
+			Run:           newTestWorkflowRun(map[string]string{"sample": "sample2"}),
+			Template:      template,
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:   genev1alpha1.VertexError,
+				Message: "invalid value of the input sample: invalid character 's' looking for beginning of value",
+			},
+		},
+		{
+			Name:          "invalid workflow",
+			Run:           newTestWorkflowRun(nil),
+			Template:      invalidTemplate,
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:   genev1alpha1.VertexError,
+				Message: "invalid workflow: No job defined in workflows",
+			},
+		},
+		{
+			Name:          "sub workflow file",
+			Run:           newTestWorkflowRun(nil),
+			Template:      fileTemplate,
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:   genev1alpha1.VertexError,
+				Message: "workflow.germline.sub_workflow: load workflow error: workflow file germline.yaml can not be read in the cluster, use a workflow template instead",
+			},
+		},
+		{
+			Name:          "running execution",
+			Run:           expanded,
+			Template:      template,
+			Executions:    []*genev1alpha1.Execution{runningExec},
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus:  genev1alpha1.WorkflowRunStatus{Phase: genev1alpha1.VertexRunning, Execution: "germline-1"},
+		},
+		{
+			Name:          "execution not controlled by the workflow run",
+			Run:           expanded,
+			Template:      template,
+			Executions:    []*genev1alpha1.Execution{foreignExec},
+			ExpectActions: []string{"update workflowruns"},
+			ExpectStatus: genev1alpha1.WorkflowRunStatus{
+				Phase:     genev1alpha1.VertexError,
+				Execution: "germline-1",
+				Message:   "execution germline-1 already exists and is not controlled by the workflow run",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		c, client := newTestWorkflowRunController(testCase.Run, testCase.Template, testCase.Tools, testCase.Executions...)
+		if err := c.syncWorkflowRun("default/germline-1"); err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+			continue
+		}
+
+		actions := clientActions(client)
+		if !reflect.DeepEqual(actions, testCase.ExpectActions) {
+			t.Errorf("%s: Expect actions %v, but got %v", testCase.Name, testCase.ExpectActions, actions)
+		}
+
+		run, err := client.ExecutionV1alpha1().WorkflowRuns("default").Get(context.TODO(), "germline-1", metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("%s: get workflow run error: %v", testCase.Name, err)
+			continue
+		}
+		if !reflect.DeepEqual(run.Status, testCase.ExpectStatus) {
+			t.Errorf("%s: Expect status %+v, but got %+v", testCase.Name, testCase.ExpectStatus, run.Status)
+		}
+
+		if len(testCase.ExpectCommand) == 0 {
+			continue
+		}
+		exec, err := client.ExecutionV1alpha1().Executions("default").Get(context.TODO(), "germline-1", metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("%s: get execution error: %v", testCase.Name, err)
+			continue
+		}
+		if !metav1.IsControlledBy(exec, testCase.Run) {
+			t.Errorf("%s: Expect the execution controlled by the workflow run", testCase.Name)
+		}
+		if exec.Labels[WorkflowTemplateLabel] != "germline" || exec.Labels["pipeline"] != "germline" {
+			t.Errorf("%s: Expect the labels of the workflow run and its template, but got %v", testCase.Name, exec.Labels)
+		}
+		if len(exec.Spec.Tasks) != 1 || len(exec.Spec.Tasks[0].CommandSet) != 1 ||
+			exec.Spec.Tasks[0].CommandSet[0] != testCase.ExpectCommand || exec.Spec.Tasks[0].Image != testCase.ExpectImage {
+			t.Errorf("%s: Expect command %q of image %s, but got %+v", testCase.Name, testCase.ExpectCommand, testCase.ExpectImage, exec.Spec.Tasks)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are true if the day of month or the day of week is *,
+	// a day matches either of them if neither is *.
+	domAny, dowAny bool
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron schedule of the five fields minute, hour, day of month,
+// month and day of week, or one of the descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly. A field is a list of
+// values, ranges and steps separated by commas, e.g. "1,15-20,*/10".
+func Parse(spec string) (*Schedule, error) {
+	if expanded, ok := descriptors[strings.TrimSpace(spec)]; ok {
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q, found %d", spec, len(fields))
+	}
+
+	schedule := &Schedule{
+		domAny: fields[2] == "*" || fields[2] == "?",
+		dowAny: fields[4] == "*" || fields[4] == "?",
+	}
+	var err error
+	if schedule.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	// 7 is sunday as well.
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	return schedule, nil
+}
+
+// parseField returns the bits of the values of a field.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(expr, "/")
+		if len(rangeAndStep) > 2 {
+			return 0, fmt.Errorf("invalid expression %q", expr)
+		}
+
+		var start, end uint
+		switch lowAndHigh := strings.Split(rangeAndStep[0], "-"); {
+		case rangeAndStep[0] == "*" || rangeAndStep[0] == "?":
+			start, end = b.min, b.max
+		case len(lowAndHigh) == 1:
+			value, err := parseValue(lowAndHigh[0], b)
+			if err != nil {
+				return 0, err
+			}
+			start, end = value, value
+			// a single value with a step stands for the range up to the max.
+			if len(rangeAndStep) == 2 {
+				end = b.max
+			}
+		case len(lowAndHigh) == 2:
+			var err error
+			if start, err = parseValue(lowAndHigh[0], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("invalid range %q", rangeAndStep[0])
+		}
+		if start > end {
+			return 0, fmt.Errorf("beginning of range %q is beyond its end", rangeAndStep[0])
+		}
+
+		step := uint64(1)
+		if len(rangeAndStep) == 2 {
+			var err error
+			if step, err = strconv.ParseUint(rangeAndStep[1], 10, 8); err != nil || step == 0 {
+				return 0, fmt.Errorf("invalid step %q", rangeAndStep[1])
+			}
+		}
+		for value := uint64(start); value <= uint64(end); value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (uint, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if uint(n) < b.min || uint(n) > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, b.min, b.max)
+	}
+	return uint(n), nil
+}
+
+// Next returns the first time of the schedule after t, the zero time if
+// there is none within five years, e.g. for 30 February.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Spec      string
+		ExpectErr bool
+	}{
+		{Name: "every minute", Spec: "* * * * *"},
+		{Name: "lists ranges and steps", Spec: "0,30 2-4 */2 1-12/3 mon-fri"},
+		{Name: "descriptor", Spec: "@daily"},
+		{Name: "sunday as 7", Spec: "0 0 * * 7"},
+		{Name: "too few fields", Spec: "* * * *", ExpectErr: true},
+		{Name: "minute out of range", Spec: "60 * * * *", ExpectErr: true},
+		{Name: "day of month out of range", Spec: "0 0 0 * *", ExpectErr: true},
+		{Name: "reversed range", Spec: "0 5-2 * * *", ExpectErr: true},
+		{Name: "zero step", Spec: "*/0 * * * *", ExpectErr: true},
+		{Name: "unknown name", Spec: "0 0 * foo *", ExpectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		_, err := Parse(testCase.Spec)
+		if testCase.ExpectErr && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if !testCase.ExpectErr && err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a monday.
+	now := time.Date(2026, time.March, 2, 10, 15, 30, 0, time.UTC)
+
+	testCases := []struct {
+		Name   string
+		Spec   string
+		Expect time.Time
+	}{
+		{
+			Name:   "every minute",
+			Spec:   "* * * * *",
+			Expect: time.Date(2026, time.March, 2, 10, 16, 0, 0, time.UTC),
+		},
+		{
+			Name:   "daily",
+			Spec:   "@daily",
+			Expect: time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:   "later today",
+			Spec:   "30 22 * * *",
+			Expect: time.Date(2026, time.March, 2, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			Name:   "every 20 minutes",
+			Spec:   "*/20 * * * *",
+			Expect: time.Date(2026, time.March, 2, 10, 20, 0, 0, time.UTC),
+		},
+		{
+			Name:   "weekly on sunday as 7",
+			Spec:   "0 3 * * 7",
+			Expect: time.Date(2026, time.March, 8, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:   "day of month or day of week",
+			Spec:   "0 0 10 * fri",
+			Expect: time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:   "next year",
+			Spec:   "0 0 1 jan *",
+			Expect: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:   "leap day",
+			Spec:   "0 0 29 2 *",
+			Expect: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name: "never",
+			Spec: "0 0 30 2 *",
+		},
+	}
+
+	for _, testCase := range testCases {
+		schedule, err := Parse(testCase.Spec)
+		if err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+			continue
+		}
+		if next := schedule.Next(now); !next.Equal(testCase.Expect) {
+			t.Errorf("%s: Expect %v, but got %v", testCase.Name, testCase.Expect, next)
+		}
+	}
+}
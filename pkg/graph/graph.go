@@ -22,20 +22,20 @@ import (
 	"strings"
 	"sync"
 
-	batch "k8s.io/api/batch/v1"
-
 	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
 )
 
 // JobInfo stores job information for running
 type JobInfo struct {
 	Finished   bool
-	Job        *batch.Job
+	Skipped    bool
+	Job        *workload.Workload
 	TaskType   genev1alpha1.TaskType
 	DynamicJob *genev1alpha1.Task
 }
 
-func NewJobInfo(job *batch.Job, finished bool, taskType genev1alpha1.TaskType, dynamicJob *genev1alpha1.Task) *JobInfo {
+func NewJobInfo(job *workload.Workload, finished bool, taskType genev1alpha1.TaskType, dynamicJob *genev1alpha1.Task) *JobInfo {
 	return &JobInfo{
 		Job:        job,
 		Finished:   finished,
@@ -55,6 +55,7 @@ type Vertex struct {
 type Graph struct {
 	sync.RWMutex
 	NumOfSuccess  int
+	NumOfSkipped  int
 	VertexCount   int
 	Size          int
 	VertexArray   []*Vertex
@@ -328,6 +329,26 @@ func (g *Graph) GetNumOfSuccess() int {
 	return g.NumOfSuccess
 }
 
+func (g *Graph) PlusNumOfSkipped() {
+	g.Lock()
+	defer g.Unlock()
+	g.NumOfSkipped++
+}
+
+func (g *Graph) GetNumOfSkipped() int {
+	g.Lock()
+	defer g.Unlock()
+	return g.NumOfSkipped
+}
+
+// IsCompleted returns true if every vertex of the graph has either
+// succeeded or been skipped.
+func (g *Graph) IsCompleted() bool {
+	g.Lock()
+	defer g.Unlock()
+	return g.NumOfSuccess+g.NumOfSkipped == g.VertexCount+g.DynamicJobCnt
+}
+
 func (g *Graph) AddDynamicJobCnt(cnt int) {
 	g.Lock()
 	defer g.Unlock()
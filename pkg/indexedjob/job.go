@@ -0,0 +1,324 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package indexedjob runs all the jobs of a task as one Indexed Job, each
+// pod of which runs the command of its completion index. The client library
+// predates the completion mode, so the Indexed Jobs are handled through the
+// dynamic client, and are presented to the controller as one workload per
+// index, named after the Indexed Job and the index.
+package indexedjob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+var (
+	// JobResource is the resource of the Indexed Jobs.
+	JobResource = batch.SchemeGroupVersion.WithResource("jobs")
+)
+
+const (
+	// Label marks the Indexed Jobs, the controller watches them with this label only.
+	Label = "kubegene.io/indexed-job"
+	// CompletionIndexAnnotation is set by the Job controller on the pods of an Indexed Job.
+	CompletionIndexAnnotation = "batch.kubernetes.io/job-completion-index"
+	// CommandsMountPath is the directory the commands are mounted in, one file per index.
+	CommandsMountPath = "/kubegene/commands"
+	// Command runs the command of the completion index of the pod.
+	Command = "sh " + CommandsMountPath + `/"$JOB_COMPLETION_INDEX"`
+	// MaxCommandsBytes is the size of the commands held by one configmap,
+	// well below the size limit of an object so that the metadata fits too.
+	MaxCommandsBytes = 512 * 1024
+
+	commandsVolumeName = "kubegene-commands"
+	indexedCompletion  = "Indexed"
+	separator          = "."
+)
+
+// IsIndexedJob returns true if the batch Job is an Indexed Job, whose
+// indexes are listed and watched instead of the batch Job.
+func IsIndexedJob(job metav1.Object) bool {
+	_, ok := job.GetLabels()[Label]
+	return ok
+}
+
+// IndexName returns the name of the workload standing for an index of the Indexed Job.
+func IndexName(name string, index int) string {
+	return name + separator + strconv.Itoa(index)
+}
+
+// ParseIndexName returns the name of the Indexed Job and the index the
+// workload of the name stands for.
+func ParseIndexName(indexName string) (string, int, error) {
+	pos := strings.LastIndex(indexName, separator)
+	if pos < 0 {
+		return "", 0, fmt.Errorf("%s is not the name of an index", indexName)
+	}
+	index, err := strconv.Atoi(indexName[pos+1:])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("%s is not the name of an index", indexName)
+	}
+	return indexName[:pos], index, nil
+}
+
+// CommandsName returns the name of the configmap holding a shard of the commands of the Indexed Job.
+func CommandsName(name string, shard int) string {
+	return name + "-commands-" + strconv.Itoa(shard)
+}
+
+// shardCommands splits the commands, keyed by index, into shards of at
+// most MaxCommandsBytes each, a command longer than that has a shard alone.
+func shardCommands(commands []string) []map[string]string {
+	var shards []map[string]string
+	size := 0
+	for index, command := range commands {
+		if len(shards) == 0 || size+len(command) > MaxCommandsBytes {
+			shards = append(shards, map[string]string{})
+			size = 0
+		}
+		shards[len(shards)-1][strconv.Itoa(index)] = command
+		size += len(command)
+	}
+	return shards
+}
+
+// CommandShards returns the number of configmaps holding the commands.
+func CommandShards(commands []string) int {
+	return len(shardCommands(commands))
+}
+
+// ValidateCommands checks that every command fits in a configmap.
+func ValidateCommands(commands []string) error {
+	for index, command := range commands {
+		if len(command) > MaxCommandsBytes {
+			return fmt.Errorf("command %d is %d bytes, longer than %d bytes", index, len(command), MaxCommandsBytes)
+		}
+	}
+	return nil
+}
+
+// MountCommands mounts the shards of the commands of the Indexed Job name in
+// the task container of the job. The shards are projected into one directory,
+// so that the pod of an index finds its command whichever shard holds it.
+func MountCommands(job *workload.Workload, name string, shards int) {
+	sources := make([]v1.VolumeProjection, 0, shards)
+	for shard := 0; shard < shards; shard++ {
+		sources = append(sources, v1.VolumeProjection{
+			ConfigMap: &v1.ConfigMapProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: CommandsName(name, shard)},
+			},
+		})
+	}
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: commandsVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{Sources: sources},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+		Name:      commandsVolumeName,
+		MountPath: CommandsMountPath,
+		ReadOnly:  true,
+	})
+}
+
+// NewCommands returns the configmaps holding the shards of the commands of
+// the Indexed Job, the command of an index is keyed by the index.
+func NewCommands(job *batch.Job, commands []string) []*v1.ConfigMap {
+	shards := shardCommands(commands)
+	configMaps := make([]*v1.ConfigMap, 0, len(shards))
+	for shard, data := range shards {
+		configMaps = append(configMaps, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            CommandsName(job.Name, shard),
+				Namespace:       job.Namespace,
+				Labels:          job.Labels,
+				OwnerReferences: job.OwnerReferences,
+			},
+			Data: data,
+		})
+	}
+	return configMaps
+}
+
+// NewJob returns the Indexed Job name running the pod template of the
+// workload of an index for all the indexes.
+func NewJob(job *workload.Workload, name string) *batch.Job {
+	indexed := workload.NewBatchJob(job)
+	indexed.Name = name
+	indexed.Labels = make(map[string]string, len(job.Labels)+1)
+	for key, value := range job.Labels {
+		indexed.Labels[key] = value
+	}
+	indexed.Labels[Label] = "true"
+	// the pods of the indexes are not restarted in place, so that a failed
+	// index is retried in a new pod with the same completion index.
+	indexed.Spec.Template.Spec.RestartPolicy = v1.RestartPolicyNever
+	return indexed
+}
+
+// ToUnstructured returns the Indexed Job as an unstructured object for the dynamic client.
+func ToUnstructured(job *batch.Job) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(job)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if err := unstructured.SetNestedField(obj.Object, indexedCompletion, "spec", "completionMode"); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// IndexWorkloads returns the workloads standing for all the indexes of the Indexed Job.
+func IndexWorkloads(obj runtime.Object) ([]*workload.Workload, error) {
+	job, completed, err := fromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	completions := 0
+	if job.Spec.Completions != nil {
+		completions = int(*job.Spec.Completions)
+	}
+	active := activeIndexes(job, completed)
+	jobs := make([]*workload.Workload, 0, completions)
+	for index := 0; index < completions; index++ {
+		jobs = append(jobs, indexWorkload(job, completed, active, index))
+	}
+	return jobs, nil
+}
+
+// IndexWorkload returns the workload standing for the index of the Indexed Job.
+func IndexWorkload(obj runtime.Object, index int) (*workload.Workload, error) {
+	job, completed, err := fromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	if job.Spec.Completions == nil || index >= int(*job.Spec.Completions) {
+		return nil, fmt.Errorf("job %s/%s has no index %d", job.Namespace, job.Name, index)
+	}
+	return indexWorkload(job, completed, activeIndexes(job, completed), index), nil
+}
+
+// activeIndexes returns the indexes which have an active pod. The status of
+// the Indexed Job only counts the active pods, and the Job controller starts
+// the pods of the lowest indexes not completed first, so they are taken to
+// be the active ones.
+func activeIndexes(job *batch.Job, completed map[int]bool) map[int]bool {
+	active := make(map[int]bool, job.Status.Active)
+	for index := 0; len(active) < int(job.Status.Active); index++ {
+		if job.Spec.Completions == nil || index >= int(*job.Spec.Completions) {
+			break
+		}
+		if !completed[index] {
+			active[index] = true
+		}
+	}
+	return active
+}
+
+// indexWorkload returns the workload standing for the index. The index has
+// completed once it is in the completed indexes, and has failed once the
+// Indexed Job has failed without completing it. Otherwise it is running if it
+// has an active pod, and pending until then. The indexes share the resource
+// version of the Indexed Job.
+func indexWorkload(job *batch.Job, completed, active map[int]bool, index int) *workload.Workload {
+	one := int32(1)
+	indexed := workload.FromBatchJob(job)
+	indexed.Name = IndexName(job.Name, index)
+	indexed.Spec.Completions = &one
+	indexed.Spec.Parallelism = &one
+	indexed.Status.CompletionTime = nil
+
+	switch {
+	case completed[index]:
+		indexed.Status = workload.Status{Phase: workload.Succeeded, StartTime: indexed.Status.StartTime}
+	case indexed.Status.Phase == workload.Failed:
+	case active[index]:
+		indexed.Status = workload.Status{Phase: workload.Running, StartTime: indexed.Status.StartTime}
+	default:
+		indexed.Status = workload.Status{Phase: workload.Pending}
+	}
+	return indexed
+}
+
+// fromUnstructured returns the Indexed Job of an unstructured object with its completed indexes.
+func fromUnstructured(obj runtime.Object) (*batch.Job, map[int]bool, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil, fmt.Errorf("object %T is not unstructured", obj)
+	}
+	job := &batch.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, job); err != nil {
+		return nil, nil, err
+	}
+	completedIndexes, _, err := unstructured.NestedString(u.Object, "status", "completedIndexes")
+	if err != nil {
+		return nil, nil, err
+	}
+	completed, err := ParseIndexes(completedIndexes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("job %s/%s: %v", job.Namespace, job.Name, err)
+	}
+	return job, completed, nil
+}
+
+// ParseIndexes parses the indexes in the text format of the Job status,
+// e.g. "1,3-5,7".
+func ParseIndexes(indexes string) (map[int]bool, error) {
+	parsed := make(map[int]bool)
+	if len(indexes) == 0 {
+		return parsed, nil
+	}
+	for _, interval := range strings.Split(indexes, ",") {
+		bounds := strings.SplitN(interval, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid indexes %q", indexes)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid indexes %q", indexes)
+			}
+		}
+		for index := first; index <= last; index++ {
+			parsed[index] = true
+		}
+	}
+	return parsed, nil
+}
+
+// SucceededPod returns the pod which has completed the index, or nil.
+func SucceededPod(pods []v1.Pod, index int) *v1.Pod {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Annotations[CompletionIndexAnnotation] == strconv.Itoa(index) && pod.Status.Phase == v1.PodSucceeded {
+			return pod
+		}
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexedjob
+
+import (
+	"strings"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func newIndexedJob(t *testing.T, completions, active int32, completedIndexes string, failed bool) *unstructured.Unstructured {
+	job := NewJob(&workload.Workload{
+		ObjectMeta: metav1.ObjectMeta{Name: "exec.a.0", Namespace: "default"},
+		Spec: workload.Spec{
+			Completions: &completions,
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers:    []v1.Container{{Name: "main", Image: "busybox"}},
+				RestartPolicy: v1.RestartPolicyOnFailure,
+			}},
+		},
+	}, "exec.a")
+	job.Status.Active = active
+	if failed {
+		job.Status.Conditions = []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue}}
+	}
+	obj, err := ToUnstructured(job)
+	if err != nil {
+		t.Fatalf("convert job error: %v", err)
+	}
+	if len(completedIndexes) != 0 {
+		unstructured.SetNestedField(obj.Object, completedIndexes, "status", "completedIndexes")
+	}
+	return obj
+}
+
+func TestParseIndexName(t *testing.T) {
+	testCases := []struct {
+		IndexName string
+		Name      string
+		Index     int
+		ExpectErr bool
+	}{
+		{
+			IndexName: IndexName("exec.a", 3),
+			Name:      "exec.a",
+			Index:     3,
+		},
+		{
+			IndexName: "exec",
+			ExpectErr: true,
+		},
+		{
+			IndexName: "exec.a.b",
+			ExpectErr: true,
+		},
+		{
+			IndexName: "exec.a.-1",
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		name, index, err := ParseIndexName(testCase.IndexName)
+		if testCase.ExpectErr {
+			if err == nil {
+				t.Errorf("%s: Expect error, but got nil", testCase.IndexName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.IndexName, err)
+			continue
+		}
+		if name != testCase.Name || index != testCase.Index {
+			t.Errorf("%s: Expect %s and %d, but got %s and %d", testCase.IndexName, testCase.Name, testCase.Index, name, index)
+		}
+	}
+}
+
+func TestParseIndexes(t *testing.T) {
+	testCases := []struct {
+		Indexes   string
+		Expect    []int
+		ExpectErr bool
+	}{
+		{
+			Indexes: "",
+		},
+		{
+			Indexes: "1,3-5,7",
+			Expect:  []int{1, 3, 4, 5, 7},
+		},
+		{
+			Indexes:   "5-3",
+			ExpectErr: true,
+		},
+		{
+			Indexes:   "1,a",
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		parsed, err := ParseIndexes(testCase.Indexes)
+		if testCase.ExpectErr {
+			if err == nil {
+				t.Errorf("%q: Expect error, but got nil", testCase.Indexes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: Expect no error, but got error %v", testCase.Indexes, err)
+			continue
+		}
+		if len(parsed) != len(testCase.Expect) {
+			t.Errorf("%q: Expect %v, but got %v", testCase.Indexes, testCase.Expect, parsed)
+		}
+		for _, index := range testCase.Expect {
+			if !parsed[index] {
+				t.Errorf("%q: Expect index %d, but it is missing", testCase.Indexes, index)
+			}
+		}
+	}
+}
+
+func TestIndexWorkloads(t *testing.T) {
+	running, pending, succeeded, failed := workload.Running, workload.Pending, workload.Succeeded, workload.Failed
+	testCases := []struct {
+		Name   string
+		Object *unstructured.Unstructured
+		Phases []workload.Phase
+	}{
+		{
+			Name:   "running",
+			Object: newIndexedJob(t, 3, 3, "", false),
+			Phases: []workload.Phase{running, running, running},
+		},
+		{
+			Name:   "not started",
+			Object: newIndexedJob(t, 3, 0, "", false),
+			Phases: []workload.Phase{pending, pending, pending},
+		},
+		{
+			Name:   "beyond the parallelism",
+			Object: newIndexedJob(t, 4, 2, "", false),
+			Phases: []workload.Phase{running, running, pending, pending},
+		},
+		{
+			Name:   "some indexes completed",
+			Object: newIndexedJob(t, 4, 1, "0,2", false),
+			Phases: []workload.Phase{succeeded, running, succeeded, pending},
+		},
+		{
+			Name:   "failed",
+			Object: newIndexedJob(t, 3, 0, "1", true),
+			Phases: []workload.Phase{failed, succeeded, failed},
+		},
+	}
+
+	for _, testCase := range testCases {
+		jobs, err := IndexWorkloads(testCase.Object)
+		if err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+			continue
+		}
+		if len(jobs) != len(testCase.Phases) {
+			t.Errorf("%s: Expect %d jobs, but got %d", testCase.Name, len(testCase.Phases), len(jobs))
+			continue
+		}
+		for index, job := range jobs {
+			if job.Name != IndexName("exec.a", index) {
+				t.Errorf("%s: Expect job name %s, but got %s", testCase.Name, IndexName("exec.a", index), job.Name)
+			}
+			if job.Status.Phase != testCase.Phases[index] {
+				t.Errorf("%s: Expect index %d to be %s, but got %s", testCase.Name, index, testCase.Phases[index], job.Status.Phase)
+			}
+		}
+	}
+
+	if _, err := IndexWorkload(newIndexedJob(t, 3, 0, "", false), 3); err == nil {
+		t.Errorf("Expect error for an index out of the completions, but got nil")
+	}
+}
+
+func TestNewJob(t *testing.T) {
+	obj := newIndexedJob(t, 2, 0, "", false)
+	if obj.GetName() != "exec.a" {
+		t.Errorf("Expect name exec.a, but got %s", obj.GetName())
+	}
+	if obj.GetLabels()[Label] != "true" {
+		t.Errorf("Expect label %s, but got labels %v", Label, obj.GetLabels())
+	}
+	mode, _, _ := unstructured.NestedString(obj.Object, "spec", "completionMode")
+	if mode != indexedCompletion {
+		t.Errorf("Expect completion mode %s, but got %s", indexedCompletion, mode)
+	}
+	policy, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "restartPolicy")
+	if policy != string(v1.RestartPolicyNever) {
+		t.Errorf("Expect restart policy Never, but got %s", policy)
+	}
+}
+
+func TestNewCommands(t *testing.T) {
+	long := strings.Repeat("A", MaxCommandsBytes*2/5)
+	testCases := []struct {
+		Name     string
+		Commands []string
+		Shards   [][]string
+	}{
+		{
+			Name:     "one shard",
+			Commands: []string{"echo A", "echo B"},
+			Shards:   [][]string{{"0", "1"}},
+		},
+		{
+			Name:     "shards of long commands",
+			Commands: []string{long, long, long, long, long},
+			Shards:   [][]string{{"0", "1"}, {"2", "3"}, {"4"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		indexed := NewJob(&workload.Workload{
+			ObjectMeta: metav1.ObjectMeta{Name: "exec.a.0", Namespace: "default"},
+		}, "exec.a")
+		configMaps := NewCommands(indexed, testCase.Commands)
+		if len(configMaps) != len(testCase.Shards) || CommandShards(testCase.Commands) != len(testCase.Shards) {
+			t.Errorf("%s: Expect %d shards, but got %d", testCase.Name, len(testCase.Shards), len(configMaps))
+			continue
+		}
+		for shard, keys := range testCase.Shards {
+			configMap := configMaps[shard]
+			if configMap.Name != CommandsName("exec.a", shard) || len(configMap.Data) != len(keys) {
+				t.Errorf("%s: Expect shard %d to hold %v, but got %s holding %d commands", testCase.Name, shard, keys,
+					configMap.Name, len(configMap.Data))
+			}
+			for _, key := range keys {
+				if _, ok := configMap.Data[key]; !ok {
+					t.Errorf("%s: Expect shard %d to hold command %s", testCase.Name, shard, key)
+				}
+			}
+		}
+
+		job := &workload.Workload{}
+		job.Spec.Template.Spec.Containers = []v1.Container{{Name: "main"}}
+		MountCommands(job, "exec.a", len(configMaps))
+		if sources := job.Spec.Template.Spec.Volumes[0].Projected.Sources; len(sources) != len(configMaps) {
+			t.Errorf("%s: Expect %d projected configmaps, but got %d", testCase.Name, len(configMaps), len(sources))
+		}
+	}
+
+	if err := ValidateCommands([]string{"echo A", strings.Repeat("A", MaxCommandsBytes+1)}); err == nil {
+		t.Errorf("Expect error for a command longer than a configmap, but got nil")
+	}
+}
+
+func TestSucceededPod(t *testing.T) {
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "p0", Annotations: map[string]string{CompletionIndexAnnotation: "0"}},
+			Status:     v1.PodStatus{Phase: v1.PodFailed},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "p1", Annotations: map[string]string{CompletionIndexAnnotation: "0"}},
+			Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+		},
+	}
+	if pod := SucceededPod(pods, 0); pod == nil || pod.Name != "p1" {
+		t.Errorf("Expect pod p1 to have completed index 0, but got %v", pod)
+	}
+	if pod := SucceededPod(pods, 1); pod != nil {
+		t.Errorf("Expect no pod to have completed index 1, but got %s", pod.Name)
+	}
+}
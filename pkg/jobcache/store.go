@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcache
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Label marks the configmaps holding cache entries.
+	Label = "kubegene.io/cache"
+
+	namePrefix = "kubegene-cache-"
+	// the length of the key used in the configmap name.
+	nameKeyLength = 40
+
+	keyData       = "key"
+	executionData = "execution"
+	jobData       = "job"
+	createdAtData = "createdAt"
+	resultData    = "result"
+)
+
+// Entry records a job which has succeeded.
+type Entry struct {
+	// Key is the cache key of the job.
+	Key string
+	// Execution is the name of the execution the job belonged to.
+	Execution string
+	// Job is the name of the job.
+	Job string
+	// CreatedAt is the time the entry was recorded.
+	CreatedAt time.Time
+	// Result is the result of the job, read by the tasks depending on it.
+	// Nil if it was not recorded.
+	Result *string
+}
+
+// Store records the jobs which have succeeded by their cache key.
+type Store interface {
+	// Get returns the entry of the key in the namespace, or nil if there is none.
+	Get(namespace, key string) (*Entry, error)
+	// Put records the entry in the namespace.
+	Put(namespace string, entry *Entry) error
+}
+
+// configMapStore keeps every entry in a configmap in the namespace of the execution.
+type configMapStore struct {
+	kubeClient clientset.Interface
+}
+
+var _ Store = &configMapStore{}
+
+// NewConfigMapStore returns a Store which keeps the entries in configmaps.
+func NewConfigMapStore(kubeClient clientset.Interface) Store {
+	return &configMapStore{kubeClient: kubeClient}
+}
+
+func configMapName(key string) string {
+	if len(key) > nameKeyLength {
+		key = key[:nameKeyLength]
+	}
+	return namePrefix + key
+}
+
+func (s *configMapStore) Get(namespace, key string) (*Entry, error) {
+	cm, err := s.kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), configMapName(key), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the name only holds a prefix of the key.
+	if cm.Data[keyData] != key {
+		return nil, nil
+	}
+	createdAt, _ := time.Parse(time.RFC3339, cm.Data[createdAtData])
+	entry := &Entry{
+		Key:       key,
+		Execution: cm.Data[executionData],
+		Job:       cm.Data[jobData],
+		CreatedAt: createdAt,
+	}
+	if result, ok := cm.Data[resultData]; ok {
+		entry.Result = &result
+	}
+	return entry, nil
+}
+
+func (s *configMapStore) Put(namespace string, entry *Entry) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName(entry.Key),
+			Namespace: namespace,
+			Labels:    map[string]string{Label: "true"},
+		},
+		Data: map[string]string{
+			keyData:       entry.Key,
+			executionData: entry.Execution,
+			jobData:       entry.Job,
+			createdAtData: entry.CreatedAt.UTC().Format(time.RFC3339),
+		},
+	}
+	if entry.Result != nil {
+		cm.Data[resultData] = *entry.Result
+	}
+	_, err := s.kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		_, err = s.kubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	}
+	return err
+}
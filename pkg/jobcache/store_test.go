@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	store := NewConfigMapStore(fake.NewSimpleClientset())
+	key := strings.Repeat("a", 64)
+
+	entry, err := store.Get("default", key)
+	if err != nil || entry != nil {
+		t.Fatalf("Expect no entry, but got %v, %v", entry, err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	for _, job := range []string{"exec.a.0", "exec2.a.0"} {
+		if err := store.Put("default", &Entry{Key: key, Execution: "exec", Job: job, CreatedAt: now}); err != nil {
+			t.Fatalf("Expect no error, but got error %v", err)
+		}
+	}
+
+	entry, err = store.Get("default", key)
+	if err != nil || entry == nil {
+		t.Fatalf("Expect entry, but got %v, %v", entry, err)
+	}
+	if entry.Job != "exec2.a.0" || !entry.CreatedAt.Equal(now) || entry.Result != nil {
+		t.Errorf("Expect the last entry, but got %+v", entry)
+	}
+
+	// keys sharing the prefix used in the configmap name do not match.
+	entry, err = store.Get("default", strings.Repeat("a", 63)+"b")
+	if err != nil || entry != nil {
+		t.Errorf("Expect no entry, but got %v, %v", entry, err)
+	}
+
+	result := "a,b"
+	if err := store.Put("default", &Entry{Key: key, Execution: "exec", Job: "exec3.a.0", CreatedAt: now, Result: &result}); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	entry, err = store.Get("default", key)
+	if err != nil || entry == nil || entry.Result == nil || *entry.Result != result {
+		t.Errorf("Expect entry with result %s, but got %+v, %v", result, entry, err)
+	}
+
+	entry, err = store.Get("other", key)
+	if err != nil || entry != nil {
+		t.Errorf("Expect no entry in other namespace, but got %v, %v", entry, err)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func ValidateArtifacts(jobName string, job JobInfo, jobs map[string]JobInfo) ErrorList {
+	errors := ErrorList{}
+	errors = append(errors, validateArtifactList(jobName, "input_artifacts", job.InputArtifacts)...)
+	errors = append(errors, validateArtifactList(jobName, "output_artifacts", job.OutputArtifacts)...)
+
+	for i, input := range job.InputArtifacts {
+		prefix := fmt.Sprintf("workflow.%s.input_artifacts[%d].from", jobName, i)
+		source := strings.SplitN(input.From, ".", 2)
+		if len(source) != 2 {
+			errors = append(errors, fmt.Errorf("%s: should be in the form of <job>.<artifact>, but the real one is %s", prefix, input.From))
+			continue
+		}
+		depend := false
+		for _, d := range job.Depends {
+			if d.Target == source[0] {
+				depend = true
+				break
+			}
+		}
+		if !depend {
+			errors = append(errors, fmt.Errorf("%s: job %s should be in the depends", prefix, source[0]))
+			continue
+		}
+		found := false
+		for _, output := range jobs[source[0]].OutputArtifacts {
+			if output.Name == source[1] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, fmt.Errorf("%s: %s is not an output artifact of job %s", prefix, source[1], source[0]))
+		}
+	}
+
+	for i, output := range job.OutputArtifacts {
+		if len(output.From) != 0 {
+			errors = append(errors, fmt.Errorf("workflow.%s.output_artifacts[%d].from: should be empty", jobName, i))
+		}
+	}
+	return errors
+}
+
+func validateArtifactList(jobName, field string, artifacts []Artifact) ErrorList {
+	errors := ErrorList{}
+	names := make(map[string]struct{}, len(artifacts))
+	for i, artifact := range artifacts {
+		prefix := fmt.Sprintf("workflow.%s.%s[%d]", jobName, field, i)
+		if matched, _ := regexp.MatchString(JobNameRegexFmt, artifact.Name); !matched {
+			errors = append(errors, fmt.Errorf("%s.name: must consist of lower case alphanumeric characters or '-', but the real one is %s", prefix, artifact.Name))
+		}
+		if _, exist := names[artifact.Name]; exist {
+			errors = append(errors, fmt.Errorf("%s.name: %s duplicated", prefix, artifact.Name))
+		}
+		names[artifact.Name] = struct{}{}
+		if !path.IsAbs(artifact.Path) || strings.Contains(artifact.Path, "'") {
+			errors = append(errors, fmt.Errorf("%s.path: should be an absolute path, but the real one is %s", prefix, artifact.Path))
+		}
+	}
+	return errors
+}
+
+func ValidateArtifactRepository(repo *ArtifactRepository, jobs map[string]JobInfo) ErrorList {
+	errors := ErrorList{}
+	if repo == nil {
+		for jobName, job := range jobs {
+			if len(job.InputArtifacts) != 0 || len(job.OutputArtifacts) != 0 {
+				errors = append(errors, fmt.Errorf("artifact_repository: should be specified because job %s has artifacts", jobName))
+				break
+			}
+		}
+		return errors
+	}
+
+	if (repo.Local == nil) == (repo.S3 == nil) {
+		return append(errors, fmt.Errorf("artifact_repository: one of local, s3 should be specified"))
+	}
+	if repo.Local != nil && len(repo.Local.PVC) == 0 {
+		errors = append(errors, fmt.Errorf("artifact_repository.local.pvc: should not be empty"))
+	}
+	if repo.S3 != nil {
+		if len(repo.S3.Endpoint) == 0 {
+			errors = append(errors, fmt.Errorf("artifact_repository.s3.endpoint: should not be empty"))
+		}
+		if len(repo.S3.Bucket) == 0 {
+			errors = append(errors, fmt.Errorf("artifact_repository.s3.bucket: should not be empty"))
+		}
+		if len(repo.S3.AccessKeySecret.Name) == 0 || len(repo.S3.AccessKeySecret.Key) == 0 {
+			errors = append(errors, fmt.Errorf("artifact_repository.s3.access_key_secret: name and key should not be empty"))
+		}
+		if len(repo.S3.SecretKeySecret.Name) == 0 || len(repo.S3.SecretKeySecret.Key) == 0 {
+			errors = append(errors, fmt.Errorf("artifact_repository.s3.secret_key_secret: name and key should not be empty"))
+		}
+	}
+	return errors
+}
+
+func TransArtifacts2ExecArtifacts(artifacts []Artifact) []execv1alpha1.Artifact {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	execArtifacts := make([]execv1alpha1.Artifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		execArtifacts = append(execArtifacts, execv1alpha1.Artifact{
+			Name: artifact.Name,
+			Path: artifact.Path,
+			From: artifact.From,
+		})
+	}
+	return execArtifacts
+}
+
+func TransArtifactRepository2ExecArtifactRepository(repo *ArtifactRepository) *execv1alpha1.ArtifactRepository {
+	if repo == nil {
+		return nil
+	}
+	execRepo := &execv1alpha1.ArtifactRepository{Image: repo.Image}
+	if repo.Local != nil {
+		execRepo.Local = &execv1alpha1.LocalArtifactRepository{
+			Pvc:     repo.Local.PVC,
+			SubPath: repo.Local.SubPath,
+		}
+	}
+	if repo.S3 != nil {
+		execRepo.S3 = &execv1alpha1.S3ArtifactRepository{
+			Endpoint:  repo.S3.Endpoint,
+			Bucket:    repo.S3.Bucket,
+			KeyPrefix: repo.S3.KeyPrefix,
+			Insecure:  repo.S3.Insecure,
+			AccessKeySecret: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: repo.S3.AccessKeySecret.Name},
+				Key:                  repo.S3.AccessKeySecret.Key,
+			},
+			SecretKeySecret: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: repo.S3.SecretKeySecret.Name},
+				Key:                  repo.S3.SecretKeySecret.Key,
+			},
+		}
+	}
+	return execRepo
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestValidateArtifacts(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Jobs      string
+		ExpectErr bool
+	}{
+		{
+			Name: "valid artifacts",
+			Jobs: `
+  job-a:
+    output_artifacts:
+      - name: bam
+        path: /data/bam
+  job-b:
+    depends:
+      - target: job-a
+    input_artifacts:
+      - name: bam
+        path: /data/bam
+        from: job-a.bam`,
+			ExpectErr: false,
+		},
+		{
+			Name: "workflow.job-b.input_artifacts[0].from: job job-a should be in the depends",
+			Jobs: `
+  job-a:
+    output_artifacts:
+      - name: bam
+        path: /data/bam
+  job-b:
+    input_artifacts:
+      - name: bam
+        path: /data/bam
+        from: job-a.bam`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-b.input_artifacts[0].from: vcf is not an output artifact of job job-a",
+			Jobs: `
+  job-a:
+    output_artifacts:
+      - name: bam
+        path: /data/bam
+  job-b:
+    depends:
+      - target: job-a
+    input_artifacts:
+      - name: bam
+        path: /data/bam
+        from: job-a.vcf`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.output_artifacts[0].path: should be an absolute path",
+			Jobs: `
+  job-a:
+    output_artifacts:
+      - name: bam
+        path: data/bam`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.output_artifacts[1].name: bam duplicated",
+			Jobs: `
+  job-a:
+    output_artifacts:
+      - name: bam
+        path: /data/bam
+      - name: bam
+        path: /data/bam2`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var jobs map[string]JobInfo
+		if err := yaml.Unmarshal([]byte(testCase.Jobs), &jobs); err != nil {
+			t.Fatalf("%s: unexpected error: %v", testCase.Name, err)
+		}
+		var err ErrorList
+		for jobName, job := range jobs {
+			err = append(err, ValidateArtifacts(jobName, job, jobs)...)
+		}
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestValidateArtifactRepository(t *testing.T) {
+	jobs := map[string]JobInfo{
+		"job-a": {OutputArtifacts: []Artifact{{Name: "bam", Path: "/data/bam"}}},
+	}
+	testCases := []struct {
+		Name       string
+		Repository string
+		ExpectErr  bool
+	}{
+		{
+			Name: "valid local repository",
+			Repository: `
+local:
+  pvc: artifacts`,
+			ExpectErr: false,
+		},
+		{
+			Name: "valid s3 repository",
+			Repository: `
+s3:
+  endpoint: minio:9000
+  bucket: artifacts
+  access_key_secret:
+    name: minio
+    key: accesskey
+  secret_key_secret:
+    name: minio
+    key: secretkey`,
+			ExpectErr: false,
+		},
+		{
+			Name:      "artifact_repository: should be specified because job job-a has artifacts",
+			ExpectErr: true,
+		},
+		{
+			Name: "artifact_repository.s3.access_key_secret: name and key should not be empty",
+			Repository: `
+s3:
+  endpoint: minio:9000
+  bucket: artifacts`,
+			ExpectErr: true,
+		},
+		{
+			Name: "artifact_repository: one of local, s3 should be specified",
+			Repository: `
+image: busybox`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var repo *ArtifactRepository
+		if len(testCase.Repository) != 0 {
+			repo = &ArtifactRepository{}
+			if err := yaml.Unmarshal([]byte(testCase.Repository), repo); err != nil {
+				t.Fatalf("%s: unexpected error: %v", testCase.Name, err)
+			}
+		}
+		err := ValidateArtifactRepository(repo, jobs)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
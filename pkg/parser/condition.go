@@ -26,7 +26,11 @@ import (
 
 const IsCheckResultFuncRegexFmt = `^check_result\(\s*([^,]+)\s*(,\s*("([^,]+)"|'([^,]+)'|\$\{[^,]+\}))?\)$`
 
+const IsExpressionFuncRegexFmt = `^expression\((.+)\)$`
+
 var checkResultRegExp = regexp.MustCompile(IsCheckResultFuncRegexFmt)
+var expressionRegExp = regexp.MustCompile(IsExpressionFuncRegexFmt)
+var expressionResultRegExp = regexp.MustCompile(`result\(\s*"([^"]*)"\s*\)`)
 var inputsChkVarRegExp = regexp.MustCompile("\\$\\{[^,]+\\}")
 
 // IsCheckResultFunc checks a string whether is a check_result function.
@@ -69,6 +73,36 @@ func checkResultFuncParam(str string) (jobName string, exp string) {
 	return
 }
 
+// IsExpressionFunc checks a string whether is an expression function.
+// expression function in the workflows must follow the format:
+// 		expression(exp)
+// The exp compares numbers and strings and combines the comparisons with
+// the go syntax. result(jobName) is the result of a job the job depends on
+// and number(str) the number in a string. Variables such as ${input} are
+// replaced by the values of the inputs.
+//
+// expression function example
+//
+// ---- expression(number(result("job-a")) > ${depth})
+// ---- expression(result("job-a") == "${ref}" && result("job-b") != "")
+func IsExpressionFunc(str string) bool {
+	return expressionRegExp.MatchString(str)
+}
+
+// expressionFuncParam extract the expression from expression function.
+func expressionFuncParam(str string) string {
+	return expressionRegExp.FindStringSubmatch(str)[1]
+}
+
+// mapExpressionResults replaces the names of the jobs whose results the
+// expression reads.
+func mapExpressionResults(expression string, mapping func(string) string) string {
+	return expressionResultRegExp.ReplaceAllStringFunc(expression, func(call string) string {
+		jobName := expressionResultRegExp.FindStringSubmatch(call)[1]
+		return fmt.Sprintf("result(%q)", mapping(jobName))
+	})
+}
+
 func validateCheckResultDependency(prefix string, jobName string, dependJobName string, workflow *Workflow) error {
 
 	dependJob, ok := workflow.Jobs[dependJobName]
@@ -139,6 +173,8 @@ func validateStringCondition(prefix string, condition string, inputs map[string]
 		}
 	} else if IsCheckResultFunc(condition) {
 		allErr = validateCheckResultFunc(prefix, condition, inputs, jobName, workflow)
+	} else if IsExpressionFunc(condition) {
+		_, allErr = ValidateTemplate(expressionFuncParam(condition), prefix, "condition", inputs)
 	} else {
 		err := fmt.Errorf("In validateStringCondition Invalid condition string %v", condition)
 		allErr = append(allErr, err)
@@ -191,6 +227,9 @@ func InstantiateCondition(prefix string, condition interface{}, data map[string]
 				err := fmt.Errorf("Invalid data in the condition %v", condition)
 				return nil, err
 			}
+		} else if IsExpressionFunc(str) {
+			expression := common.ReplaceVariant(expressionFuncParam(str), data)
+			return []interface{}{"expression", expression}, nil
 		} else {
 			ret := InstantiateCheckResultFunc(prefix, condition.(string), data)
 			return ret, nil
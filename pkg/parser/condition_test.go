@@ -17,7 +17,10 @@ limitations under the License.
 package parser
 
 import (
+	"reflect"
 	"testing"
+
+	"kubegene.io/kubegene/pkg/common"
 )
 
 func TestIsCheckResultFunc(t *testing.T) {
@@ -97,3 +100,64 @@ func TestCheckResultFuncParam(t *testing.T) {
 		}
 	}
 }
+
+func TestInstantiateExpressionCondition(t *testing.T) {
+	testCases := []struct {
+		Condition string
+		Data      map[string]string
+		Expect    common.Var
+	}{
+		{
+			Condition: `expression(number(result("job-a")) > ${depth})`,
+			Data:      map[string]string{"depth": "30"},
+			Expect:    common.Var{"expression", `number(result("job-a")) > 30`},
+		},
+		{
+			Condition: `expression(result("job-a") == "${ref}")`,
+			Data:      map[string]string{"ref": "hg38"},
+			Expect:    common.Var{"expression", `result("job-a") == "hg38"`},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if !IsExpressionFunc(testCase.Condition) {
+			t.Errorf("%d: expected %s to be an expression function", i, testCase.Condition)
+			continue
+		}
+		condition, err := InstantiateCondition("", testCase.Condition, testCase.Data)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(condition, testCase.Expect) {
+			t.Errorf("%d: unexpected condition; got %v, expected %v", i, condition, testCase.Expect)
+		}
+	}
+}
+
+func TestValidateExpressionCondition(t *testing.T) {
+	inputs := map[string]Input{"depth": {Type: NumberType, Default: 30}}
+	testCases := []struct {
+		Condition string
+		ExpectErr bool
+	}{
+		{Condition: `expression(number(result("job-a")) > ${depth})`},
+		{Condition: `expression(result("job-a") == "${ref}")`, ExpectErr: true},
+		{Condition: `expression()`, ExpectErr: true},
+	}
+
+	for i, testCase := range testCases {
+		errs := validateStringCondition("workflow.job-b.condition", testCase.Condition, inputs, "job-b", &Workflow{})
+		if (len(errs) != 0) != testCase.ExpectErr {
+			t.Errorf("%d: expected error %v, got %v", i, testCase.ExpectErr, errs)
+		}
+	}
+}
+
+func TestMapExpressionResults(t *testing.T) {
+	mapping := func(jobName string) string { return "align-" + jobName }
+	expression := `number(result("job-a")) > 30 && result( "job-b" ) == "result(\"x\")"`
+	expect := `number(result("align-job-a")) > 30 && result("align-job-b") == "result(\"x\")"`
+	if mapped := mapExpressionResults(expression, mapping); mapped != expect {
+		t.Errorf("unexpected expression; got %s, expected %s", mapped, expect)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	"kubegene.io/kubegene/pkg/common"
+)
+
+// reservedContainerPrefix prefixes the names of the containers added by the controller.
+const reservedContainerPrefix = "kubegene-"
+
+func ValidateContainers(jobName string, job JobInfo, volumes map[string]Volume) ErrorList {
+	errors := ErrorList{}
+	names := map[string]struct{}{}
+	jobVolumes := make(map[string]struct{}, len(job.Volumes))
+	for _, v := range job.Volumes {
+		jobVolumes[v.Name] = struct{}{}
+	}
+
+	validate := func(field string, containers []Container) {
+		for i, c := range containers {
+			prefix := fmt.Sprintf("workflow.%s.%s[%d]", jobName, field, i)
+			if matched, _ := regexp.MatchString(JobNameRegexFmt, c.Name); !matched {
+				errors = append(errors, fmt.Errorf("%s.name: must consist of lower case alphanumeric characters or '-', but the real one is %s", prefix, c.Name))
+			} else if strings.HasPrefix(c.Name, reservedContainerPrefix) {
+				errors = append(errors, fmt.Errorf("%s.name: should not start with %s", prefix, reservedContainerPrefix))
+			}
+			if _, exist := names[c.Name]; exist {
+				errors = append(errors, fmt.Errorf("%s.name: %s duplicated", prefix, c.Name))
+			}
+			names[c.Name] = struct{}{}
+			if len(c.Image) == 0 {
+				errors = append(errors, fmt.Errorf("%s.image: should not be empty", prefix))
+			}
+			if len(c.Command) == 0 {
+				errors = append(errors, fmt.Errorf("%s.command: should not be empty", prefix))
+			}
+			errors = append(errors, validateEnv(prefix+".env", c.Env)...)
+			for j, v := range c.Volumes {
+				if _, ok := volumes[v.Name]; !ok {
+					errors = append(errors, fmt.Errorf("%s.volumes[%d].name: volume %s is not defined in the volumes", prefix, j, v.Name))
+					continue
+				}
+				if _, ok := jobVolumes[v.Name]; len(jobVolumes) != 0 && !ok {
+					errors = append(errors, fmt.Errorf("%s.volumes[%d].name: volume %s is not a volume of the job", prefix, j, v.Name))
+				}
+			}
+		}
+	}
+	validate("init_containers", job.InitContainers)
+	validate("sidecars", job.Sidecars)
+	return errors
+}
+
+// instantiateContainers populates the inputs in the commands and the env of the containers.
+func instantiateContainers(containers []Container, data map[string]string) []Container {
+	if containers == nil {
+		return nil
+	}
+	result := make([]Container, 0, len(containers))
+	for _, c := range containers {
+		c.Command = common.ReplaceVariant(c.Command, data)
+		c.Env = instantiateEnv(c.Env, data)
+		result = append(result, c)
+	}
+	return result
+}
+
+func TransContainers2ExecContainers(containers []Container, volumes map[string]Volume) []v1.Container {
+	var execContainers []v1.Container
+	for _, c := range containers {
+		container := v1.Container{
+			Name:            c.Name,
+			Image:           c.Image,
+			Command:         []string{"sh", "-c", c.Command},
+			Env:             TransEnv2ExecEnv(c.Env),
+			ImagePullPolicy: v1.PullIfNotPresent,
+		}
+		for _, v := range c.Volumes {
+			volume := volumes[v.Name]
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+				Name:      v.Name,
+				MountPath: volume.MountPath,
+				ReadOnly:  volume.ReadOnly || v.ReadOnly,
+			})
+		}
+		execContainers = append(execContainers, container)
+	}
+	return execContainers
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestValidateContainers(t *testing.T) {
+	volumes := map[string]Volume{
+		"reference": {MountPath: "/ref", MountFrom: VolumeSource{PVC: "ref"}},
+		"sample":    {MountPath: "/sample", MountFrom: VolumeSource{PVC: "sample"}},
+	}
+	testCases := []struct {
+		Name      string
+		Job       string
+		ExpectErr bool
+	}{
+		{
+			Name: "valid init containers and sidecars",
+			Job: `
+init_containers:
+  - name: fetch-ref
+    image: busybox
+    command: wget -O- ${ref-url} | tar -xz -C /ref
+    volumes:
+      - name: reference
+sidecars:
+  - name: log-shipper
+    image: fluent-bit
+    command: fluent-bit -c /etc/fluent-bit.conf`,
+			ExpectErr: false,
+		},
+		{
+			Name: "workflow.job-a.sidecars[0].command: should not be empty",
+			Job: `
+sidecars:
+  - name: log-shipper
+    image: fluent-bit`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.sidecars[0].name: stage duplicated",
+			Job: `
+init_containers:
+  - name: stage
+    image: busybox
+    command: echo
+sidecars:
+  - name: stage
+    image: busybox
+    command: echo`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.init_containers[0].name: should not start with kubegene-",
+			Job: `
+init_containers:
+  - name: kubegene-stage
+    image: busybox
+    command: echo`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.init_containers[0].volumes[0].name: volume sample is not a volume of the job",
+			Job: `
+volumes:
+  - name: reference
+init_containers:
+  - name: stage
+    image: busybox
+    command: echo
+    volumes:
+      - name: sample`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var job JobInfo
+		if err := yaml.Unmarshal([]byte(testCase.Job), &job); err != nil {
+			t.Fatalf("%s: unexpected error: %v", testCase.Name, err)
+		}
+		err := ValidateContainers("job-a", job, volumes)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestTransContainers2ExecContainers(t *testing.T) {
+	volumes := map[string]Volume{
+		"reference": {MountPath: "/ref", MountFrom: VolumeSource{PVC: "ref"}},
+	}
+	containers := TransContainers2ExecContainers([]Container{
+		{Name: "fetch-ref", Image: "busybox", Command: "echo", Volumes: []JobVolume{{Name: "reference", ReadOnly: true}}},
+	}, volumes)
+
+	if len(containers) != 1 {
+		t.Fatalf("Expect 1 container, but got %d", len(containers))
+	}
+	c := containers[0]
+	if len(c.Command) != 3 || c.Command[2] != "echo" {
+		t.Errorf("Expect command run with sh -c, but got %v", c.Command)
+	}
+	if len(c.VolumeMounts) != 1 || c.VolumeMounts[0].MountPath != "/ref" || !c.VolumeMounts[0].ReadOnly {
+		t.Errorf("Expect read-only mount of reference at /ref, but got %v", c.VolumeMounts)
+	}
+}
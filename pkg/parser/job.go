@@ -18,8 +18,12 @@ package parser
 
 import (
 	"fmt"
-	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 )
 
 const CPURegexFmt = `^\d+(\.\d+)?[cC]?$`
@@ -41,8 +45,11 @@ func ValidateMemory(prefix, memory string) error {
 }
 
 func ValidateResources(jobName string, res Resources) ErrorList {
+	return validateResources(fmt.Sprintf("workflow.%s.resources", jobName), res)
+}
+
+func validateResources(prefix string, res Resources) ErrorList {
 	errors := ErrorList{}
-	prefix := fmt.Sprintf("workflow.%s.resources", jobName)
 	if len(res.Cpu) != 0 {
 		if err := ValidateCPU(prefix, res.Cpu); err != nil {
 			errors = append(errors, err)
@@ -158,6 +165,111 @@ func ValidateGenericCondition(jobName string, gCondition *GenericCondition, inpu
 	return allErrs
 }
 
+func ValidateSkipPolicy(jobName string, skipPolicy string) ErrorList {
+	errors := ErrorList{}
+	if len(skipPolicy) == 0 {
+		return errors
+	}
+	if skipPolicy != ContinueSkipPolicy && skipPolicy != CascadeSkipPolicy {
+		err := fmt.Errorf("workflow.%s.skip_policy should only be continue or cascade", jobName)
+		errors = append(errors, err)
+	}
+	return errors
+}
+
+// ValidateIndexed checks that the commands of an indexed job can share one Indexed Job.
+func ValidateIndexed(jobName string, job JobInfo) ErrorList {
+	errors := ErrorList{}
+	if !job.Indexed {
+		return errors
+	}
+	if job.Condition != nil || job.GenericCondition != nil {
+		errors = append(errors, fmt.Errorf("workflow.%s.indexed: an indexed job can not have condition or generic_condition", jobName))
+	}
+	for _, vars := range job.CommandsIter.VarsIter {
+		if str, ok := vars.(string); ok && IsGetResultFunc(str) {
+			errors = append(errors, fmt.Errorf("workflow.%s.indexed: the commands of an indexed job can not use get_result", jobName))
+		}
+	}
+	if len(job.InputArtifacts) != 0 || len(job.OutputArtifacts) != 0 {
+		errors = append(errors, fmt.Errorf("workflow.%s.indexed: an indexed job can not have input_artifacts or output_artifacts", jobName))
+	}
+	if job.Volcano != nil {
+		errors = append(errors, fmt.Errorf("workflow.%s.indexed: an indexed job can not run as a Volcano Job", jobName))
+	}
+	return errors
+}
+
+// ValidateChunkSize checks that the commands of the job can run in chunks.
+func ValidateChunkSize(jobName string, job JobInfo, workflowVolcano *Volcano) ErrorList {
+	errors := ErrorList{}
+	if job.ChunkSize == nil {
+		return errors
+	}
+	if *job.ChunkSize < 1 || *job.ChunkSize > MaxChunkSize {
+		errors = append(errors, fmt.Errorf("workflow.%s.chunk_size should be between 1 and %d", jobName, MaxChunkSize))
+	}
+	if job.Indexed || job.Volcano != nil || workflowVolcano != nil {
+		errors = append(errors, fmt.Errorf("workflow.%s.chunk_size: the job can not be indexed or run as a Volcano Job", jobName))
+	}
+	if len(job.InputArtifacts) != 0 || len(job.OutputArtifacts) != 0 {
+		errors = append(errors, fmt.Errorf("workflow.%s.chunk_size: the job can not have input_artifacts or output_artifacts", jobName))
+	}
+	return errors
+}
+
+// ValidateEstimatedDuration checks that the estimated duration of the job is a positive duration.
+func ValidateEstimatedDuration(jobName string, job JobInfo) ErrorList {
+	errors := ErrorList{}
+	if job.EstimatedDuration == "" {
+		return errors
+	}
+	duration, err := time.ParseDuration(job.EstimatedDuration)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("workflow.%s.estimated_duration %s is illegal: %v", jobName, job.EstimatedDuration, err))
+	} else if duration <= 0 {
+		errors = append(errors, fmt.Errorf("workflow.%s.estimated_duration should be positive", jobName))
+	}
+	return errors
+}
+
+// ValidateMemoryEscalation checks that the memory of the job can be escalated.
+func ValidateMemoryEscalation(jobName string, job JobInfo, workflowVolcano *Volcano) ErrorList {
+	errors := ErrorList{}
+	escalation := job.MemoryEscalation
+	if escalation == nil {
+		return errors
+	}
+	prefix := fmt.Sprintf("workflow.%s.memory_escalation", jobName)
+	if escalation.Multiplier <= 1 {
+		errors = append(errors, fmt.Errorf("%s.multiplier should be greater than 1", prefix))
+	}
+	if err := ValidateMemory(prefix+".max", escalation.Max); err != nil {
+		errors = append(errors, err)
+	} else if len(job.Resources.Memory) == 0 {
+		errors = append(errors, fmt.Errorf("%s: the job should have resources.memory", prefix))
+	} else if ValidateMemory(prefix, job.Resources.Memory) == nil {
+		max := resource.MustParse(strings.ToUpper(escalation.Max))
+		if max.Cmp(resource.MustParse(strings.ToUpper(job.Resources.Memory))) < 0 {
+			errors = append(errors, fmt.Errorf("%s.max should not be less than resources.memory", prefix))
+		}
+	}
+	if job.Indexed || job.Volcano != nil || workflowVolcano != nil {
+		errors = append(errors, fmt.Errorf("%s: the job can not be indexed or run as a Volcano Job", prefix))
+	}
+	return errors
+}
+
+func TransSkipPolicy2ExecSkipPolicy(skipPolicy string) execv1alpha1.SkipPolicy {
+	switch skipPolicy {
+	case CascadeSkipPolicy:
+		return execv1alpha1.SkipPolicyCascade
+	case ContinueSkipPolicy:
+		return execv1alpha1.SkipPolicyContinue
+	}
+	return ""
+}
+
 func ValidateTool(jobName string, toolName string) ErrorList {
 	errors := ErrorList{}
 	if len(toolName) == 0 {
@@ -171,6 +171,52 @@ func TestValidateResources(t *testing.T) {
 	}
 }
 
+func TestTransResources2ExecResources(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Res       Resources
+		CPU       string
+		Memory    string
+		ExpectErr bool
+	}{
+		{
+			Name:   "cpu and memory",
+			Res:    Resources{Cpu: "256C", Memory: "1T"},
+			CPU:    "256",
+			Memory: "1T",
+		},
+		{
+			Name:   "cpu only",
+			Res:    Resources{Cpu: "8"},
+			CPU:    "8",
+			Memory: "0",
+		},
+		{
+			Name:      "invalid memory",
+			Res:       Resources{Memory: "8Gg"},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		res, err := TransResources2ExecResources(testCase.Res)
+		if testCase.ExpectErr {
+			if err == nil {
+				t.Errorf("%s: Expect error, but got nil", testCase.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+			continue
+		}
+		if res.Cpu.String() != testCase.CPU || res.Memory.String() != testCase.Memory {
+			t.Errorf("%s: Expect cpu %s and memory %s, but got %s and %s", testCase.Name,
+				testCase.CPU, testCase.Memory, res.Cpu.String(), res.Memory.String())
+		}
+	}
+}
+
 func TestValidateDepend(t *testing.T) {
 	testCases := []struct {
 		Name         string
@@ -324,6 +370,236 @@ func TestValidateTool(t *testing.T) {
 	}
 }
 
+func TestValidateSkipPolicy(t *testing.T) {
+	testCases := []struct {
+		SkipPolicy string
+		ExpectErr  bool
+	}{
+		{
+			ExpectErr: false,
+		},
+		{
+			SkipPolicy: "continue",
+			ExpectErr:  false,
+		},
+		{
+			SkipPolicy: "cascade",
+			ExpectErr:  false,
+		},
+		{
+			SkipPolicy: "ignore",
+			ExpectErr:  true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := ValidateSkipPolicy("test", testCase.SkipPolicy)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%d: Expect error, but got nil", i)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%d: Expect no error, but got error %v", i, err)
+		}
+	}
+}
+
+func TestValidateIndexed(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Job       JobInfo
+		ExpectErr bool
+	}{
+		{
+			Name:      "not indexed",
+			Job:       JobInfo{Condition: "job-a"},
+			ExpectErr: false,
+		},
+		{
+			Name: "indexed commands iter",
+			Job: JobInfo{
+				Indexed:      true,
+				CommandsIter: CommandsIter{Command: "echo ${1}", VarsIter: []interface{}{[]interface{}{"a", "b"}}},
+			},
+			ExpectErr: false,
+		},
+		{
+			Name:      "indexed with condition",
+			Job:       JobInfo{Indexed: true, Condition: "job-a"},
+			ExpectErr: true,
+		},
+		{
+			Name: "indexed with get_result",
+			Job: JobInfo{
+				Indexed:      true,
+				CommandsIter: CommandsIter{Command: "echo ${1}", VarsIter: []interface{}{"get_result(job-a, \" \")"}},
+			},
+			ExpectErr: true,
+		},
+		{
+			Name:      "indexed volcano job",
+			Job:       JobInfo{Indexed: true, Volcano: &Volcano{}},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateIndexed("test", testCase.Job)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestValidateChunkSize(t *testing.T) {
+	zero, ten := int32(0), int32(10)
+	testCases := []struct {
+		Name            string
+		Job             JobInfo
+		WorkflowVolcano *Volcano
+		ExpectErr       bool
+	}{
+		{
+			Name:      "no chunk size",
+			Job:       JobInfo{Indexed: true},
+			ExpectErr: false,
+		},
+		{
+			Name:      "chunks of ten commands",
+			Job:       JobInfo{ChunkSize: &ten},
+			ExpectErr: false,
+		},
+		{
+			Name:      "chunk size is zero",
+			Job:       JobInfo{ChunkSize: &zero},
+			ExpectErr: true,
+		},
+		{
+			Name:      "indexed job in chunks",
+			Job:       JobInfo{ChunkSize: &ten, Indexed: true},
+			ExpectErr: true,
+		},
+		{
+			Name:            "workflow run as volcano jobs",
+			Job:             JobInfo{ChunkSize: &ten},
+			WorkflowVolcano: &Volcano{},
+			ExpectErr:       true,
+		},
+		{
+			Name:      "job with artifacts",
+			Job:       JobInfo{ChunkSize: &ten, OutputArtifacts: []Artifact{{Name: "out", Path: "/out"}}},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateChunkSize("test", testCase.Job, testCase.WorkflowVolcano)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestValidateEstimatedDuration(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Job       JobInfo
+		ExpectErr bool
+	}{
+		{
+			Name:      "no estimated duration",
+			Job:       JobInfo{},
+			ExpectErr: false,
+		},
+		{
+			Name:      "thirty minutes",
+			Job:       JobInfo{EstimatedDuration: "30m"},
+			ExpectErr: false,
+		},
+		{
+			Name:      "illegal duration",
+			Job:       JobInfo{EstimatedDuration: "half an hour"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "negative duration",
+			Job:       JobInfo{EstimatedDuration: "-1h"},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateEstimatedDuration("test", testCase.Job)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestValidateMemoryEscalation(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Job             JobInfo
+		WorkflowVolcano *Volcano
+		ExpectErr       bool
+	}{
+		{
+			Name:      "no memory escalation",
+			Job:       JobInfo{},
+			ExpectErr: false,
+		},
+		{
+			Name:      "doubled up to 64G",
+			Job:       JobInfo{Resources: Resources{Memory: "8g"}, MemoryEscalation: &MemoryEscalation{Multiplier: 2, Max: "64g"}},
+			ExpectErr: false,
+		},
+		{
+			Name:      "no memory",
+			Job:       JobInfo{MemoryEscalation: &MemoryEscalation{Multiplier: 2, Max: "64G"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "multiplier less than 1",
+			Job:       JobInfo{Resources: Resources{Memory: "8G"}, MemoryEscalation: &MemoryEscalation{Multiplier: 0.5, Max: "64G"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "illegal max",
+			Job:       JobInfo{Resources: Resources{Memory: "8G"}, MemoryEscalation: &MemoryEscalation{Multiplier: 2, Max: "64T"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "max less than memory",
+			Job:       JobInfo{Resources: Resources{Memory: "8G"}, MemoryEscalation: &MemoryEscalation{Multiplier: 2, Max: "4G"}},
+			ExpectErr: true,
+		},
+		{
+			Name:            "workflow run as volcano jobs",
+			Job:             JobInfo{Resources: Resources{Memory: "8G"}, MemoryEscalation: &MemoryEscalation{Multiplier: 2, Max: "64G"}},
+			WorkflowVolcano: &Volcano{},
+			ExpectErr:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateMemoryEscalation("test", testCase.Job, testCase.WorkflowVolcano)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
 func TestValidateCommands(t *testing.T) {
 	testCases := []struct {
 		Commands  []string
@@ -17,8 +17,11 @@ limitations under the License.
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"kubegene.io/kubegene/cmd/genectl/util"
+	"k8s.io/apimachinery/pkg/util/uuid"
 )
 
 func GetExecutionNamespace(inputs map[string]Input) string {
@@ -32,7 +35,13 @@ func GetExecutionNamespace(inputs map[string]Input) string {
 func GetExecutionName(inputs map[string]Input) string {
 	name := GetStringValue("executionName", inputs)
 	if len(name) == 0 {
-		name = util.GenerateExecName("execution")
+		name = generateExecName("execution")
 	}
 	return name
 }
+
+func generateExecName(prefix string) string {
+	uuid := uuid.NewUUID()
+	randStr := strings.Replace(string(uuid), "-", "", -1)[0:5]
+	return fmt.Sprintf("%s-%s", prefix, randStr)
+}
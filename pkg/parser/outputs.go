@@ -18,6 +18,8 @@ package parser
 
 import (
 	"fmt"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 )
 
 func ValidatePaths(outputName string, paths []string, inputs map[string]Input) ErrorList {
@@ -61,3 +63,16 @@ func ValidatePathsIter(outputName string, pathIter PathsIter, inputs map[string]
 
 	return allErr
 }
+
+// TransOutputs2ExecOutputs converts the instantiated outputs of the workflow,
+// whose paths_iter has already been expanded into paths.
+func TransOutputs2ExecOutputs(outputs map[string]OutputDesc) map[string]execv1alpha1.Output {
+	if len(outputs) == 0 {
+		return nil
+	}
+	execOutputs := make(map[string]execv1alpha1.Output, len(outputs))
+	for name, output := range outputs {
+		execOutputs[name] = execv1alpha1.Output{Paths: output.Paths}
+	}
+	return execOutputs
+}
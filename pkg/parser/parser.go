@@ -19,6 +19,7 @@ package parser
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -57,6 +58,13 @@ func ValidateWorkflow(workflow *Workflow) ErrorList {
 		// validate job Name
 		allErr = append(allErr, ValidateJobName(jobName)...)
 
+		if job.SubWorkflow != nil {
+			// the jobs of the sub workflow are validated when it is expanded.
+			allErr = append(allErr, ValidateDepends(jobName, job.Depends, workflow.Jobs)...)
+			allErr = append(allErr, ValidateSubWorkflow(jobName, job, workflow.Inputs)...)
+			continue
+		}
+
 		// validate resources
 		allErr = append(allErr, ValidateResources(jobName, job.Resources)...)
 
@@ -87,6 +95,39 @@ func ValidateWorkflow(workflow *Workflow) ErrorList {
 		// validate condition
 		allErr = append(allErr, validateCondition(jobName, job.Condition, workflow.Inputs, workflow)...)
 
+		// validate skip policy
+		allErr = append(allErr, ValidateSkipPolicy(jobName, job.SkipPolicy)...)
+
+		// validate artifacts
+		allErr = append(allErr, ValidateArtifacts(jobName, job, workflow.Jobs)...)
+		if job.Cache != nil && *job.Cache && len(job.OutputArtifacts) != 0 {
+			allErr = append(allErr, fmt.Errorf("workflow.%s.cache: job with output_artifacts can not be cached", jobName))
+		}
+
+		// validate pod options
+		allErr = append(allErr, ValidatePodOptions(jobName, job)...)
+
+		// validate volumes of the job
+		allErr = append(allErr, ValidateJobVolumes(jobName, job.Volumes, workflow.Volumes)...)
+
+		// validate init containers and sidecars
+		allErr = append(allErr, ValidateContainers(jobName, job, workflow.Volumes)...)
+
+		// validate volcano options
+		allErr = append(allErr, ValidateVolcano(fmt.Sprintf("workflow.%s.volcano", jobName), job.Volcano)...)
+
+		// validate indexed job
+		allErr = append(allErr, ValidateIndexed(jobName, job)...)
+
+		// validate chunk size
+		allErr = append(allErr, ValidateChunkSize(jobName, job, workflow.Volcano)...)
+
+		// validate estimated duration
+		allErr = append(allErr, ValidateEstimatedDuration(jobName, job)...)
+
+		// validate memory escalation
+		allErr = append(allErr, ValidateMemoryEscalation(jobName, job, workflow.Volcano)...)
+
 	}
 
 	// detect cycle depends.
@@ -97,6 +138,17 @@ func ValidateWorkflow(workflow *Workflow) ErrorList {
 	// validate volumes
 	allErr = append(allErr, ValidateVolumes(workflow.Volumes, workflow.Inputs)...)
 
+	// validate parallel resources of the workflow
+	if workflow.ParallelResources != nil {
+		allErr = append(allErr, validateResources("parallel_resources", *workflow.ParallelResources)...)
+	}
+
+	// validate volcano options of the workflow
+	allErr = append(allErr, ValidateVolcano("volcano", workflow.Volcano)...)
+
+	// validate artifact repository
+	allErr = append(allErr, ValidateArtifactRepository(workflow.ArtifactRepository, workflow.Jobs)...)
+
 	// validate output
 	for key, output := range workflow.Outputs {
 		// validate paths
@@ -108,6 +160,44 @@ func ValidateWorkflow(workflow *Workflow) ErrorList {
 	return allErr
 }
 
+// ExpandWorkflow validates the workflow, instantiates it with the inputs and
+// the tools, expands its sub workflows loaded by load, and returns the
+// execution of it.
+func ExpandWorkflow(workflowData []byte, inputs map[string]interface{}, tools map[string]Tool, load WorkflowLoader) (*execv1alpha1.Execution, error) {
+	workflow, err := UnmarshalWorkflow(workflowData)
+	if err != nil {
+		return nil, err
+	}
+
+	SetDefaultWorkflow(workflow)
+
+	errList := ValidateWorkflow(workflow)
+	if len(errList) > 0 {
+		return nil, fmt.Errorf("invalid workflow: %s", joinErrors(errList))
+	}
+
+	err = InstantiateWorkflow(workflow, inputs, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ExpandSubWorkflows(workflow, tools, load)
+	if err != nil {
+		return nil, err
+	}
+
+	return TransWorkflow2Execution(workflow)
+}
+
+// joinErrors returns the messages of the errors separated by semicolons.
+func joinErrors(errList ErrorList) string {
+	errMsgs := make([]string, 0, len(errList))
+	for _, err := range errList {
+		errMsgs = append(errMsgs, err.Error())
+	}
+	return strings.Join(errMsgs, "; ")
+}
+
 func convert2ArrayOfIfs(data []common.Var) []interface{} {
 	vars := make([]interface{}, 0)
 	for _, arr := range data {
@@ -131,6 +221,16 @@ func InstantiateWorkflow(workflow *Workflow, inputs map[string]interface{}, tool
 	for key, volume := range workflow.Volumes {
 		volume.MountPath = common.ReplaceVariant(volume.MountPath, inputsReplaceData)
 		volume.MountFrom.PVC = common.ReplaceVariant(volume.MountFrom.PVC, inputsReplaceData)
+		if volume.MountFrom.HostPath != nil {
+			hostPath := *volume.MountFrom.HostPath
+			hostPath.Path = common.ReplaceVariant(hostPath.Path, inputsReplaceData)
+			volume.MountFrom.HostPath = &hostPath
+		}
+		if volume.MountFrom.NFS != nil {
+			nfs := *volume.MountFrom.NFS
+			nfs.Path = common.ReplaceVariant(nfs.Path, inputsReplaceData)
+			volume.MountFrom.NFS = &nfs
+		}
 		volumes[key] = volume
 	}
 	workflow.Volumes = volumes
@@ -141,6 +241,15 @@ func InstantiateWorkflow(workflow *Workflow, inputs map[string]interface{}, tool
 	for jobName, jobInfo := range workflow.Jobs {
 		var tmpJob JobInfo
 
+		if jobInfo.SubWorkflow != nil {
+			// the jobs of the sub workflow are instantiated when it is expanded.
+			tmpJob.Description = jobInfo.Description
+			tmpJob.Depends = jobInfo.Depends
+			tmpJob.SubWorkflow = instantiateSubWorkflow(jobInfo.SubWorkflow, mergedInputs, inputsReplaceData)
+			jobs[jobName] = tmpJob
+			continue
+		}
+
 		tmpJob.Description = jobInfo.Description
 		tmpJob.Tool = jobInfo.Tool
 
@@ -220,6 +329,26 @@ func InstantiateWorkflow(workflow *Workflow, inputs map[string]interface{}, tool
 			tmpJob.Depends = jobInfo.Depends
 			tmpJob.Condition = jobInfo.Condition
 			tmpJob.GenericCondition = jobInfo.GenericCondition
+			tmpJob.SkipPolicy = jobInfo.SkipPolicy
+			tmpJob.InputArtifacts = jobInfo.InputArtifacts
+			tmpJob.OutputArtifacts = jobInfo.OutputArtifacts
+			tmpJob.Cache = jobInfo.Cache
+			tmpJob.InputDigests = instantiateStrings(jobInfo.InputDigests, inputsReplaceData)
+			tmpJob.Env = instantiateEnv(jobInfo.Env, inputsReplaceData)
+			tmpJob.EnvFrom = jobInfo.EnvFrom
+			tmpJob.ServiceAccount = jobInfo.ServiceAccount
+			tmpJob.ImagePullSecrets = jobInfo.ImagePullSecrets
+			tmpJob.ImagePullPolicy = jobInfo.ImagePullPolicy
+			tmpJob.SecurityContext = jobInfo.SecurityContext
+			tmpJob.Volumes = jobInfo.Volumes
+			tmpJob.InitContainers = instantiateContainers(jobInfo.InitContainers, inputsReplaceData)
+			tmpJob.Sidecars = instantiateContainers(jobInfo.Sidecars, inputsReplaceData)
+			tmpJob.Volcano = jobInfo.Volcano
+			tmpJob.Indexed = jobInfo.Indexed
+			tmpJob.ChunkSize = jobInfo.ChunkSize
+			tmpJob.Priority = jobInfo.Priority
+			tmpJob.EstimatedDuration = jobInfo.EstimatedDuration
+			tmpJob.MemoryEscalation = jobInfo.MemoryEscalation
 			jobs[jobName] = tmpJob
 
 		} else {
@@ -233,6 +362,26 @@ func InstantiateWorkflow(workflow *Workflow, inputs map[string]interface{}, tool
 			tmpJob.Depends = jobInfo.Depends
 			tmpJob.Condition = jobInfo.Condition
 			tmpJob.GenericCondition = jobInfo.GenericCondition
+			tmpJob.SkipPolicy = jobInfo.SkipPolicy
+			tmpJob.InputArtifacts = jobInfo.InputArtifacts
+			tmpJob.OutputArtifacts = jobInfo.OutputArtifacts
+			tmpJob.Cache = jobInfo.Cache
+			tmpJob.InputDigests = instantiateStrings(jobInfo.InputDigests, inputsReplaceData)
+			tmpJob.Env = instantiateEnv(jobInfo.Env, inputsReplaceData)
+			tmpJob.EnvFrom = jobInfo.EnvFrom
+			tmpJob.ServiceAccount = jobInfo.ServiceAccount
+			tmpJob.ImagePullSecrets = jobInfo.ImagePullSecrets
+			tmpJob.ImagePullPolicy = jobInfo.ImagePullPolicy
+			tmpJob.SecurityContext = jobInfo.SecurityContext
+			tmpJob.Volumes = jobInfo.Volumes
+			tmpJob.InitContainers = instantiateContainers(jobInfo.InitContainers, inputsReplaceData)
+			tmpJob.Sidecars = instantiateContainers(jobInfo.Sidecars, inputsReplaceData)
+			tmpJob.Volcano = jobInfo.Volcano
+			tmpJob.Indexed = jobInfo.Indexed
+			tmpJob.ChunkSize = jobInfo.ChunkSize
+			tmpJob.Priority = jobInfo.Priority
+			tmpJob.EstimatedDuration = jobInfo.EstimatedDuration
+			tmpJob.MemoryEscalation = jobInfo.MemoryEscalation
 			jobs[jobName] = tmpJob
 
 		}
@@ -285,10 +434,65 @@ func InstantiateWorkflow(workflow *Workflow, inputs map[string]interface{}, tool
 	return nil
 }
 
+// TransMemoryEscalation2ExecMemoryEscalation parses the max memory of the escalation.
+// instantiateStrings replaces the variables of the values with the data.
+func instantiateStrings(values []string, data map[string]string) []string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, common.ReplaceVariant(value, data))
+	}
+	return result
+}
+
+func TransMemoryEscalation2ExecMemoryEscalation(escalation MemoryEscalation) (*execv1alpha1.MemoryEscalation, error) {
+	max, err := resource.ParseQuantity(strings.ToUpper(escalation.Max))
+	if err != nil {
+		return nil, fmt.Errorf("parse max memory quantity error: %v", err)
+	}
+	return &execv1alpha1.MemoryEscalation{
+		Multiplier: escalation.Multiplier,
+		Max:        max,
+	}, nil
+}
+
+// TransResources2ExecResources parses the quantities of the resources.
+func TransResources2ExecResources(res Resources) (execv1alpha1.ResourceRequirements, error) {
+	var cpuQuantity resource.Quantity
+	var memoryQuantity resource.Quantity
+	var err error
+
+	// parse cpu
+	if len(res.Cpu) > 0 {
+		cpuNum := strings.TrimRight(res.Cpu, "cC")
+		cpuQuantity, err = resource.ParseQuantity(cpuNum)
+		if err != nil {
+			return execv1alpha1.ResourceRequirements{}, fmt.Errorf("parse cpu quantity error: %v", err)
+		}
+	}
+	// parse memory
+	if len(res.Memory) > 0 {
+		memoryQuantity, err = resource.ParseQuantity(res.Memory)
+		if err != nil {
+			return execv1alpha1.ResourceRequirements{}, fmt.Errorf("parse mem quantity error: %v", err)
+		}
+	}
+
+	return execv1alpha1.ResourceRequirements{
+		Cpu:    cpuQuantity,
+		Memory: memoryQuantity,
+	}, nil
+}
+
 func TransWorkflow2Execution(workflow *Workflow) (*execv1alpha1.Execution, error) {
 	namespace := GetExecutionNamespace(workflow.Inputs)
 	name := GetExecutionName(workflow.Inputs)
-	execVolumes := TransVolume2ExecVolume(workflow.Volumes)
+	execVolumes, err := TransVolume2ExecVolume(workflow.Volumes)
+	if err != nil {
+		return nil, err
+	}
 
 	// TODO make parallelism configurable
 	parallelism := int64(5000)
@@ -299,17 +503,30 @@ func TransWorkflow2Execution(workflow *Workflow) (*execv1alpha1.Execution, error
 			Namespace: namespace,
 		},
 		Spec: execv1alpha1.ExecutionSpec{
-			Parallelism: &parallelism,
-			Tasks:       []execv1alpha1.Task{},
+			Parallelism:        &parallelism,
+			Tasks:              []execv1alpha1.Task{},
+			ArtifactRepository: TransArtifactRepository2ExecArtifactRepository(workflow.ArtifactRepository),
+			Outputs:            TransOutputs2ExecOutputs(workflow.Outputs),
+			Cache:              workflow.Cache,
+			Volcano:            TransVolcano2ExecVolcano(workflow.Volcano),
+			Priority:           workflow.Priority,
 		},
 	}
 
+	if workflow.ParallelResources != nil {
+		parallelResources, err := TransResources2ExecResources(*workflow.ParallelResources)
+		if err != nil {
+			return nil, err
+		}
+		exec.Spec.ParallelResources = &parallelResources
+	}
+
 	for jobName, jobInfo := range workflow.Jobs {
 		var task execv1alpha1.Task
 		task.Name = jobName
 		task.Type = "Job"
 		task.Image = jobInfo.Image
-		task.Volumes = execVolumes
+		task.Volumes = SelectJobVolumes(execVolumes, jobInfo.Volumes)
 		// we have alreay merge workflows command and commandIter.
 		task.CommandSet = jobInfo.Commands
 
@@ -318,29 +535,9 @@ func TransWorkflow2Execution(workflow *Workflow) (*execv1alpha1.Execution, error
 			task.CommandsIter = TransCommandIter2ExecCommandIter(jobInfo.CommandsIter)
 		}
 
-		var cpuQuantity resource.Quantity
-		var memoryQuantity resource.Quantity
-		var err error
-
-		// parse cpu
-		if len(jobInfo.Resources.Cpu) > 0 {
-			cpuNum := strings.TrimRight(jobInfo.Resources.Cpu, "cC")
-			cpuQuantity, err = resource.ParseQuantity(cpuNum)
-			if err != nil {
-				return nil, fmt.Errorf("parse cpu quantity error: %v", err)
-			}
-		}
-		// parse memory
-		if len(jobInfo.Resources.Memory) > 0 {
-			memoryQuantity, err = resource.ParseQuantity(jobInfo.Resources.Memory)
-			if err != nil {
-				return nil, fmt.Errorf("parse mem quantity error: %v", err)
-			}
-		}
-
-		task.Resources = execv1alpha1.ResourceRequirements{
-			Cpu:    cpuQuantity,
-			Memory: memoryQuantity,
+		task.Resources, err = TransResources2ExecResources(jobInfo.Resources)
+		if err != nil {
+			return nil, err
 		}
 
 		if jobInfo.Condition != nil {
@@ -351,6 +548,32 @@ func TransWorkflow2Execution(workflow *Workflow) (*execv1alpha1.Execution, error
 			task.GenericCondition = TransGenericCond2ExecGenericCond(jobInfo.GenericCondition)
 		}
 
+		task.SkipPolicy = TransSkipPolicy2ExecSkipPolicy(jobInfo.SkipPolicy)
+		task.InputArtifacts = TransArtifacts2ExecArtifacts(jobInfo.InputArtifacts)
+		task.OutputArtifacts = TransArtifacts2ExecArtifacts(jobInfo.OutputArtifacts)
+		task.Cache = jobInfo.Cache
+		task.InputDigests = jobInfo.InputDigests
+		TransPodOptions2ExecTask(jobInfo, &task)
+		task.InitContainers = TransContainers2ExecContainers(jobInfo.InitContainers, workflow.Volumes)
+		task.Sidecars = TransContainers2ExecContainers(jobInfo.Sidecars, workflow.Volumes)
+		task.Volcano = TransVolcano2ExecVolcano(jobInfo.Volcano)
+		task.ChunkSize = jobInfo.ChunkSize
+		task.Priority = jobInfo.Priority
+		if jobInfo.MemoryEscalation != nil {
+			task.MemoryEscalation, err = TransMemoryEscalation2ExecMemoryEscalation(*jobInfo.MemoryEscalation)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if jobInfo.EstimatedDuration != "" {
+			duration, _ := time.ParseDuration(jobInfo.EstimatedDuration)
+			task.EstimatedDuration = &metav1.Duration{Duration: duration}
+		}
+		if jobInfo.Indexed {
+			task.Type = execv1alpha1.IndexedJobTaskType
+		} else if jobInfo.Volcano != nil || workflow.Volcano != nil {
+			task.Type = execv1alpha1.VolcanoJobTaskType
+		}
 		task.Dependents = TransDepend2ExecDepend(jobInfo.Depends)
 		exec.Spec.Tasks = append(exec.Spec.Tasks, task)
 	}
@@ -19,6 +19,7 @@ package parser
 import (
 	"encoding/json"
 	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -157,7 +158,7 @@ outputs:
 `
 
 func TestValidateWorkflow(t *testing.T) {
-	data, _ := ioutil.ReadFile("../../../example/gatk4-practices/gatk4-practices.yaml")
+	data, _ := ioutil.ReadFile("../../example/gatk4-practices/gatk4-practices.yaml")
 	workflow, err := UnmarshalWorkflow(data)
 	if err != nil {
 		t.Fatalf("unmarshal workflow err: %v", err)
@@ -510,27 +511,27 @@ func TestValidateWorkflowSucc(t *testing.T) {
 	}{
 		{
 			Name:      "valid case 1",
-			path:      "../../../example/simple-sample/simple-sample.yaml",
+			path:      "../../example/simple-sample/simple-sample.yaml",
 			ExpectErr: false,
 		},
 		{
 			Name:      "valid case 2",
-			path:      "../../../example/simple-sample-getresult/simple-sample-getresult.yaml",
+			path:      "../../example/simple-sample-getresult/simple-sample-getresult.yaml",
 			ExpectErr: false,
 		},
 		{
 			Name:      "valid case 3",
-			path:      "../../../example/conditional-sample/simple-sample-chkresult.yaml",
+			path:      "../../example/conditional-sample/simple-sample-chkresult.yaml",
 			ExpectErr: false,
 		},
 		{
 			Name:      "valid case 4",
-			path:      "../../../example/conditional-getresult-combination/simple-sample-get-chkresult.yaml",
+			path:      "../../example/conditional-getresult-combination/simple-sample-get-chkresult.yaml",
 			ExpectErr: false,
 		},
 		{
 			Name:      "valid case 5",
-			path:      "../../../example/generic-condition/generic-condition-workflow.yaml",
+			path:      "../../example/generic-condition/generic-condition-workflow.yaml",
 			ExpectErr: false,
 		},
 	}
@@ -586,3 +587,82 @@ func TestValidateWorkflowSucc(t *testing.T) {
 	}
 
 }
+
+func TestExpandWorkflow(t *testing.T) {
+	workflowStr := version + `
+inputs:
+  obs-path:
+    default: /root
+    type: string
+  npart:
+    default: 2
+    type: number
+workflow:
+  job-gatk:
+    tool: GATK:4.0.1
+    resources:
+      memory: 8G
+      cpu: 2c
+    commands:
+    - ./gatk HaplotypeCallerSpark -R ${obs-path}/ref.2bit -n ${npart}
+`
+	testCases := []struct {
+		Name          string
+		Workflow      string
+		Inputs        map[string]interface{}
+		ExpectCommand string
+		ExpectErr     bool
+	}{
+		{
+			Name:          "defaults",
+			Workflow:      workflowStr,
+			ExpectCommand: "./gatk HaplotypeCallerSpark -R /root/ref.2bit -n 2",
+		},
+		{
+			Name:          "inputs",
+			Workflow:      workflowStr,
+			Inputs:        map[string]interface{}{"obs-path": "/data", "npart": 4},
+			ExpectCommand: "./gatk HaplotypeCallerSpark -R /data/ref.2bit -n 4",
+		},
+		{
+			Name:      "invalid input type",
+			Workflow:  workflowStr,
+			Inputs:    map[string]interface{}{"npart": "four"},
+			ExpectErr: true,
+		},
+		{
+			Name:      "unknown tool",
+			Workflow:  strings.Replace(workflowStr, "GATK:4.0.1", "GATK:3.8", 1),
+			ExpectErr: true,
+		},
+		{
+			Name:      "invalid workflow",
+			Workflow:  version,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		exec, err := ExpandWorkflow([]byte(testCase.Workflow), testCase.Inputs, makeTools(), nil)
+		if testCase.ExpectErr && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if !testCase.ExpectErr && err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+		}
+		if err != nil {
+			continue
+		}
+		if len(exec.Spec.Tasks) != 1 {
+			t.Errorf("%s: Expect 1 task, but got %d", testCase.Name, len(exec.Spec.Tasks))
+			continue
+		}
+		task := exec.Spec.Tasks[0]
+		if task.Image != "1.0.0.21:/root/GATK:4.0.1" {
+			t.Errorf("%s: Expect the image of the tool, but got %s", testCase.Name, task.Image)
+		}
+		if len(task.CommandSet) != 1 || task.CommandSet[0] != testCase.ExpectCommand {
+			t.Errorf("%s: Expect command %q, but got %v", testCase.Name, testCase.ExpectCommand, task.CommandSet)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/common"
+)
+
+func ValidatePodOptions(jobName string, job JobInfo) ErrorList {
+	errors := validateEnv(fmt.Sprintf("workflow.%s.env", jobName), job.Env)
+
+	for i, envFrom := range job.EnvFrom {
+		if (len(envFrom.Secret) == 0) == (len(envFrom.ConfigMap) == 0) {
+			errors = append(errors, fmt.Errorf("workflow.%s.env_from[%d]: one of secret, config_map should be specified", jobName, i))
+		}
+	}
+
+	if len(job.ServiceAccount) != 0 {
+		if msgs := validation.IsDNS1123Subdomain(job.ServiceAccount); len(msgs) > 0 {
+			errors = append(errors, fmt.Errorf("workflow.%s.service_account: %s is not valid %v", jobName, job.ServiceAccount, msgs))
+		}
+	}
+
+	for i, secret := range job.ImagePullSecrets {
+		if len(secret) == 0 {
+			errors = append(errors, fmt.Errorf("workflow.%s.image_pull_secrets[%d]: should not be empty", jobName, i))
+		}
+	}
+
+	switch v1.PullPolicy(job.ImagePullPolicy) {
+	case "", v1.PullAlways, v1.PullNever, v1.PullIfNotPresent:
+	default:
+		err := fmt.Errorf("workflow.%s.image_pull_policy should only be Always, Never or IfNotPresent", jobName)
+		errors = append(errors, err)
+	}
+	return errors
+}
+
+func validateEnv(field string, envs []EnvVar) ErrorList {
+	errors := ErrorList{}
+	for i, env := range envs {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		if msgs := validation.IsEnvVarName(env.Name); len(msgs) > 0 {
+			errors = append(errors, fmt.Errorf("%s.name: %s is not valid %v", prefix, env.Name, msgs))
+		}
+		sources := 0
+		if len(env.Value) != 0 {
+			sources++
+		}
+		if env.Secret != nil {
+			sources++
+			if len(env.Secret.Name) == 0 || len(env.Secret.Key) == 0 {
+				errors = append(errors, fmt.Errorf("%s.secret: name and key should not be empty", prefix))
+			}
+		}
+		if env.ConfigMap != nil {
+			sources++
+			if len(env.ConfigMap.Name) == 0 || len(env.ConfigMap.Key) == 0 {
+				errors = append(errors, fmt.Errorf("%s.config_map: name and key should not be empty", prefix))
+			}
+		}
+		if sources > 1 {
+			errors = append(errors, fmt.Errorf("%s: only one of value, secret, config_map can be specified", prefix))
+		}
+	}
+	return errors
+}
+
+// instantiateEnv populates the inputs in the values of the env.
+func instantiateEnv(env []EnvVar, data map[string]string) []EnvVar {
+	if env == nil {
+		return nil
+	}
+	result := make([]EnvVar, 0, len(env))
+	for _, e := range env {
+		e.Value = common.ReplaceVariant(e.Value, data)
+		result = append(result, e)
+	}
+	return result
+}
+
+func TransEnv2ExecEnv(envs []EnvVar) []v1.EnvVar {
+	var execEnv []v1.EnvVar
+	for _, env := range envs {
+		envVar := v1.EnvVar{Name: env.Name, Value: env.Value}
+		if env.Secret != nil {
+			envVar.ValueFrom = &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: env.Secret.Name},
+					Key:                  env.Secret.Key,
+				},
+			}
+		}
+		if env.ConfigMap != nil {
+			envVar.ValueFrom = &v1.EnvVarSource{
+				ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: env.ConfigMap.Name},
+					Key:                  env.ConfigMap.Key,
+				},
+			}
+		}
+		execEnv = append(execEnv, envVar)
+	}
+	return execEnv
+}
+
+func TransPodOptions2ExecTask(job JobInfo, task *execv1alpha1.Task) {
+	task.Env = TransEnv2ExecEnv(job.Env)
+	for _, envFrom := range job.EnvFrom {
+		source := v1.EnvFromSource{Prefix: envFrom.Prefix}
+		if len(envFrom.Secret) != 0 {
+			source.SecretRef = &v1.SecretEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: envFrom.Secret},
+			}
+		}
+		if len(envFrom.ConfigMap) != 0 {
+			source.ConfigMapRef = &v1.ConfigMapEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: envFrom.ConfigMap},
+			}
+		}
+		task.EnvFrom = append(task.EnvFrom, source)
+	}
+
+	task.ServiceAccountName = job.ServiceAccount
+	for _, secret := range job.ImagePullSecrets {
+		task.ImagePullSecrets = append(task.ImagePullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+	task.ImagePullPolicy = v1.PullPolicy(job.ImagePullPolicy)
+
+	if job.SecurityContext != nil {
+		task.SecurityContext = &v1.SecurityContext{
+			RunAsUser:              job.SecurityContext.RunAsUser,
+			RunAsGroup:             job.SecurityContext.RunAsGroup,
+			RunAsNonRoot:           job.SecurityContext.RunAsNonRoot,
+			Privileged:             job.SecurityContext.Privileged,
+			ReadOnlyRootFilesystem: job.SecurityContext.ReadOnlyRootFilesystem,
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestValidatePodOptions(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Job       string
+		ExpectErr bool
+	}{
+		{
+			Name: "valid pod options",
+			Job: `
+env:
+  - name: SAMPLE
+    value: ${sample}
+  - name: PASSWORD
+    secret:
+      name: db
+      key: password
+env_from:
+  - config_map: settings
+    prefix: GENE_
+service_account: gene
+image_pull_secrets:
+  - registry
+image_pull_policy: Always
+security_context:
+  run_as_user: 1000`,
+			ExpectErr: false,
+		},
+		{
+			Name: "workflow.job-a.env[0].name: is not valid",
+			Job: `
+env:
+  - name: 1SAMPLE
+    value: a`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.env[0]: only one of value, secret, config_map can be specified",
+			Job: `
+env:
+  - name: PASSWORD
+    value: a
+    secret:
+      name: db
+      key: password`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.env_from[0]: one of secret, config_map should be specified",
+			Job: `
+env_from:
+  - secret: creds
+    config_map: settings`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.service_account: is not valid",
+			Job: `
+service_account: Gene_SA`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.image_pull_policy should only be Always, Never or IfNotPresent",
+			Job: `
+image_pull_policy: always`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var job JobInfo
+		if err := yaml.Unmarshal([]byte(testCase.Job), &job); err != nil {
+			t.Fatalf("%s: unexpected error: %v", testCase.Name, err)
+		}
+		err := ValidatePodOptions("job-a", job)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestTransPodOptions2ExecTask(t *testing.T) {
+	job := JobInfo{
+		Env: []EnvVar{
+			{Name: "SAMPLE", Value: "a"},
+			{Name: "PASSWORD", Secret: &SecretKey{Name: "db", Key: "password"}},
+		},
+		EnvFrom:          []EnvFromSource{{ConfigMap: "settings"}},
+		ServiceAccount:   "gene",
+		ImagePullSecrets: []string{"registry"},
+		ImagePullPolicy:  "Always",
+	}
+	task := execv1alpha1.Task{}
+	TransPodOptions2ExecTask(job, &task)
+
+	if len(task.Env) != 2 || task.Env[0].Value != "a" || task.Env[1].ValueFrom.SecretKeyRef.Key != "password" {
+		t.Errorf("Expect env from the job, but got %v", task.Env)
+	}
+	if len(task.EnvFrom) != 1 || task.EnvFrom[0].ConfigMapRef.Name != "settings" {
+		t.Errorf("Expect envFrom from the job, but got %v", task.EnvFrom)
+	}
+	if task.ServiceAccountName != "gene" || task.ImagePullPolicy != v1.PullAlways {
+		t.Errorf("Expect serviceAccountName gene and imagePullPolicy Always, but got %s and %s", task.ServiceAccountName, task.ImagePullPolicy)
+	}
+	if len(task.ImagePullSecrets) != 1 || task.ImagePullSecrets[0].Name != "registry" {
+		t.Errorf("Expect imagePullSecrets from the job, but got %v", task.ImagePullSecrets)
+	}
+}
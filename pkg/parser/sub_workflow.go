@@ -0,0 +1,335 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"kubegene.io/kubegene/pkg/common"
+)
+
+// MaxSubWorkflowDepth is the max number of levels of nested sub workflows,
+// which also stops a workflow running itself.
+const MaxSubWorkflowDepth = 5
+
+// WorkflowLoader returns the workflow of a sub workflow.
+type WorkflowLoader func(sub *SubWorkflow) ([]byte, error)
+
+// ValidateSubWorkflow validates the job running a sub workflow.
+func ValidateSubWorkflow(jobName string, job JobInfo, inputs map[string]Input) ErrorList {
+	errors := ErrorList{}
+	prefix := fmt.Sprintf("workflow.%s.sub_workflow", jobName)
+	sub := job.SubWorkflow
+
+	rest := job
+	rest.Description = ""
+	rest.Depends = nil
+	rest.SubWorkflow = nil
+	if !reflect.DeepEqual(rest, JobInfo{}) {
+		errors = append(errors, fmt.Errorf("workflow.%s: a job with a sub workflow can only have description and depends besides", jobName))
+	}
+
+	if (len(sub.Path) == 0) == (len(sub.Template) == 0) {
+		errors = append(errors, fmt.Errorf("%s: one of path and template is required", prefix))
+	}
+
+	if sub.Scatter != nil {
+		if len(sub.Scatter.Input) == 0 {
+			errors = append(errors, fmt.Errorf("%s.scatter.input is required", prefix))
+		}
+		switch values := sub.Scatter.Values.(type) {
+		case []interface{}:
+		case string:
+			if !IsVariant(values) {
+				errors = append(errors, fmt.Errorf("%s.scatter.values: should be an array or ${input}, but the real one is %s", prefix, values))
+				break
+			}
+			name := GetVariantName(values)
+			if input, ok := inputs[name]; !ok || input.Type != ArrayType {
+				errors = append(errors, fmt.Errorf("%s.scatter.values: %s is not an array input", prefix, name))
+			}
+		default:
+			errors = append(errors, fmt.Errorf("%s.scatter.values: should be an array or ${input}, but the real one is %v", prefix, values))
+		}
+	}
+	return errors
+}
+
+// instantiateSubWorkflow returns the sub workflow with the values of the
+// inputs of the parent workflow, and the values of its scatter.
+func instantiateSubWorkflow(sub *SubWorkflow, inputs map[string]Input, data map[string]string) *SubWorkflow {
+	instance := *sub
+
+	instance.Inputs = make(map[string]interface{}, len(sub.Inputs))
+	for key, value := range sub.Inputs {
+		instance.Inputs[key] = instantiateSubWorkflowValue(value, inputs, data)
+	}
+
+	if sub.Scatter != nil {
+		scatter := *sub.Scatter
+		scatter.Values = instantiateSubWorkflowValue(scatter.Values, inputs, data)
+		instance.Scatter = &scatter
+	}
+	return &instance
+}
+
+// instantiateSubWorkflowValue returns the value of the input of the parent
+// workflow if the value is ${input}, so that it keeps its type.
+func instantiateSubWorkflowValue(value interface{}, inputs map[string]Input, data map[string]string) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if IsVariant(str) {
+		if input, ok := inputs[GetVariantName(str)]; ok {
+			return input.Value
+		}
+	}
+	return common.ReplaceVariant(str, data)
+}
+
+// ExpandSubWorkflows replaces the jobs running a sub workflow of the
+// instantiated workflow with the jobs of the sub workflow.
+func ExpandSubWorkflows(workflow *Workflow, tools map[string]Tool, load WorkflowLoader) error {
+	return expandSubWorkflows(workflow, tools, load, 0)
+}
+
+func expandSubWorkflows(workflow *Workflow, tools map[string]Tool, load WorkflowLoader, depth int) error {
+	subJobNames := make([]string, 0)
+	for jobName, job := range workflow.Jobs {
+		if job.SubWorkflow != nil {
+			subJobNames = append(subJobNames, jobName)
+		}
+	}
+	if len(subJobNames) == 0 {
+		return nil
+	}
+	if depth >= MaxSubWorkflowDepth {
+		return fmt.Errorf("sub workflows are nested more than %d levels", MaxSubWorkflowDepth)
+	}
+	if load == nil {
+		return fmt.Errorf("sub workflows are not supported")
+	}
+	sort.Strings(subJobNames)
+
+	// leaves are the jobs no other job of the sub workflows depends on,
+	// keyed by the job running the sub workflow.
+	leaves := make(map[string][]string, len(subJobNames))
+	for _, jobName := range subJobNames {
+		job := workflow.Jobs[jobName]
+		delete(workflow.Jobs, jobName)
+		leaves[jobName] = []string{}
+
+		prefix := fmt.Sprintf("workflow.%s.sub_workflow", jobName)
+		data, err := load(job.SubWorkflow)
+		if err != nil {
+			return fmt.Errorf("%s: load workflow error: %v", prefix, err)
+		}
+
+		instances, err := subWorkflowInstances(jobName, job.SubWorkflow)
+		if err != nil {
+			return fmt.Errorf("%s: %v", prefix, err)
+		}
+		for _, instance := range instances {
+			subWorkflow, err := UnmarshalWorkflow(data)
+			if err != nil {
+				return fmt.Errorf("%s: %v", prefix, err)
+			}
+			SetDefaultWorkflow(subWorkflow)
+			if errList := ValidateWorkflow(subWorkflow); len(errList) > 0 {
+				return fmt.Errorf("%s: invalid workflow: %s", prefix, joinErrors(errList))
+			}
+			if err := InstantiateWorkflow(subWorkflow, instance.inputs, tools); err != nil {
+				return fmt.Errorf("%s: %v", prefix, err)
+			}
+			if err := expandSubWorkflows(subWorkflow, tools, load, depth+1); err != nil {
+				return fmt.Errorf("%s: %v", prefix, err)
+			}
+
+			jobLeaves, err := mergeSubWorkflow(workflow, subWorkflow, instance.name, job.Depends)
+			if err != nil {
+				return fmt.Errorf("%s: %v", prefix, err)
+			}
+			leaves[jobName] = append(leaves[jobName], jobLeaves...)
+		}
+	}
+
+	// the jobs depending on a job running a sub workflow depend on the
+	// leaves of its sub workflows instead.
+	for jobName, job := range workflow.Jobs {
+		var depends []Depend
+		for _, depend := range job.Depends {
+			jobLeaves, ok := leaves[depend.Target]
+			if !ok {
+				depends = append(depends, depend)
+				continue
+			}
+			if depend.Type == IterateDependType {
+				return fmt.Errorf("workflow.%s: can not depend on the sub workflow of job %s by iterate", jobName, depend.Target)
+			}
+			for _, leaf := range jobLeaves {
+				depends = append(depends, Depend{Target: leaf, Type: WholeDependType})
+			}
+		}
+		job.Depends = depends
+
+		var err error
+		mapJobRefs(&job, func(ref string) string {
+			if _, ok := leaves[ref]; ok && err == nil {
+				err = fmt.Errorf("workflow.%s: can not refer to the result of the sub workflow of job %s", jobName, ref)
+			}
+			return ref
+		})
+		if err != nil {
+			return err
+		}
+		workflow.Jobs[jobName] = job
+	}
+	return nil
+}
+
+type subWorkflowInstance struct {
+	// name is the prefix of the names of the jobs of the instance.
+	name   string
+	inputs map[string]interface{}
+}
+
+// subWorkflowInstances returns an instance of the sub workflow for every
+// value of its scatter, or a single one without scatter.
+func subWorkflowInstances(jobName string, sub *SubWorkflow) ([]subWorkflowInstance, error) {
+	if sub.Scatter == nil {
+		return []subWorkflowInstance{{name: jobName, inputs: sub.Inputs}}, nil
+	}
+
+	values := reflect.ValueOf(sub.Scatter.Values)
+	if values.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("scatter values should be an array, but the real one is %v", sub.Scatter.Values)
+	}
+	instances := make([]subWorkflowInstance, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		inputs := make(map[string]interface{}, len(sub.Inputs)+1)
+		for key, value := range sub.Inputs {
+			inputs[key] = value
+		}
+		inputs[sub.Scatter.Input] = values.Index(i).Interface()
+		instances = append(instances, subWorkflowInstance{name: jobName + "-" + strconv.Itoa(i), inputs: inputs})
+	}
+	return instances, nil
+}
+
+// mergeSubWorkflow adds the jobs, volumes and outputs of the expanded sub
+// workflow to the workflow, and returns the jobs no other job of the sub
+// workflow depends on.
+func mergeSubWorkflow(workflow, subWorkflow *Workflow, name string, depends []Depend) ([]string, error) {
+	rename := func(jobName string) string {
+		return name + "-" + jobName
+	}
+
+	dependedOn := make(map[string]bool, len(subWorkflow.Jobs))
+	for _, job := range subWorkflow.Jobs {
+		for _, depend := range job.Depends {
+			dependedOn[depend.Target] = true
+		}
+	}
+
+	leaves := make([]string, 0)
+	for jobName, job := range subWorkflow.Jobs {
+		newName := rename(jobName)
+		if msgs := validation.IsDNS1123Label(newName); len(msgs) > 0 {
+			return nil, fmt.Errorf("job name %s is not valid: %s", newName, strings.Join(msgs, ", "))
+		}
+		if _, ok := workflow.Jobs[newName]; ok {
+			return nil, fmt.Errorf("job %s already exists", newName)
+		}
+
+		mapJobRefs(&job, rename)
+		if len(job.Depends) == 0 {
+			job.Depends = append([]Depend(nil), depends...)
+		}
+		workflow.Jobs[newName] = job
+
+		if !dependedOn[jobName] {
+			leaves = append(leaves, newName)
+		}
+	}
+	sort.Strings(leaves)
+
+	for volumeName, volume := range subWorkflow.Volumes {
+		if existing, ok := workflow.Volumes[volumeName]; ok && !reflect.DeepEqual(existing, volume) {
+			return nil, fmt.Errorf("volume %s differs from the one of the parent workflow", volumeName)
+		}
+		if workflow.Volumes == nil {
+			workflow.Volumes = make(map[string]Volume)
+		}
+		workflow.Volumes[volumeName] = volume
+	}
+
+	for outputName, output := range subWorkflow.Outputs {
+		if workflow.Outputs == nil {
+			workflow.Outputs = make(map[string]OutputDesc)
+		}
+		workflow.Outputs[name+"-"+outputName] = output
+	}
+	return leaves, nil
+}
+
+// mapJobRefs replaces the names of the jobs the instantiated job refers to,
+// in its depends, condition, expression, get_result functions and input
+// artifacts.
+func mapJobRefs(job *JobInfo, mapping func(string) string) {
+	for i := range job.Depends {
+		job.Depends[i].Target = mapping(job.Depends[i].Target)
+	}
+
+	if cond, ok := job.Condition.(common.Var); ok && len(cond) == 3 && cond[0] == "check_result" {
+		if jobName, ok := cond[1].(string); ok {
+			cond[1] = mapping(jobName)
+		}
+	}
+	if cond, ok := job.Condition.(common.Var); ok && len(cond) == 2 && cond[0] == "expression" {
+		if expression, ok := cond[1].(string); ok {
+			cond[1] = mapExpressionResults(expression, mapping)
+		}
+	}
+
+	for _, v := range job.CommandsIter.VarsIter {
+		if getResult, ok := v.(common.Var); ok && len(getResult) == 3 && getResult[0] == "get_result" {
+			if jobName, ok := getResult[1].(string); ok {
+				getResult[1] = mapping(jobName)
+			}
+		}
+	}
+
+	if job.GenericCondition != nil {
+		genericCondition := *job.GenericCondition
+		genericCondition.DependJobName = mapping(genericCondition.DependJobName)
+		job.GenericCondition = &genericCondition
+	}
+
+	for i, artifact := range job.InputArtifacts {
+		source := strings.SplitN(artifact.From, ".", 2)
+		if len(source) == 2 {
+			job.InputArtifacts[i].From = mapping(source[0]) + "." + source[1]
+		}
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var germlineWorkflow = version + `
+inputs:
+  sample:
+    type: string
+  reference:
+    type: string
+workflow:
+  align:
+    tool: bwa:0.71r
+    commands:
+    - bwa mem ${reference} ${sample}.fastq
+  call:
+    tool: GATK:4.0.1
+    commands:
+    - gatk HaplotypeCaller ${sample}.bam
+    depends:
+    - target: align
+      type: whole
+`
+
+var cohortWorkflow = version + `
+inputs:
+  samples:
+    default: [s1, s2]
+    type: array
+  reference:
+    default: /ref/hg19.fa
+    type: string
+workflow:
+  prepare:
+    tool: bwa:0.71r
+    commands:
+    - bwa index ${reference}
+  germline:
+    sub_workflow:
+      template: germline
+      inputs:
+        reference: ${reference}
+      scatter:
+        input: sample
+        values: ${samples}
+    depends:
+    - target: prepare
+      type: whole
+  report:
+    tool: zsplit:0.2
+    commands:
+    - report
+    depends:
+    - target: germline
+      type: whole
+`
+
+func expandTestWorkflow(data string, load WorkflowLoader) (*Workflow, error) {
+	workflow, err := UnmarshalWorkflow([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	SetDefaultWorkflow(workflow)
+	if errList := ValidateWorkflow(workflow); len(errList) > 0 {
+		return nil, fmt.Errorf("invalid workflow: %s", joinErrors(errList))
+	}
+	if err := InstantiateWorkflow(workflow, nil, makeTools()); err != nil {
+		return nil, err
+	}
+	if err := ExpandSubWorkflows(workflow, makeTools(), load); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+func templateLoader(templates map[string]string) WorkflowLoader {
+	return func(sub *SubWorkflow) ([]byte, error) {
+		workflow, ok := templates[sub.Template]
+		if !ok {
+			return nil, fmt.Errorf("workflow template %s not found", sub.Template)
+		}
+		return []byte(workflow), nil
+	}
+}
+
+// jobDepends returns the targets of the depends of the jobs of the workflow.
+func jobDepends(workflow *Workflow) map[string][]string {
+	depends := make(map[string][]string, len(workflow.Jobs))
+	for jobName, job := range workflow.Jobs {
+		targets := []string{}
+		for _, depend := range job.Depends {
+			targets = append(targets, depend.Target)
+		}
+		sort.Strings(targets)
+		depends[jobName] = targets
+	}
+	return depends
+}
+
+func TestExpandSubWorkflows(t *testing.T) {
+	load := templateLoader(map[string]string{"germline": germlineWorkflow})
+
+	testCases := []struct {
+		Name          string
+		Workflow      string
+		Load          WorkflowLoader
+		ExpectDepends map[string][]string
+		ExpectErr     bool
+	}{
+		{
+			Name:     "scatter",
+			Workflow: cohortWorkflow,
+			Load:     load,
+			ExpectDepends: map[string][]string{
+				"prepare":          {},
+				"germline-0-align": {"prepare"},
+				"germline-0-call":  {"germline-0-align"},
+				"germline-1-align": {"prepare"},
+				"germline-1-call":  {"germline-1-align"},
+				"report":           {"germline-0-call", "germline-1-call"},
+			},
+		},
+		{
+			Name: "no scatter",
+			Workflow: strings.Replace(cohortWorkflow, `      scatter:
+        input: sample
+        values: ${samples}
+`, `        sample: s3
+`, 1),
+			Load: load,
+			ExpectDepends: map[string][]string{
+				"prepare":        {},
+				"germline-align": {"prepare"},
+				"germline-call":  {"germline-align"},
+				"report":         {"germline-call"},
+			},
+		},
+		{
+			Name:     "nested",
+			Workflow: strings.Replace(cohortWorkflow, "template: germline", "template: single", 1),
+			Load: templateLoader(map[string]string{
+				"germline": germlineWorkflow,
+				"single": version + `
+inputs:
+  sample:
+    type: string
+  reference:
+    type: string
+workflow:
+  run:
+    sub_workflow:
+      template: germline
+      inputs:
+        sample: ${sample}
+        reference: ${reference}
+`,
+			}),
+			ExpectDepends: map[string][]string{
+				"prepare":              {},
+				"germline-0-run-align": {"prepare"},
+				"germline-0-run-call":  {"germline-0-run-align"},
+				"germline-1-run-align": {"prepare"},
+				"germline-1-run-call":  {"germline-1-run-align"},
+				"report":               {"germline-0-run-call", "germline-1-run-call"},
+			},
+		},
+		{
+			Name:      "recursive",
+			Workflow:  cohortWorkflow,
+			Load:      templateLoader(map[string]string{"germline": cohortWorkflow}),
+			ExpectErr: true,
+		},
+		{
+			Name:      "no loader",
+			Workflow:  cohortWorkflow,
+			ExpectErr: true,
+		},
+		{
+			Name:      "template not found",
+			Workflow:  cohortWorkflow,
+			Load:      templateLoader(nil),
+			ExpectErr: true,
+		},
+		{
+			Name:      "depend by iterate",
+			Workflow:  strings.Replace(cohortWorkflow, "target: germline\n      type: whole", "target: germline\n      type: iterate", 1),
+			Load:      load,
+			ExpectErr: true,
+		},
+		{
+			Name:      "result of a sub workflow",
+			Workflow:  strings.Replace(cohortWorkflow, "    - report\n", "    - report\n    condition: check_result(germline, \"ok\")\n", 1),
+			Load:      load,
+			ExpectErr: true,
+		},
+		{
+			Name:      "path and template",
+			Workflow:  strings.Replace(cohortWorkflow, "template: germline", "template: germline\n      path: germline.yaml", 1),
+			Load:      load,
+			ExpectErr: true,
+		},
+		{
+			Name:      "scatter over a string input",
+			Workflow:  strings.Replace(cohortWorkflow, "values: ${samples}", "values: ${reference}", 1),
+			Load:      load,
+			ExpectErr: true,
+		},
+		{
+			Name:      "sub workflow with commands",
+			Workflow:  strings.Replace(cohortWorkflow, "  germline:\n", "  germline:\n    commands:\n    - echo\n", 1),
+			Load:      load,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		workflow, err := expandTestWorkflow(testCase.Workflow, testCase.Load)
+		if testCase.ExpectErr && err == nil {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if !testCase.ExpectErr && err != nil {
+			t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+		}
+		if err != nil {
+			continue
+		}
+		if depends := jobDepends(workflow); !reflect.DeepEqual(depends, testCase.ExpectDepends) {
+			t.Errorf("%s: Expect depends %v, but got %v", testCase.Name, testCase.ExpectDepends, depends)
+		}
+	}
+}
+
+func TestExpandSubWorkflowsInputs(t *testing.T) {
+	load := templateLoader(map[string]string{"germline": germlineWorkflow})
+	workflow, err := expandTestWorkflow(cohortWorkflow, load)
+	if err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+
+	expectCommands := map[string][]string{
+		"germline-0-align": {"bwa mem /ref/hg19.fa s1.fastq"},
+		"germline-1-call":  {"gatk HaplotypeCaller s2.bam"},
+	}
+	for jobName, expectCommand := range expectCommands {
+		if commands := workflow.Jobs[jobName].Commands; !reflect.DeepEqual(commands, expectCommand) {
+			t.Errorf("%s: expect commands %v, but got %v", jobName, expectCommand, commands)
+		}
+	}
+	if image := workflow.Jobs["germline-1-call"].Image; image != "1.0.0.21:/root/GATK:4.0.1" {
+		t.Errorf("expect the image of the tool of the sub workflow, but got %s", image)
+	}
+}
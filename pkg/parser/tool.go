@@ -28,14 +28,33 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/spf13/cobra"
 	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
-	"kubegene.io/kubegene/cmd/genectl/util"
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
 )
 
-func FetchTools(cmd *cobra.Command) (map[string]Tool, error) {
-	toolRepo := util.GetFlagString(cmd, "tool-repo")
+// TransClusterTools2Map returns the valid tools of the cluster keyed by name:version.
+func TransClusterTools2Map(clusterTools []*execv1alpha1.Tool) map[string]Tool {
+	tools := make([]Tool, 0, len(clusterTools))
+	for _, clusterTool := range clusterTools {
+		tool := Tool{
+			Name:        clusterTool.Spec.Name,
+			Version:     clusterTool.Spec.Version,
+			Image:       clusterTool.Spec.Image,
+			Command:     clusterTool.Spec.Command,
+			Type:        clusterTool.Spec.Type,
+			Description: clusterTool.Spec.Description,
+		}
+		if ValidateToolAttr(tool) != nil {
+			continue
+		}
+		tools = append(tools, tool)
+	}
+	return TransTools2Map(tools)
+}
 
+// LoadTools reads the tools of the tool repo, a directory of tool files or
+// the URL of a tool file.
+func LoadTools(toolRepo string) (map[string]Tool, error) {
 	// remote tool repo
 	if strings.Index(toolRepo, "http://") == 0 || strings.Index(toolRepo, "https://") == 0 {
 		bytes, err := DownloadToolFile(toolRepo)
@@ -51,7 +70,7 @@ func FetchTools(cmd *cobra.Command) (map[string]Tool, error) {
 	}
 
 	// local tool repo
-	if flag, err := util.PathExists(toolRepo); !flag {
+	if flag, err := pathExists(toolRepo); !flag {
 		return nil, fmt.Errorf("tool repo path %v is not exist: %v", toolRepo, err)
 	}
 
@@ -156,3 +175,14 @@ func DownloadToolFile(url string) ([]byte, error) {
 	// ready body
 	return ioutil.ReadAll(resp.Body)
 }
+
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func TestTransClusterTools2Map(t *testing.T) {
+	clusterTools := []*execv1alpha1.Tool{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gatk-4.0.1"},
+			Spec: execv1alpha1.ToolSpec{
+				Name:    "GATK",
+				Version: "4.0.1",
+				Image:   "gatk:4.0.1",
+				Command: "gatk --help",
+				Type:    "basic",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bwa"},
+			Spec:       execv1alpha1.ToolSpec{Name: "bwa", Version: "0.7.17"},
+		},
+	}
+
+	expectTools := map[string]Tool{
+		"GATK:4.0.1": {Name: "GATK", Version: "4.0.1", Image: "gatk:4.0.1", Command: "gatk --help", Type: "basic"},
+	}
+	tools := TransClusterTools2Map(clusterTools)
+	if !reflect.DeepEqual(tools, expectTools) {
+		t.Errorf("expect tools %v, but got %v", expectTools, tools)
+	}
+}
@@ -0,0 +1,636 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+const (
+	StringType = "string"
+	NumberType = "number"
+	BoolType   = "bool"
+	ArrayType  = "array"
+)
+
+var InputTypeList = []string{StringType, NumberType, BoolType, ArrayType}
+
+// Tool is an abstraction of the gene sequencing container. It contains the basic information
+// about a gene sequencing container. such as image, version, description and so on. When we
+// use it in gene sequencing workflows, we can simply specify the tool as Name:version, and the
+// real image and other information will be replaced when running.
+//
+// tool example
+//
+// Name: GATK
+// version: 4.0.1
+// image: 1.0.0.21:/root/GATK:4.0.1
+// command: gatk hello world
+// type: basic
+// description: software package to analyze next-generation sequencing data
+//
+// use example
+//
+// job-GATK:
+//   tool: GATK:4.0.1
+//   resources:
+//     memory: 2G
+//     cpu: 2C
+//   command:
+//     - sh ${obs-path}/${jobid}/bwa_mem.sh obs/path/sample1.fastq.gz obs/path/hg19.fa >obs/path/sample1.sam
+//     - sh ${obs-path}/${jobid}/bwa_mem.sh obs/path/sample1.fastq.gz obs/path/hg19.fa >obs/path/sample2.sam
+//
+// the final workflows job
+//
+// job-GATK:
+//   tool: GATK:4.0.1
+//   image: 1.0.0.21:/root/GATK:4.0.1
+//   resources:
+//     memory: 2G
+//     cpu: 2C
+//   command:
+//     - sh ${obs-path}/${jobid}/bwa_mem.sh obs/path/sample1.fastq.gz obs/path/hg19.fa >obs/path/sample1.sam
+//     - sh ${obs-path}/${jobid}/bwa_mem.sh obs/path/sample1.fastq.gz obs/path/hg19.fa >obs/path/sample2.sam
+type Tool struct {
+	// The Name of tool.
+	// Required.
+	Name string `json:"Name" yaml:"Name"`
+	// The version of tool.
+	// Required.
+	Version string `json:"version" yaml:"version"`
+	// Docker image Name.
+	// Required.
+	Image string `json:"image" yaml:"image"`
+	// Command is the task that will be run for gene sequencing.
+	// If set, it will append to workflows commands.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// the type of tool.
+	Type string `json:"type" yaml:"type"`
+	// Description describes what the tool is used for.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Input defines input parameter that used for gene sequencing.
+//
+// input example
+//
+// sample:
+//   default: /sample/data
+//   description: the path that stores the sample data.
+//   type: string
+type Input struct {
+	// Default is the default value that will be used for an input parameter
+	// if a value was not provided.
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Value is the literal value to use for the parameter.
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Description is the information about what the parameter is used for.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Type defines the type for the input parameter.
+	// One of string, number, bool, array.
+	// The default type is string if not specified.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Compute Resources required by this container.
+type Resources struct {
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Cpu    string `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+}
+
+// CommandsIter defines command for workflows job. If both Vars and Vars_iter are specified,
+// the generate command will be merged.
+type CommandsIter struct {
+	// Command is the base command that contains variables.
+	Command string `json:"command" yaml:"command"`
+
+	// Vars list all the parameters for the command.
+	//
+	// commandsIter example
+	//
+	//    commands_iter:
+	//      command: sh /tmp/scripts/step1.splitfq.sh ${1} ${2} /tmp/data ${3}
+	//      vars:
+	//        - ["sample1", 0, 25] # Each member of the array will be the ${1}, ${2}, ${3}
+	//        - ["sample2", 0, 25]
+	//        - ["sample1", 1, 25]
+	//        - ["sample2", 1, 25]
+	//
+	// then the final command will be:
+	//
+	// sh /tmp/scripts/step1.splitfq.sh sample1 0 ${sample-path} 25
+	// sh /tmp/scripts/step1.splitfq.sh sample2 0 ${sample-path} 25
+	// sh /tmp/scripts/step1.splitfq.sh sample1 1 ${sample-path} 25
+	// sh /tmp/scripts/step1.splitfq.sh sample2 1 ${sample-path} 25
+	Vars []interface{} `json:"vars,omitempty" yaml:"vars,omitempty"`
+
+	// VarsIter list all the possible parameters for every position in the command line.
+	// And we will use algorithm Of Full Permutation to generate all the permutation and
+	// combinations for these parameter that will be used to replace the ${number} variable.
+	//
+	// commandsIter example
+	//
+	//    commands_iter:
+	//      command: sh /tmp/scripts/step1.splitfq.sh ${1} ${2} /tmp/data ${3}
+	//      vars_iter:
+	//        - ["sample1", "sample2"]
+	//        - [0, 1]
+	//        - [25]
+	//
+	// then the final command will be:
+	//
+	// sh /tmp/scripts/step1.splitfq.sh sample1 0 /tmp/data 25
+	// sh /tmp/scripts/step1.splitfq.sh sample2 0 /tmp/data 25
+	// sh /tmp/scripts/step1.splitfq.sh sample1 1 /tmp/data 25
+	// sh /tmp/scripts/step1.splitfq.sh sample2 1 /tmp/data 25
+	VarsIter []interface{} `json:"vars_iter,omitempty" yaml:"vars_iter,omitempty"`
+}
+
+const (
+	WholeDependType   = "whole"
+	IterateDependType = "iterate"
+)
+
+const (
+	ContinueSkipPolicy = "continue"
+	CascadeSkipPolicy  = "cascade"
+)
+
+// MaxChunkSize is the max number of commands run by one Kubernetes Job of a job.
+const MaxChunkSize = 256
+
+type Depend struct {
+	// Target is the Name of job this depends on.
+	Target string `json:"target" yaml:"target"`
+
+	// Type is the depends type.
+	// One of whole, iterate
+	// Default to `whole`.
+	//
+	// Examples:
+	//
+	// "whole" - A-->B": jobs of B depends on all jobs of A execution done.
+	// "iterate" - A[1,2,3]-->B[1,2,3]: jobs of B depends on jobs of A one by one.
+	// That is to say: A[1]->B[1], A[2]->B[2], A[3]->B[3]
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// A match  operator is the set of operators that can be used in
+// a MatchRequirement.
+type MatchOperator string
+
+const (
+	MatchOperatorOpIn           MatchOperator = "In"
+	MatchOperatorOpNotIn        MatchOperator = "NotIn"
+	MatchOperatorOpExists       MatchOperator = "Exists"
+	MatchOperatorOpDoesNotExist MatchOperator = "DoesNotExist"
+	MatchOperatorOpGt           MatchOperator = "Gt"
+	MatchOperatorOpLt           MatchOperator = "Lt"
+	MatchOperatorOpEqual        MatchOperator = "="
+	MatchOperatorOpNotEqual     MatchOperator = "!="
+	MatchOperatorOpDoubleEqual  MatchOperator = "=="
+)
+
+// A matching rule is a requirement that contains values, a key, and an operator
+// that relates the key and values.
+type MatchRule struct {
+	// The key that the requirement applies to.
+	Key string `json:"key" yaml:"key"`
+	// Represents a key's relationship to a set of values.
+	// Valid operators are In, NotIn, Exists, DoesNotExist. Gt, and Lt.
+	Operator MatchOperator `json:"operator" yaml:"operator"`
+	// An array of string values. If the operator is In or NotIn,
+	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
+	// the values array must be empty. If the operator is Gt or Lt, the values
+	// array must have a single element, which will be interpreted as an integer.
+	// +optional
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// generic Conditional dynamic handling match rules are ORed.
+type GenericCondition struct {
+	// when we use generic condition this DependJobName should be same as jobName in Depends
+	// and Depends should have only one JobName
+	DependJobName string      `json:"depend_job_name" yaml:"depend_job_name"`
+	MatchRules    []MatchRule `json:"match_rules" yaml:"match_rules"`
+}
+
+// job information.
+type JobInfo struct {
+	// Description describes what this job is to do.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// tool Name
+	Tool string `json:"tool" yaml:"tool"`
+	// docker image Name.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// Compute Resources required by this job.
+	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// command to run for gene sequencing.
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// CommandsIter defines batch command for workflows job.
+	CommandsIter CommandsIter `json:"commands_iter,omitempty" yaml:"commands_iter,omitempty"`
+	// Depends is the Name of task this depends on.
+	Depends []Depend `json:"depends,omitempty" yaml:"depends,omitempty"`
+	// conditional branch handling
+	Condition interface{} `json:"condition,omitempty" yaml:"condition,omitempty"`
+
+	// generic conditional handling using the match rules are ORed.
+	GenericCondition *GenericCondition `json:"generic_condition,omitempty" yaml:"generic_condition,omitempty"`
+
+	// SkipPolicy decides what happens to the jobs depending on this job
+	// when this job is skipped because its condition is not satisfied.
+	// One of continue, cascade.
+	// Default to `continue`.
+	SkipPolicy string `json:"skip_policy,omitempty" yaml:"skip_policy,omitempty"`
+
+	// InputArtifacts are fetched from the artifact repository before the job runs.
+	InputArtifacts []Artifact `json:"input_artifacts,omitempty" yaml:"input_artifacts,omitempty"`
+
+	// OutputArtifacts are uploaded to the artifact repository after the job succeeds.
+	OutputArtifacts []Artifact `json:"output_artifacts,omitempty" yaml:"output_artifacts,omitempty"`
+
+	// Cache reuses the result of an identical job which has already succeeded.
+	// Overrides the cache of the workflow.
+	Cache *bool `json:"cache,omitempty" yaml:"cache,omitempty"`
+
+	// InputDigests identify the content of the data read by the job, e.g. the
+	// digests of its input files. A cached result is only reused while they
+	// are the same. The cache of the workflow does not apply to the jobs
+	// reading volumes without them.
+	InputDigests []string `json:"input_digests,omitempty" yaml:"input_digests,omitempty"`
+
+	// Env is the environment variables set in the job container.
+	Env []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// EnvFrom populates environment variables in the job container from secrets or configmaps.
+	EnvFrom []EnvFromSource `json:"env_from,omitempty" yaml:"env_from,omitempty"`
+
+	// ServiceAccount is the name of the ServiceAccount to run the job pods.
+	ServiceAccount string `json:"service_account,omitempty" yaml:"service_account,omitempty"`
+
+	// ImagePullSecrets are the names of the secrets used to pull the image.
+	ImagePullSecrets []string `json:"image_pull_secrets,omitempty" yaml:"image_pull_secrets,omitempty"`
+
+	// ImagePullPolicy of the image, one of Always, Never, IfNotPresent.
+	// Default to `IfNotPresent`.
+	ImagePullPolicy string `json:"image_pull_policy,omitempty" yaml:"image_pull_policy,omitempty"`
+
+	// SecurityContext is the security options the job container runs with.
+	SecurityContext *SecurityContext `json:"security_context,omitempty" yaml:"security_context,omitempty"`
+
+	// Volumes are the volumes of the workflow mounted in the job.
+	// Default to all the volumes of the workflow.
+	Volumes []JobVolume `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// InitContainers run in order before the job command, e.g. to download a reference.
+	InitContainers []Container `json:"init_containers,omitempty" yaml:"init_containers,omitempty"`
+
+	// Sidecars run next to the job command, e.g. a log shipper, and are
+	// stopped once the job command succeeds, or once it fails in a pod
+	// which does not run it again.
+	Sidecars []Container `json:"sidecars,omitempty" yaml:"sidecars,omitempty"`
+
+	// Volcano runs the job as a Volcano Job with the options.
+	// Overrides the volcano options of the workflow.
+	Volcano *Volcano `json:"volcano,omitempty" yaml:"volcano,omitempty"`
+
+	// Indexed runs all the commands of the job as one Kubernetes Indexed Job
+	// instead of one Kubernetes Job per command.
+	// Default to false.
+	Indexed bool `json:"indexed,omitempty" yaml:"indexed,omitempty"`
+
+	// ChunkSize is the number of commands run one after another by each
+	// Kubernetes Job of the job, for commands too short to pay for a pod each.
+	// Default to 1.
+	ChunkSize *int32 `json:"chunk_size,omitempty" yaml:"chunk_size,omitempty"`
+
+	// Priority orders the jobs ready to start at the same time when the
+	// controller starts them by priority, higher first.
+	// Default to 0.
+	Priority *int32 `json:"priority,omitempty" yaml:"priority,omitempty"`
+
+	// EstimatedDuration is the expected duration of one command of the job,
+	// e.g. "30m", used to find the critical path of the workflow before
+	// the job has run.
+	EstimatedDuration string `json:"estimated_duration,omitempty" yaml:"estimated_duration,omitempty"`
+
+	// MemoryEscalation starts a command killed for running out of memory
+	// again with more memory. Requires the memory of the job.
+	MemoryEscalation *MemoryEscalation `json:"memory_escalation,omitempty" yaml:"memory_escalation,omitempty"`
+
+	// SubWorkflow runs another workflow as this job. A job with a sub
+	// workflow only has a description and depends besides.
+	SubWorkflow *SubWorkflow `json:"sub_workflow,omitempty" yaml:"sub_workflow,omitempty"`
+}
+
+// SubWorkflow is a workflow run as a job of another workflow. The jobs of the
+// sub workflow are expanded into the parent workflow, named after the job:
+// job germline running job align of its sub workflow gets job germline-align,
+// or germline-0-align, germline-1-align... with a scatter. The jobs of the
+// sub workflow without depends depend on the depends of the job, and the jobs
+// depending on the job depend on all the jobs of the sub workflow no other
+// one depends on. Only the workflow wide options of the parent apply.
+//
+// sub workflow example
+//
+// germline:
+//   sub_workflow:
+//     path: germline.yaml
+//     inputs:
+//       reference: ${reference}
+//     scatter:
+//       input: sample
+//       values: ${samples}
+//   depends:
+//     - target: prepare
+//       type: whole
+type SubWorkflow struct {
+	// Path is the path of the workflow file, relative to the directory of
+	// the submitted workflow file. Only genectl reads workflow files.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Template is the name of the workflow template in the namespace of the
+	// execution. One of path and template is required.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Inputs are the values of the inputs of the sub workflow. A value of
+	// the form ${input} is the value of the input of the parent workflow,
+	// of any type.
+	Inputs map[string]interface{} `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+
+	// Scatter runs the sub workflow once per value of an array.
+	Scatter *Scatter `json:"scatter,omitempty" yaml:"scatter,omitempty"`
+}
+
+// Scatter runs a sub workflow once per value of an array, e.g. per sample.
+type Scatter struct {
+	// Input is the input of the sub workflow set to each value.
+	Input string `json:"input" yaml:"input"`
+
+	// Values is an array, or an array input of the parent workflow, e.g. ${samples}.
+	Values interface{} `json:"values" yaml:"values"`
+}
+
+// MemoryEscalation describes how the memory of a command grows on every
+// attempt after it runs out of memory.
+type MemoryEscalation struct {
+	// Multiplier of the memory of the last attempt, greater than 1.
+	Multiplier float64 `json:"multiplier" yaml:"multiplier"`
+	// Max is the most memory of an attempt, e.g. "64G".
+	Max string `json:"max" yaml:"max"`
+}
+
+// Container is an init container or a sidecar of a job.
+type Container struct {
+	Name  string `json:"name" yaml:"name"`
+	Image string `json:"image" yaml:"image"`
+	// Command is run with `sh -c`.
+	Command string   `json:"command" yaml:"command"`
+	Env     []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+	// Volumes are the volumes of the job mounted in the container.
+	// Default to all the volumes of the job.
+	Volumes []JobVolume `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+}
+
+// EnvVar is an environment variable. The value is either given by value,
+// or read from a key of a secret or of a configmap.
+type EnvVar struct {
+	Name      string        `json:"name" yaml:"name"`
+	Value     string        `json:"value,omitempty" yaml:"value,omitempty"`
+	Secret    *SecretKey    `json:"secret,omitempty" yaml:"secret,omitempty"`
+	ConfigMap *ConfigMapKey `json:"config_map,omitempty" yaml:"config_map,omitempty"`
+}
+
+// ConfigMapKey selects a key of a configmap.
+type ConfigMapKey struct {
+	// Name of the configmap.
+	Name string `json:"name" yaml:"name"`
+	// Key in the configmap.
+	Key string `json:"key" yaml:"key"`
+}
+
+// EnvFromSource populates environment variables from all the keys of a secret
+// or of a configmap. One of secret, config_map must be specified.
+type EnvFromSource struct {
+	// Name of the secret.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// Name of the configmap.
+	ConfigMap string `json:"config_map,omitempty" yaml:"config_map,omitempty"`
+	// Prefix prepended to the name of every variable.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// SecurityContext holds the security options the job container runs with.
+type SecurityContext struct {
+	RunAsUser              *int64 `json:"run_as_user,omitempty" yaml:"run_as_user,omitempty"`
+	RunAsGroup             *int64 `json:"run_as_group,omitempty" yaml:"run_as_group,omitempty"`
+	RunAsNonRoot           *bool  `json:"run_as_non_root,omitempty" yaml:"run_as_non_root,omitempty"`
+	Privileged             *bool  `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+	ReadOnlyRootFilesystem *bool  `json:"read_only_root_filesystem,omitempty" yaml:"read_only_root_filesystem,omitempty"`
+}
+
+// Volcano holds the options of a job run as a Volcano Job.
+//
+// use example
+//
+// volcano:
+//   queue: genomics
+//   replicas: 4
+//   min_available: 4
+type Volcano struct {
+	// Queue the Volcano Job is submitted to.
+	Queue string `json:"queue,omitempty" yaml:"queue,omitempty"`
+	// SchedulerName schedules the pods, default to `volcano`.
+	SchedulerName string `json:"scheduler_name,omitempty" yaml:"scheduler_name,omitempty"`
+	// PriorityClass is the name of the priority class of the pods.
+	PriorityClass string `json:"priority_class,omitempty" yaml:"priority_class,omitempty"`
+	// Replicas is the number of pods running the job command, default to 1.
+	Replicas *int32 `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	// MinAvailable is the number of pods scheduled together, default to replicas.
+	MinAvailable *int32 `json:"min_available,omitempty" yaml:"min_available,omitempty"`
+}
+
+// Artifact is a directory passed between jobs through the artifact repository.
+//
+// use example
+//
+// job-a:
+//   output_artifacts:
+//     - name: bam
+//       path: /data/bam
+// job-b:
+//   depends:
+//     - target: job-a
+//   input_artifacts:
+//     - name: bam
+//       path: /data/bam
+//       from: job-a.bam
+type Artifact struct {
+	// Name of the artifact.
+	// Required.
+	Name string `json:"name" yaml:"name"`
+	// Path is the directory of the artifact in the job container.
+	// Required.
+	Path string `json:"path" yaml:"path"`
+	// From is the output artifact an input artifact is fetched from,
+	// in the form of <job>.<artifact>.
+	// Only used by input artifacts.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+}
+
+type SecretKey struct {
+	// Name of the secret.
+	Name string `json:"name" yaml:"name"`
+	// Key in the secret.
+	Key string `json:"key" yaml:"key"`
+}
+
+type LocalArtifactRepository struct {
+	// PVC stores the artifacts.
+	PVC string `json:"pvc" yaml:"pvc"`
+	// SubPath is the directory in the volume artifacts are stored under.
+	SubPath string `json:"sub_path,omitempty" yaml:"sub_path,omitempty"`
+}
+
+type S3ArtifactRepository struct {
+	Endpoint        string    `json:"endpoint" yaml:"endpoint"`
+	Bucket          string    `json:"bucket" yaml:"bucket"`
+	KeyPrefix       string    `json:"key_prefix,omitempty" yaml:"key_prefix,omitempty"`
+	Insecure        bool      `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	AccessKeySecret SecretKey `json:"access_key_secret" yaml:"access_key_secret"`
+	SecretKeySecret SecretKey `json:"secret_key_secret" yaml:"secret_key_secret"`
+}
+
+// ArtifactRepository is where the artifacts of the jobs are stored.
+// One of local, s3 must be specified.
+type ArtifactRepository struct {
+	Local *LocalArtifactRepository `json:"local,omitempty" yaml:"local,omitempty"`
+	S3    *S3ArtifactRepository    `json:"s3,omitempty" yaml:"s3,omitempty"`
+	// Image used to fetch and upload artifacts.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// PathsIter similar to CommandsIter.
+type PathsIter struct {
+	Path     string        `json:"path" yaml:"path"`
+	Vars     []interface{} `json:"vars,omitempty" yaml:"vars,omitempty"`
+	VarsIter []interface{} `json:"vars_iter,omitempty" yaml:"vars_iter,omitempty"`
+}
+
+// VolumeSource is the source of a volume.
+// Only one of pvc, empty_dir, host_path, config_map, secret, nfs, ephemeral should be specified.
+type VolumeSource struct {
+	PVC       string           `json:"pvc,omitempty" yaml:"pvc,omitempty"`
+	EmptyDir  *EmptyDirSource  `json:"empty_dir,omitempty" yaml:"empty_dir,omitempty"`
+	HostPath  *HostPathSource  `json:"host_path,omitempty" yaml:"host_path,omitempty"`
+	ConfigMap *ConfigMapSource `json:"config_map,omitempty" yaml:"config_map,omitempty"`
+	Secret    *SecretSource    `json:"secret,omitempty" yaml:"secret,omitempty"`
+	NFS       *NFSSource       `json:"nfs,omitempty" yaml:"nfs,omitempty"`
+	Ephemeral *EphemeralSource `json:"ephemeral,omitempty" yaml:"ephemeral,omitempty"`
+}
+
+// EmptyDirSource is a scratch directory of the job.
+type EmptyDirSource struct {
+	// Medium backing the directory, one of "" (the disk of the node) or Memory.
+	Medium string `json:"medium,omitempty" yaml:"medium,omitempty"`
+	// SizeLimit of the directory, e.g. 10Gi.
+	SizeLimit string `json:"size_limit,omitempty" yaml:"size_limit,omitempty"`
+}
+
+// HostPathSource is a directory of the node, e.g. a local NVMe disk.
+type HostPathSource struct {
+	Path string `json:"path" yaml:"path"`
+	// Type of the path, e.g. Directory, DirectoryOrCreate.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// ConfigMapSource populates the volume with the keys of a configmap.
+type ConfigMapSource struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// SecretSource populates the volume with the keys of a secret.
+type SecretSource struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// NFSSource is a NFS share.
+type NFSSource struct {
+	Server string `json:"server" yaml:"server"`
+	Path   string `json:"path" yaml:"path"`
+}
+
+// EphemeralSource is a volume provisioned for every job and deleted with the job.
+type EphemeralSource struct {
+	// Size of the volume, e.g. 100Gi.
+	Size string `json:"size" yaml:"size"`
+	// StorageClass of the volume. Default to the default storage class of the cluster.
+	StorageClass string `json:"storage_class,omitempty" yaml:"storage_class,omitempty"`
+	// AccessModes of the volume. Default to `ReadWriteOnce`.
+	AccessModes []string `json:"access_modes,omitempty" yaml:"access_modes,omitempty"`
+}
+
+type Volume struct {
+	MountPath string       `json:"mount_path" yaml:"mount_path"`
+	MountFrom VolumeSource `json:"mount_from" yaml:"mount_from"`
+	// ReadOnly mounts the volume read-only in all the jobs.
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// JobVolume selects a volume of the workflow to mount in the job.
+type JobVolume struct {
+	// Name of the volume in the volumes of the workflow.
+	Name string `json:"name" yaml:"name"`
+	// ReadOnly mounts the volume read-only in the job.
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+type OutputDesc struct {
+	Paths     []string  `json:"paths" yaml:"paths"`
+	PathsIter PathsIter `json:"paths_iter,omitempty" yaml:"paths_iter,omitempty"`
+}
+
+//
+type Workflow struct {
+	// Workflow version
+	Version string                `json:"version" yaml:"version"`
+	Inputs  map[string]Input      `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Jobs    map[string]JobInfo    `json:"workflow" yaml:"workflow"`
+	Volumes map[string]Volume     `json:"volumes" yaml:"volumes"`
+	Outputs map[string]OutputDesc `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Tools   map[string]Tool       `json:"tools" yaml:"tools"`
+
+	ArtifactRepository *ArtifactRepository `json:"artifact_repository,omitempty" yaml:"artifact_repository,omitempty"`
+
+	// Cache turns on call caching for all the jobs of the workflow.
+	// Default to false.
+	Cache *bool `json:"cache,omitempty" yaml:"cache,omitempty"`
+
+	// Volcano runs all the jobs of the workflow as Volcano Jobs with the options.
+	Volcano *Volcano `json:"volcano,omitempty" yaml:"volcano,omitempty"`
+
+	// ParallelResources limits the total resources requested by the jobs of
+	// the workflow running at the same time, e.g. cpu: 256.
+	ParallelResources *Resources `json:"parallel_resources,omitempty" yaml:"parallel_resources,omitempty"`
+
+	// Priority orders the workflows waiting to run when kube-dag limits the
+	// number of running workflows, a higher priority runs first.
+	// Default to 0.
+	Priority *int32 `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// ErrorList holds a set of Errors.
+type ErrorList []error
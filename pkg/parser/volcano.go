@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func ValidateVolcano(field string, volcano *Volcano) ErrorList {
+	errors := ErrorList{}
+	if volcano == nil {
+		return errors
+	}
+
+	names := []struct {
+		key   string
+		value string
+	}{
+		{"queue", volcano.Queue},
+		{"scheduler_name", volcano.SchedulerName},
+		{"priority_class", volcano.PriorityClass},
+	}
+	for _, name := range names {
+		if len(name.value) == 0 {
+			continue
+		}
+		if msgs := validation.IsDNS1123Subdomain(name.value); len(msgs) > 0 {
+			errors = append(errors, fmt.Errorf("%s.%s: %s is not valid %v", field, name.key, name.value, msgs))
+		}
+	}
+
+	replicas := int32(1)
+	if volcano.Replicas != nil {
+		replicas = *volcano.Replicas
+		if replicas < 1 {
+			errors = append(errors, fmt.Errorf("%s.replicas: must be greater than or equal to 1", field))
+		}
+	}
+	if volcano.MinAvailable != nil && (*volcano.MinAvailable < 1 || *volcano.MinAvailable > replicas) {
+		errors = append(errors, fmt.Errorf("%s.min_available: must be between 1 and replicas", field))
+	}
+	return errors
+}
+
+func TransVolcano2ExecVolcano(volcano *Volcano) *execv1alpha1.VolcanoOptions {
+	if volcano == nil {
+		return nil
+	}
+	return &execv1alpha1.VolcanoOptions{
+		Queue:             volcano.Queue,
+		SchedulerName:     volcano.SchedulerName,
+		PriorityClassName: volcano.PriorityClass,
+		Replicas:          volcano.Replicas,
+		MinAvailable:      volcano.MinAvailable,
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestValidateVolcano(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Volcano   string
+		ExpectErr bool
+	}{
+		{
+			Name: "valid volcano options",
+			Volcano: `
+queue: genomics
+priority_class: high
+replicas: 4
+min_available: 2`,
+			ExpectErr: false,
+		},
+		{
+			Name: "workflow.job-a.volcano.queue: is not valid",
+			Volcano: `
+queue: Genomics_Queue`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.volcano.replicas: must be greater than or equal to 1",
+			Volcano: `
+replicas: 0`,
+			ExpectErr: true,
+		},
+		{
+			Name: "workflow.job-a.volcano.min_available: must be between 1 and replicas",
+			Volcano: `
+replicas: 2
+min_available: 3`,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var volcano Volcano
+		if err := yaml.Unmarshal([]byte(testCase.Volcano), &volcano); err != nil {
+			t.Fatalf("%s: unexpected error: %v", testCase.Name, err)
+		}
+		err := ValidateVolcano("workflow.job-a.volcano", &volcano)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestTransVolcano2ExecVolcano(t *testing.T) {
+	if TransVolcano2ExecVolcano(nil) != nil {
+		t.Errorf("Expect nil volcano options")
+	}
+	replicas := int32(4)
+	options := TransVolcano2ExecVolcano(&Volcano{Queue: "genomics", PriorityClass: "high", Replicas: &replicas})
+	if options.Queue != "genomics" || options.PriorityClassName != "high" || *options.Replicas != 4 {
+		t.Errorf("Expect volcano options from the job, but got %v", options)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	execv1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+func ValidateVolumes(volumes map[string]Volume, inputs map[string]Input) ErrorList {
+	errors := ErrorList{}
+	for key, volume := range volumes {
+		if matched, _ := regexp.MatchString(JobNameRegexFmt, key); !matched {
+			err := fmt.Errorf("volumes[%s]: name must consist of lower case alphanumeric characters or '-'", key)
+			errors = append(errors, err)
+		}
+
+		errors = append(errors, validateVolumeSource(key, volume.MountFrom, inputs)...)
+
+		mountPath := volume.MountPath
+		if len(mountPath) == 0 {
+			errors = append(errors, fmt.Errorf("volumes[%s].mountPath: mountPath should be empty", key))
+			continue
+		} else if IsVariant(mountPath) {
+			prefix := fmt.Sprintf("volumes[%s].mountPath", key)
+			if err := ValidateVariant(prefix, mountPath, []string{StringType}, inputs); err != nil {
+				errors = append(errors, err)
+			}
+		} else if !path.IsAbs(mountPath) {
+			err := fmt.Errorf("volumes[%s].mountPath: mountPath should be an absolute path, but the real one is %s", key, mountPath)
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+func validateVolumeSource(key string, from VolumeSource, inputs map[string]Input) ErrorList {
+	errors := ErrorList{}
+	sources := 0
+	for _, set := range []bool{len(from.PVC) != 0, from.EmptyDir != nil, from.HostPath != nil,
+		from.ConfigMap != nil, from.Secret != nil, from.NFS != nil, from.Ephemeral != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		err := fmt.Errorf("volumes[%s].mountFrom: only one of pvc, empty_dir, host_path, config_map, secret, nfs, ephemeral should be specified", key)
+		return append(errors, err)
+	}
+
+	validateString := func(field, value string) {
+		prefix := fmt.Sprintf("volumes[%s].mountFrom.%s", key, field)
+		if len(value) == 0 {
+			errors = append(errors, fmt.Errorf("%s: should not be empty", prefix))
+		} else if IsVariant(value) {
+			if err := ValidateVariant(prefix, value, []string{StringType}, inputs); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+	validateQuantity := func(field, value string) {
+		if _, err := resource.ParseQuantity(value); err != nil {
+			errors = append(errors, fmt.Errorf("volumes[%s].mountFrom.%s: %s is not a valid quantity", key, field, value))
+		}
+	}
+
+	switch {
+	case len(from.PVC) != 0:
+		validateString("pvc", from.PVC)
+	case from.EmptyDir != nil:
+		if from.EmptyDir.Medium != "" && from.EmptyDir.Medium != string(v1.StorageMediumMemory) {
+			errors = append(errors, fmt.Errorf("volumes[%s].mountFrom.empty_dir.medium: should only be empty or Memory", key))
+		}
+		if len(from.EmptyDir.SizeLimit) != 0 {
+			validateQuantity("empty_dir.size_limit", from.EmptyDir.SizeLimit)
+		}
+	case from.HostPath != nil:
+		validateString("host_path.path", from.HostPath.Path)
+	case from.ConfigMap != nil:
+		validateString("config_map.name", from.ConfigMap.Name)
+	case from.Secret != nil:
+		validateString("secret.name", from.Secret.Name)
+	case from.NFS != nil:
+		validateString("nfs.server", from.NFS.Server)
+		validateString("nfs.path", from.NFS.Path)
+	case from.Ephemeral != nil:
+		validateQuantity("ephemeral.size", from.Ephemeral.Size)
+		for _, mode := range from.Ephemeral.AccessModes {
+			switch v1.PersistentVolumeAccessMode(mode) {
+			case v1.ReadWriteOnce, v1.ReadOnlyMany, v1.ReadWriteMany:
+			default:
+				errors = append(errors, fmt.Errorf("volumes[%s].mountFrom.ephemeral.access_modes: %s is not a valid access mode", key, mode))
+			}
+		}
+	}
+	return errors
+}
+
+func ValidateJobVolumes(jobName string, jobVolumes []JobVolume, volumes map[string]Volume) ErrorList {
+	errors := ErrorList{}
+	names := make(map[string]struct{}, len(jobVolumes))
+	for i, jobVolume := range jobVolumes {
+		prefix := fmt.Sprintf("workflow.%s.volumes[%d].name", jobName, i)
+		if _, ok := volumes[jobVolume.Name]; !ok {
+			errors = append(errors, fmt.Errorf("%s: volume %s is not defined in the volumes", prefix, jobVolume.Name))
+		}
+		if _, exist := names[jobVolume.Name]; exist {
+			errors = append(errors, fmt.Errorf("%s: volume %s duplicated", prefix, jobVolume.Name))
+		}
+		names[jobVolume.Name] = struct{}{}
+	}
+	return errors
+}
+
+func TransVolume2ExecVolume(volumes map[string]Volume) (map[string]execv1alpha1.Volume, error) {
+	execVolumes := make(map[string]execv1alpha1.Volume, len(volumes))
+	for key, volume := range volumes {
+		var tmpVolume execv1alpha1.Volume
+		tmpVolume.MountPath = volume.MountPath
+		tmpVolume.ReadOnly = volume.ReadOnly
+
+		from := volume.MountFrom
+		switch {
+		case from.EmptyDir != nil:
+			emptyDir := &v1.EmptyDirVolumeSource{Medium: v1.StorageMedium(from.EmptyDir.Medium)}
+			if len(from.EmptyDir.SizeLimit) != 0 {
+				sizeLimit, err := resource.ParseQuantity(from.EmptyDir.SizeLimit)
+				if err != nil {
+					return nil, fmt.Errorf("parse size_limit of volume %s error: %v", key, err)
+				}
+				emptyDir.SizeLimit = &sizeLimit
+			}
+			tmpVolume.MountFrom.EmptyDir = emptyDir
+		case from.HostPath != nil:
+			hostPath := &v1.HostPathVolumeSource{Path: from.HostPath.Path}
+			if len(from.HostPath.Type) != 0 {
+				hostPathType := v1.HostPathType(from.HostPath.Type)
+				hostPath.Type = &hostPathType
+			}
+			tmpVolume.MountFrom.HostPath = hostPath
+		case from.ConfigMap != nil:
+			tmpVolume.MountFrom.ConfigMap = &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: from.ConfigMap.Name},
+			}
+		case from.Secret != nil:
+			tmpVolume.MountFrom.Secret = &v1.SecretVolumeSource{SecretName: from.Secret.Name}
+		case from.NFS != nil:
+			tmpVolume.MountFrom.NFS = &v1.NFSVolumeSource{Server: from.NFS.Server, Path: from.NFS.Path}
+		case from.Ephemeral != nil:
+			size, err := resource.ParseQuantity(from.Ephemeral.Size)
+			if err != nil {
+				return nil, fmt.Errorf("parse size of volume %s error: %v", key, err)
+			}
+			spec := v1.PersistentVolumeClaimSpec{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: size},
+				},
+			}
+			if len(from.Ephemeral.StorageClass) != 0 {
+				storageClass := from.Ephemeral.StorageClass
+				spec.StorageClassName = &storageClass
+			}
+			for _, mode := range from.Ephemeral.AccessModes {
+				spec.AccessModes = append(spec.AccessModes, v1.PersistentVolumeAccessMode(mode))
+			}
+			if len(spec.AccessModes) == 0 {
+				spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+			}
+			tmpVolume.MountFrom.Ephemeral = &execv1alpha1.EphemeralVolumeSource{VolumeClaimTemplate: spec}
+		default:
+			tmpVolume.MountFrom.Pvc = from.PVC
+		}
+		execVolumes[key] = tmpVolume
+	}
+	return execVolumes, nil
+}
+
+// SelectJobVolumes returns the volumes mounted in the job. All the volumes
+// are mounted if the job does not select any.
+func SelectJobVolumes(volumes map[string]execv1alpha1.Volume, jobVolumes []JobVolume) map[string]execv1alpha1.Volume {
+	if len(jobVolumes) == 0 {
+		return volumes
+	}
+	selected := make(map[string]execv1alpha1.Volume, len(jobVolumes))
+	for _, jobVolume := range jobVolumes {
+		volume, ok := volumes[jobVolume.Name]
+		if !ok {
+			continue
+		}
+		volume.ReadOnly = volume.ReadOnly || jobVolume.ReadOnly
+		selected[jobVolume.Name] = volume
+	}
+	return selected
+}
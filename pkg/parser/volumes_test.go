@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"github.com/ghodss/yaml"
+	"testing"
+)
+
+func TestValidateVolumes(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Volumes   string
+		Inputs    map[string]Input
+		ExpectErr bool
+	}{
+		{
+			Name: "valid volume",
+			Volumes: `
+  reference:
+    mount_path: /root
+    mount_from:
+      pvc: ${gcs-pvc}`,
+			Inputs:    makeInputs(),
+			ExpectErr: false,
+		},
+		{
+			Name: "valid volumes of all types",
+			Volumes: `
+  scratch:
+    mount_path: /scratch
+    mount_from:
+      empty_dir:
+        medium: Memory
+        size_limit: 1Gi
+  nvme:
+    mount_path: /nvme
+    mount_from:
+      host_path:
+        path: /mnt/nvme
+        type: Directory
+  config:
+    mount_path: /config
+    read_only: true
+    mount_from:
+      config_map:
+        name: settings
+  creds:
+    mount_path: /creds
+    mount_from:
+      secret:
+        name: creds
+  ref:
+    mount_path: /ref
+    mount_from:
+      nfs:
+        server: nfs.example.com
+        path: /ref
+  work:
+    mount_path: /work
+    mount_from:
+      ephemeral:
+        size: 100Gi
+        storage_class: fast`,
+			Inputs:    makeInputs(),
+			ExpectErr: false,
+		},
+		{
+			Name: "volumes[reference].mountFrom: only one of pvc, empty_dir, host_path, config_map, secret, nfs, ephemeral should be specified",
+			Volumes: `
+  reference:
+    mount_path: /root
+    mount_from:
+      pvc: ${gcs-pvc}
+      empty_dir: {}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[work].mountFrom.ephemeral.size: xxx is not a valid quantity",
+			Volumes: `
+  work:
+    mount_path: /work
+    mount_from:
+      ephemeral:
+        size: xxx`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[ref].mountFrom.nfs.server: should not be empty",
+			Volumes: `
+  ref:
+    mount_path: /ref
+    mount_from:
+      nfs:
+        path: /ref`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_from: volume only support pvc and mount_from.pvc should not be empty",
+			Volumes: `
+  reference:
+    mount_path: /root
+    mount_from:
+      xxx: ${gcs-pvc}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_from.pvc: the variant [xxx] is not defined in the inputs",
+			Volumes: `
+  reference:
+    mount_path: /root
+    mount_from:
+      pvc: ${xxx}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_from.pvc: the type of bwaArray can only be in [string], but the real one is array",
+			Volumes: `
+volumes:
+  reference:
+    mount_path: /root
+    mount_from:
+      pvc: ${bwaArray}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_path: the type of bwaArray can only be in [string], but the real one is array",
+			Volumes: `
+  reference:
+    mount_path: ${bwaArray}
+    mount_from:
+      pvc: ${gcs-pvc}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_path: mount path should be empty",
+			Volumes: `
+  reference:
+    mount_path:
+    mount_from:
+      pvc: ${gcs-pvc}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+		{
+			Name: "volumes[reference].mount_path: mount path should be an absolute path, but the real one is fdafdasf/fdaf",
+			Volumes: `
+  reference:
+    mount_path: fdafdasf/fdaf
+    mount_from:
+      pvc: ${gcs-pvc}`,
+			Inputs:    makeInputs(),
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var volumes map[string]Volume
+		yaml.Unmarshal([]byte(testCase.Volumes), &volumes)
+		err := ValidateVolumes(volumes, testCase.Inputs)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestValidateJobVolumes(t *testing.T) {
+	volumes := map[string]Volume{
+		"reference": {MountPath: "/ref", MountFrom: VolumeSource{PVC: "ref"}},
+	}
+	testCases := []struct {
+		Name       string
+		JobVolumes []JobVolume
+		ExpectErr  bool
+	}{
+		{
+			Name:       "valid job volumes",
+			JobVolumes: []JobVolume{{Name: "reference", ReadOnly: true}},
+			ExpectErr:  false,
+		},
+		{
+			Name:       "workflow.job-a.volumes[0].name: volume xxx is not defined in the volumes",
+			JobVolumes: []JobVolume{{Name: "xxx"}},
+			ExpectErr:  true,
+		},
+		{
+			Name:       "workflow.job-a.volumes[1].name: volume reference duplicated",
+			JobVolumes: []JobVolume{{Name: "reference"}, {Name: "reference"}},
+			ExpectErr:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := ValidateJobVolumes("job-a", testCase.JobVolumes, volumes)
+		if testCase.ExpectErr == true && len(err) == 0 {
+			t.Errorf("%s: Expect error, but got nil", testCase.Name)
+		}
+		if testCase.ExpectErr == false && len(err) != 0 {
+			t.Errorf("%s: Expect no error, but got error %v", testCase.Name, err)
+		}
+	}
+}
+
+func TestSelectJobVolumes(t *testing.T) {
+	volumes, err := TransVolume2ExecVolume(map[string]Volume{
+		"reference": {MountPath: "/ref", MountFrom: VolumeSource{PVC: "ref"}},
+		"work":      {MountPath: "/work", MountFrom: VolumeSource{Ephemeral: &EphemeralSource{Size: "10Gi"}}},
+	})
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if claim := volumes["work"].MountFrom.Ephemeral; claim == nil || len(claim.VolumeClaimTemplate.AccessModes) != 1 {
+		t.Errorf("Expect ephemeral volume with default access mode, but got %v", volumes["work"])
+	}
+
+	if selected := SelectJobVolumes(volumes, nil); len(selected) != 2 {
+		t.Errorf("Expect all the volumes, but got %v", selected)
+	}
+	selected := SelectJobVolumes(volumes, []JobVolume{{Name: "reference", ReadOnly: true}})
+	if len(selected) != 1 || !selected["reference"].ReadOnly {
+		t.Errorf("Expect read-only volume reference, but got %v", selected)
+	}
+	if volumes["reference"].ReadOnly {
+		t.Errorf("Expect the volumes of the workflow unchanged")
+	}
+}
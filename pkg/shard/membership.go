@@ -0,0 +1,213 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+const (
+	// Label marks the leases of the replicas sharing the executions.
+	Label = "kubegene.io/shard-member"
+)
+
+// Membership keeps the lease of a replica renewed and finds the replicas
+// whose leases are alive. Every execution is owned by exactly one of them.
+type Membership struct {
+	kubeClient    clientset.Interface
+	namespace     string
+	name          string
+	identity      string
+	leaseDuration time.Duration
+	// now is replaced in tests.
+	now func() time.Time
+
+	lock     sync.RWMutex
+	members  []string
+	handlers []func()
+}
+
+// NewMembership returns the membership of the replica holding the lease
+// name in the namespace. The replica leaves the shards if the lease is not
+// renewed within the lease duration.
+func NewMembership(kubeClient clientset.Interface, namespace, name, identity string, leaseDuration time.Duration) *Membership {
+	return &Membership{
+		kubeClient:    kubeClient,
+		namespace:     namespace,
+		name:          name,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		now:           time.Now,
+	}
+}
+
+// AddHandler calls the handler every time the replicas change.
+func (m *Membership) AddHandler(handler func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Members returns the names of the leases of the live replicas.
+func (m *Membership) Members() []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.members
+}
+
+// Owns returns true if this replica owns the execution of the key.
+func (m *Membership) Owns(key string) bool {
+	return Owner(m.Members(), key) == m.name
+}
+
+// Run renews the lease and refreshes the replicas until stopCh is closed,
+// then gives up the lease so that the other replicas take over its shard.
+func (m *Membership) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := m.Sync(); err != nil {
+			klog.Errorf("sync shard membership error: %v", err)
+		}
+	}, m.leaseDuration/3, stopCh)
+
+	err := m.kubeClient.CoordinationV1().Leases(m.namespace).Delete(context.TODO(), m.name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("delete shard lease %s error: %v", m.name, err)
+	}
+}
+
+// Sync renews the lease of this replica and refreshes the live replicas.
+func (m *Membership) Sync() error {
+	renewErr := m.renew()
+	if renewErr != nil {
+		klog.Errorf("renew shard lease %s error: %v", m.name, renewErr)
+	}
+
+	leases, err := m.kubeClient.CoordinationV1().Leases(m.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: Label})
+	if err != nil {
+		return err
+	}
+	now := m.now()
+	members := []string{}
+	for i := range leases.Items {
+		if m.alive(&leases.Items[i], now) {
+			members = append(members, leases.Items[i].Name)
+		}
+	}
+	sort.Strings(members)
+
+	m.lock.Lock()
+	changed := !reflect.DeepEqual(members, m.members)
+	m.members = members
+	handlers := m.handlers
+	m.lock.Unlock()
+
+	if changed {
+		klog.Infof("shard members changed: %v", members)
+		for _, handler := range handlers {
+			handler()
+		}
+	}
+	return renewErr
+}
+
+// renew creates the lease of this replica or renews it.
+func (m *Membership) renew() error {
+	leases := m.kubeClient.CoordinationV1().Leases(m.namespace)
+	now := metav1.NewMicroTime(m.now())
+	durationSeconds := int32(m.leaseDuration / time.Second)
+
+	lease, err := leases.Get(context.TODO(), m.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.name,
+				Namespace: m.namespace,
+				Labels:    map[string]string{Label: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(context.TODO(), lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	// the lease is named after the pod of the replica, a replica restarted
+	// in the same pod takes it over from its last process.
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != m.identity {
+		lease.Spec.HolderIdentity = &m.identity
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(context.TODO(), lease, metav1.UpdateOptions{})
+	return err
+}
+
+// alive returns true if the lease has been renewed within its duration.
+func (m *Membership) alive(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.Before(expiry)
+}
+
+// Owner returns the member owning the key by rendezvous hashing, so that
+// only the keys of a member which leaves or joins move to another member.
+func Owner(members []string, key string) string {
+	var owner string
+	var highest uint64
+	for _, member := range members {
+		hash := fnv.New64a()
+		hash.Write([]byte(member))
+		hash.Write([]byte{0})
+		hash.Write([]byte(key))
+		if weight := mix(hash.Sum64()); len(owner) == 0 || weight > highest {
+			owner, highest = member, weight
+		}
+	}
+	return owner
+}
+
+// mix spreads the bits of a hash, the weights fnv gives to members with
+// similar names are too close to share the keys evenly.
+func mix(hash uint64) uint64 {
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	return hash
+}
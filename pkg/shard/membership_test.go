@@ -0,0 +1,137 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestOwner(t *testing.T) {
+	members := []string{"a", "b", "c"}
+	keys := []string{}
+	for i := 0; i < 300; i++ {
+		keys = append(keys, fmt.Sprintf("default/execution-%d", i))
+	}
+
+	owners := map[string]string{}
+	counts := map[string]int{}
+	for _, key := range keys {
+		owner := Owner(members, key)
+		owners[key] = owner
+		counts[owner]++
+	}
+	for _, member := range members {
+		if counts[member] == 0 {
+			t.Errorf("Expect member %s to own some executions, but got none", member)
+		}
+	}
+
+	// only the executions of the member which leaves move.
+	for _, key := range keys {
+		owner := Owner([]string{"a", "c"}, key)
+		if owners[key] != "b" && owner != owners[key] {
+			t.Errorf("%s: Expect owner %s to be kept, but got %s", key, owners[key], owner)
+		}
+	}
+
+	if owner := Owner(nil, "default/execution"); owner != "" {
+		t.Errorf("Expect no owner without members, but got %s", owner)
+	}
+}
+
+func TestMembership(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	now := time.Now()
+	a := NewMembership(kubeClient, "kube-system", "replica-a", "host-a", 15*time.Second)
+	b := NewMembership(kubeClient, "kube-system", "replica-b", "host-b", 15*time.Second)
+	a.now = func() time.Time { return now }
+	b.now = func() time.Time { return now }
+
+	changes := 0
+	a.AddHandler(func() { changes++ })
+
+	if err := a.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if err := b.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if err := a.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if members := a.Members(); !reflect.DeepEqual(members, []string{"replica-a", "replica-b"}) {
+		t.Errorf("Expect members [replica-a replica-b], but got %v", members)
+	}
+	if changes != 2 {
+		t.Errorf("Expect 2 changes of members, but got %d", changes)
+	}
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("default/execution-%d", i)
+		if a.Owns(key) == b.Owns(key) {
+			t.Errorf("%s: Expect exactly one owner", key)
+		}
+	}
+
+	// b stops renewing its lease.
+	now = now.Add(20 * time.Second)
+	if err := a.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if members := a.Members(); !reflect.DeepEqual(members, []string{"replica-a"}) {
+		t.Errorf("Expect members [replica-a], but got %v", members)
+	}
+	if changes != 3 {
+		t.Errorf("Expect 3 changes of members, but got %d", changes)
+	}
+	if !a.Owns("default/execution-0") {
+		t.Errorf("Expect the only member to own every execution")
+	}
+}
+
+func TestMembershipRestarted(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	now := time.Now()
+	a := NewMembership(kubeClient, "kube-system", "replica-a", "host-a_1", 15*time.Second)
+	a.now = func() time.Time { return now }
+	if err := a.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+
+	// the replica restarts in the same pod without giving up its lease.
+	restarted := NewMembership(kubeClient, "kube-system", "replica-a", "host-a_2", 15*time.Second)
+	restarted.now = func() time.Time { return now.Add(5 * time.Second) }
+	if err := restarted.Sync(); err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if members := restarted.Members(); !reflect.DeepEqual(members, []string{"replica-a"}) {
+		t.Errorf("Expect members [replica-a], but got %v", members)
+	}
+	lease, err := kubeClient.CoordinationV1().Leases("kube-system").Get(context.TODO(), "replica-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expect lease replica-a, but got error %v", err)
+	}
+	if holder := *lease.Spec.HolderIdentity; holder != "host-a_2" {
+		t.Errorf("Expect the restarted replica to hold the lease, but got holder %s", holder)
+	}
+}
@@ -21,17 +21,27 @@ import (
 	"fmt"
 	"time"
 
-	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-// EnsureCreateCRD creates CustomResourceDefinition
-func EnsureCreateCRD(clientset apiextensionsclient.Interface, crd *apiextensionsv1beta1.CustomResourceDefinition) error {
-	_, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Create(context.TODO(), crd, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+// EnsureCreateCRD creates CustomResourceDefinition, an existing one is
+// updated in place to the given spec.
+func EnsureCreateCRD(clientset apiextensionsclient.Interface, crd *apiextensionsv1.CustomResourceDefinition) error {
+	crds := clientset.ApiextensionsV1().CustomResourceDefinitions()
+	_, err := crds.Create(context.TODO(), crd, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		var existing *apiextensionsv1.CustomResourceDefinition
+		existing, err = crds.Get(context.TODO(), crd.Name, metav1.GetOptions{})
+		if err == nil {
+			existing.Spec = crd.Spec
+			_, err = crds.Update(context.TODO(), existing, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
 		return err
 	}
 
@@ -45,18 +55,18 @@ func EnsureCreateCRD(clientset apiextensionsclient.Interface, crd *apiextensions
 // waitForExecutionResource waits for the Execution resource
 func waitForEstablishedCRD(clientset apiextensionsclient.Interface, name string) error {
 	return wait.Poll(100*time.Millisecond, 60*time.Second, func() (bool, error) {
-		crd, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
+		crd, err := clientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
 		if err != nil {
 			return false, err
 		}
 		for _, cond := range crd.Status.Conditions {
 			switch cond.Type {
-			case apiextensionsv1beta1.Established:
-				if cond.Status == apiextensionsv1beta1.ConditionTrue {
+			case apiextensionsv1.Established:
+				if cond.Status == apiextensionsv1.ConditionTrue {
 					return true, nil
 				}
-			case apiextensionsv1beta1.NamesAccepted:
-				if cond.Status == apiextensionsv1beta1.ConditionFalse {
+			case apiextensionsv1.NamesAccepted:
+				if cond.Status == apiextensionsv1.ConditionFalse {
 					return false, fmt.Errorf("name conflict: %v", cond.Reason)
 				}
 			}
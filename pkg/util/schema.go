@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	quantityType = reflect.TypeOf(resource.Quantity{})
+	timeType     = reflect.TypeOf(metav1.Time{})
+	durationType = reflect.TypeOf(metav1.Duration{})
+	jsonType     = reflect.TypeOf(apiextensionsv1.JSON{})
+)
+
+// kubernetesAPIPackages are the package path prefixes of the kubernetes API
+// types. Their fields are validated by the API server of the objects they end
+// up in, so the schema keeps them as opaque objects.
+var kubernetesAPIPackages = []string{"k8s.io/api/", "k8s.io/apimachinery/pkg/apis/meta/"}
+
+// StructuralSchema generates a structural OpenAPI v3 schema of a custom resource
+// whose spec and status are of the types of the given values. The custom
+// resource has no status if status is nil.
+func StructuralSchema(spec, status interface{}) *apiextensionsv1.JSONSchemaProps {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"metadata":   {Type: "object"},
+			"spec":       *typeSchema(reflect.TypeOf(spec), map[reflect.Type]bool{}),
+		},
+	}
+	if status != nil {
+		schema.Properties["status"] = *typeSchema(reflect.TypeOf(status), map[reflect.Type]bool{})
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the json encoding of type t. visiting holds
+// the struct types being generated, a recursive type is not expanded again.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) *apiextensionsv1.JSONSchemaProps {
+	switch t {
+	case quantityType:
+		return &apiextensionsv1.JSONSchemaProps{
+			XIntOrString: true,
+			AnyOf: []apiextensionsv1.JSONSchemaProps{
+				{Type: "integer"},
+				{Type: "string"},
+			},
+		}
+	case timeType:
+		return &apiextensionsv1.JSONSchemaProps{Type: "string", Format: "date-time", Nullable: true}
+	case durationType:
+		return &apiextensionsv1.JSONSchemaProps{Type: "string"}
+	case jsonType:
+		// any json value, encoded as is rather than as a struct.
+		return &apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := typeSchema(t.Elem(), visiting)
+		schema.Nullable = true
+		return schema
+	case reflect.Interface:
+		return &apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}
+	case reflect.Bool:
+		return &apiextensionsv1.JSONSchemaProps{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &apiextensionsv1.JSONSchemaProps{Type: "number"}
+	case reflect.String:
+		return &apiextensionsv1.JSONSchemaProps{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &apiextensionsv1.JSONSchemaProps{Type: "string", Format: "byte"}
+		}
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:     "array",
+			Nullable: true,
+			Items:    &apiextensionsv1.JSONSchemaPropsOrArray{Schema: typeSchema(t.Elem(), visiting)},
+		}
+	case reflect.Map:
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:     "object",
+			Nullable: true,
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+				Allows: true,
+				Schema: typeSchema(t.Elem(), visiting),
+			},
+		}
+	case reflect.Struct:
+		if isKubernetesAPIType(t) || visiting[t] {
+			return &apiextensionsv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: boolPtr(true)}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{}}
+		addFieldSchemas(schema, t, visiting)
+		return schema
+	}
+
+	return &apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}
+}
+
+// addFieldSchemas adds the schemas of the exported fields of struct type t
+// to the properties of schema, the fields of inlined structs included.
+func addFieldSchemas(schema *apiextensionsv1.JSONSchemaProps, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) != 0 && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if len(name) == 0 && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addFieldSchemas(schema, field.Type, visiting)
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		schema.Properties[name] = *typeSchema(field.Type, visiting)
+	}
+}
+
+func isKubernetesAPIType(t reflect.Type) bool {
+	for _, prefix := range kubernetesAPIPackages {
+		if strings.HasPrefix(t.PkgPath(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+)
+
+type recursive struct {
+	Name     string      `json:"name"`
+	Children []recursive `json:"children,omitempty"`
+	Skipped  string      `json:"-"`
+	inner
+}
+
+type inner struct {
+	Count int32 `json:"count"`
+}
+
+// checkStructural returns the path of the first node of the schema that
+// specifies no type and is neither int-or-string nor preserves unknown fields.
+func checkStructural(path string, schema *apiextensionsv1.JSONSchemaProps) string {
+	if len(schema.Type) == 0 && !schema.XIntOrString &&
+		(schema.XPreserveUnknownFields == nil || !*schema.XPreserveUnknownFields) {
+		return path
+	}
+	for name, property := range schema.Properties {
+		if p := checkStructural(path+"."+name, &property); len(p) != 0 {
+			return p
+		}
+	}
+	if schema.Items != nil && schema.Items.Schema != nil {
+		if p := checkStructural(path+"[]", schema.Items.Schema); len(p) != 0 {
+			return p
+		}
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		if p := checkStructural(path+"{}", schema.AdditionalProperties.Schema); len(p) != 0 {
+			return p
+		}
+	}
+	return ""
+}
+
+func TestStructuralSchema(t *testing.T) {
+	schema := StructuralSchema(genev1alpha1.ExecutionSpec{}, genev1alpha1.ExecutionStatus{})
+	if p := checkStructural("", schema); len(p) != 0 {
+		t.Fatalf("node %s of the schema is not structural", p)
+	}
+
+	spec := schema.Properties["spec"]
+	tasks := spec.Properties["tasks"]
+	if tasks.Type != "array" || tasks.Items == nil || tasks.Items.Schema.Type != "object" {
+		t.Fatalf("expect tasks to be an array of objects, but got %#v", tasks)
+	}
+	task := tasks.Items.Schema
+	if condition := task.Properties["condition"].Properties["condition"]; condition.XPreserveUnknownFields == nil || len(condition.Type) != 0 {
+		t.Errorf("expect condition to be an untyped node preserving unknown fields, but got %#v", condition)
+	}
+	if affinity := task.Properties["affinity"]; affinity.XPreserveUnknownFields == nil || !affinity.Nullable {
+		t.Errorf("expect affinity to be a nullable object preserving unknown fields, but got %#v", affinity)
+	}
+	if max := task.Properties["memoryEscalation"].Properties["max"]; !max.XIntOrString {
+		t.Errorf("expect max to be int or string, but got %#v", max)
+	}
+
+	status := schema.Properties["status"]
+	if startedAt := status.Properties["startedAt"]; startedAt.Type != "string" || startedAt.Format != "date-time" {
+		t.Errorf("expect startedAt to be a date-time string, but got %#v", startedAt)
+	}
+	if _, ok := status.Properties["progress"]; !ok {
+		t.Errorf("expect status to have a progress property")
+	}
+}
+
+func TestStructuralSchemaTemplate(t *testing.T) {
+	schema := StructuralSchema(genev1alpha1.CronExecutionSpec{}, genev1alpha1.CronExecutionStatus{})
+	if p := checkStructural("", schema); len(p) != 0 {
+		t.Fatalf("node %s of the schema is not structural", p)
+	}
+	template := schema.Properties["spec"].Properties["executionTemplate"]
+	if metadata := template.Properties["metadata"]; metadata.XPreserveUnknownFields == nil || len(metadata.Properties) != 0 {
+		t.Errorf("expect the metadata of the template to preserve unknown fields, but got %#v", metadata)
+	}
+	if tasks := template.Properties["spec"].Properties["tasks"]; tasks.Type != "array" {
+		t.Errorf("expect the tasks of the template to be an array, but got %#v", tasks)
+	}
+}
+
+func TestStructuralSchemaNoStatus(t *testing.T) {
+	schema := StructuralSchema(genev1alpha1.WorkflowTemplateSpec{}, nil)
+	if p := checkStructural("", schema); len(p) != 0 {
+		t.Fatalf("node %s of the schema is not structural", p)
+	}
+	if _, ok := schema.Properties["status"]; ok {
+		t.Errorf("expect no status property")
+	}
+	if workflow := schema.Properties["spec"].Properties["workflow"]; workflow.Type != "string" {
+		t.Errorf("expect workflow to be a string, but got %#v", workflow)
+	}
+}
+
+func TestStructuralSchemaRecursive(t *testing.T) {
+	schema := StructuralSchema(recursive{}, recursive{})
+	spec := schema.Properties["spec"]
+	if _, ok := spec.Properties["Skipped"]; ok {
+		t.Errorf("expect the field ignored by json to be skipped")
+	}
+	if count := spec.Properties["count"]; count.Type != "integer" {
+		t.Errorf("expect the inlined field count to be an integer, but got %#v", count)
+	}
+	children := spec.Properties["children"].Items.Schema
+	if children.XPreserveUnknownFields == nil || len(children.Properties) != 0 {
+		t.Errorf("expect the recursive children to preserve unknown fields, but got %#v", children)
+	}
+}
+
+// checkValue returns why the value is rejected or pruned by the schema, as
+// the API server would validate it, or an empty string if it is kept as is.
+func checkValue(path string, schema *apiextensionsv1.JSONSchemaProps, value interface{}) string {
+	preserve := schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields
+	if value == nil {
+		if schema.Nullable || preserve && len(schema.Type) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s: null is not allowed", path)
+	}
+	if schema.XIntOrString {
+		if _, ok := value.(string); ok {
+			return ""
+		}
+		if f, ok := value.(float64); ok && f == math.Trunc(f) {
+			return ""
+		}
+		return fmt.Sprintf("%s: %v is neither an integer nor a string", path, value)
+	}
+
+	switch schema.Type {
+	case "":
+		if !preserve {
+			return fmt.Sprintf("%s: untyped node", path)
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: %v is not an object", path, value)
+		}
+		for name, field := range object {
+			fieldSchema, ok := schema.Properties[name]
+			switch {
+			case ok:
+			case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+				fieldSchema = *schema.AdditionalProperties.Schema
+			case preserve:
+				continue
+			default:
+				return fmt.Sprintf("%s.%s: pruned", path, name)
+			}
+			if reason := checkValue(path+"."+name, &fieldSchema, field); len(reason) != 0 {
+				return reason
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: %v is not an array", path, value)
+		}
+		for i, item := range items {
+			if reason := checkValue(fmt.Sprintf("%s[%d]", path, i), schema.Items.Schema, item); len(reason) != 0 {
+				return reason
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("%s: %v is not a string", path, value)
+		}
+	case "integer":
+		if f, ok := value.(float64); !ok || f != math.Trunc(f) {
+			return fmt.Sprintf("%s: %v is not an integer", path, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Sprintf("%s: %v is not a number", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("%s: %v is not a boolean", path, value)
+		}
+	}
+	return ""
+}
+
+func TestStructuralSchemaExample(t *testing.T) {
+	schemas := map[string]*apiextensionsv1.JSONSchemaProps{
+		"WorkflowTemplate": StructuralSchema(genev1alpha1.WorkflowTemplateSpec{}, nil),
+		"WorkflowRun":      StructuralSchema(genev1alpha1.WorkflowRunSpec{}, genev1alpha1.WorkflowRunStatus{}),
+	}
+	data, err := ioutil.ReadFile("../../example/execution/workflow-run.yaml")
+	if err != nil {
+		t.Fatalf("read example error: %v", err)
+	}
+
+	for _, document := range strings.Split(string(data), "\n---\n") {
+		jsonData, err := yaml.YAMLToJSON([]byte(document))
+		if err != nil {
+			t.Fatalf("convert example error: %v", err)
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal(jsonData, &object); err != nil {
+			t.Fatalf("decode example error: %v", err)
+		}
+		kind, _ := object["kind"].(string)
+		schema, ok := schemas[kind]
+		if !ok {
+			t.Fatalf("unexpected kind %s in the example", kind)
+		}
+		// the metadata is validated as the metadata of any object.
+		delete(object, "metadata")
+		if reason := checkValue(kind, schema, object); len(reason) != 0 {
+			t.Errorf("expect the %s of the example to be kept as is, but got %s", kind, reason)
+		}
+	}
+}
@@ -125,8 +125,8 @@ func MarkExecutionPhase(exec *genev1alpha1.Execution, phase genev1alpha1.VertexP
 		exec.Status.Phase = phase
 	}
 
-	// update start time if it is zero
-	if exec.Status.StartedAt.IsZero() {
+	// update start time if it is zero, a queued execution has not started yet.
+	if exec.Status.StartedAt.IsZero() && phase != genev1alpha1.VertexQueued {
 		exec.Status.StartedAt = metav1.Time{Time: time.Now().UTC()}
 	}
 
@@ -154,6 +154,10 @@ func MarkVertexFailed(exec *genev1alpha1.Execution, vertexName string, message s
 	MarkVertexPhase(exec, vertexName, genev1alpha1.VertexFailed, message)
 }
 
+func MarkVertexSkipped(exec *genev1alpha1.Execution, vertexName string, message string) {
+	MarkVertexPhase(exec, vertexName, genev1alpha1.VertexSkipped, message)
+}
+
 func MarkVertexError(exec *genev1alpha1.Execution, vertexName string, err error) {
 	MarkVertexPhase(exec, vertexName, genev1alpha1.VertexError, err.Error())
 }
@@ -183,7 +187,8 @@ func MarkVertexPhase(exec *genev1alpha1.Execution, vertexName string, phase gene
 		vertexStatus.Message = message
 	}
 
-	if vertexStatus.Phase == genev1alpha1.VertexSucceeded && vertexStatus.FinishedAt.IsZero() {
+	if (vertexStatus.Phase == genev1alpha1.VertexSucceeded || vertexStatus.Phase == genev1alpha1.VertexSkipped) &&
+		vertexStatus.FinishedAt.IsZero() {
 		vertexStatus.FinishedAt = metav1.Now()
 	}
 
@@ -0,0 +1,228 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package volcano runs the jobs of the tasks as Volcano Jobs. The Volcano
+// Jobs are handled through the dynamic client with the minimal types below,
+// and are presented to the controller as the workloads they run.
+package volcano
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+var (
+	// GroupVersion of the Volcano Jobs.
+	GroupVersion = schema.GroupVersion{Group: "batch.volcano.sh", Version: "v1alpha1"}
+	// JobResource is the resource of the Volcano Jobs.
+	JobResource = GroupVersion.WithResource("jobs")
+)
+
+const (
+	// JobKind is the kind of the Volcano Jobs.
+	JobKind = "Job"
+	// JobNameLabel is set by Volcano on the pods of a job.
+	JobNameLabel = "volcano.sh/job-name"
+	// DefaultSchedulerName is the scheduler of the pods by default.
+	DefaultSchedulerName = "volcano"
+
+	// taskName is the name of the only task of the Volcano Job.
+	taskName = "main"
+)
+
+// Job is a Volcano Job.
+type Job struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JobSpec   `json:"spec,omitempty"`
+	Status JobStatus `json:"status,omitempty"`
+}
+
+// JobSpec is the spec of a Volcano Job.
+type JobSpec struct {
+	SchedulerName     string     `json:"schedulerName,omitempty"`
+	MinAvailable      int32      `json:"minAvailable,omitempty"`
+	Tasks             []TaskSpec `json:"tasks,omitempty"`
+	MaxRetry          int32      `json:"maxRetry,omitempty"`
+	Queue             string     `json:"queue,omitempty"`
+	PriorityClassName string     `json:"priorityClassName,omitempty"`
+}
+
+// TaskSpec is a group of identical pods of a Volcano Job.
+type TaskSpec struct {
+	Name     string             `json:"name,omitempty"`
+	Replicas int32              `json:"replicas,omitempty"`
+	Template v1.PodTemplateSpec `json:"template,omitempty"`
+}
+
+// JobPhase is the phase of a Volcano Job.
+type JobPhase string
+
+// Phases of a Volcano Job.
+const (
+	Pending     JobPhase = "Pending"
+	Aborting    JobPhase = "Aborting"
+	Aborted     JobPhase = "Aborted"
+	Running     JobPhase = "Running"
+	Restarting  JobPhase = "Restarting"
+	Completing  JobPhase = "Completing"
+	Completed   JobPhase = "Completed"
+	Terminating JobPhase = "Terminating"
+	Terminated  JobPhase = "Terminated"
+	Failed      JobPhase = "Failed"
+)
+
+// JobState is the state of a Volcano Job.
+type JobState struct {
+	Phase              JobPhase    `json:"phase,omitempty"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// JobStatus is the status of a Volcano Job.
+type JobStatus struct {
+	State     JobState `json:"state,omitempty"`
+	Pending   int32    `json:"pending,omitempty"`
+	Running   int32    `json:"running,omitempty"`
+	Succeeded int32    `json:"succeeded,omitempty"`
+	Failed    int32    `json:"failed,omitempty"`
+}
+
+// IsVolcanoJob returns true if the workload has been converted from a Volcano Job.
+func IsVolcanoJob(job *workload.Workload) bool {
+	return job.APIVersion == GroupVersion.String()
+}
+
+// NewJob returns the Volcano Job running the pods of the workload with the options.
+func NewJob(job *workload.Workload, options *genev1alpha1.VolcanoOptions) *Job {
+	replicas := int32(1)
+	var opts genev1alpha1.VolcanoOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Replicas != nil {
+		replicas = *opts.Replicas
+	}
+	minAvailable := replicas
+	if opts.MinAvailable != nil {
+		minAvailable = *opts.MinAvailable
+	}
+	schedulerName := opts.SchedulerName
+	if len(schedulerName) == 0 {
+		schedulerName = DefaultSchedulerName
+	}
+
+	template := *job.Spec.Template.DeepCopy()
+	template.Spec.SchedulerName = schedulerName
+	template.Spec.PriorityClassName = opts.PriorityClassName
+
+	vcJob := &Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: JobKind},
+		ObjectMeta: *job.ObjectMeta.DeepCopy(),
+		Spec: JobSpec{
+			SchedulerName:     schedulerName,
+			MinAvailable:      minAvailable,
+			Queue:             opts.Queue,
+			PriorityClassName: opts.PriorityClassName,
+			Tasks: []TaskSpec{{
+				Name:     taskName,
+				Replicas: replicas,
+				Template: template,
+			}},
+		},
+	}
+	if job.Spec.BackoffLimit != nil {
+		vcJob.Spec.MaxRetry = *job.Spec.BackoffLimit
+	}
+	return vcJob
+}
+
+// ToWorkload returns the workload the Volcano Job runs. The Volcano phases
+// are mapped onto the phase of the workload, and the selector selects the
+// pods of the Volcano Job.
+func ToWorkload(vcJob *Job) *workload.Workload {
+	job := &workload.Workload{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: JobKind},
+		ObjectMeta: *vcJob.ObjectMeta.DeepCopy(),
+		Spec: workload.Spec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{JobNameLabel: vcJob.Name},
+			},
+		},
+		Status: workload.Status{
+			Phase:   workload.Running,
+			Reason:  vcJob.Status.State.Reason,
+			Message: vcJob.Status.State.Message,
+		},
+	}
+	if len(vcJob.Spec.Tasks) != 0 {
+		replicas := vcJob.Spec.Tasks[0].Replicas
+		job.Spec.Completions = &replicas
+		job.Spec.Parallelism = &replicas
+		job.Spec.Template = *vcJob.Spec.Tasks[0].Template.DeepCopy()
+	}
+	if vcJob.Spec.MaxRetry != 0 {
+		maxRetry := vcJob.Spec.MaxRetry
+		job.Spec.BackoffLimit = &maxRetry
+	}
+
+	switch vcJob.Status.State.Phase {
+	case Completed:
+		job.Status.Phase = workload.Succeeded
+		job.Status.CompletionTime = vcJob.Status.State.LastTransitionTime.DeepCopy()
+	case Failed, Aborted, Terminated:
+		job.Status.Phase = workload.Failed
+		job.Status.CompletionTime = vcJob.Status.State.LastTransitionTime.DeepCopy()
+		if len(job.Status.Message) == 0 {
+			job.Status.Message = fmt.Sprintf("volcano job is %s", vcJob.Status.State.Phase)
+		}
+	}
+	return job
+}
+
+// ToUnstructured returns the Volcano Job as an unstructured object for the dynamic client.
+func ToUnstructured(vcJob *Job) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(vcJob)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(GroupVersion.String())
+	obj.SetKind(JobKind)
+	return obj, nil
+}
+
+// FromUnstructured returns the Volcano Job of an unstructured object.
+func FromUnstructured(obj runtime.Object) (*Job, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("object %T is not unstructured", obj)
+	}
+	vcJob := &Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, vcJob); err != nil {
+		return nil, err
+	}
+	return vcJob, nil
+}
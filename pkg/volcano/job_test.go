@@ -0,0 +1,172 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volcano
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+func newWorkload(name string) *workload.Workload {
+	backoffLimit := int32(3)
+	return &workload.Workload{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: workload.Spec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main", Image: "busybox"}},
+			}},
+		},
+	}
+}
+
+func TestNewJob(t *testing.T) {
+	replicas, minAvailable := int32(4), int32(2)
+	testCases := []struct {
+		Name          string
+		Options       *genev1alpha1.VolcanoOptions
+		Replicas      int32
+		MinAvailable  int32
+		SchedulerName string
+	}{
+		{
+			Name:          "default options",
+			Replicas:      1,
+			MinAvailable:  1,
+			SchedulerName: DefaultSchedulerName,
+		},
+		{
+			Name:          "gang of replicas",
+			Options:       &genev1alpha1.VolcanoOptions{Queue: "genomics", Replicas: &replicas},
+			Replicas:      4,
+			MinAvailable:  4,
+			SchedulerName: DefaultSchedulerName,
+		},
+		{
+			Name: "min available and scheduler",
+			Options: &genev1alpha1.VolcanoOptions{SchedulerName: "custom", Replicas: &replicas,
+				MinAvailable: &minAvailable},
+			Replicas:      4,
+			MinAvailable:  2,
+			SchedulerName: "custom",
+		},
+	}
+
+	for _, testCase := range testCases {
+		vcJob := NewJob(newWorkload("a"), testCase.Options)
+		if vcJob.Spec.Tasks[0].Replicas != testCase.Replicas {
+			t.Errorf("%s: Expect replicas %d, but got %d", testCase.Name, testCase.Replicas, vcJob.Spec.Tasks[0].Replicas)
+		}
+		if vcJob.Spec.MinAvailable != testCase.MinAvailable {
+			t.Errorf("%s: Expect minAvailable %d, but got %d", testCase.Name, testCase.MinAvailable, vcJob.Spec.MinAvailable)
+		}
+		if vcJob.Spec.Tasks[0].Template.Spec.SchedulerName != testCase.SchedulerName {
+			t.Errorf("%s: Expect scheduler %s, but got %s", testCase.Name, testCase.SchedulerName,
+				vcJob.Spec.Tasks[0].Template.Spec.SchedulerName)
+		}
+		if vcJob.Spec.MaxRetry != 3 {
+			t.Errorf("%s: Expect maxRetry 3, but got %d", testCase.Name, vcJob.Spec.MaxRetry)
+		}
+	}
+}
+
+func TestToWorkload(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Phase  JobPhase
+		Expect workload.Phase
+	}{
+		{Name: "pending", Phase: Pending, Expect: workload.Running},
+		{Name: "running", Phase: Running, Expect: workload.Running},
+		{Name: "completed", Phase: Completed, Expect: workload.Succeeded},
+		{Name: "failed", Phase: Failed, Expect: workload.Failed},
+		{Name: "aborted", Phase: Aborted, Expect: workload.Failed},
+		{Name: "terminated", Phase: Terminated, Expect: workload.Failed},
+	}
+
+	for _, testCase := range testCases {
+		vcJob := NewJob(newWorkload("a"), nil)
+		vcJob.Status.State.Phase = testCase.Phase
+		job := ToWorkload(vcJob)
+		if !IsVolcanoJob(job) {
+			t.Errorf("%s: Expect a volcano job, but got %s", testCase.Name, job.APIVersion)
+		}
+		if job.Spec.Selector.MatchLabels[JobNameLabel] != "a" {
+			t.Errorf("%s: Expect selector of the volcano job, but got %v", testCase.Name, job.Spec.Selector)
+		}
+		if job.Status.Phase != testCase.Expect {
+			t.Errorf("%s: Expect phase %s, but got %s", testCase.Name, testCase.Expect, job.Status.Phase)
+		}
+		if *job.Spec.BackoffLimit != 3 {
+			t.Errorf("%s: Expect backoff limit 3, but got %d", testCase.Name, *job.Spec.BackoffLimit)
+		}
+	}
+}
+
+func TestUnstructured(t *testing.T) {
+	vcJob := NewJob(newWorkload("a"), &genev1alpha1.VolcanoOptions{Queue: "genomics"})
+	obj, err := ToUnstructured(vcJob)
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if obj.GetAPIVersion() != GroupVersion.String() || obj.GetKind() != JobKind {
+		t.Errorf("Expect volcano job kind, but got %s %s", obj.GetAPIVersion(), obj.GetKind())
+	}
+	got, err := FromUnstructured(obj)
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if got.Name != "a" || got.Spec.Queue != "genomics" || len(got.Spec.Tasks) != 1 {
+		t.Errorf("Expect the same volcano job, but got %v", got)
+	}
+	if _, err := FromUnstructured(&v1.Pod{}); err == nil {
+		t.Errorf("Expect error, but got nil")
+	}
+}
+
+func TestJobLister(t *testing.T) {
+	obj, err := ToUnstructured(NewJob(newWorkload("b"), nil))
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(obj)
+
+	lister := NewJobLister(cache.NewGenericLister(indexer, JobResource.GroupResource()))
+	jobs, err := lister.Workloads("default").List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Expect no error, but got error %v", err)
+	}
+	if len(jobs) != 1 || !IsVolcanoJob(jobs[0]) {
+		t.Errorf("Expect volcano job b, but got %v", jobs)
+	}
+
+	job, err := lister.Workloads("default").Get("b")
+	if err != nil || !IsVolcanoJob(job) {
+		t.Errorf("Expect volcano job b, but got %v %v", job, err)
+	}
+	if _, err := lister.Workloads("default").Get("c"); err == nil {
+		t.Errorf("Expect error, but got nil")
+	}
+}
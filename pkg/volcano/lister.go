@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volcano
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"kubegene.io/kubegene/pkg/workload"
+)
+
+// jobLister lists the Volcano Jobs converted to workloads.
+type jobLister struct {
+	lister cache.GenericLister
+}
+
+var _ workload.Lister = &jobLister{}
+
+// NewJobLister returns a Lister listing the Volcano Jobs of lister as workloads.
+func NewJobLister(lister cache.GenericLister) workload.Lister {
+	return &jobLister{lister: lister}
+}
+
+func (l *jobLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	objs, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return toWorkloads(objs), nil
+}
+
+func (l *jobLister) Workloads(namespace string) workload.NamespaceLister {
+	return &jobNamespaceLister{lister: l.lister.ByNamespace(namespace)}
+}
+
+type jobNamespaceLister struct {
+	lister cache.GenericNamespaceLister
+}
+
+func (l *jobNamespaceLister) List(selector labels.Selector) ([]*workload.Workload, error) {
+	objs, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return toWorkloads(objs), nil
+}
+
+func (l *jobNamespaceLister) Get(name string) (*workload.Workload, error) {
+	obj, err := l.lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	vcJob, err := FromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return ToWorkload(vcJob), nil
+}
+
+func toWorkloads(objs []runtime.Object) []*workload.Workload {
+	jobs := make([]*workload.Workload, 0, len(objs))
+	for _, obj := range objs {
+		vcJob, err := FromUnstructured(obj)
+		if err != nil {
+			klog.Errorf("convert volcano job error: %v", err)
+			continue
+		}
+		jobs = append(jobs, ToWorkload(vcJob))
+	}
+	return jobs
+}
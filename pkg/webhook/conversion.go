@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+
+	genev1alpha1 "kubegene.io/kubegene/pkg/apis/gene/v1alpha1"
+	genev1beta1 "kubegene.io/kubegene/pkg/apis/gene/v1beta1"
+)
+
+// ConversionPath is the path the conversion webhook of the executions is served at.
+const ConversionPath = "/convert"
+
+// ServeConversion serves the conversion webhook converting the executions
+// between v1alpha1 and v1beta1.
+func ServeConversion(w http.ResponseWriter, r *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("decode conversion review error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "conversion review has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = Convert(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("encode conversion review error: %v", err)
+	}
+}
+
+// Convert converts the objects of a conversion request to the desired version.
+func Convert(request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	response := &apiextensionsv1.ConversionResponse{UID: request.UID}
+	for _, object := range request.Objects {
+		converted, err := convertExecution(object.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			klog.V(2).Infof("convert execution to %s error: %v", request.DesiredAPIVersion, err)
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return response
+}
+
+// convertExecution converts the json encoding of an execution to the version.
+func convertExecution(raw []byte, version string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+
+	var converted interface{}
+	switch {
+	case typeMeta.APIVersion == version:
+		return raw, nil
+	case typeMeta.APIVersion == genev1alpha1.SchemeGroupVersion.String() && version == genev1beta1.SchemeGroupVersion.String():
+		in, out := &genev1alpha1.Execution{}, &genev1beta1.Execution{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		if err := genev1beta1.ConvertFromV1alpha1(in, out); err != nil {
+			return nil, fmt.Errorf("execution %s/%s: %v", in.Namespace, in.Name, err)
+		}
+		converted = out
+	case typeMeta.APIVersion == genev1beta1.SchemeGroupVersion.String() && version == genev1alpha1.SchemeGroupVersion.String():
+		in, out := &genev1beta1.Execution{}, &genev1alpha1.Execution{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		if err := genev1beta1.ConvertToV1alpha1(in, out); err != nil {
+			return nil, fmt.Errorf("execution %s/%s: %v", in.Namespace, in.Name, err)
+		}
+		converted = out
+	default:
+		return nil, fmt.Errorf("unsupported conversion from %s to %s", typeMeta.APIVersion, version)
+	}
+	return json.Marshal(converted)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	genev1beta1 "kubegene.io/kubegene/pkg/apis/gene/v1beta1"
+)
+
+func conversionRequest(version string, objects ...string) *apiextensionsv1.ConversionRequest {
+	request := &apiextensionsv1.ConversionRequest{UID: "uid", DesiredAPIVersion: version}
+	for _, object := range objects {
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: []byte(object)})
+	}
+	return request
+}
+
+func TestConvert(t *testing.T) {
+	v1alpha1Exec := `{"apiVersion": "execution.kubegene.io/v1alpha1", "kind": "Execution",
+		"metadata": {"name": "exec"},
+		"spec": {"tasks": [{"name": "a", "condition": {"condition": [false]}}]}}`
+	v1beta1Exec := `{"apiVersion": "execution.kubegene.io/v1beta1", "kind": "Execution",
+		"metadata": {"name": "exec"},
+		"spec": {"tasks": [{"name": "a", "condition": {"bool": false}}]}}`
+
+	testCases := []struct {
+		Name      string
+		Request   *apiextensionsv1.ConversionRequest
+		ExpectErr bool
+	}{
+		{
+			Name:    "v1alpha1 to v1beta1",
+			Request: conversionRequest("execution.kubegene.io/v1beta1", v1alpha1Exec, v1beta1Exec),
+		},
+		{
+			Name:    "v1beta1 to v1alpha1",
+			Request: conversionRequest("execution.kubegene.io/v1alpha1", v1beta1Exec, v1alpha1Exec),
+		},
+		{
+			Name:      "unsupported version",
+			Request:   conversionRequest("execution.kubegene.io/v1", v1alpha1Exec),
+			ExpectErr: true,
+		},
+		{
+			Name: "invalid condition",
+			Request: conversionRequest("execution.kubegene.io/v1beta1", `{"apiVersion": "execution.kubegene.io/v1alpha1",
+				"spec": {"tasks": [{"name": "a", "condition": {"condition": ["unknown"]}}]}}`),
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		response := Convert(testCase.Request)
+		if response.UID != testCase.Request.UID {
+			t.Errorf("%s: Expect uid %s, but got %s", testCase.Name, testCase.Request.UID, response.UID)
+		}
+		if testCase.ExpectErr {
+			if response.Result.Status != metav1.StatusFailure || len(response.ConvertedObjects) != 0 {
+				t.Errorf("%s: Expect failure, but got %#v", testCase.Name, response)
+			}
+			continue
+		}
+		if response.Result.Status != metav1.StatusSuccess {
+			t.Errorf("%s: Expect success, but got %s", testCase.Name, response.Result.Message)
+			continue
+		}
+		if len(response.ConvertedObjects) != len(testCase.Request.Objects) {
+			t.Errorf("%s: Expect %d objects, but got %d", testCase.Name, len(testCase.Request.Objects), len(response.ConvertedObjects))
+		}
+		for _, object := range response.ConvertedObjects {
+			typeMeta := metav1.TypeMeta{}
+			if err := json.Unmarshal(object.Raw, &typeMeta); err != nil {
+				t.Errorf("%s: Expect no error, but got %v", testCase.Name, err)
+			}
+			if typeMeta.APIVersion != testCase.Request.DesiredAPIVersion || typeMeta.Kind != "Execution" {
+				t.Errorf("%s: Expect %s Execution, but got %s %s", testCase.Name, testCase.Request.DesiredAPIVersion, typeMeta.APIVersion, typeMeta.Kind)
+			}
+		}
+	}
+}
+
+func TestServeConversion(t *testing.T) {
+	review := apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: conversionRequest("execution.kubegene.io/v1beta1", `{"apiVersion": "execution.kubegene.io/v1alpha1",
+			"kind": "Execution", "spec": {"tasks": [{"name": "a", "commandsIter": {"command": "echo ${1}",
+			"varsIter": [["get_result", "exec.b.", ","]]}}]}}`),
+	}
+	body, _ := json.Marshal(review)
+	recorder := httptest.NewRecorder()
+	ServeConversion(recorder, httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expect status 200, but got %d", recorder.Code)
+	}
+
+	response := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expect no error, but got %v", err)
+	}
+	if response.Kind != "ConversionReview" || response.Request != nil || response.Response == nil {
+		t.Fatalf("unexpected conversion review %#v", response)
+	}
+	exec := genev1beta1.Execution{}
+	if err := json.Unmarshal(response.Response.ConvertedObjects[0].Raw, &exec); err != nil {
+		t.Fatalf("Expect no error, but got %v", err)
+	}
+	result := exec.Spec.Tasks[0].CommandsIter.VarsIter[0].Result
+	if result == nil || result.Job != "exec.b." || result.Separator != "," {
+		t.Errorf("Expect the result of exec.b. separated by a comma, but got %#v", result)
+	}
+
+	recorder = httptest.NewRecorder()
+	ServeConversion(recorder, httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader([]byte("{"))))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expect status 400, but got %d", recorder.Code)
+	}
+}
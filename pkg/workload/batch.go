@@ -0,0 +1,141 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+)
+
+// NewBatchJob returns the batch Job running the workload.
+func NewBatchJob(w *Workload) *batch.Job {
+	copied := w.DeepCopy()
+	job := &batch.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: batch.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: copied.ObjectMeta,
+		Spec: batch.JobSpec{
+			Template:              copied.Spec.Template,
+			Completions:           copied.Spec.Completions,
+			Parallelism:           copied.Spec.Parallelism,
+			BackoffLimit:          copied.Spec.BackoffLimit,
+			ActiveDeadlineSeconds: copied.Spec.ActiveDeadlineSeconds,
+		},
+	}
+	job.ResourceVersion = ""
+	return job
+}
+
+// FromBatchJob returns the workload the batch Job runs, the conditions of
+// the batch Job are mapped onto the phase of the workload.
+func FromBatchJob(job *batch.Job) *Workload {
+	copied := job.DeepCopy()
+	w := &Workload{
+		TypeMeta:   metav1.TypeMeta{APIVersion: batch.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: copied.ObjectMeta,
+		Spec: Spec{
+			Template:              copied.Spec.Template,
+			Selector:              copied.Spec.Selector,
+			Completions:           copied.Spec.Completions,
+			Parallelism:           copied.Spec.Parallelism,
+			BackoffLimit:          copied.Spec.BackoffLimit,
+			ActiveDeadlineSeconds: copied.Spec.ActiveDeadlineSeconds,
+		},
+		Status: Status{
+			Phase:          Running,
+			StartTime:      copied.Status.StartTime,
+			CompletionTime: copied.Status.CompletionTime,
+		},
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batch.JobComplete:
+			w.Status.Phase = Succeeded
+		case batch.JobFailed:
+			w.Status.Phase = Failed
+		default:
+			continue
+		}
+		w.Status.Reason = condition.Reason
+		w.Status.Message = condition.Message
+		break
+	}
+	return w
+}
+
+// batchLister lists the batch Jobs accepted by filter as workloads.
+type batchLister struct {
+	lister batchv1listers.JobLister
+	filter func(job *batch.Job) bool
+}
+
+// NewBatchLister returns a Lister listing the batch Jobs of lister for
+// which filter returns true as workloads.
+func NewBatchLister(lister batchv1listers.JobLister, filter func(job *batch.Job) bool) Lister {
+	return &batchLister{lister: lister, filter: filter}
+}
+
+func (l *batchLister) List(selector labels.Selector) ([]*Workload, error) {
+	jobs, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return fromBatchJobs(jobs, l.filter), nil
+}
+
+func (l *batchLister) Workloads(namespace string) NamespaceLister {
+	return &batchNamespaceLister{lister: l.lister.Jobs(namespace), filter: l.filter}
+}
+
+type batchNamespaceLister struct {
+	lister batchv1listers.JobNamespaceLister
+	filter func(job *batch.Job) bool
+}
+
+func (l *batchNamespaceLister) List(selector labels.Selector) ([]*Workload, error) {
+	jobs, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return fromBatchJobs(jobs, l.filter), nil
+}
+
+func (l *batchNamespaceLister) Get(name string) (*Workload, error) {
+	job, err := l.lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !l.filter(job) {
+		return nil, NewNotFound(name)
+	}
+	return FromBatchJob(job), nil
+}
+
+func fromBatchJobs(jobs []*batch.Job, filter func(job *batch.Job) bool) []*Workload {
+	workloads := make([]*Workload, 0, len(jobs))
+	for _, job := range jobs {
+		if filter(job) {
+			workloads = append(workloads, FromBatchJob(job))
+		}
+	}
+	return workloads
+}
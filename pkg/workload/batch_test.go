@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFromBatchJob(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Conditions []batch.JobCondition
+		Phase      Phase
+		Message    string
+	}{
+		{
+			Name:  "running",
+			Phase: Running,
+		},
+		{
+			Name:       "suspended",
+			Conditions: []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionFalse}},
+			Phase:      Running,
+		},
+		{
+			Name:       "complete",
+			Conditions: []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}},
+			Phase:      Succeeded,
+		},
+		{
+			Name: "failed",
+			Conditions: []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue,
+				Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}},
+			Phase:   Failed,
+			Message: "Job has reached the specified backoff limit",
+		},
+	}
+
+	for _, testCase := range testCases {
+		backoffLimit := int32(2)
+		job := &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", ResourceVersion: "1"},
+			Spec:       batch.JobSpec{BackoffLimit: &backoffLimit},
+			Status:     batch.JobStatus{Conditions: testCase.Conditions},
+		}
+		w := FromBatchJob(job)
+		if w.Kind != "Job" || w.Name != "a" {
+			t.Errorf("%s: Expect workload of job a, but got %s %s", testCase.Name, w.Kind, w.Name)
+		}
+		if w.Status.Phase != testCase.Phase || w.Status.Message != testCase.Message {
+			t.Errorf("%s: Expect phase %s %q, but got %s %q", testCase.Name, testCase.Phase, testCase.Message,
+				w.Status.Phase, w.Status.Message)
+		}
+		if w.Finished() != (testCase.Phase != Running) {
+			t.Errorf("%s: Expect finished to be %v", testCase.Name, testCase.Phase != Running)
+		}
+
+		created := NewBatchJob(w)
+		if created.ResourceVersion != "" || *created.Spec.BackoffLimit != 2 {
+			t.Errorf("%s: Expect a new job with backoff limit 2, but got %v", testCase.Name, created)
+		}
+		*created.Spec.BackoffLimit = 3
+		if *w.Spec.BackoffLimit != 2 {
+			t.Errorf("%s: Expect the workload not to share the spec of the new job", testCase.Name)
+		}
+	}
+}
+
+func TestBatchLister(t *testing.T) {
+	jobInformer := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Batch().V1().Jobs()
+	jobInformer.Informer().GetIndexer().Add(&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}})
+	jobInformer.Informer().GetIndexer().Add(&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}})
+
+	lister := NewBatchLister(jobInformer.Lister(), func(job *batch.Job) bool { return job.Name != "b" })
+	workloads, err := lister.List(labels.Everything())
+	if err != nil || len(workloads) != 1 {
+		t.Errorf("Expect workload a, but got %v %v", workloads, err)
+	}
+	if w, err := lister.Workloads("default").Get("a"); err != nil || w.Name != "a" {
+		t.Errorf("Expect workload a, but got %v %v", w, err)
+	}
+	for _, key := range [][]string{{"other", "a"}, {"default", "b"}} {
+		if _, err := lister.Workloads(key[0]).Get(key[1]); !errors.IsNotFound(err) {
+			t.Errorf("%s/%s: Expect not found error, but got %v", key[0], key[1], err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Kubegene Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workload describes the jobs of the vertices of an execution
+// independently of the backend running them. The controller describes every
+// job as a Workload, an executor runs it as the object of its backend, e.g.
+// a batch Job, a Pod or a Volcano Job, and presents that object back as a
+// Workload whose status tells whether the job has finished.
+package workload
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Resource is the resource of the workloads in the errors of the listers.
+var Resource = schema.GroupResource{Group: "kubegene.io", Resource: "workloads"}
+
+// Phase is the phase of a workload.
+type Phase string
+
+// Phases of a workload.
+const (
+	// Running workloads have not finished yet, pending ones included.
+	Running Phase = "Running"
+	// Pending workloads wait for their backend to start their pod, e.g. the
+	// indexes of an Indexed Job beyond its parallelism. Unlike the running
+	// ones, they do not count against the parallelism of the execution.
+	Pending   Phase = "Pending"
+	Succeeded Phase = "Succeeded"
+	Failed    Phase = "Failed"
+)
+
+// Workload runs the pods of the job of a vertex. The type meta is the one
+// of the object of the backend running the workload, empty until created.
+type Workload struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec   Spec
+	Status Status
+}
+
+// Spec is what a workload runs.
+type Spec struct {
+	// Template describes the pods of the workload.
+	Template v1.PodTemplateSpec
+	// Selector selects the pods of the workload once it has been created.
+	Selector *metav1.LabelSelector
+	// Completions is the number of pods to complete, and Parallelism the
+	// number of pods running at once, one each if nil.
+	Completions *int32
+	Parallelism *int32
+	// BackoffLimit is the number of retries before the workload fails.
+	BackoffLimit *int32
+	// ActiveDeadlineSeconds is how long the workload may run.
+	ActiveDeadlineSeconds *int64
+}
+
+// Status is the observed state of a workload.
+type Status struct {
+	Phase   Phase
+	Reason  string
+	Message string
+	// StartTime and CompletionTime are set once known by the backend.
+	StartTime      *metav1.Time
+	CompletionTime *metav1.Time
+}
+
+// Finished returns true if the workload has succeeded or failed.
+func (w *Workload) Finished() bool {
+	return w.Status.Phase == Succeeded || w.Status.Phase == Failed
+}
+
+// DeepCopy returns a deep copy of the workload.
+func (w *Workload) DeepCopy() *Workload {
+	if w == nil {
+		return nil
+	}
+	out := &Workload{
+		TypeMeta:   w.TypeMeta,
+		ObjectMeta: *w.ObjectMeta.DeepCopy(),
+		Spec: Spec{
+			Template: *w.Spec.Template.DeepCopy(),
+			Selector: w.Spec.Selector.DeepCopy(),
+		},
+		Status: Status{
+			Phase:          w.Status.Phase,
+			Reason:         w.Status.Reason,
+			Message:        w.Status.Message,
+			StartTime:      w.Status.StartTime.DeepCopy(),
+			CompletionTime: w.Status.CompletionTime.DeepCopy(),
+		},
+	}
+	out.Spec.Completions = copyInt32(w.Spec.Completions)
+	out.Spec.Parallelism = copyInt32(w.Spec.Parallelism)
+	out.Spec.BackoffLimit = copyInt32(w.Spec.BackoffLimit)
+	if w.Spec.ActiveDeadlineSeconds != nil {
+		seconds := *w.Spec.ActiveDeadlineSeconds
+		out.Spec.ActiveDeadlineSeconds = &seconds
+	}
+	return out
+}
+
+func copyInt32(value *int32) *int32 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// Lister lists the workloads of a backend.
+type Lister interface {
+	// List lists all the workloads matching the selector.
+	List(selector labels.Selector) ([]*Workload, error)
+	// Workloads returns the lister of the workloads of the namespace.
+	Workloads(namespace string) NamespaceLister
+}
+
+// NamespaceLister lists the workloads of a namespace.
+type NamespaceLister interface {
+	// List lists the workloads of the namespace matching the selector.
+	List(selector labels.Selector) ([]*Workload, error)
+	// Get returns the workload of the name, or a NotFound error.
+	Get(name string) (*Workload, error)
+}
+
+// NewNotFound returns the error of a lister not finding the workload of the name.
+func NewNotFound(name string) error {
+	return errors.NewNotFound(Resource, name)
+}